@@ -24,42 +24,33 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/customers": {
+        "/api-keys": {
             "get": {
-                "description": "Returns a list of customers filtered by organization ID",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the API keys of the organization, without their secrets",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "customers"
-                ],
-                "summary": "Get all customers",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Pagination limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
-                        "in": "query"
-                    }
+                    "api-keys"
                 ],
+                "summary": "Get API keys",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/customer.Customer"
+                                "$ref": "#/definitions/apikey.APIKey"
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -70,7 +61,12 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Registers a new customer within the organization",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Issues a key for integrations, presented as \"Authorization: ApiKey \u003ckey\u003e\". The key is only returned in this response.",
                 "consumes": [
                     "application/json"
                 ],
@@ -78,25 +74,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "customers"
+                    "api-keys"
                 ],
-                "summary": "Create a new customer",
+                "summary": "Create an API key",
                 "parameters": [
                     {
-                        "description": "Customer details",
-                        "name": "customer",
+                        "description": "API key",
+                        "name": "key",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "email": {
-                                    "type": "string"
-                                },
-                                "full_name": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/apikey.CreateAPIKeyRequest"
                         }
                     }
                 ],
@@ -104,7 +92,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/customer.Customer"
+                            "$ref": "#/definitions/apikey.CreateAPIKeyResponse"
                         }
                     },
                     "400": {
@@ -119,27 +107,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/customers/{id}": {
-            "get": {
+        "/api-keys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Requests made with the key are rejected from now on",
                 "tags": [
-                    "customers"
+                    "api-keys"
                 ],
-                "summary": "Get customer by ID",
+                "summary": "Revoke an API key",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Customer ID",
+                        "description": "API key ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/customer.Customer"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "404": {
                         "description": "Not Found",
@@ -151,47 +142,75 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/audit": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns who changed an entity, when, from which request and what changed, newest first",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "customers"
+                    "audit"
                 ],
-                "summary": "Delete a customer",
+                "summary": "Get the audit log of an entity",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Customer ID",
+                        "enum": [
+                            "reservation",
+                            "privacy_request"
+                        ],
+                        "type": "string",
+                        "description": "Entity type",
+                        "name": "entity",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Entity ID",
                         "name": "id",
-                        "in": "path",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size (max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/audit.EntryPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "boolean"
+                                "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/health/liveness": {
-            "get": {
-                "description": "Check if the service process is alive",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "health"
-                ],
-                "summary": "Liveness probe",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -202,19 +221,80 @@ const docTemplate = `{
                 }
             }
         },
-        "/health/readiness": {
-            "get": {
-                "description": "Check if the service is ready to handle requests",
+        "/communication/email": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Queues an email to the guest of a reservation of the organization. The payment link is the one stored on the reservation. Each request is recorded and counted against a per-organization hourly limit.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "communication"
+                ],
+                "summary": "Send a reservation email",
+                "parameters": [
+                    {
+                        "description": "Email",
+                        "name": "email",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/communication.SendEmailRequest"
+                        }
+                    }
                 ],
-                "summary": "Readiness probe",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/communication.Delivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -225,44 +305,49 @@ const docTemplate = `{
                 }
             }
         },
-        "/reservations": {
+        "/communication/schedules": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Returns a list of all reservations for the authenticated organization",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns the active guest communication schedules of the organization",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "reservations"
+                    "communication"
                 ],
-                "summary": "Get all reservations",
+                "summary": "Get message schedules",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/booking.ReservationResponse"
+                                "$ref": "#/definitions/scheduler.Schedule"
                             }
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/booking.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             },
             "post": {
-                "description": "Create a new reservation with the provided details",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sends the given email type offset_days from the check-in or check-out date of every confirmed reservation",
                 "consumes": [
                     "application/json"
                 ],
@@ -270,17 +355,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "reservations"
+                    "communication"
                 ],
-                "summary": "Create a new reservation",
+                "summary": "Create a message schedule",
                 "parameters": [
                     {
-                        "description": "Reservation details",
-                        "name": "reservation",
+                        "description": "Schedule",
+                        "name": "schedule",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/booking.ReservationRequest"
+                            "$ref": "#/definitions/scheduler.ScheduleRequest"
                         }
                     }
                 ],
@@ -288,27 +373,29 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/booking.ReservationResponse"
+                            "$ref": "#/definitions/scheduler.Schedule"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/booking.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/booking.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/reservations/{id}": {
-            "get": {
-                "description": "Get reservation details by its integer ID",
+        "/communication/schedules/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Messages already planned with the old timing are re-planned",
                 "consumes": [
                     "application/json"
                 ],
@@ -316,74 +403,122 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "reservations"
+                    "communication"
                 ],
-                "summary": "Get reservation by ID",
+                "summary": "Update a message schedule",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Reservation ID",
+                        "description": "Schedule ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Schedule",
+                        "name": "schedule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/scheduler.ScheduleRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/booking.ReservationResponse"
+                            "$ref": "#/definitions/scheduler.Schedule"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/booking.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/booking.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             },
-            "put": {
-                "description": "Update reservation details by integer ID",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Stops the schedule; messages that have not been sent yet are dropped",
                 "tags": [
-                    "reservations"
+                    "communication"
                 ],
-                "summary": "Update a reservation",
+                "summary": "Delete a message schedule",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Reservation ID",
+                        "description": "Schedule ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
                     },
-                    {
-                        "description": "Updated reservation details",
-                        "name": "reservation",
-                        "in": "body",
-                        "required": true,
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/booking.ReservationRequest"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
+                }
+            }
+        },
+        "/customer/{id}/reservations": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the reservation history of a customer within the authenticated organization, newest stay first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Get a customer's reservations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/booking.ReservationResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/booking.ReservationResponse"
+                            }
                         }
                     },
                     "400": {
@@ -391,190 +526,6577 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/booking.ErrorResponse"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/customer/{id}/summary": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns total stays, nights, revenue, cancellations, no-shows, the last stay and upcoming stays of a customer within the authenticated organization",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "reservations"
+                    "customers"
                 ],
-                "summary": "Delete a reservation",
+                "summary": "Get a customer's stay summary",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Reservation ID",
+                        "description": "Customer ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/booking.CustomerSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/reservations/{id}/cancel": {
-            "post": {
+        "/customers": {
+            "get": {
+                "description": "Returns a page of the organization's customers, optionally filtered by name or email",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "reservations"
+                    "customers"
                 ],
-                "summary": "Cancel a reservation",
+                "summary": "Get all customers",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search by full name or email",
+                        "name": "q",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Reservation ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Pagination limit (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination offset",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/booking.ReservationResponse"
+                            "$ref": "#/definitions/customer.CustomerPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Registers a new customer within the organization",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Create a new customer",
+                "parameters": [
+                    {
+                        "description": "Customer details",
+                        "name": "customer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "email": {
+                                    "type": "string"
+                                },
+                                "full_name": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/customer.Customer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/customers/merge": {
+            "post": {
+                "description": "Moves every reservation of the duplicate customer to the surviving customer and deletes the duplicate. Both customers must belong to the organization. The duplicate is deleted once the reservations are moved; if the customer service can't delete it right away, duplicate_deleted is false and the deletion is retried in the background.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Merge duplicate customers",
+                "parameters": [
+                    {
+                        "description": "Customers to merge",
+                        "name": "merge",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/customer.MergeCustomersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/customer.MergeCustomersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/reservations/{id}/status": {
-            "patch": {
-                "description": "Update the status of a reservation",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "reservations"
-                ],
-                "summary": "Update reservation status",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Reservation ID (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "New status",
-                        "name": "status",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/booking.StatusUpdateRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/booking.ReservationResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/booking.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/booking.ErrorResponse"
-                        }
+        "/customers/{id}": {
+            "get": {
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Get customer by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/customer.Customer"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces the name and email of a customer within the organization",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Update a customer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Customer details",
+                        "name": "customer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/hostflow_booking-service_internal_customer.UpdateCustomerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/customer.Customer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Delete a customer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "boolean"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/health/liveness": {
+            "get": {
+                "description": "Check if the service process is alive",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/health/readiness": {
+            "get": {
+                "description": "Check if the service is ready to handle requests",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/invoices/settings": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the issuer details and default VAT rate printed on the invoices of the organization",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Get the invoice settings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/invoice.Settings"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates or replaces the issuer details and default VAT rate of the organization. Invoices already issued keep the details they were issued with.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Save the invoice settings",
+                "parameters": [
+                    {
+                        "description": "Settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/invoice.SettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/invoice.Settings"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/portal/reservation": {
+            "get": {
+                "security": [
+                    {
+                        "GuestPortalToken": []
+                    }
+                ],
+                "description": "Returns the reservation of the portal link. Encrypted guest data is redacted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "guest-portal"
+                ],
+                "summary": "View the reservation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "PIN of a PIN-protected link",
+                        "name": "X-Guest-Pin",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/portal.View"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/portal.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/portal.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/portal.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/portal/reservation/arrival": {
+            "put": {
+                "security": [
+                    {
+                        "GuestPortalToken": []
+                    }
+                ],
+                "description": "Stores the expected arrival time (HH:MM) and special requests in the additional requests of the reservation. Empty values clear them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "guest-portal"
+                ],
+                "summary": "Set the arrival time and special requests",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "PIN of a PIN-protected link",
+                        "name": "X-Guest-Pin",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Arrival details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/portal.ArrivalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/portal.View"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/portal.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/portal.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/portal.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/portal/reservation/guests": {
+            "put": {
+                "security": [
+                    {
+                        "GuestPortalToken": []
+                    }
+                ],
+                "description": "Replaces the registered guests of the reservation. The guests are validated against the required fields of their nationality and their number must equal no_of_guests. Fields returned redacted may be sent back as they are to keep them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "guest-portal"
+                ],
+                "summary": "Register the guests",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "PIN of a PIN-protected link",
+                        "name": "X-Guest-Pin",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Guests",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/portal.GuestsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/portal.View"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/portal.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/portal.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/portal.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/portal/reservation/house-rules": {
+            "post": {
+                "security": [
+                    {
+                        "GuestPortalToken": []
+                    }
+                ],
+                "description": "Records when the guest accepted the house rules, and optionally their version, in the additional requests of the reservation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "guest-portal"
+                ],
+                "summary": "Accept the house rules",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "PIN of a PIN-protected link",
+                        "name": "X-Guest-Pin",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Acceptance",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/portal.HouseRulesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/portal.View"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/portal.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/portal.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/portal.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/privacy/customers/{id}/erasure": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Anonymizes every reservation of the customer: guest data, additional requests and payment links are cleared, also from earlier audit entries, while dates, guest counts and financial totals are kept. The request is tracked as an ERASURE privacy request.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "privacy"
+                ],
+                "summary": "Erase a customer's personal data",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Erasure details",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/privacy.ErasureRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/privacy.Request"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/privacy/customers/{id}/export": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns every reservation of the customer, including deleted ones, and the emails sent about them as a JSON bundle. The request is tracked as an EXPORT privacy request; the bundle itself is not stored.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "privacy"
+                ],
+                "summary": "Export a customer's data",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/privacy.ExportBundle"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/privacy/requests": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the export and erasure requests of the organization, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "privacy"
+                ],
+                "summary": "Get privacy requests",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size (max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/privacy.RequestPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/privacy/requests/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "privacy"
+                ],
+                "summary": "Get a privacy request",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/privacy.Request"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/properties/{id}/room-types": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the room types of a property by name, with the number of active units of each",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rooms"
+                ],
+                "summary": "Get the room types of a property",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Property ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/room.RoomType"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a room type of a property. Once a property has room types, its reservations must reference a room type or a unit, and are available while the active units of the type are not all booked on any night. Properties without room types remain a single bookable unit.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rooms"
+                ],
+                "summary": "Create a room type",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Property ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Room type",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/room.RoomTypeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/room.RoomType"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/cancellations": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the share (%) of the reservations checking in per period that were cancelled. Rejected reservations are not counted.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Cancellation rate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day (YYYY-MM-DD), by default the first day of the current month",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day (YYYY-MM-DD), by default a month after from",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "day",
+                            "week",
+                            "month"
+                        ],
+                        "type": "string",
+                        "default": "month",
+                        "description": "Period length",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "One row per property and period",
+                        "name": "by_property",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only this property",
+                        "name": "property_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Output format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/report.CancellationReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/lead-time": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the average, median, minimum and maximum days between booking and check-in of the reservations checking in per period, except cancelled and rejected ones",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Lead time",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day (YYYY-MM-DD), by default the first day of the current month",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day (YYYY-MM-DD), by default a month after from",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "day",
+                            "week",
+                            "month"
+                        ],
+                        "type": "string",
+                        "default": "month",
+                        "description": "Period length",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "One row per property and period",
+                        "name": "by_property",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only this property",
+                        "name": "property_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Output format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/report.LeadTimeReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/length-of-stay": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the number of reservations checking in per period by number of nights (1 to 7, 8-13 and 14+), except cancelled and rejected ones",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Length of stay distribution",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day (YYYY-MM-DD), by default the first day of the current month",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day (YYYY-MM-DD), by default a month after from",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "day",
+                            "week",
+                            "month"
+                        ],
+                        "type": "string",
+                        "default": "month",
+                        "description": "Period length",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "One row per property and period",
+                        "name": "by_property",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only this property",
+                        "name": "property_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Output format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/report.StayLengthReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/performance": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the available and booked nights, occupancy (%), revenue, ADR and RevPAR per period. Every property with reservations is available every night, once per active unit if it has room types; nights are booked by confirmed and completed reservations, whose revenue excluding tourist tax is spread evenly over their nights.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Occupancy, ADR and RevPAR",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day (YYYY-MM-DD), by default the first day of the current month",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day (YYYY-MM-DD), by default a month after from",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "day",
+                            "week",
+                            "month"
+                        ],
+                        "type": "string",
+                        "default": "month",
+                        "description": "Period length",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "One row per property and period",
+                        "name": "by_property",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only this property",
+                        "name": "property_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Output format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/report.PerformanceReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/revenue-by-status": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the number and total price of the reservations checking in per period, by status",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Revenue by status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day (YYYY-MM-DD), by default the first day of the current month",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day (YYYY-MM-DD), by default a month after from",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "day",
+                            "week",
+                            "month"
+                        ],
+                        "type": "string",
+                        "default": "month",
+                        "description": "Period length",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "One row per property and period",
+                        "name": "by_property",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only this property",
+                        "name": "property_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Output format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/report.StatusReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reservation-groups": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Books several stays for a customer at once, possibly at different properties and on different dates,\nfor example apartments for a family or an event. Availability of all stays is checked in a single\ntransaction: either all are booked or none is. The group is priced as the sum of its stays and paid\nwith a single payment link; once paid, all stays are confirmed. Invalid guests are reported with\nfields prefixed by their stay (stays[0].guest_data...).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Book a group of stays",
+                "parameters": [
+                    {
+                        "description": "Stays of the group",
+                        "name": "group",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/booking.BookingGroupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/booking.BookingGroup"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservation-groups/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns a booking group with its stays, total price and payment link",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Get a booking group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Booking group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/booking.BookingGroup"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservation-groups/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Cancels the given stays of a booking group, or all its stays without a body, in a single transaction.\nStays already cancelled are skipped; completed stays can't be cancelled. The group total drops by\nthe cancelled stays.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Cancel a booking group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Booking group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Stays to cancel",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/booking.GroupCancelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/booking.BookingGroup"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns a list of all reservations for the authenticated organization",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Get all reservations",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/booking.ReservationResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new reservation with the provided details. Guests registered in guest_data.guests\nare validated against the required fields of their nationality; invalid guests are reported\nas {\"errors\": [{\"field\", \"message\"}]}. On a property with room types, room_type_id or unit_id\nis required and the stay is available while the units of the type are not all booked on any\nnight; a unit is assigned when the reservation is confirmed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Create a new reservation",
+                "parameters": [
+                    {
+                        "description": "Reservation details",
+                        "name": "reservation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/booking.ReservationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ReservationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/calendar": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the bookings and active holds whose stay overlaps from to to (YYYY-MM-DD, at most 366 days),\noptionally of one property. Holds have kind HOLD and the time they expire.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Get the reservation calendar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First date",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date after the last one",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Property ID",
+                        "name": "property_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/booking.CalendarEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Streams the reservations of the organization as CSV, NDJSON or XLSX, oldest first. The rows are\nfetched from a database cursor and written as they arrive, so exports of any size use constant\nmemory. price_elements is flattened into a column per value, named by its path (for example\nprice_elements.cleaning.amount). Guest data is redacted without the guestdata:decrypt permission.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Export reservations",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Output format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated columns, by default all: id, property_id, room_type_id, unit_id, group_id, customer_id, check_in_date, check_out_date, nights, status, kind, hold_expires_at, total_price, price_elements, payment_url, no_of_guests, guest_data, additional_requests, created_at, updated_at",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated statuses",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Property",
+                        "name": "property_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Customer",
+                        "name": "customer_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First check-in day (YYYY-MM-DD)",
+                        "name": "check_in_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last check-in day (YYYY-MM-DD)",
+                        "name": "check_in_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First booking day (YYYY-MM-DD)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last booking day (YYYY-MM-DD)",
+                        "name": "created_to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/holds": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Puts dates on hold for a prospective guest without a payment. The hold blocks availability like a\nreservation until expires_at, by default RESERVATION_HOLD_HOURS (48) from now, and is validated like a\ncreated reservation. It is then converted into a booking, released, or expires and frees its dates.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Put a stay on hold",
+                "parameters": [
+                    {
+                        "description": "Stay to hold",
+                        "name": "hold",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/booking.HoldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ReservationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/import": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Imports reservations from a CSV file, or the first sheet of an XLSX workbook, with a header row.\nWorkbooks are recognized by their content. The columns property_id, check_in_date,\ncheck_out_date, no_of_guests and total_price are required, with customer_id or customer_email\n(and customer_name to create a customer that doesn't exist yet); status, price_elements,\nguest_data, additional_requests (JSON objects), created_at, room_type_id and unit_id are optional. Dates are YYYY-MM-DD\nor RFC 3339, or dates typed into a workbook's cells. Every row is validated like a created reservation and against the other rows; when\nany row is invalid, nothing is imported and the per-row errors are returned with status 400.\nWith dry_run nothing is written. Rows are committed in batches; CREATED reservations get a\npayment unless skip_payment is set, other statuses are imported as they are.",
+                "consumes": [
+                    "multipart/form-data",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Import reservations from CSV or XLSX",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV or XLSX file, or the file as the request body",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only validate the file",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Don't initiate payments, for historical imports",
+                        "name": "skip_payment",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dry run",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ImportResult"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ImportResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/{id}": {
+            "get": {
+                "description": "Get reservation details by its integer ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Get reservation by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ReservationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update reservation details by integer ID. Guests are validated as on create.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Update a reservation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated reservation details",
+                        "name": "reservation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/booking.ReservationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ReservationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Delete a reservation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/reservations/{id}/cancel": {
+            "post": {
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Cancel a reservation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ReservationResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/{id}/convert": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Turns an active hold into a booking of its guest, which asks for the payment like a created\nreservation. Expired holds can't be converted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Convert a hold into a booking",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ReservationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/{id}/emails": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the delivery status of every email sent automatically for the reservation, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Get email deliveries of a reservation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/communication.Delivery"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/{id}/invoices": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the invoices and credit notes of the reservation in the order they were issued",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Get the invoices of a reservation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/invoice.Invoice"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Issues an invoice with the next number of the organization in the current fiscal year, built from the price elements and total price of the reservation. Amounts include VAT at the rate of the settings, unless a price element sets its own vat_rate; tourist tax is not subject to VAT. Confirmed and completed reservations are invoiced as paid. A reservation can only be invoiced again once its invoice is fully credited. Invoices can't be changed once issued.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Issue the invoice of a reservation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Buyer details and notes",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/invoice.IssueRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/invoice.Invoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Already invoiced, not invoiceable, a hold or settings not configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/{id}/invoices/{invoiceId}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns an invoice or credit note of the reservation, with its document as issued",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Get an invoice",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "invoiceId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/invoice.Invoice"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/{id}/invoices/{invoiceId}/credit-notes": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Credits an invoice for a cancellation or a refund with a credit note from the organization's own number series. Without an amount, everything not yet credited is credited; a partial amount is split between the VAT rates of the invoice.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Issue a credit note",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "invoiceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason and amount",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/invoice.CreditNoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/invoice.Invoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/{id}/invoices/{invoiceId}/pdf": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the PDF of an invoice or credit note, as rendered when it was issued",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Download an invoice as PDF",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "invoiceId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/{id}/portal-links": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the links issued for the reservation, newest first, without their tokens",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "guest-portal"
+                ],
+                "summary": "Get the guest portal links of a reservation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/portal.Link"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/portal.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/portal.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Issues a signed magic link to the guest portal of the reservation, valid until the day after check-out unless expires_at is given (at most 90 days). With a PIN, guests must also send it in the X-Guest-Pin header; the PIN is stored hashed. The token and URL are only returned once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "guest-portal"
+                ],
+                "summary": "Create a guest portal link",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Link options",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/portal.LinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/portal.IssuedLink"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/portal.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/portal.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/portal.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/{id}/portal-links/{linkId}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "guest-portal"
+                ],
+                "summary": "Revoke a guest portal link",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Link ID",
+                        "name": "linkId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/portal.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/{id}/release": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Cancels an active hold, which frees its dates",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Release a hold",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ReservationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Undoes the deletion of a reservation that has not been purged yet. The dates must still be available, unless the reservation was cancelled or rejected.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Restore a deleted reservation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ReservationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/{id}/scheduled-messages": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Get scheduled messages of a reservation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/scheduler.ScheduledMessage"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/{id}/status": {
+            "patch": {
+                "description": "Update the status of a reservation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Update reservation status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Reservation ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/booking.StatusUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ReservationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/booking.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/room-types/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the name, description and guest limit of a room type",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rooms"
+                ],
+                "summary": "Update a room type",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Room type ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Room type",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/room.RoomTypeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/room.RoomType"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes a room type that has no units or reservations",
+                "tags": [
+                    "rooms"
+                ],
+                "summary": "Delete a room type",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Room type ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/room-types/{id}/units": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rooms"
+                ],
+                "summary": "Get the units of a room type",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Room type ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/room.Unit"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Adds a unit to a room type. Active units count as inventory and are assigned to reservations of the type when they are confirmed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rooms"
+                ],
+                "summary": "Add a unit to a room type",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Room type ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Unit",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/room.UnitRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/room.Unit"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tourist-tax/properties": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tourist-tax"
+                ],
+                "summary": "Get the municipalities of the properties",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/touristtax.PropertyMunicipality"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tourist-tax/properties/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Reservations of the property are charged the tourist tax of the municipality, unless the property has a rule of its own",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tourist-tax"
+                ],
+                "summary": "Assign a property to a municipality",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Property ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Municipality",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/touristtax.PropertyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/touristtax.PropertyMunicipality"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "tourist-tax"
+                ],
+                "summary": "Remove the municipality of a property",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Property ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tourist-tax/reports/{month}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the tourist tax owed for the guest-nights of a month by municipality, from the tourist tax charged on reservations that were not cancelled, rejected or no-shows. Stays spanning two months are split by night. CSV has one row per stay. The municipalities' XML format is not supported yet.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "tourist-tax"
+                ],
+                "summary": "Get the monthly tourist tax report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Month (YYYY-MM)",
+                        "name": "month",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Report format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/touristtax.Report"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tourist-tax/rules": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the tourist tax rules of the organization by municipality, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tourist-tax"
+                ],
+                "summary": "Get the tourist tax rules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/touristtax.Rule"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates the tourist tax of a municipality, or of a single property when property_id is set. The rate is charged per guest and night; age bands reduce it for guests up to max_age years old on check-in. A property rule takes precedence over the rule of its municipality.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tourist-tax"
+                ],
+                "summary": "Create a tourist tax rule",
+                "parameters": [
+                    {
+                        "description": "Rule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/touristtax.RuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/touristtax.Rule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tourist-tax/rules/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces a tourist tax rule. Reservations already charged keep their tourist tax until they are updated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tourist-tax"
+                ],
+                "summary": "Update a tourist tax rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/touristtax.RuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/touristtax.Rule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "tourist-tax"
+                ],
+                "summary": "Delete a tourist tax rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/units/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Renames, activates or deactivates a unit. Inactive units are not counted as inventory and are not assigned; reservations already assigned keep their unit.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rooms"
+                ],
+                "summary": "Update a unit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Unit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Unit",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/room.UnitRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/room.Unit"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes a unit that was never assigned to a reservation; deactivate it otherwise",
+                "tags": [
+                    "rooms"
+                ],
+                "summary": "Delete a unit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Unit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/waitlist": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the waitlist entries of the organization in the order they are offered freed dates: by descending priority, then in the order they were added. Filtering by property includes the entries for any property.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "waitlist"
+                ],
+                "summary": "Get the waitlist",
+                "parameters": [
+                    {
+                        "enum": [
+                            "WAITING",
+                            "OFFERED",
+                            "BOOKED",
+                            "EXPIRED"
+                        ],
+                        "type": "string",
+                        "description": "Status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Property ID",
+                        "name": "property_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Customer ID",
+                        "name": "customer_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/waitlist.Entry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Puts a customer on the waitlist for a stay that is fully booked, at a property or at any property of the organization when property_id is omitted. When a cancellation, deletion or expired offer frees dates the stay fits in, the customer is emailed an offer that expires after WAITLIST_OFFER_HOURS; booking the stay fulfils the entry.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "waitlist"
+                ],
+                "summary": "Add a customer to the waitlist",
+                "parameters": [
+                    {
+                        "description": "Waitlist entry",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/waitlist.EntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/waitlist.Entry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/waitlist/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the details of an entry that wasn't booked and puts it back on the waitlist, withdrawing any offer it had. Expired entries are put back in line this way.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "waitlist"
+                ],
+                "summary": "Update a waitlist entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Waitlist entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Waitlist entry",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/waitlist.EntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/waitlist.Entry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Takes a customer off the waitlist",
+                "tags": [
+                    "waitlist"
+                ],
+                "summary": "Remove a waitlist entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Waitlist entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "apikey.APIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "last_used_ip": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "apikey.CreateAPIKeyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2027-01-01T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Channel manager"
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "reservations:read",
+                        "reservations:create"
+                    ]
+                }
+            }
+        },
+        "apikey.CreateAPIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "hfk_3f9a1c2e_Jc0n7Vb..."
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "last_used_ip": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "audit.Change": {
+            "type": "object",
+            "properties": {
+                "after": {},
+                "before": {}
+            }
+        },
+        "audit.Entry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "actor_id": {
+                    "type": "string",
+                    "example": "7d1c3f5e-5b8e-4a43-9d0b-2f6b1f0c9a11"
+                },
+                "actor_role": {
+                    "type": "string",
+                    "example": "manager"
+                },
+                "actor_type": {
+                    "type": "string",
+                    "enum": [
+                        "user",
+                        "api_key",
+                        "system",
+                        "guest"
+                    ],
+                    "example": "user"
+                },
+                "changes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/audit.Change"
+                    }
+                },
+                "client_ip": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "entity_id": {
+                    "type": "string",
+                    "example": "42"
+                },
+                "entity_type": {
+                    "type": "string",
+                    "example": "reservation"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "request_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "audit.EntryPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/audit.Entry"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "booking.BookingGroup": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-12-01T09:00:00Z"
+                },
+                "customer_id": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "organization_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "payment_url": {
+                    "type": "string"
+                },
+                "reservations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/booking.ReservationResponse"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "PAYMENT_REQUIRED"
+                },
+                "total_price": {
+                    "type": "number",
+                    "example": 1840
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-12-01T09:00:00Z"
+                }
+            }
+        },
+        "booking.BookingGroupRequest": {
+            "type": "object",
+            "required": [
+                "customer_id",
+                "stays"
+            ],
+            "properties": {
+                "customer_id": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "stays": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/booking.GroupStayRequest"
+                    }
+                }
+            }
+        },
+        "booking.CalendarEntry": {
+            "type": "object",
+            "properties": {
+                "check_in_date": {
+                    "type": "string",
+                    "example": "2024-12-20T15:00:00Z"
+                },
+                "check_out_date": {
+                    "type": "string",
+                    "example": "2024-12-25T11:00:00Z"
+                },
+                "customer_id": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "hold_expires_at": {
+                    "type": "string",
+                    "example": "2024-12-03T09:00:00Z"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "BOOKING",
+                        "HOLD"
+                    ],
+                    "example": "HOLD"
+                },
+                "property_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "reservation_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "room_type_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "status": {
+                    "type": "string",
+                    "example": "CONFIRMED"
+                },
+                "unit_id": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "booking.CustomerSummary": {
+            "type": "object",
+            "properties": {
+                "cancellations": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "customer_id": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "is_repeat_guest": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "last_stay": {
+                    "type": "string",
+                    "example": "2024-08-10T15:00:00Z"
+                },
+                "no_shows": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "total_nights": {
+                    "type": "integer",
+                    "example": 11
+                },
+                "total_revenue": {
+                    "type": "number",
+                    "example": 1450
+                },
+                "total_stays": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "upcoming_stays": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/booking.ReservationResponse"
+                    }
+                }
+            }
+        },
+        "booking.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Invalid request"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "The provided data is invalid"
+                }
+            }
+        },
+        "booking.GroupCancelRequest": {
+            "type": "object",
+            "properties": {
+                "reservation_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        48213
+                    ]
+                }
+            }
+        },
+        "booking.GroupStayRequest": {
+            "type": "object",
+            "required": [
+                "check_in_date",
+                "check_out_date",
+                "no_of_guests",
+                "property_id",
+                "total_price"
+            ],
+            "properties": {
+                "additional_requests": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "check_in_date": {
+                    "type": "string",
+                    "example": "2024-12-20T15:00:00Z"
+                },
+                "check_out_date": {
+                    "type": "string",
+                    "example": "2024-12-25T11:00:00Z"
+                },
+                "guest_data": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "no_of_guests": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 4
+                },
+                "price_elements": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "property_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "room_type_id": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 3
+                },
+                "total_price": {
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 920
+                },
+                "unit_id": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 12
+                }
+            }
+        },
+        "booking.HoldRequest": {
+            "type": "object",
+            "required": [
+                "check_in_date",
+                "check_out_date",
+                "customer_id",
+                "no_of_guests",
+                "organization_id",
+                "property_id",
+                "total_price"
+            ],
+            "properties": {
+                "additional_requests": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "check_in_date": {
+                    "type": "string",
+                    "example": "2024-12-20T15:00:00Z"
+                },
+                "check_out_date": {
+                    "type": "string",
+                    "example": "2024-12-25T11:00:00Z"
+                },
+                "customer_id": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2024-12-03T09:00:00Z"
+                },
+                "guest_data": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "no_of_guests": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 2
+                },
+                "organization_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "price_elements": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "property_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "room_type_id": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 3
+                },
+                "status": {
+                    "type": "string",
+                    "example": "CREATED"
+                },
+                "total_price": {
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 500
+                },
+                "unit_id": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 12
+                }
+            }
+        },
+        "booking.ImportResult": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "imported": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "invalid": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/booking.ImportRowResult"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "valid": {
+                    "type": "integer",
+                    "example": 119
+                }
+            }
+        },
+        "booking.ImportRowResult": {
+            "type": "object",
+            "properties": {
+                "customer_id": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "check_out_date must be after check_in_date"
+                    ]
+                },
+                "line": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "new_customer": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "payment_error": {
+                    "type": "string"
+                },
+                "payment_url": {
+                    "type": "string"
+                },
+                "reservation_id": {
+                    "type": "integer",
+                    "example": 48213
+                },
+                "status": {
+                    "type": "string",
+                    "example": "CONFIRMED"
+                },
+                "total_price": {
+                    "type": "number",
+                    "example": 500
+                }
+            }
+        },
+        "booking.ReservationRequest": {
+            "type": "object",
+            "required": [
+                "check_in_date",
+                "check_out_date",
+                "customer_id",
+                "no_of_guests",
+                "organization_id",
+                "property_id",
+                "total_price"
+            ],
+            "properties": {
+                "additional_requests": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "check_in_date": {
+                    "type": "string",
+                    "example": "2024-12-20T15:00:00Z"
+                },
+                "check_out_date": {
+                    "type": "string",
+                    "example": "2024-12-25T11:00:00Z"
+                },
+                "customer_id": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "guest_data": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "no_of_guests": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 2
+                },
+                "organization_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "price_elements": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "property_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "room_type_id": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 3
+                },
+                "status": {
+                    "type": "string",
+                    "example": "CREATED"
+                },
+                "total_price": {
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 500
+                },
+                "unit_id": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 12
+                }
+            }
+        },
+        "booking.ReservationResponse": {
+            "type": "object",
+            "properties": {
+                "additional_requests": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "check_in_date": {
+                    "type": "string",
+                    "example": "2024-12-20T15:00:00Z"
+                },
+                "check_out_date": {
+                    "type": "string",
+                    "example": "2024-12-25T11:00:00Z"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-12-01T09:00:00Z"
+                },
+                "customer_id": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "group_id": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "guest_data": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "hold_expires_at": {
+                    "type": "string",
+                    "example": "2024-12-03T09:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "BOOKING",
+                        "HOLD"
+                    ],
+                    "example": "BOOKING"
+                },
+                "no_of_guests": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "organization_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "price_elements": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "property_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "room_type_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "CREATED CONFIRMED PAYMENT_REQUIRED REJECTED CANCELLED COMPLETED NO_SHOW"
+                    ],
+                    "example": "CREATED"
+                },
+                "total_price": {
+                    "type": "number",
+                    "example": 500
+                },
+                "unit_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-12-01T09:00:00Z"
+                }
+            }
+        },
+        "booking.StatusUpdateRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "CREATED",
+                        "CONFIRMED",
+                        "PAYMENT_REQUIRED",
+                        "REJECTED",
+                        "CANCELLED",
+                        "COMPLETED",
+                        "NO_SHOW"
+                    ],
+                    "example": "CONFIRMED"
+                }
+            }
+        },
+        "communication.Delivery": {
+            "type": "object",
+            "properties": {
+                "api_key_id": {
+                    "type": "integer"
+                },
+                "attempts": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "client_ip": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "integer"
+                },
+                "email_type": {
+                    "type": "string",
+                    "example": "PAYMENT"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "offer": {
+                    "$ref": "#/definitions/communication.Offer"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "property_id": {
+                    "type": "integer"
+                },
+                "requested_by": {
+                    "type": "string"
+                },
+                "reservation_id": {
+                    "type": "integer"
+                },
+                "sent_at": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string",
+                    "enum": [
+                        "AUTOMATIC MANUAL"
+                    ],
+                    "example": "AUTOMATIC"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "PENDING SENDING RETRYING SENT FAILED"
+                    ],
+                    "example": "SENT"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "communication.Offer": {
+            "type": "object",
+            "properties": {
+                "check_in_date": {
+                    "type": "string"
+                },
+                "check_out_date": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "waitlist_entry_id": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "communication.SendEmailRequest": {
+            "type": "object",
+            "required": [
+                "reservation_id",
+                "type"
+            ],
+            "properties": {
+                "reservation_id": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "PAYMENT",
+                        "CONFIRMATION",
+                        "PRE_ARRIVAL",
+                        "CHECK_IN_INSTRUCTIONS",
+                        "REVIEW_REQUEST"
+                    ],
+                    "example": "PAYMENT"
+                }
+            }
+        },
+        "customer.Customer": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "organization_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "customer.CustomerPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/customer.Customer"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "offset": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "customer.MergeCustomersRequest": {
+            "type": "object",
+            "required": [
+                "duplicate_id",
+                "surviving_id"
+            ],
+            "properties": {
+                "duplicate_id": {
+                    "type": "integer",
+                    "example": 34
+                },
+                "surviving_id": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "customer.MergeCustomersResponse": {
+            "type": "object",
+            "properties": {
+                "customer": {
+                    "$ref": "#/definitions/customer.Customer"
+                },
+                "duplicate_deleted": {
+                    "description": "DuplicateDeleted is false when the duplicate could not be deleted yet;\nthe deletion is retried in the background.",
+                    "type": "boolean",
+                    "example": true
+                },
+                "merged_customer_id": {
+                    "type": "integer",
+                    "example": 34
+                },
+                "reservations_moved": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "guest.Address": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "example": "Ljubljana"
+                },
+                "country": {
+                    "type": "string",
+                    "example": "SI"
+                },
+                "postal_code": {
+                    "type": "string",
+                    "example": "1000"
+                },
+                "street": {
+                    "type": "string",
+                    "example": "Slovenska cesta 1"
+                }
+            }
+        },
+        "guest.Guest": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "$ref": "#/definitions/guest.Address"
+                },
+                "birth_date": {
+                    "type": "string",
+                    "example": "1990-04-21"
+                },
+                "document_number": {
+                    "type": "string",
+                    "example": "PB1234567"
+                },
+                "document_type": {
+                    "type": "string",
+                    "enum": [
+                        "PASSPORT ID_CARD DRIVING_LICENCE RESIDENCE_PERMIT OTHER"
+                    ],
+                    "example": "PASSPORT"
+                },
+                "first_name": {
+                    "type": "string",
+                    "example": "Ana"
+                },
+                "last_name": {
+                    "type": "string",
+                    "example": "Novak"
+                },
+                "nationality": {
+                    "type": "string",
+                    "example": "SI"
+                }
+            }
+        },
+        "hostflow_booking-service_internal_customer.UpdateCustomerRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "full_name"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "ana.novak@example.com"
+                },
+                "full_name": {
+                    "type": "string",
+                    "example": "Ana Novak"
+                }
+            }
+        },
+        "invoice.Buyer": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Slovenska cesta 1, 1000 Ljubljana"
+                },
+                "customer_id": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Ana Novak"
+                },
+                "tax_id": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "SI87654321"
+                }
+            }
+        },
+        "invoice.CreditNoteRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 50
+                },
+                "notes": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Early departure"
+                },
+                "reason": {
+                    "type": "string",
+                    "enum": [
+                        "cancellation",
+                        "refund"
+                    ],
+                    "example": "refund"
+                }
+            }
+        },
+        "invoice.CreditedRef": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 17
+                },
+                "issue_date": {
+                    "type": "string",
+                    "example": "2026-07-01"
+                },
+                "number": {
+                    "type": "string",
+                    "example": "INV-2026-000017"
+                }
+            }
+        },
+        "invoice.Document": {
+            "type": "object",
+            "properties": {
+                "buyer": {
+                    "$ref": "#/definitions/invoice.Buyer"
+                },
+                "credited_invoice": {
+                    "$ref": "#/definitions/invoice.CreditedRef"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "footer": {
+                    "type": "string"
+                },
+                "issue_date": {
+                    "type": "string",
+                    "example": "2026-07-01"
+                },
+                "issuer": {
+                    "$ref": "#/definitions/invoice.Issuer"
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "INVOICE"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/invoice.Line"
+                    }
+                },
+                "net_total": {
+                    "type": "number",
+                    "example": 456.62
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "string",
+                    "example": "INV-2026-000017"
+                },
+                "payment": {
+                    "$ref": "#/definitions/invoice.Payment"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "refund"
+                },
+                "reservation": {
+                    "$ref": "#/definitions/invoice.ReservationInfo"
+                },
+                "total": {
+                    "type": "number",
+                    "example": 500
+                },
+                "vat_breakdown": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/invoice.VatAmount"
+                    }
+                },
+                "vat_total": {
+                    "type": "number",
+                    "example": 43.38
+                }
+            }
+        },
+        "invoice.Invoice": {
+            "type": "object",
+            "properties": {
+                "credited_invoice_id": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "document": {
+                    "$ref": "#/definitions/invoice.Document"
+                },
+                "fiscal_year": {
+                    "type": "integer",
+                    "example": 2026
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "issued_at": {
+                    "type": "string"
+                },
+                "issued_by": {
+                    "type": "string",
+                    "example": "user:7d1c3f5e-5b8e-4a43-9d0b-2f6b1f0c9a11"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "INVOICE",
+                        "CREDIT_NOTE"
+                    ],
+                    "example": "INVOICE"
+                },
+                "net_total": {
+                    "type": "number",
+                    "example": 456.62
+                },
+                "number": {
+                    "type": "string",
+                    "example": "INV-2026-000017"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "reservation_id": {
+                    "type": "integer"
+                },
+                "sequence_number": {
+                    "type": "integer",
+                    "example": 17
+                },
+                "series": {
+                    "type": "string",
+                    "example": "INV"
+                },
+                "total": {
+                    "type": "number",
+                    "example": 500
+                },
+                "vat_total": {
+                    "type": "number",
+                    "example": 43.38
+                }
+            }
+        },
+        "invoice.IssueRequest": {
+            "type": "object",
+            "properties": {
+                "buyer": {
+                    "$ref": "#/definitions/invoice.Buyer"
+                },
+                "due_date": {
+                    "type": "string",
+                    "example": "2026-07-10"
+                },
+                "notes": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Booking reference 42"
+                }
+            }
+        },
+        "invoice.Issuer": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "Cesta svobode 1, 4260 Bled"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "info@example.com"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Apartmaji Bled d.o.o."
+                },
+                "tax_id": {
+                    "type": "string",
+                    "example": "SI12345678"
+                }
+            }
+        },
+        "invoice.Line": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "accommodation"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Accommodation"
+                },
+                "net": {
+                    "type": "number",
+                    "example": 431.51
+                },
+                "total": {
+                    "type": "number",
+                    "example": 472.5
+                },
+                "vat": {
+                    "type": "number",
+                    "example": 40.99
+                },
+                "vat_rate": {
+                    "type": "number",
+                    "example": 9.5
+                }
+            }
+        },
+        "invoice.Payment": {
+            "type": "object",
+            "properties": {
+                "amount_due": {
+                    "type": "number",
+                    "example": 0
+                },
+                "due_date": {
+                    "type": "string",
+                    "example": "2026-07-10"
+                },
+                "paid": {
+                    "type": "number",
+                    "example": 500
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "PAID",
+                        "DUE"
+                    ],
+                    "example": "PAID"
+                }
+            }
+        },
+        "invoice.ReservationInfo": {
+            "type": "object",
+            "properties": {
+                "check_in_date": {
+                    "type": "string",
+                    "example": "2026-07-10"
+                },
+                "check_out_date": {
+                    "type": "string",
+                    "example": "2026-07-14"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "nights": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "no_of_guests": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "property_id": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "invoice.Settings": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "footer": {
+                    "type": "string",
+                    "example": "Thank you for your stay"
+                },
+                "issuer_address": {
+                    "type": "string",
+                    "example": "Cesta svobode 1, 4260 Bled"
+                },
+                "issuer_email": {
+                    "type": "string",
+                    "example": "info@example.com"
+                },
+                "issuer_name": {
+                    "type": "string",
+                    "example": "Apartmaji Bled d.o.o."
+                },
+                "issuer_tax_id": {
+                    "type": "string",
+                    "example": "SI12345678"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "vat_rate": {
+                    "type": "number",
+                    "example": 9.5
+                }
+            }
+        },
+        "invoice.SettingsRequest": {
+            "type": "object",
+            "required": [
+                "issuer_address",
+                "issuer_name",
+                "issuer_tax_id"
+            ],
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "footer": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Thank you for your stay"
+                },
+                "issuer_address": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Cesta svobode 1, 4260 Bled"
+                },
+                "issuer_email": {
+                    "type": "string",
+                    "example": "info@example.com"
+                },
+                "issuer_name": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Apartmaji Bled d.o.o."
+                },
+                "issuer_tax_id": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "SI12345678"
+                },
+                "vat_rate": {
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 9.5
+                }
+            }
+        },
+        "invoice.VatAmount": {
+            "type": "object",
+            "properties": {
+                "net": {
+                    "type": "number",
+                    "example": 431.51
+                },
+                "rate": {
+                    "type": "number",
+                    "example": 9.5
+                },
+                "total": {
+                    "type": "number",
+                    "example": 472.5
+                },
+                "vat": {
+                    "type": "number",
+                    "example": 40.99
+                }
+            }
+        },
+        "portal.ArrivalRequest": {
+            "type": "object",
+            "properties": {
+                "arrival_time": {
+                    "type": "string",
+                    "example": "16:30"
+                },
+                "special_requests": {
+                    "type": "string",
+                    "maxLength": 2000,
+                    "example": "Baby cot, please"
+                }
+            }
+        },
+        "portal.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "invalid or expired portal link"
+                }
+            }
+        },
+        "portal.GuestsRequest": {
+            "type": "object",
+            "required": [
+                "guests"
+            ],
+            "properties": {
+                "guests": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/guest.Guest"
+                    }
+                }
+            }
+        },
+        "portal.HouseRulesRequest": {
+            "type": "object",
+            "required": [
+                "accepted"
+            ],
+            "properties": {
+                "accepted": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "version": {
+                    "description": "Version optionally identifies the accepted house rules",
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "2026-01"
+                }
+            }
+        },
+        "portal.IssuedLink": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string",
+                    "example": "user:7d1c3f5e-5b8e-4a43-9d0b-2f6b1f0c9a11"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "failed_pin_attempts": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "pin_protected": {
+                    "type": "boolean"
+                },
+                "reservation_id": {
+                    "type": "integer"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "42.1786788000.kq3V0dG9r..."
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://guest.hostflow.app/check-in?token=42.1786788000.kq3V0dG9r..."
+                }
+            }
+        },
+        "portal.Link": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string",
+                    "example": "user:7d1c3f5e-5b8e-4a43-9d0b-2f6b1f0c9a11"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "failed_pin_attempts": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "pin_protected": {
+                    "type": "boolean"
+                },
+                "reservation_id": {
+                    "type": "integer"
+                },
+                "revoked_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "portal.LinkRequest": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "ExpiresAt defaults to the day after check-out",
+                    "type": "string",
+                    "example": "2026-08-15T12:00:00Z"
+                },
+                "pin": {
+                    "description": "Pin optionally protects the link with 4 to 8 digits, which must be\nshared with the guest separately",
+                    "type": "string",
+                    "maxLength": 8,
+                    "minLength": 4,
+                    "example": "482913"
+                }
+            }
+        },
+        "portal.View": {
+            "type": "object",
+            "properties": {
+                "arrival_time": {
+                    "type": "string",
+                    "example": "16:30"
+                },
+                "can_edit": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "check_in_date": {
+                    "type": "string",
+                    "example": "2026-08-10T15:00:00Z"
+                },
+                "check_out_date": {
+                    "type": "string",
+                    "example": "2026-08-14T10:00:00Z"
+                },
+                "guests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/guest.Guest"
+                    }
+                },
+                "house_rules_accepted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "no_of_guests": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "payment_url": {
+                    "type": "string"
+                },
+                "property_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "special_requests": {
+                    "type": "string",
+                    "example": "Baby cot, please"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "CONFIRMED"
+                },
+                "total_price": {
+                    "type": "number",
+                    "example": 480
+                }
+            }
+        },
+        "privacy.EmailRecord": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email_type": {
+                    "type": "string",
+                    "example": "CONFIRMATION"
+                },
+                "reservation_id": {
+                    "type": "integer"
+                },
+                "sent_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "SENT"
+                }
+            }
+        },
+        "privacy.ErasureRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Request by e-mail of 2026-03-01"
+                }
+            }
+        },
+        "privacy.ExportBundle": {
+            "type": "object",
+            "properties": {
+                "customer_id": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "emails": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/privacy.EmailRecord"
+                    }
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "request_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "reservations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/privacy.ReservationRecord"
+                    }
+                }
+            }
+        },
+        "privacy.Request": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "integer"
+                },
+                "failure_reason": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Request by e-mail of 2026-03-01"
+                },
+                "requested_by": {
+                    "type": "string",
+                    "example": "user:7d1c3f5e-5b8e-4a43-9d0b-2f6b1f0c9a11"
+                },
+                "result": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "PENDING",
+                        "COMPLETED",
+                        "FAILED"
+                    ],
+                    "example": "COMPLETED"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "EXPORT",
+                        "ERASURE"
+                    ],
+                    "example": "ERASURE"
+                }
+            }
+        },
+        "privacy.RequestPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/privacy.Request"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "privacy.ReservationRecord": {
+            "type": "object",
+            "properties": {
+                "additional_requests": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "anonymized_at": {
+                    "type": "string"
+                },
+                "check_in_date": {
+                    "type": "string"
+                },
+                "check_out_date": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "guest_data": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "no_of_guests": {
+                    "type": "integer"
+                },
+                "price_elements": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "property_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total_price": {
+                    "type": "number"
+                }
+            }
+        },
+        "report.CancellationReport": {
+            "type": "object",
+            "properties": {
+                "by_property": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2026-07-01"
+                },
+                "group_by": {
+                    "type": "string",
+                    "example": "month"
+                },
+                "property_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/report.CancellationRow"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2026-07-31"
+                }
+            }
+        },
+        "report.CancellationRow": {
+            "type": "object",
+            "properties": {
+                "cancellation_rate": {
+                    "type": "number",
+                    "example": 15
+                },
+                "cancelled": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "period": {
+                    "type": "string",
+                    "example": "2026-07-01"
+                },
+                "property_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "reservations": {
+                    "type": "integer",
+                    "example": 20
+                }
+            }
+        },
+        "report.LeadTimeReport": {
+            "type": "object",
+            "properties": {
+                "by_property": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2026-07-01"
+                },
+                "group_by": {
+                    "type": "string",
+                    "example": "month"
+                },
+                "property_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/report.LeadTimeRow"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2026-07-31"
+                }
+            }
+        },
+        "report.LeadTimeRow": {
+            "type": "object",
+            "properties": {
+                "average_days": {
+                    "type": "number",
+                    "example": 34.5
+                },
+                "max_days": {
+                    "type": "integer",
+                    "example": 180
+                },
+                "median_days": {
+                    "type": "number",
+                    "example": 28
+                },
+                "min_days": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "period": {
+                    "type": "string",
+                    "example": "2026-07-01"
+                },
+                "property_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "reservations": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "report.PerformanceReport": {
+            "type": "object",
+            "properties": {
+                "by_property": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2026-07-01"
+                },
+                "group_by": {
+                    "type": "string",
+                    "example": "month"
+                },
+                "property_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/report.PerformanceRow"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2026-07-31"
+                }
+            }
+        },
+        "report.PerformanceRow": {
+            "type": "object",
+            "properties": {
+                "adr": {
+                    "type": "number",
+                    "example": 120
+                },
+                "available_nights": {
+                    "type": "integer",
+                    "example": 31
+                },
+                "booked_nights": {
+                    "type": "integer",
+                    "example": 24
+                },
+                "occupancy": {
+                    "type": "number",
+                    "example": 77.42
+                },
+                "period": {
+                    "type": "string",
+                    "example": "2026-07-01"
+                },
+                "property_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "revenue": {
+                    "type": "number",
+                    "example": 2880
+                },
+                "revpar": {
+                    "type": "number",
+                    "example": 92.9
+                }
+            }
+        },
+        "report.StatusReport": {
+            "type": "object",
+            "properties": {
+                "by_property": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2026-07-01"
+                },
+                "group_by": {
+                    "type": "string",
+                    "example": "month"
+                },
+                "property_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/report.StatusRow"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2026-07-31"
+                }
+            }
+        },
+        "report.StatusRow": {
+            "type": "object",
+            "properties": {
+                "period": {
+                    "type": "string",
+                    "example": "2026-07-01"
+                },
+                "property_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "reservations": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "revenue": {
+                    "type": "number",
+                    "example": 2880
+                },
+                "status": {
+                    "type": "string",
+                    "example": "CONFIRMED"
+                }
+            }
+        },
+        "report.StayLengthReport": {
+            "type": "object",
+            "properties": {
+                "by_property": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2026-07-01"
+                },
+                "group_by": {
+                    "type": "string",
+                    "example": "month"
+                },
+                "property_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/report.StayLengthRow"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2026-07-31"
+                }
+            }
+        },
+        "report.StayLengthRow": {
+            "type": "object",
+            "properties": {
+                "nights": {
+                    "type": "string",
+                    "example": "3"
+                },
+                "period": {
+                    "type": "string",
+                    "example": "2026-07-01"
+                },
+                "property_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "reservations": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "room.RoomType": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Queen bed, garden view"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "max_guests": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Double room"
+                },
+                "organization_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "property_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "units": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "room.RoomTypeRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 2000,
+                    "example": "Queen bed, garden view"
+                },
+                "max_guests": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 2
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Double room"
+                }
+            }
+        },
+        "room.Unit": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 21
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Room 4"
+                },
+                "organization_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "property_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "room_type_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "room.UnitRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Room 4"
+                }
+            }
+        },
+        "scheduler.Schedule": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "anchor": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "email_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "offset_days": {
+                    "type": "integer"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "property_id": {
+                    "type": "integer"
+                },
+                "send_hour": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "scheduler.ScheduleRequest": {
+            "type": "object",
+            "required": [
+                "anchor",
+                "email_type"
+            ],
+            "properties": {
+                "anchor": {
+                    "type": "string",
+                    "enum": [
+                        "CHECK_IN",
+                        "CHECK_OUT"
+                    ],
+                    "example": "CHECK_IN"
+                },
+                "email_type": {
+                    "type": "string",
+                    "enum": [
+                        "PRE_ARRIVAL",
+                        "CHECK_IN_INSTRUCTIONS",
+                        "REVIEW_REQUEST"
+                    ],
+                    "example": "PRE_ARRIVAL"
+                },
+                "offset_days": {
+                    "type": "integer",
+                    "maximum": 365,
+                    "minimum": -365,
+                    "example": -7
+                },
+                "property_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "send_hour": {
+                    "type": "integer",
+                    "maximum": 23,
+                    "minimum": 0,
+                    "example": 9
+                }
+            }
+        },
+        "scheduler.ScheduledMessage": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "reservation_id": {
+                    "type": "integer"
+                },
+                "schedule_id": {
+                    "type": "integer"
+                },
+                "send_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "PENDING QUEUED SKIPPED"
+                    ]
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "touristtax.AgeBand": {
+            "type": "object",
+            "properties": {
+                "max_age": {
+                    "type": "integer",
+                    "maximum": 120,
+                    "minimum": 0,
+                    "example": 6
+                },
+                "percent": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0,
+                    "example": 0
+                }
+            }
+        },
+        "touristtax.MunicipalityReport": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 356.82
+                },
+                "code": {
+                    "type": "string",
+                    "example": "061"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "exempt_guest_nights": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "guest_nights": {
+                    "type": "integer",
+                    "example": 124
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Ljubljana"
+                },
+                "reduced_guest_nights": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "stays": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/touristtax.StayReport"
+                    }
+                }
+            }
+        },
+        "touristtax.PropertyMunicipality": {
+            "type": "object",
+            "properties": {
+                "municipality_code": {
+                    "type": "string",
+                    "example": "061"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "property_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "touristtax.PropertyRequest": {
+            "type": "object",
+            "required": [
+                "municipality_code"
+            ],
+            "properties": {
+                "municipality_code": {
+                    "type": "string",
+                    "maxLength": 20,
+                    "example": "061"
+                }
+            }
+        },
+        "touristtax.Report": {
+            "type": "object",
+            "properties": {
+                "month": {
+                    "type": "string",
+                    "example": "2026-07"
+                },
+                "municipalities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/touristtax.MunicipalityReport"
+                    }
+                },
+                "organization_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "touristtax.Rule": {
+            "type": "object",
+            "properties": {
+                "age_bands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/touristtax.AgeBand"
                     }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "municipality_code": {
+                    "type": "string",
+                    "example": "061"
+                },
+                "municipality_name": {
+                    "type": "string",
+                    "example": "Ljubljana"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "property_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "rate": {
+                    "type": "number",
+                    "example": 3.13
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "valid_from": {
+                    "type": "string",
+                    "example": "2026-01-01T00:00:00Z"
+                },
+                "valid_to": {
+                    "type": "string"
                 }
             }
-        }
-    },
-    "definitions": {
-        "booking.ErrorResponse": {
+        },
+        "touristtax.RuleRequest": {
             "type": "object",
+            "required": [
+                "municipality_code",
+                "municipality_name",
+                "valid_from"
+            ],
             "properties": {
-                "error": {
+                "age_bands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/touristtax.AgeBand"
+                    }
+                },
+                "currency": {
                     "type": "string",
-                    "example": "Invalid request"
+                    "example": "EUR"
                 },
-                "message": {
+                "municipality_code": {
                     "type": "string",
-                    "example": "The provided data is invalid"
+                    "maxLength": 20,
+                    "example": "061"
+                },
+                "municipality_name": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Ljubljana"
+                },
+                "property_id": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 10
+                },
+                "rate": {
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 3.13
+                },
+                "valid_from": {
+                    "type": "string",
+                    "example": "2026-01-01"
+                },
+                "valid_to": {
+                    "type": "string",
+                    "example": "2026-12-31"
                 }
             }
         },
-        "booking.ReservationRequest": {
+        "touristtax.StayReport": {
             "type": "object",
-            "required": [
-                "check_in_date",
-                "check_out_date",
-                "customer_id",
-                "no_of_guests",
-                "organization_id",
-                "property_id",
-                "total_price"
-            ],
             "properties": {
-                "additional_requests": {
-                    "type": "object",
-                    "additionalProperties": true
+                "amount": {
+                    "type": "number",
+                    "example": 25.04
                 },
                 "check_in_date": {
                     "type": "string",
-                    "example": "2024-12-20T15:00:00Z"
+                    "example": "2026-07-10"
                 },
                 "check_out_date": {
                     "type": "string",
-                    "example": "2024-12-25T11:00:00Z"
+                    "example": "2026-07-14"
                 },
-                "customer_id": {
+                "exempt_guest_nights": {
                     "type": "integer",
-                    "example": 100
+                    "example": 0
                 },
-                "guest_data": {
-                    "type": "object",
-                    "additionalProperties": true
+                "guest_nights": {
+                    "type": "integer",
+                    "example": 8
                 },
-                "no_of_guests": {
+                "guests": {
                     "type": "integer",
-                    "minimum": 1,
                     "example": 2
                 },
-                "organization_id": {
+                "nights": {
                     "type": "integer",
-                    "example": 1
-                },
-                "price_elements": {
-                    "type": "object",
-                    "additionalProperties": true
+                    "example": 4
                 },
                 "property_id": {
                     "type": "integer",
                     "example": 10
                 },
-                "status": {
-                    "type": "string",
-                    "example": "CREATED"
-                },
-                "total_price": {
+                "rate": {
                     "type": "number",
-                    "minimum": 0,
-                    "example": 500
+                    "example": 3.13
+                },
+                "reduced_guest_nights": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "reservation_id": {
+                    "type": "integer",
+                    "example": 42
                 }
             }
         },
-        "booking.ReservationResponse": {
+        "waitlist.Entry": {
             "type": "object",
             "properties": {
-                "additional_requests": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
                 "check_in_date": {
                     "type": "string",
                     "example": "2024-12-20T15:00:00Z"
@@ -584,32 +7106,41 @@ const docTemplate = `{
                     "example": "2024-12-25T11:00:00Z"
                 },
                 "created_at": {
-                    "type": "string",
-                    "example": "2024-12-01T09:00:00Z"
+                    "type": "string"
                 },
                 "customer_id": {
                     "type": "integer",
                     "example": 100
                 },
-                "guest_data": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
                 "id": {
                     "type": "integer",
-                    "example": 1
+                    "example": 12
                 },
                 "no_of_guests": {
                     "type": "integer",
                     "example": 2
                 },
+                "notes": {
+                    "type": "string",
+                    "example": "Prefers a sea view"
+                },
+                "offer_expires_at": {
+                    "type": "string"
+                },
+                "offered_at": {
+                    "type": "string"
+                },
+                "offered_property_id": {
+                    "type": "integer",
+                    "example": 10
+                },
                 "organization_id": {
                     "type": "integer",
                     "example": 1
                 },
-                "price_elements": {
-                    "type": "object",
-                    "additionalProperties": true
+                "priority": {
+                    "type": "integer",
+                    "example": 0
                 },
                 "property_id": {
                     "type": "integer",
@@ -618,57 +7149,58 @@ const docTemplate = `{
                 "status": {
                     "type": "string",
                     "enum": [
-                        "CREATED CONFIRMED PAYMENT_REQUIRED REJECTED CANCELLED COMPLETED"
+                        "WAITING",
+                        "OFFERED",
+                        "BOOKED",
+                        "EXPIRED"
                     ],
-                    "example": "CREATED"
-                },
-                "total_price": {
-                    "type": "number",
-                    "example": 500
+                    "example": "WAITING"
                 },
                 "updated_at": {
-                    "type": "string",
-                    "example": "2024-12-01T09:00:00Z"
+                    "type": "string"
                 }
             }
         },
-        "booking.StatusUpdateRequest": {
+        "waitlist.EntryRequest": {
             "type": "object",
             "required": [
-                "status"
+                "check_in_date",
+                "check_out_date",
+                "customer_id",
+                "no_of_guests"
             ],
             "properties": {
-                "status": {
+                "check_in_date": {
                     "type": "string",
-                    "enum": [
-                        "CREATED",
-                        "CONFIRMED",
-                        "PAYMENT_REQUIRED",
-                        "REJECTED",
-                        "CANCELLED",
-                        "COMPLETED"
-                    ],
-                    "example": "CONFIRMED"
-                }
-            }
-        },
-        "customer.Customer": {
-            "type": "object",
-            "properties": {
-                "created_at": {
-                    "type": "string"
+                    "example": "2024-12-20T15:00:00Z"
                 },
-                "email": {
-                    "type": "string"
+                "check_out_date": {
+                    "type": "string",
+                    "example": "2024-12-25T11:00:00Z"
                 },
-                "full_name": {
-                    "type": "string"
+                "customer_id": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 100
                 },
-                "id": {
-                    "type": "integer"
+                "no_of_guests": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 2
                 },
-                "organization_id": {
-                    "type": "integer"
+                "notes": {
+                    "type": "string",
+                    "maxLength": 2000,
+                    "example": "Prefers a sea view"
+                },
+                "priority": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "property_id": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 10
                 }
             }
         }
@@ -678,6 +7210,12 @@ const docTemplate = `{
             "type": "apiKey",
             "name": "Authorization",
             "in": "header"
+        },
+        "GuestPortalToken": {
+            "description": "Guest portal link token, as \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     },
     "tags": [
@@ -688,6 +7226,46 @@ const docTemplate = `{
         {
             "description": "Customer-specific reservation operations",
             "name": "customers"
+        },
+        {
+            "description": "Guest emails and scheduled guest communications",
+            "name": "communication"
+        },
+        {
+            "description": "Organization API keys for integrations",
+            "name": "api-keys"
+        },
+        {
+            "description": "Append-only log of changes to reservations",
+            "name": "audit"
+        },
+        {
+            "description": "GDPR export and erasure of guest data",
+            "name": "privacy"
+        },
+        {
+            "description": "Magic links and the self-service portal for guests",
+            "name": "guest-portal"
+        },
+        {
+            "description": "Tourist tax rules and monthly municipal reports",
+            "name": "tourist-tax"
+        },
+        {
+            "description": "Invoices and credit notes of reservations",
+            "name": "invoices"
+        },
+        {
+            "description": "Occupancy, revenue and booking reports",
+            "name": "reports"
+        },
+        {
+            "description": "Room types and units of properties with several bookable units",
+            "name": "rooms"
+        },
+        {
+            "description": "Customers waiting for fully booked dates, offered them when they free up",
+            "name": "waitlist"
         }
     ]
 }`
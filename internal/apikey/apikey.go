@@ -0,0 +1,19 @@
+package apikey
+
+import (
+	"hostflow/booking-service/pkg/interfaces"
+
+	"go.uber.org/fx"
+)
+
+// ======== EXPORTS ========
+
+// Module exports the API key subsystem. The service is also provided as the
+// interfaces.APIKeyAuthenticator the auth middleware accepts keys with.
+var Module = fx.Options(
+	fx.Provide(NewRepository, NewService, NewController, SetRoutes),
+	fx.Provide(fx.Annotate(
+		func(service *Service) *Service { return service },
+		fx.As(new(interfaces.APIKeyAuthenticator)),
+	)),
+)
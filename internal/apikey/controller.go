@@ -0,0 +1,124 @@
+package apikey
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Controller handles HTTP requests for API keys
+type Controller struct {
+	service *Service
+}
+
+// NewController returns a Controller
+func NewController(service *Service) *Controller {
+	return &Controller{
+		service: service,
+	}
+}
+
+func (c *Controller) getOrgID(ctx *gin.Context) (int64, bool) {
+	val, exists := ctx.Get("organization_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return 0, false
+	}
+	return val.(int64), true
+}
+
+// GetAPIKeysHandler godoc
+// @Summary Get API keys
+// @Description Returns the API keys of the organization, without their secrets
+// @Tags api-keys
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} APIKey
+// @Failure 500 {object} map[string]string
+// @Router /api-keys [get]
+func (c *Controller) GetAPIKeysHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	keys, err := c.service.GetAPIKeys(orgID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, keys)
+}
+
+// CreateAPIKeyHandler godoc
+// @Summary Create an API key
+// @Description Issues a key for integrations, presented as "Authorization: ApiKey <key>". The key is only returned in this response.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param key body CreateAPIKeyRequest true "API key"
+// @Success 201 {object} CreateAPIKeyResponse
+// @Failure 400 {object} map[string]string
+// @Router /api-keys [post]
+func (c *Controller) CreateAPIKeyHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	var req CreateAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := c.service.CreateAPIKey(orgID, ctx.GetString("user_id"), &req)
+	if errors.Is(err, ErrInvalidScope) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, created)
+}
+
+// RevokeAPIKeyHandler godoc
+// @Summary Revoke an API key
+// @Description Requests made with the key are rejected from now on
+// @Tags api-keys
+// @Security ApiKeyAuth
+// @Param id path int true "API key ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /api-keys/{id} [delete]
+func (c *Controller) RevokeAPIKeyHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid API key id"})
+		return
+	}
+
+	revoked, err := c.service.RevokeAPIKey(id, orgID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !revoked {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
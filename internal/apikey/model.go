@@ -0,0 +1,34 @@
+package apikey
+
+import "time"
+
+// APIKey is an organization-scoped key used by integrations. The secret
+// itself is never stored or returned after creation.
+type APIKey struct {
+	ID             int64      `json:"id" db:"id"`
+	OrganizationID int64      `json:"organization_id" db:"organization_id"`
+	Name           string     `json:"name" db:"name"`
+	Prefix         string     `json:"prefix" db:"prefix"`
+	KeyHash        string     `json:"-" db:"key_hash"`
+	Scopes         []string   `json:"scopes" db:"scopes"`
+	CreatedBy      *string    `json:"created_by" db:"created_by"`
+	ExpiresAt      *time.Time `json:"expires_at" db:"expires_at"`
+	LastUsedAt     *time.Time `json:"last_used_at" db:"last_used_at"`
+	LastUsedIP     *string    `json:"last_used_ip" db:"last_used_ip"`
+	RevokedAt      *time.Time `json:"revoked_at" db:"revoked_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
+
+// CreateAPIKeyRequest represents the API key creation request
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required,max=100" example:"Channel manager"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,required" example:"reservations:read,reservations:create"`
+	ExpiresAt *time.Time `json:"expires_at" example:"2027-01-01T00:00:00Z"`
+}
+
+// CreateAPIKeyResponse is returned once, when the key is created. The key
+// cannot be retrieved again.
+type CreateAPIKeyResponse struct {
+	APIKey
+	Key string `json:"key" example:"hfk_3f9a1c2e_Jc0n7Vb..."`
+}
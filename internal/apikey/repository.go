@@ -0,0 +1,132 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// apiKeyColumns are the columns scanned into APIKey
+const apiKeyColumns = `
+    id, organization_id, name, prefix, key_hash, scopes, created_by, expires_at,
+    last_used_at, last_used_ip, revoked_at, created_at
+`
+
+// Repository persists API keys
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+// NewRepository returns a Repository
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+// CreateAPIKey stores a new key
+func (r *Repository) CreateAPIKey(key *APIKey) (*APIKey, error) {
+	query := `
+        INSERT INTO api_key (organization_id, name, prefix, key_hash, scopes, created_by, expires_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING ` + apiKeyColumns
+
+	rows, err := r.db.Query(context.Background(), query,
+		key.OrganizationID,
+		key.Name,
+		key.Prefix,
+		key.KeyHash,
+		key.Scopes,
+		key.CreatedBy,
+		key.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	created, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[APIKey])
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// GetAPIKeys returns every key of an organization, revoked ones included
+func (r *Repository) GetAPIKeys(organizationID int64) ([]APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + `
+        FROM api_key
+        WHERE organization_id = $1
+        ORDER BY created_at DESC
+    `
+
+	rows, err := r.db.Query(context.Background(), query, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[APIKey])
+}
+
+// GetActiveAPIKeyByPrefix returns the key with the given prefix, if it is
+// neither revoked nor expired
+func (r *Repository) GetActiveAPIKeyByPrefix(prefix string) (*APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + `
+        FROM api_key
+        WHERE prefix = $1
+          AND revoked_at IS NULL
+          AND (expires_at IS NULL OR expires_at > NOW())
+    `
+
+	rows, err := r.db.Query(context.Background(), query, prefix)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	key, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[APIKey])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// RevokeAPIKey revokes a key of the organization. It returns false if the
+// key does not exist or was already revoked.
+func (r *Repository) RevokeAPIKey(id, organizationID int64) (bool, error) {
+	query := `
+        UPDATE api_key
+        SET revoked_at = NOW()
+        WHERE id = $1
+          AND organization_id = $2
+          AND revoked_at IS NULL
+    `
+
+	tag, err := r.db.Exec(context.Background(), query, id, organizationID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// TouchAPIKey records the use of a key. Uses within a minute of the last
+// recorded one are not written, so busy integrations don't cause a write
+// per request.
+func (r *Repository) TouchAPIKey(id int64, clientIP string) error {
+	query := `
+        UPDATE api_key
+        SET last_used_at = NOW(),
+            last_used_ip = $2
+        WHERE id = $1
+          AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')
+    `
+
+	_, err := r.db.Exec(context.Background(), query, id, clientIP)
+	return err
+}
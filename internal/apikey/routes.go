@@ -0,0 +1,46 @@
+package apikey
+
+import (
+	"hostflow/booking-service/internal/middlewares"
+	"hostflow/booking-service/pkg/lib"
+)
+
+// Routes struct
+type Routes struct {
+	logger              lib.Logger
+	router              *lib.Router
+	controller          *Controller
+	authMiddleware      middlewares.AuthMiddleware
+	rateLimitMiddleware middlewares.RateLimitMiddleware
+}
+
+// SetRoutes returns a Routes struct
+func SetRoutes(
+	logger lib.Logger,
+	router *lib.Router,
+	controller *Controller,
+	authMiddleware middlewares.AuthMiddleware,
+	rateLimitMiddleware middlewares.RateLimitMiddleware,
+) Routes {
+	return Routes{
+		logger:              logger,
+		router:              router,
+		controller:          controller,
+		authMiddleware:      authMiddleware,
+		rateLimitMiddleware: rateLimitMiddleware,
+	}
+}
+
+// Setup registers the API key routes. API keys can't manage API keys, as
+// no scope grants apikeys:manage.
+func (route Routes) Setup() {
+	route.logger.Info("Setting up [API KEY] routes.")
+
+	keys := route.router.Group("/api-keys")
+	keys.Use(route.authMiddleware.Handler(), middlewares.RequirePermission(middlewares.APIKeysManage))
+	{
+		keys.GET("", route.rateLimitMiddleware.Limit(middlewares.BudgetDefault), route.controller.GetAPIKeysHandler)
+		keys.POST("", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), route.controller.CreateAPIKeyHandler)
+		keys.DELETE("/:id", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), route.controller.RevokeAPIKeyHandler)
+	}
+}
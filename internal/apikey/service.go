@@ -0,0 +1,212 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"hostflow/booking-service/internal/middlewares"
+	"hostflow/booking-service/pkg/common"
+	"hostflow/booking-service/pkg/interfaces"
+	"hostflow/booking-service/pkg/lib"
+)
+
+// keyPrefix marks the keys issued by this service
+const keyPrefix = "hfk"
+
+// verifiedTTL is how long a successfully verified key is trusted without
+// hashing it again. argon2id is deliberately expensive, so verifying it on
+// every request of a busy integration is not an option. A key revoked on
+// the other replica keeps working there for at most this long.
+const verifiedTTL = time.Minute
+
+// ErrInvalidScope is returned when a key is requested with an unknown scope
+var ErrInvalidScope = errors.New("invalid scope")
+
+// verifiedKey is a cached successful verification
+type verifiedKey struct {
+	identity  interfaces.APIKeyIdentity
+	expiresAt time.Time
+}
+
+// Service issues and verifies API keys
+type Service struct {
+	repo   *Repository
+	logger lib.Logger
+
+	mu       sync.Mutex
+	verified map[[32]byte]verifiedKey
+}
+
+// NewService returns a Service
+func NewService(repo *Repository, logger lib.Logger) *Service {
+	return &Service{
+		repo:     repo,
+		logger:   logger,
+		verified: make(map[[32]byte]verifiedKey),
+	}
+}
+
+// CreateAPIKey issues a new key for the organization. The returned response
+// is the only place the plain key ever appears.
+func (s *Service) CreateAPIKey(organizationID int64, createdBy string, req *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error) {
+	if err := validateScopes(req.Scopes); err != nil {
+		return nil, err
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("expires_at must be in the future")
+	}
+
+	prefix, secret, err := generateKey()
+	if err != nil {
+		return nil, err
+	}
+	hash, err := common.Hasher.Hash(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &APIKey{
+		OrganizationID: organizationID,
+		Name:           req.Name,
+		Prefix:         prefix,
+		KeyHash:        hash,
+		Scopes:         req.Scopes,
+		ExpiresAt:      req.ExpiresAt,
+	}
+	if createdBy != "" {
+		key.CreatedBy = &createdBy
+	}
+
+	created, err := s.repo.CreateAPIKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreateAPIKeyResponse{
+		APIKey: *created,
+		Key:    formatKey(prefix, secret),
+	}, nil
+}
+
+// GetAPIKeys returns the keys of the organization
+func (s *Service) GetAPIKeys(organizationID int64) ([]APIKey, error) {
+	return s.repo.GetAPIKeys(organizationID)
+}
+
+// RevokeAPIKey revokes a key of the organization
+func (s *Service) RevokeAPIKey(id, organizationID int64) (bool, error) {
+	revoked, err := s.repo.RevokeAPIKey(id, organizationID)
+	if err != nil || !revoked {
+		return revoked, err
+	}
+
+	// The cache is keyed by the secret, which we don't have here.
+	s.mu.Lock()
+	s.verified = make(map[[32]byte]verifiedKey)
+	s.mu.Unlock()
+
+	return true, nil
+}
+
+// AuthenticateAPIKey implements interfaces.APIKeyAuthenticator
+func (s *Service) AuthenticateAPIKey(key, clientIP string) (*interfaces.APIKeyIdentity, error) {
+	prefix, secret, ok := parseKey(key)
+	if !ok {
+		return nil, interfaces.InvalidAPIKeyException
+	}
+
+	digest := sha256.Sum256([]byte(key))
+	now := time.Now()
+
+	s.mu.Lock()
+	cached, found := s.verified[digest]
+	s.mu.Unlock()
+	if found && now.Before(cached.expiresAt) {
+		identity := cached.identity
+		return &identity, nil
+	}
+
+	stored, err := s.repo.GetActiveAPIKeyByPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil {
+		return nil, interfaces.InvalidAPIKeyException
+	}
+
+	matches, err := common.Hasher.Compare(secret, stored.KeyHash)
+	if err != nil {
+		return nil, err
+	}
+	if !matches {
+		return nil, interfaces.InvalidAPIKeyException
+	}
+
+	if err := s.repo.TouchAPIKey(stored.ID, clientIP); err != nil {
+		s.logger.Error("Failed to record use of API key ", stored.ID, ": ", err)
+	}
+
+	identity := interfaces.APIKeyIdentity{
+		ID:             stored.ID,
+		OrganizationID: stored.OrganizationID,
+		Name:           stored.Name,
+		Scopes:         stored.Scopes,
+	}
+
+	expiresAt := now.Add(verifiedTTL)
+	if stored.ExpiresAt != nil && stored.ExpiresAt.Before(expiresAt) {
+		expiresAt = *stored.ExpiresAt
+	}
+	s.mu.Lock()
+	s.verified[digest] = verifiedKey{identity: identity, expiresAt: expiresAt}
+	s.mu.Unlock()
+
+	return &identity, nil
+}
+
+// ======== PRIVATE METHODS ========
+
+// generateKey returns a random public prefix and secret
+func generateKey() (prefix, secret string, err error) {
+	prefixBytes := make([]byte, 4)
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return "", "", err
+	}
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(prefixBytes), base64.RawURLEncoding.EncodeToString(secretBytes), nil
+}
+
+// formatKey builds the key handed to the client: hfk_<prefix>_<secret>
+func formatKey(prefix, secret string) string {
+	return fmt.Sprintf("%s_%s_%s", keyPrefix, prefix, secret)
+}
+
+// parseKey splits a key into its prefix and secret. The secret may itself
+// contain underscores.
+func parseKey(key string) (prefix, secret string, ok bool) {
+	parts := strings.SplitN(key, "_", 3)
+	if len(parts) != 3 || parts[0] != keyPrefix || parts[1] == "" || parts[2] == "" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+// validateScopes checks that every scope can be granted to an API key
+func validateScopes(scopes []string) error {
+	for _, scope := range scopes {
+		if !middlewares.IsScopePermission(middlewares.Permission(scope)) {
+			return fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package apikey
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateAndParseKey(t *testing.T) {
+	prefix, secret, err := generateKey()
+	require.NoError(t, err)
+
+	key := formatKey(prefix, secret)
+	assert.True(t, strings.HasPrefix(key, "hfk_"+prefix+"_"))
+
+	parsedPrefix, parsedSecret, ok := parseKey(key)
+	assert.True(t, ok)
+	assert.Equal(t, prefix, parsedPrefix)
+	assert.Equal(t, secret, parsedSecret)
+}
+
+func TestParseKey_SecretWithUnderscores(t *testing.T) {
+	prefix, secret, ok := parseKey("hfk_0a1b2c3d_ab_cd_ef")
+
+	assert.True(t, ok)
+	assert.Equal(t, "0a1b2c3d", prefix)
+	assert.Equal(t, "ab_cd_ef", secret)
+}
+
+func TestParseKey_Invalid(t *testing.T) {
+	for _, key := range []string{"", "hfk", "hfk_prefix", "hfk__secret", "sk_0a1b2c3d_secret", "eyJhbGciOiJFUzI1NiJ9"} {
+		_, _, ok := parseKey(key)
+		assert.False(t, ok, key)
+	}
+}
+
+func TestValidateScopes(t *testing.T) {
+	assert.NoError(t, validateScopes([]string{"reservations:read", "communication:send"}))
+
+	err := validateScopes([]string{"reservations:read", "apikeys:manage"})
+	assert.True(t, errors.Is(err, ErrInvalidScope))
+
+	err = validateScopes([]string{"everything"})
+	assert.True(t, errors.Is(err, ErrInvalidScope))
+}
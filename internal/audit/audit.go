@@ -0,0 +1,12 @@
+package audit
+
+import (
+	"go.uber.org/fx"
+)
+
+// ======== EXPORTS ========
+
+// Module exports the audit log
+var Module = fx.Options(
+	fx.Provide(NewRepository, NewController, SetRoutes),
+)
@@ -0,0 +1,86 @@
+package audit
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultPageSize and maxPageSize bound the entries returned per request
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// entityTypes are the entities that can be queried
+var entityTypes = map[string]bool{
+	EntityReservation:    true,
+	EntityPrivacyRequest: true,
+}
+
+// Controller handles HTTP requests for the audit log
+type Controller struct {
+	repo *Repository
+}
+
+// NewController returns a Controller
+func NewController(repo *Repository) *Controller {
+	return &Controller{
+		repo: repo,
+	}
+}
+
+// GetEntriesHandler godoc
+// @Summary Get the audit log of an entity
+// @Description Returns who changed an entity, when, from which request and what changed, newest first
+// @Tags audit
+// @Produce json
+// @Security ApiKeyAuth
+// @Param entity query string true "Entity type" Enums(reservation, privacy_request)
+// @Param id query string true "Entity ID"
+// @Param limit query int false "Page size (max 200)" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} EntryPage
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /audit [get]
+func (c *Controller) GetEntriesHandler(ctx *gin.Context) {
+	val, exists := ctx.Get("organization_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+	orgID := val.(int64)
+
+	entityType := ctx.Query("entity")
+	if !entityTypes[entityType] {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid entity"})
+		return
+	}
+
+	entityID := ctx.Query("id")
+	if entityID == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Missing id"})
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", strconv.Itoa(defaultPageSize)))
+	if err != nil || limit < 1 || limit > maxPageSize {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+	offset, err := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+		return
+	}
+
+	page, err := c.repo.GetEntries(orgID, entityType, entityID, limit, offset)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, page)
+}
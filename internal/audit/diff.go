@@ -0,0 +1,109 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// ignoredFields are not recorded, as they change on every write
+var ignoredFields = map[string]bool{
+	"updated_at": true,
+}
+
+// Diff returns the fields that differ between two values, keyed by their
+// JSON name. Nested objects, such as the JSONB columns of a reservation, are
+// compared field by field and reported with dotted paths
+// (e.g. "guest_data.address.city"); arrays are compared as a whole. Either
+// value may be nil, for creations and deletions.
+func Diff(before, after interface{}) (map[string]Change, error) {
+	beforeFields, err := toFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := toFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]Change)
+	diffObjects("", beforeFields, afterFields, changes)
+	return changes, nil
+}
+
+// toFields converts a value to its JSON object representation
+func toFields(value interface{}) (map[string]interface{}, error) {
+	if value == nil || (reflect.ValueOf(value).Kind() == reflect.Pointer && reflect.ValueOf(value).IsNil()) {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// diffObjects records the differences between two JSON objects under prefix
+func diffObjects(prefix string, before, after map[string]interface{}, changes map[string]Change) {
+	keys := make(map[string]bool, len(before)+len(after))
+	for key := range before {
+		keys[key] = true
+	}
+	for key := range after {
+		keys[key] = true
+	}
+
+	for key := range keys {
+		if prefix == "" && ignoredFields[key] {
+			continue
+		}
+
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		beforeValue, afterValue := before[key], after[key]
+		beforeObject, beforeIsObject := beforeValue.(map[string]interface{})
+		afterObject, afterIsObject := afterValue.(map[string]interface{})
+
+		switch {
+		case beforeIsObject && afterIsObject:
+			diffObjects(path, beforeObject, afterObject, changes)
+		case beforeIsObject && afterValue == nil:
+			diffObjects(path, beforeObject, nil, changes)
+		case afterIsObject && beforeValue == nil:
+			diffObjects(path, nil, afterObject, changes)
+		case !reflect.DeepEqual(beforeValue, afterValue):
+			changes[path] = Change{Before: beforeValue, After: afterValue}
+		}
+	}
+}
+
+// Redacted replaces personal data in redacted changes
+const Redacted = "[redacted]"
+
+// Redact replaces the values of the changes to the given fields, and to any
+// path inside them, with Redacted. The changed paths are kept.
+func Redact(changes map[string]Change, fields ...string) {
+	for path, change := range changes {
+		for _, field := range fields {
+			if path == field || strings.HasPrefix(path, field+".") {
+				if change.Before != nil {
+					change.Before = Redacted
+				}
+				if change.After != nil {
+					change.After = Redacted
+				}
+				changes[path] = change
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type record struct {
+	ID        int                    `json:"id"`
+	Status    string                 `json:"status"`
+	GuestData map[string]interface{} `json:"guest_data"`
+	Tags      []string               `json:"tags"`
+	UpdatedAt string                 `json:"updated_at"`
+}
+
+func TestDiffReportsChangedFieldsOnly(t *testing.T) {
+	before := &record{ID: 1, Status: "CREATED", UpdatedAt: "a"}
+	after := &record{ID: 1, Status: "CONFIRMED", UpdatedAt: "b"}
+
+	changes, err := Diff(before, after)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]Change{
+		"status": {Before: "CREATED", After: "CONFIRMED"},
+	}, changes)
+}
+
+func TestDiffComparesJSONFieldsByPath(t *testing.T) {
+	before := &record{
+		ID: 1,
+		GuestData: map[string]interface{}{
+			"name":    "Ana",
+			"address": map[string]interface{}{"city": "Ljubljana", "zip": "1000"},
+		},
+	}
+	after := &record{
+		ID: 1,
+		GuestData: map[string]interface{}{
+			"name":    "Ana",
+			"address": map[string]interface{}{"city": "Maribor", "zip": "1000"},
+			"phone":   "+386 40 000 000",
+		},
+	}
+
+	changes, err := Diff(before, after)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]Change{
+		"guest_data.address.city": {Before: "Ljubljana", After: "Maribor"},
+		"guest_data.phone":        {Before: nil, After: "+386 40 000 000"},
+	}, changes)
+}
+
+func TestDiffComparesArraysAsAWhole(t *testing.T) {
+	changes, err := Diff(&record{Tags: []string{"a"}}, &record{Tags: []string{"a", "b"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]Change{
+		"tags": {Before: []interface{}{"a"}, After: []interface{}{"a", "b"}},
+	}, changes)
+}
+
+func TestDiffOfCreationAndDeletion(t *testing.T) {
+	var missing *record
+
+	created, err := Diff(missing, &record{ID: 7, Status: "CREATED"})
+	require.NoError(t, err)
+	assert.Equal(t, Change{Before: nil, After: float64(7)}, created["id"])
+	assert.Equal(t, Change{Before: nil, After: "CREATED"}, created["status"])
+
+	deleted, err := Diff(&record{ID: 7, GuestData: map[string]interface{}{"name": "Ana"}}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, Change{Before: "Ana", After: nil}, deleted["guest_data.name"])
+	assert.NotContains(t, deleted, "updated_at")
+}
+
+func TestNewEntryOmitsEmptyActorFields(t *testing.T) {
+	entry, err := NewEntry(SystemActor("payments"), 3, "payment_confirmed", EntityReservation, "42",
+		&record{Status: "PAYMENT_REQUIRED"}, &record{Status: "CONFIRMED"})
+	require.NoError(t, err)
+
+	assert.Equal(t, ActorSystem, entry.ActorType)
+	assert.Equal(t, "payments", entry.ActorID)
+	assert.Nil(t, entry.ActorRole)
+	assert.Nil(t, entry.RequestID)
+	assert.Equal(t, "42", entry.EntityID)
+	assert.Len(t, entry.Changes, 1)
+}
+
+func TestRedactKeepsPathsAndHidesValues(t *testing.T) {
+	changes := map[string]Change{
+		"status":                  {Before: "CREATED", After: "CONFIRMED"},
+		"guest_data.name":         {Before: "Ana", After: nil},
+		"guest_data.address.city": {Before: "Ljubljana", After: "Maribor"},
+		"guest_data_version":      {Before: 1.0, After: 2.0},
+	}
+
+	Redact(changes, "guest_data")
+
+	assert.Equal(t, map[string]Change{
+		"status":                  {Before: "CREATED", After: "CONFIRMED"},
+		"guest_data.name":         {Before: Redacted, After: nil},
+		"guest_data.address.city": {Before: Redacted, After: Redacted},
+		"guest_data_version":      {Before: 1.0, After: 2.0},
+	}, changes)
+}
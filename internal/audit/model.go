@@ -0,0 +1,107 @@
+package audit
+
+import (
+	"strconv"
+	"time"
+
+	"hostflow/booking-service/internal/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Actor types
+const (
+	ActorUser   = "user"
+	ActorAPIKey = "api_key"
+	ActorSystem = "system"
+	ActorGuest  = "guest"
+)
+
+// Actions
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Entity types
+const (
+	EntityReservation    = "reservation"
+	EntityPrivacyRequest = "privacy_request"
+)
+
+// Actor is who performed an operation, and from which request
+type Actor struct {
+	Type      string
+	ID        string
+	Role      string
+	RequestID string
+	ClientIP  string
+}
+
+// Change is the value of a field before and after an operation. A nil
+// value means the field did not exist.
+type Change struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Entry is a single audit log record
+type Entry struct {
+	ID             int64             `json:"id" db:"id"`
+	OrganizationID int64             `json:"organization_id" db:"organization_id"`
+	ActorType      string            `json:"actor_type" db:"actor_type" example:"user" enums:"user,api_key,system,guest"`
+	ActorID        string            `json:"actor_id" db:"actor_id" example:"7d1c3f5e-5b8e-4a43-9d0b-2f6b1f0c9a11"`
+	ActorRole      *string           `json:"actor_role,omitempty" db:"actor_role" example:"manager"`
+	Action         string            `json:"action" db:"action" example:"update"`
+	EntityType     string            `json:"entity_type" db:"entity_type" example:"reservation"`
+	EntityID       string            `json:"entity_id" db:"entity_id" example:"42"`
+	Changes        map[string]Change `json:"changes" db:"changes"`
+	RequestID      *string           `json:"request_id,omitempty" db:"request_id"`
+	ClientIP       *string           `json:"client_ip,omitempty" db:"client_ip"`
+	CreatedAt      time.Time         `json:"created_at" db:"created_at"`
+}
+
+// EntryPage is a page of audit entries
+type EntryPage struct {
+	Items  []Entry `json:"items"`
+	Total  int64   `json:"total"`
+	Limit  int     `json:"limit"`
+	Offset int     `json:"offset"`
+}
+
+// ActorFromContext returns the authenticated caller of the request
+func ActorFromContext(ctx *gin.Context) Actor {
+	actor := Actor{
+		RequestID: ctx.GetString(middlewares.RequestIDKey),
+		ClientIP:  ctx.ClientIP(),
+	}
+
+	principal, _ := middlewares.GetPrincipal(ctx)
+	if principal.APIKeyID != 0 {
+		actor.Type = ActorAPIKey
+		actor.ID = strconv.FormatInt(principal.APIKeyID, 10)
+	} else {
+		actor.Type = ActorUser
+		actor.ID = principal.UserID
+		actor.Role = string(principal.Role)
+	}
+
+	return actor
+}
+
+// GuestActor returns the actor for a guest using a guest portal link
+func GuestActor(ctx *gin.Context, linkID int64) Actor {
+	return Actor{
+		Type:      ActorGuest,
+		ID:        "portal_link:" + strconv.FormatInt(linkID, 10),
+		RequestID: ctx.GetString(middlewares.RequestIDKey),
+		ClientIP:  ctx.ClientIP(),
+	}
+}
+
+// SystemActor returns the actor for operations triggered by the service
+// itself or by another system, e.g. payment events
+func SystemActor(name string) Actor {
+	return Actor{Type: ActorSystem, ID: name}
+}
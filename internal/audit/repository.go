@@ -0,0 +1,178 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// entryColumns are the columns scanned into Entry
+const entryColumns = `
+    id, organization_id, actor_type, actor_id, actor_role, action,
+    entity_type, entity_id, changes, request_id, client_ip, created_at
+`
+
+// Execer is satisfied by a pool, a connection and a transaction. Entries are
+// recorded with the transaction of the change they describe, so that a
+// change is never committed without its entry.
+type Execer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// Repository reads and appends audit entries
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+// NewRepository returns a Repository
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+// NewEntry builds the entry for an operation on an entity. before is nil for
+// creations and after is nil for deletions.
+func NewEntry(actor Actor, organizationID int64, action, entityType, entityID string, before, after interface{}) (Entry, error) {
+	changes, err := Diff(before, after)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	return Entry{
+		OrganizationID: organizationID,
+		ActorType:      actor.Type,
+		ActorID:        actor.ID,
+		ActorRole:      optional(actor.Role),
+		Action:         action,
+		EntityType:     entityType,
+		EntityID:       entityID,
+		Changes:        changes,
+		RequestID:      optional(actor.RequestID),
+		ClientIP:       optional(actor.ClientIP),
+	}, nil
+}
+
+// Record appends an entry using q, which should be the transaction of the
+// change the entry describes
+func (r *Repository) Record(q Execer, entry Entry) error {
+	query := `
+        INSERT INTO audit_log (
+            organization_id, actor_type, actor_id, actor_role, action,
+            entity_type, entity_id, changes, request_id, client_ip
+        )
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+    `
+
+	_, err := q.Exec(context.Background(), query,
+		entry.OrganizationID,
+		entry.ActorType,
+		entry.ActorID,
+		entry.ActorRole,
+		entry.Action,
+		entry.EntityType,
+		entry.EntityID,
+		entry.Changes,
+		entry.RequestID,
+		entry.ClientIP,
+	)
+	return err
+}
+
+// RedactEntries rewrites the recorded changes of entities so that the values
+// of the given fields are replaced with Redacted. It is the only update the
+// audit log allows, and is used to erase personal data. q must be a
+// transaction.
+func (r *Repository) RedactEntries(q Execer, entityType string, entityIDs []string, fields []string) error {
+	ctx := context.Background()
+
+	if _, err := q.Exec(ctx, `SELECT set_config('hostflow.audit_redaction', 'on', true)`); err != nil {
+		return err
+	}
+
+	query := `
+        UPDATE audit_log a
+        SET changes = (
+            SELECT jsonb_object_agg(
+                c.key,
+                CASE
+                    WHEN EXISTS (
+                        SELECT 1
+                        FROM unnest($3::text[]) f
+                        WHERE c.key = f OR c.key LIKE f || '.%'
+                    )
+                    THEN jsonb_build_object(
+                        'before', CASE WHEN c.value->'before' = 'null' THEN 'null'::jsonb ELSE to_jsonb($4::text) END,
+                        'after', CASE WHEN c.value->'after' = 'null' THEN 'null'::jsonb ELSE to_jsonb($4::text) END
+                    )
+                    ELSE c.value
+                END
+            )
+            FROM jsonb_each(a.changes) c
+        )
+        WHERE a.entity_type = $1
+          AND a.entity_id = ANY($2)
+          AND a.changes <> '{}'
+    `
+
+	if _, err := q.Exec(ctx, query, entityType, entityIDs, fields, Redacted); err != nil {
+		return err
+	}
+
+	_, err := q.Exec(ctx, `SELECT set_config('hostflow.audit_redaction', 'off', true)`)
+	return err
+}
+
+// GetEntries returns a page of the entries of an entity, newest first
+func (r *Repository) GetEntries(organizationID int64, entityType, entityID string, limit, offset int) (*EntryPage, error) {
+	ctx := context.Background()
+
+	var total int64
+	err := r.db.QueryRow(ctx, `
+        SELECT COUNT(*)
+        FROM audit_log
+        WHERE organization_id = $1
+          AND entity_type = $2
+          AND entity_id = $3
+    `, organizationID, entityType, entityID).Scan(&total)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + entryColumns + `
+        FROM audit_log
+        WHERE organization_id = $1
+          AND entity_type = $2
+          AND entity_id = $3
+        ORDER BY created_at DESC, id DESC
+        LIMIT $4 OFFSET $5
+    `
+
+	rows, err := r.db.Query(ctx, query, organizationID, entityType, entityID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries, err := pgx.CollectRows(rows, pgx.RowToStructByName[Entry])
+	if err != nil {
+		return nil, err
+	}
+
+	return &EntryPage{
+		Items:  entries,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+// optional maps empty strings to NULL
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
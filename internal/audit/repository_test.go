@@ -0,0 +1,69 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"hostflow/booking-service/internal/dbtest"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactEntries_IsTheOnlyAllowedUpdate(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	entry, err := NewEntry(SystemActor("test"), 100, ActionUpdate, EntityReservation, "1",
+		map[string]interface{}{"total_price": 400.0, "guest_data": map[string]interface{}{"phone": "+386 41 000 000"}},
+		map[string]interface{}{"total_price": 412.5, "guest_data": map[string]interface{}{"phone": "+386 41 111 111"}},
+	)
+	require.NoError(t, err)
+	require.NoError(t, repo.Record(db, entry))
+
+	inTx := func(fn func(tx pgx.Tx) error) error {
+		tx, err := db.Begin(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback(ctx)
+		if err := fn(tx); err != nil {
+			return err
+		}
+		return tx.Commit(ctx)
+	}
+
+	require.NoError(t, inTx(func(tx pgx.Tx) error {
+		return repo.RedactEntries(tx, EntityReservation, []string{"1"}, []string{"guest_data"})
+	}))
+
+	page, err := repo.GetEntries(100, EntityReservation, "1", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	changes := page.Items[0].Changes
+	assert.Equal(t, Change{Before: Redacted, After: Redacted}, changes["guest_data.phone"])
+	assert.Equal(t, Change{Before: 400.0, After: 412.5}, changes["total_price"])
+
+	// Without the redaction setting, nothing may change
+	_, err = db.Exec(ctx, `UPDATE audit_log SET changes = '{}'`)
+	assert.ErrorContains(t, err, "append-only")
+	_, err = db.Exec(ctx, `DELETE FROM audit_log`)
+	assert.ErrorContains(t, err, "append-only")
+
+	// With it, only the changes may be rewritten, and the setting ends with
+	// the redaction
+	assert.ErrorContains(t, inTx(func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `SELECT set_config('hostflow.audit_redaction', 'on', true)`); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `UPDATE audit_log SET action = 'delete'`)
+		return err
+	}), "append-only")
+	assert.ErrorContains(t, inTx(func(tx pgx.Tx) error {
+		if err := repo.RedactEntries(tx, EntityReservation, []string{"1"}, []string{"total_price"}); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `UPDATE audit_log SET changes = '{}'`)
+		return err
+	}), "append-only")
+}
@@ -0,0 +1,43 @@
+package audit
+
+import (
+	"hostflow/booking-service/internal/middlewares"
+	"hostflow/booking-service/pkg/lib"
+)
+
+// Routes struct
+type Routes struct {
+	logger              lib.Logger
+	router              *lib.Router
+	controller          *Controller
+	authMiddleware      middlewares.AuthMiddleware
+	rateLimitMiddleware middlewares.RateLimitMiddleware
+}
+
+// SetRoutes returns a Routes struct
+func SetRoutes(
+	logger lib.Logger,
+	router *lib.Router,
+	controller *Controller,
+	authMiddleware middlewares.AuthMiddleware,
+	rateLimitMiddleware middlewares.RateLimitMiddleware,
+) Routes {
+	return Routes{
+		logger:              logger,
+		router:              router,
+		controller:          controller,
+		authMiddleware:      authMiddleware,
+		rateLimitMiddleware: rateLimitMiddleware,
+	}
+}
+
+// Setup registers the audit routes
+func (route Routes) Setup() {
+	route.logger.Info("Setting up [AUDIT] routes.")
+
+	audit := route.router.Group("/audit")
+	audit.Use(route.authMiddleware.Handler(), middlewares.RequirePermission(middlewares.AuditRead))
+	{
+		audit.GET("", route.rateLimitMiddleware.Limit(middlewares.BudgetSearch), route.controller.GetEntriesHandler)
+	}
+}
@@ -1,6 +1,10 @@
 package booking
 
-import "go.uber.org/fx"
+import (
+	"hostflow/booking-service/pkg/interfaces"
+
+	"go.uber.org/fx"
+)
 
 // ======== EXPORTS ========
 
@@ -14,5 +18,17 @@ var Context = fx.Options(
 		),
 	),
 	fx.Provide(GetReservationRepository),
+	fx.Provide(
+		fx.Annotate(
+			func(repo *ReservationRepository) *ReservationRepository { return repo },
+			fx.As(new(interfaces.ReservationReassigner)),
+			fx.As(new(interfaces.ReservationLookup)),
+			fx.As(new(interfaces.AvailabilityChecker)),
+		),
+	),
 	fx.Provide(SetReservationRoutes),
+	fx.Provide(GetPurger),
+	fx.Invoke(RegisterPurgerHooks),
+	fx.Provide(GetHoldSweeper),
+	fx.Invoke(RegisterHoldSweeperHooks),
 )
@@ -1,23 +1,31 @@
 package booking
 
 import (
+	"errors"
 	"fmt"
+	"hostflow/booking-service/internal/audit"
+	"hostflow/booking-service/internal/encryption"
+	"hostflow/booking-service/internal/guest"
+	"hostflow/booking-service/internal/middlewares"
 	"net/http"
 
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 // ReservationController handles HTTP requests for reservations
 type ReservationController struct {
-	service Service
+	service   Service
+	guestData *encryption.FieldEncryptor
 }
 
 // GetReservationController creates a new controller
-func GetReservationController(service Service) *ReservationController {
+func GetReservationController(service Service, guestData *encryption.FieldEncryptor) *ReservationController {
 	return &ReservationController{
-		service: service,
+		service:   service,
+		guestData: guestData,
 	}
 }
 
@@ -30,6 +38,36 @@ func (c *ReservationController) getOrgID(ctx *gin.Context) (int64, bool) {
 	return val.(int64), true
 }
 
+// toResponse converts a reservation for the caller. Encrypted guest data is
+// decrypted for callers allowed to read it and redacted for everyone else,
+// and also when it can't be decrypted.
+func (c *ReservationController) toResponse(ctx *gin.Context, r *Reservation) *ReservationResponse {
+	response := r.ToResponse()
+	c.revealGuestData(ctx, response)
+	return response
+}
+
+// revealGuestData decrypts or redacts the guest data of a response
+func (c *ReservationController) revealGuestData(ctx *gin.Context, response *ReservationResponse) {
+	if middlewares.IsPermitted(ctx, middlewares.GuestDataDecrypt) {
+		if err := c.guestData.Decrypt(ctx, response.GuestData); err == nil {
+			return
+		}
+	}
+	c.guestData.Redact(response.GuestData)
+}
+
+// invalidGuests responds with the validation errors if err is a guest data
+// validation error
+func (c *ReservationController) invalidGuests(ctx *gin.Context, err error) bool {
+	var invalid *guest.ValidationError
+	if !errors.As(err, &invalid) {
+		return false
+	}
+	ctx.JSON(http.StatusBadRequest, ValidationErrorResponse{Errors: invalid.Errors})
+	return true
+}
+
 // GetReservationsHandler godoc
 // @Summary Get all reservations
 // @Description Returns a list of all reservations for the authenticated organization
@@ -60,7 +98,7 @@ func (c *ReservationController) GetReservationsHandler(ctx *gin.Context) {
 	// Convert to response format
 	response := make([]ReservationResponse, len(reservations))
 	for i, r := range reservations {
-		response[i] = *r.ToResponse()
+		response[i] = *c.toResponse(ctx, &r)
 	}
 
 	ctx.JSON(http.StatusOK, response)
@@ -139,12 +177,16 @@ func (c *ReservationController) GetReservationByIDHandler(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, reservation.ToResponse())
+	ctx.JSON(http.StatusOK, c.toResponse(ctx, reservation))
 }
 
 // CreateReservationHandler godoc
 // @Summary Create a new reservation
-// @Description Create a new reservation with the provided details
+// @Description Create a new reservation with the provided details. Guests registered in guest_data.guests
+// @Description are validated against the required fields of their nationality; invalid guests are reported
+// @Description as {"errors": [{"field", "message"}]}. On a property with room types, room_type_id or unit_id
+// @Description is required and the stay is available while the units of the type are not all booked on any
+// @Description night; a unit is assigned when the reservation is confirmed.
 // @Tags reservations
 // @Accept json
 // @Produce json
@@ -169,7 +211,10 @@ func (c *ReservationController) CreateReservationHandler(ctx *gin.Context) {
 		return
 	}
 
-	reservation, err := c.service.CreateReservation(&req, orgID)
+	reservation, err := c.service.CreateReservation(&req, orgID, audit.ActorFromContext(ctx))
+	if c.invalidGuests(ctx, err) {
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, ErrorResponse{
 			Error:   "Failed to create reservation",
@@ -178,12 +223,147 @@ func (c *ReservationController) CreateReservationHandler(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, reservation.ToResponse())
+	ctx.JSON(http.StatusCreated, c.toResponse(ctx, reservation))
+}
+
+// ImportReservationsHandler godoc
+// @Summary Import reservations from CSV or XLSX
+// @Description Imports reservations from a CSV file, or the first sheet of an XLSX workbook, with a header row.
+// @Description Workbooks are recognized by their content. The columns property_id, check_in_date,
+// @Description check_out_date, no_of_guests and total_price are required, with customer_id or customer_email
+// @Description (and customer_name to create a customer that doesn't exist yet); status, price_elements,
+// @Description guest_data, additional_requests (JSON objects), created_at, room_type_id and unit_id are optional. Dates are YYYY-MM-DD
+// @Description or RFC 3339, or dates typed into a workbook's cells. Every row is validated like a created reservation and against the other rows; when
+// @Description any row is invalid, nothing is imported and the per-row errors are returned with status 400.
+// @Description With dry_run nothing is written. Rows are committed in batches; CREATED reservations get a
+// @Description payment unless skip_payment is set, other statuses are imported as they are.
+// @Tags reservations
+// @Accept multipart/form-data
+// @Accept text/csv
+// @Accept application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce json
+// @Security ApiKeyAuth
+// @Param file formData file false "CSV or XLSX file, or the file as the request body"
+// @Param dry_run query bool false "Only validate the file"
+// @Param skip_payment query bool false "Don't initiate payments, for historical imports"
+// @Success 200 {object} ImportResult "Dry run"
+// @Success 201 {object} ImportResult
+// @Failure 400 {object} ImportResult
+// @Failure 500 {object} ErrorResponse
+// @Router /reservations/import [post]
+func (c *ReservationController) ImportReservationsHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	var opts ImportOptions
+	if err := ctx.ShouldBindQuery(&opts); err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid query", Message: err.Error()})
+		return
+	}
+
+	file := ctx.Request.Body
+	if strings.HasPrefix(ctx.ContentType(), "multipart/") {
+		header, err := ctx.FormFile("file")
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid import file", Message: err.Error()})
+			return
+		}
+		upload, err := header.Open()
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid import file", Message: err.Error()})
+			return
+		}
+		defer upload.Close()
+		file = upload
+	}
+
+	result, err := c.service.ImportReservations(file, opts, orgID, audit.ActorFromContext(ctx))
+	if errors.Is(err, ErrInvalidImportFile) {
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid import file", Message: err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to import reservations",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	switch {
+	case result.Invalid > 0:
+		ctx.JSON(http.StatusBadRequest, result)
+	case result.DryRun:
+		ctx.JSON(http.StatusOK, result)
+	default:
+		ctx.JSON(http.StatusCreated, result)
+	}
+}
+
+// ExportReservationsHandler godoc
+// @Summary Export reservations
+// @Description Streams the reservations of the organization as CSV, NDJSON or XLSX, oldest first. The rows are
+// @Description fetched from a database cursor and written as they arrive, so exports of any size use constant
+// @Description memory. price_elements is flattened into a column per value, named by its path (for example
+// @Description price_elements.cleaning.amount). Guest data is redacted without the guestdata:decrypt permission.
+// @Tags reservations
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security ApiKeyAuth
+// @Param format query string false "Output format" Enums(csv, ndjson, xlsx) default(csv)
+// @Param columns query string false "Comma separated columns, by default all: id, property_id, room_type_id, unit_id, group_id, customer_id, check_in_date, check_out_date, nights, status, kind, hold_expires_at, total_price, price_elements, payment_url, no_of_guests, guest_data, additional_requests, created_at, updated_at"
+// @Param status query string false "Comma separated statuses"
+// @Param property_id query int false "Property"
+// @Param customer_id query int false "Customer"
+// @Param check_in_from query string false "First check-in day (YYYY-MM-DD)"
+// @Param check_in_to query string false "Last check-in day (YYYY-MM-DD)"
+// @Param created_from query string false "First booking day (YYYY-MM-DD)"
+// @Param created_to query string false "Last booking day (YYYY-MM-DD)"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /reservations/export [get]
+func (c *ReservationController) ExportReservationsHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	var q ExportQuery
+	if err := ctx.ShouldBindQuery(&q); err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid query", Message: err.Error()})
+		return
+	}
+	filter, err := q.Filter(orgID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid query", Message: err.Error()})
+		return
+	}
+
+	ctx.Header("Content-Type", ExportContentTypes[filter.Format])
+	ctx.Header("Content-Disposition", "attachment; filename=reservations."+filter.Format)
+
+	decrypt := middlewares.IsPermitted(ctx, middlewares.GuestDataDecrypt)
+	if err := c.service.ExportReservations(filter, decrypt, ctx.Writer); err != nil {
+		if ctx.Writer.Written() {
+			// The export is truncated; the client sees the connection end
+			ctx.Error(err)
+			return
+		}
+		ctx.Header("Content-Disposition", "")
+		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to export reservations",
+			Message: err.Error(),
+		})
+	}
 }
 
 // UpdateReservationHandler godoc
 // @Summary Update a reservation
-// @Description Update reservation details by integer ID
+// @Description Update reservation details by integer ID. Guests are validated as on create.
 // @Tags reservations
 // @Accept json
 // @Produce json
@@ -218,7 +398,10 @@ func (c *ReservationController) UpdateReservationHandler(ctx *gin.Context) {
 		return
 	}
 
-	reservation, err := c.service.UpdateReservation(id, &req, orgID)
+	reservation, err := c.service.UpdateReservation(id, &req, orgID, audit.ActorFromContext(ctx))
+	if c.invalidGuests(ctx, err) {
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, ErrorResponse{
 			Error:   "Failed to update reservation",
@@ -227,7 +410,7 @@ func (c *ReservationController) UpdateReservationHandler(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, reservation.ToResponse())
+	ctx.JSON(http.StatusOK, c.toResponse(ctx, reservation))
 }
 
 // DeleteReservationHandler godoc
@@ -253,7 +436,14 @@ func (c *ReservationController) DeleteReservationHandler(ctx *gin.Context) {
 		return
 	}
 
-	err = c.service.DeleteReservation(id, orgID)
+	err = c.service.DeleteReservation(id, orgID, audit.ActorFromContext(ctx))
+	if errors.Is(err, ErrReservationNotFound) {
+		ctx.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Reservation not found",
+			Message: err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, ErrorResponse{
 			Error:   "Failed to delete reservation",
@@ -265,6 +455,58 @@ func (c *ReservationController) DeleteReservationHandler(ctx *gin.Context) {
 	ctx.Status(http.StatusNoContent)
 }
 
+// RestoreReservationHandler godoc
+// @Summary Restore a deleted reservation
+// @Description Undoes the deletion of a reservation that has not been purged yet. The dates must still be available, unless the reservation was cancelled or rejected.
+// @Tags reservations
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Reservation ID"
+// @Success 200 {object} ReservationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /reservations/{id}/restore [post]
+func (c *ReservationController) RestoreReservationHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid ID format",
+			Message: "ID must be a valid integer",
+		})
+		return
+	}
+
+	reservation, err := c.service.RestoreReservation(id, orgID, audit.ActorFromContext(ctx))
+	switch {
+	case errors.Is(err, ErrReservationNotFound):
+		ctx.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Deleted reservation not found",
+			Message: err.Error(),
+		})
+		return
+	case errors.Is(err, ErrPropertyUnavailable):
+		ctx.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Failed to restore reservation",
+			Message: err.Error(),
+		})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to restore reservation",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, c.toResponse(ctx, reservation))
+}
+
 // UpdateReservationStatusHandler godoc
 // @Summary Update reservation status
 // @Description Update the status of a reservation
@@ -303,7 +545,7 @@ func (c *ReservationController) UpdateReservationStatusHandler(ctx *gin.Context)
 		return
 	}
 
-	reservation, err := c.service.UpdateReservationStatus(id, req.Status, orgID)
+	reservation, err := c.service.UpdateReservationStatus(id, req.Status, orgID, audit.ActorFromContext(ctx))
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, ErrorResponse{
 			Error:   "Failed to update status",
@@ -312,7 +554,7 @@ func (c *ReservationController) UpdateReservationStatusHandler(ctx *gin.Context)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, reservation.ToResponse())
+	ctx.JSON(http.StatusOK, c.toResponse(ctx, reservation))
 }
 
 // CancelReservationHandler godoc
@@ -338,7 +580,7 @@ func (c *ReservationController) CancelReservationHandler(ctx *gin.Context) {
 		return
 	}
 
-	reservation, err := c.service.CancelReservation(id, orgID)
+	reservation, err := c.service.CancelReservation(id, orgID, audit.ActorFromContext(ctx))
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, ErrorResponse{
 			Error:   "Failed to cancel reservation",
@@ -347,7 +589,92 @@ func (c *ReservationController) CancelReservationHandler(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, reservation.ToResponse())
+	ctx.JSON(http.StatusOK, c.toResponse(ctx, reservation))
+}
+
+// GetCustomerReservationsHandler godoc
+// @Summary Get a customer's reservations
+// @Description Returns the reservation history of a customer within the authenticated organization, newest stay first
+// @Tags customers
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Customer ID"
+// @Success 200 {array} ReservationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /customer/{id}/reservations [get]
+func (c *ReservationController) GetCustomerReservationsHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	customerID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid ID format",
+			Message: "Customer ID must be an integer",
+		})
+		return
+	}
+
+	reservations, err := c.service.GetReservationsByCustomer(customerID, orgID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to fetch reservations",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response := make([]ReservationResponse, len(reservations))
+	for i, r := range reservations {
+		response[i] = *c.toResponse(ctx, &r)
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetCustomerSummaryHandler godoc
+// @Summary Get a customer's stay summary
+// @Description Returns total stays, nights, revenue, cancellations, no-shows, the last stay and upcoming stays of a customer within the authenticated organization
+// @Tags customers
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Customer ID"
+// @Success 200 {object} CustomerSummary
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /customer/{id}/summary [get]
+func (c *ReservationController) GetCustomerSummaryHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	customerID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid ID format",
+			Message: "Customer ID must be an integer",
+		})
+		return
+	}
+
+	summary, err := c.service.GetCustomerSummary(customerID, orgID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to build customer summary",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	for i := range summary.UpcomingStays {
+		c.revealGuestData(ctx, &summary.UpcomingStays[i])
+	}
+
+	ctx.JSON(http.StatusOK, summary)
 }
 
 /*// ConfirmReservationHandler godoc
@@ -387,7 +714,7 @@ func (c *ReservationController) ConfirmReservationHandler(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, reservation.ToResponse())
+	ctx.JSON(http.StatusOK, c.toResponse(ctx, reservation))
 }*/
 
 /*// CheckInReservationHandler godoc
@@ -427,7 +754,7 @@ func (c *ReservationController) CheckInReservationHandler(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, reservation.ToResponse())
+	ctx.JSON(http.StatusOK, c.toResponse(ctx, reservation))
 }
 
 // CheckOutReservationHandler godoc
@@ -467,5 +794,355 @@ func (c *ReservationController) CheckOutReservationHandler(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, reservation.ToResponse())
+	ctx.JSON(http.StatusOK, c.toResponse(ctx, reservation))
 }*/
+
+// CreateBookingGroupHandler godoc
+// @Summary Book a group of stays
+// @Description Books several stays for a customer at once, possibly at different properties and on different dates,
+// @Description for example apartments for a family or an event. Availability of all stays is checked in a single
+// @Description transaction: either all are booked or none is. The group is priced as the sum of its stays and paid
+// @Description with a single payment link; once paid, all stays are confirmed. Invalid guests are reported with
+// @Description fields prefixed by their stay (stays[0].guest_data...).
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param group body BookingGroupRequest true "Stays of the group"
+// @Success 201 {object} BookingGroup
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /reservation-groups [post]
+func (c *ReservationController) CreateBookingGroupHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	var req BookingGroupRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	group, err := c.service.CreateBookingGroup(&req, orgID, audit.ActorFromContext(ctx))
+	if c.invalidGuests(ctx, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, ErrPropertyUnavailable):
+		ctx.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Failed to create booking group",
+			Message: err.Error(),
+		})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Failed to create booking group",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, c.groupResponse(ctx, group))
+}
+
+// GetBookingGroupHandler godoc
+// @Summary Get a booking group
+// @Description Returns a booking group with its stays, total price and payment link
+// @Tags reservations
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Booking group ID"
+// @Success 200 {object} BookingGroup
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /reservation-groups/{id} [get]
+func (c *ReservationController) GetBookingGroupHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid ID format",
+			Message: "ID must be a valid integer",
+		})
+		return
+	}
+
+	group, err := c.service.GetBookingGroup(id, orgID)
+	switch {
+	case errors.Is(err, ErrGroupNotFound):
+		ctx.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Booking group not found",
+			Message: err.Error(),
+		})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to get booking group",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, c.groupResponse(ctx, group))
+}
+
+// CancelBookingGroupHandler godoc
+// @Summary Cancel a booking group
+// @Description Cancels the given stays of a booking group, or all its stays without a body, in a single transaction.
+// @Description Stays already cancelled are skipped; completed stays can't be cancelled. The group total drops by
+// @Description the cancelled stays.
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Booking group ID"
+// @Param request body GroupCancelRequest false "Stays to cancel"
+// @Success 200 {object} BookingGroup
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /reservation-groups/{id}/cancel [post]
+func (c *ReservationController) CancelBookingGroupHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid ID format",
+			Message: "ID must be a valid integer",
+		})
+		return
+	}
+
+	var req GroupCancelRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid request body",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	group, err := c.service.CancelBookingGroup(id, req.ReservationIDs, orgID, audit.ActorFromContext(ctx))
+	switch {
+	case errors.Is(err, ErrGroupNotFound):
+		ctx.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Booking group not found",
+			Message: err.Error(),
+		})
+		return
+	case errors.Is(err, ErrNotInGroup):
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Failed to cancel booking group",
+			Message: err.Error(),
+		})
+		return
+	case errors.Is(err, ErrNotCancellable):
+		ctx.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Failed to cancel booking group",
+			Message: err.Error(),
+		})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to cancel booking group",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, c.groupResponse(ctx, group))
+}
+
+// groupResponse decrypts or redacts the guest data of the stays of a group
+func (c *ReservationController) groupResponse(ctx *gin.Context, group *BookingGroup) *BookingGroup {
+	for i := range group.Reservations {
+		c.revealGuestData(ctx, &group.Reservations[i])
+	}
+	return group
+}
+
+// CreateHoldHandler godoc
+// @Summary Put a stay on hold
+// @Description Puts dates on hold for a prospective guest without a payment. The hold blocks availability like a
+// @Description reservation until expires_at, by default RESERVATION_HOLD_HOURS (48) from now, and is validated like a
+// @Description created reservation. It is then converted into a booking, released, or expires and frees its dates.
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param hold body HoldRequest true "Stay to hold"
+// @Success 201 {object} ReservationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /reservations/holds [post]
+func (c *ReservationController) CreateHoldHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	var req HoldRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	hold, err := c.service.CreateHold(&req, orgID, audit.ActorFromContext(ctx))
+	if c.invalidGuests(ctx, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, ErrPropertyUnavailable):
+		ctx.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Failed to create hold",
+			Message: err.Error(),
+		})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Failed to create hold",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, c.toResponse(ctx, hold))
+}
+
+// ConvertHoldHandler godoc
+// @Summary Convert a hold into a booking
+// @Description Turns an active hold into a booking of its guest, which asks for the payment like a created
+// @Description reservation. Expired holds can't be converted.
+// @Tags reservations
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Reservation ID"
+// @Success 200 {object} ReservationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /reservations/{id}/convert [post]
+func (c *ReservationController) ConvertHoldHandler(ctx *gin.Context) {
+	c.changeHold(ctx, "Failed to convert hold", c.service.ConvertHold)
+}
+
+// ReleaseHoldHandler godoc
+// @Summary Release a hold
+// @Description Cancels an active hold, which frees its dates
+// @Tags reservations
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Reservation ID"
+// @Success 200 {object} ReservationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /reservations/{id}/release [post]
+func (c *ReservationController) ReleaseHoldHandler(ctx *gin.Context) {
+	c.changeHold(ctx, "Failed to release hold", c.service.ReleaseHold)
+}
+
+// changeHold responds with the hold of the id parameter changed by change
+func (c *ReservationController) changeHold(ctx *gin.Context, failure string, change func(int, int64, audit.Actor) (*Reservation, error)) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid ID format",
+			Message: "ID must be a valid integer",
+		})
+		return
+	}
+
+	reservation, err := change(id, orgID, audit.ActorFromContext(ctx))
+	switch {
+	case errors.Is(err, ErrReservationNotFound):
+		ctx.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Reservation not found",
+			Message: err.Error(),
+		})
+		return
+	case errors.Is(err, ErrNotHold), errors.Is(err, ErrHoldExpired):
+		ctx.JSON(http.StatusConflict, ErrorResponse{
+			Error:   failure,
+			Message: err.Error(),
+		})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   failure,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, c.toResponse(ctx, reservation))
+}
+
+// GetCalendarHandler godoc
+// @Summary Get the reservation calendar
+// @Description Returns the bookings and active holds whose stay overlaps from to to (YYYY-MM-DD, at most 366 days),
+// @Description optionally of one property. Holds have kind HOLD and the time they expire.
+// @Tags reservations
+// @Produce json
+// @Security ApiKeyAuth
+// @Param from query string true "First date"
+// @Param to query string true "Date after the last one"
+// @Param property_id query int false "Property ID"
+// @Success 200 {array} CalendarEntry
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /reservations/calendar [get]
+func (c *ReservationController) GetCalendarHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	var f CalendarFilter
+	if err := ctx.ShouldBindQuery(&f); err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid query", Message: err.Error()})
+		return
+	}
+
+	entries, err := c.service.GetCalendar(&f, orgID)
+	switch {
+	case errors.Is(err, ErrInvalidCalendarRange):
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Failed to get calendar",
+			Message: err.Error(),
+		})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to get calendar",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entries)
+}
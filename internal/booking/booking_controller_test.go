@@ -1,8 +1,14 @@
 package booking
 
 import (
+	"fmt"
+	"hostflow/booking-service/internal/audit"
+	"hostflow/booking-service/internal/guest"
+	"hostflow/booking-service/pkg/common"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -19,36 +25,137 @@ func (m *MockReservationService) GetReservations(orgID int64) ([]Reservation, er
 	panic("implement me")
 }
 
-func (m *MockReservationService) CreateReservation(req *ReservationRequest, orgID int64) (*Reservation, error) {
+func (m *MockReservationService) CreateReservation(req *ReservationRequest, orgID int64, actor audit.Actor) (*Reservation, error) {
+	args := m.Called(req, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Reservation), args.Error(1)
+}
+
+func (m *MockReservationService) UpdateReservation(id int, req *ReservationRequest, orgID int64, actor audit.Actor) (*Reservation, error) {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (m *MockReservationService) UpdateReservation(id int, req *ReservationRequest, orgID int64) (*Reservation, error) {
+func (m *MockReservationService) DeleteReservation(id int, orgID int64, actor audit.Actor) error {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (m *MockReservationService) DeleteReservation(id int, orgID int64) error {
+func (m *MockReservationService) UpdateReservationStatus(id int, status string, orgID int64, actor audit.Actor) (*Reservation, error) {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (m *MockReservationService) UpdateReservationStatus(id int, status string, orgID int64) (*Reservation, error) {
+func (m *MockReservationService) CancelReservation(id int, orgID int64, actor audit.Actor) (*Reservation, error) {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (m *MockReservationService) CancelReservation(id int, orgID int64) (*Reservation, error) {
+func (m *MockReservationService) RestoreReservation(id int, orgID int64, actor audit.Actor) (*Reservation, error) {
+	args := m.Called(id, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Reservation), args.Error(1)
+}
+
+func (m *MockReservationService) ConfirmPayment(reservationID int) error {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (m *MockReservationService) ConfirmPayment(reservationID int) error {
+func (m *MockReservationService) GetReservationsByCustomer(customerID int, orgID int64) ([]Reservation, error) {
+	args := m.Called(customerID, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Reservation), args.Error(1)
+}
+
+func (m *MockReservationService) ImportReservations(file io.Reader, opts ImportOptions, orgID int64, actor audit.Actor) (*ImportResult, error) {
+	args := m.Called(opts, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ImportResult), args.Error(1)
+}
+
+func (m *MockReservationService) ExportReservations(f *ExportFilter, decrypt bool, w io.Writer) error {
+	args := m.Called(f, decrypt)
+	return args.Error(0)
+}
+
+func (m *MockReservationService) CreateBookingGroup(req *BookingGroupRequest, orgID int64, actor audit.Actor) (*BookingGroup, error) {
+	args := m.Called(req, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*BookingGroup), args.Error(1)
+}
+
+func (m *MockReservationService) GetBookingGroup(id int64, orgID int64) (*BookingGroup, error) {
+	args := m.Called(id, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*BookingGroup), args.Error(1)
+}
+
+func (m *MockReservationService) CancelBookingGroup(id int64, reservationIDs []int, orgID int64, actor audit.Actor) (*BookingGroup, error) {
+	args := m.Called(id, reservationIDs, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*BookingGroup), args.Error(1)
+}
+
+func (m *MockReservationService) CreateHold(req *HoldRequest, orgID int64, actor audit.Actor) (*Reservation, error) {
+	args := m.Called(req, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Reservation), args.Error(1)
+}
+
+func (m *MockReservationService) ConvertHold(id int, orgID int64, actor audit.Actor) (*Reservation, error) {
+	args := m.Called(id, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Reservation), args.Error(1)
+}
+
+func (m *MockReservationService) ReleaseHold(id int, orgID int64, actor audit.Actor) (*Reservation, error) {
+	args := m.Called(id, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Reservation), args.Error(1)
+}
+
+func (m *MockReservationService) ExpireHolds() (int, error) {
 	//TODO implement me
 	panic("implement me")
 }
 
+func (m *MockReservationService) GetCalendar(f *CalendarFilter, orgID int64) ([]CalendarEntry, error) {
+	args := m.Called(f, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]CalendarEntry), args.Error(1)
+}
+
+func (m *MockReservationService) GetCustomerSummary(customerID int, orgID int64) (*CustomerSummary, error) {
+	args := m.Called(customerID, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*CustomerSummary), args.Error(1)
+}
+
 func (m *MockReservationService) GetReservationByID(id int, orgID int64) (*Reservation, error) {
 	args := m.Called(id, orgID)
 	if args.Get(0) == nil {
@@ -76,7 +183,7 @@ func TestLivenessHandler(t *testing.T) {
 func TestGetReservationByID_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockSvc := new(MockReservationService)
-	controller := GetReservationController(mockSvc)
+	controller := GetReservationController(mockSvc, nil)
 
 	r := gin.Default()
 	r.GET("/reservations/:id", func(c *gin.Context) {
@@ -101,7 +208,7 @@ func TestGetReservationByID_Success(t *testing.T) {
 func TestGetReservations_NoOrgID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockSvc := new(MockReservationService)
-	controller := GetReservationController(mockSvc)
+	controller := GetReservationController(mockSvc, nil)
 
 	r := gin.Default()
 	r.GET("/reservations", controller.GetReservationsHandler)
@@ -115,3 +222,190 @@ func TestGetReservations_NoOrgID(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 	assert.Contains(t, w.Body.String(), "Organization ID not found")
 }
+
+// TEST 4: Zgodovina rezervacij stranke je omejena na organizacijo
+func TestGetCustomerReservations_ScopedToOrganization(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSvc := new(MockReservationService)
+	controller := GetReservationController(mockSvc, nil)
+
+	r := gin.Default()
+	r.GET("/customer/:id/reservations", func(c *gin.Context) {
+		c.Set("organization_id", int64(100))
+		controller.GetCustomerReservationsHandler(c)
+	})
+
+	mockSvc.On("GetReservationsByCustomer", 42, int64(100)).Return([]Reservation{
+		{ID: 1, OrganizationID: 100, CustomerID: 42, Status: StatusCompleted},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/customer/42/reservations", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"customer_id":42`)
+	mockSvc.AssertExpectations(t)
+}
+
+// TEST 5: Obnovitev izbrisane rezervacije, katere termin je medtem zaseden
+func TestRestoreReservation_Conflict(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSvc := new(MockReservationService)
+	controller := GetReservationController(mockSvc, nil)
+
+	r := gin.Default()
+	r.POST("/reservations/:id/restore", func(c *gin.Context) {
+		c.Set("organization_id", int64(100))
+		controller.RestoreReservationHandler(c)
+	})
+
+	mockSvc.On("RestoreReservation", 5, int64(100)).Return(nil, ErrPropertyUnavailable)
+	mockSvc.On("RestoreReservation", 6, int64(100)).Return(nil, ErrReservationNotFound)
+	mockSvc.On("RestoreReservation", 7, int64(100)).Return(&Reservation{ID: 7, OrganizationID: 100}, nil)
+
+	for id, code := range map[string]int{"5": http.StatusConflict, "6": http.StatusNotFound, "7": http.StatusOK} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/reservations/"+id+"/restore", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, code, w.Code, id)
+	}
+	mockSvc.AssertExpectations(t)
+}
+
+// TEST 6: Neveljavni podatki gostov se vrnejo v obliki common.Validation
+func TestCreateReservation_InvalidGuests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSvc := new(MockReservationService)
+	controller := GetReservationController(mockSvc, nil)
+
+	r := gin.Default()
+	r.POST("/reservations", func(c *gin.Context) {
+		c.Set("organization_id", int64(100))
+		controller.CreateReservationHandler(c)
+	})
+
+	mockSvc.On("CreateReservation", mock.Anything, int64(100)).Return(nil, &guest.ValidationError{
+		Errors: []common.ValidationErrorMessage{{Field: "guest_data.guests[0].birth_date", Message: "This field is required."}},
+	})
+
+	body := `{"organization_id":100,"property_id":10,"customer_id":42,"check_in_date":"2030-01-10T15:00:00Z",` +
+		`"check_out_date":"2030-01-12T10:00:00Z","no_of_guests":1,"total_price":200,` +
+		`"guest_data":{"guests":[{"first_name":"Ana","last_name":"Novak"}]}}`
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/reservations", strings.NewReader(body))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"errors":[{"field":"guest_data.guests[0].birth_date","message":"This field is required."}]}`, w.Body.String())
+	mockSvc.AssertExpectations(t)
+}
+
+// TEST 7: Uvoz z neveljavnimi vrsticami vrne poročilo po vrsticah, poskusni uvoz pa 200
+func TestImportReservations_Report(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSvc := new(MockReservationService)
+	controller := GetReservationController(mockSvc, nil)
+
+	r := gin.Default()
+	r.POST("/reservations/import", func(c *gin.Context) {
+		c.Set("organization_id", int64(100))
+		controller.ImportReservationsHandler(c)
+	})
+
+	invalid := &ImportResult{Total: 1, Invalid: 1, Rows: []ImportRowResult{{Line: 2, Errors: []string{"property_id is required"}}}}
+	mockSvc.On("ImportReservations", ImportOptions{}, int64(100)).Return(invalid, nil)
+	mockSvc.On("ImportReservations", ImportOptions{DryRun: true, SkipPayment: true}, int64(100)).Return(&ImportResult{DryRun: true, Total: 1, Valid: 1}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/reservations/import", strings.NewReader("property_id\n"))
+	req.Header.Set("Content-Type", "text/csv")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"errors":["property_id is required"]`)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/reservations/import?dry_run=true&skip_payment=true", strings.NewReader("property_id\n"))
+	req.Header.Set("Content-Type", "text/csv")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+// TEST 8: Preklic dela skupinske rezervacije
+func TestCancelBookingGroup(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSvc := new(MockReservationService)
+	controller := GetReservationController(mockSvc, nil)
+
+	r := gin.Default()
+	r.POST("/reservation-groups/:id/cancel", func(c *gin.Context) {
+		c.Set("organization_id", int64(100))
+		controller.CancelBookingGroupHandler(c)
+	})
+
+	mockSvc.On("CancelBookingGroup", int64(5), []int{7}, int64(100)).Return(&BookingGroup{ID: 5, Status: StatusPaymentRequired, TotalPrice: 300}, nil)
+	mockSvc.On("CancelBookingGroup", int64(5), []int(nil), int64(100)).Return(&BookingGroup{ID: 5, Status: StatusCancelled}, nil)
+	mockSvc.On("CancelBookingGroup", int64(6), []int(nil), int64(100)).Return(nil, ErrGroupNotFound)
+	mockSvc.On("CancelBookingGroup", int64(5), []int{8}, int64(100)).Return(nil, fmt.Errorf("%w: reservation 8 is COMPLETED", ErrNotCancellable))
+
+	for _, tc := range []struct {
+		path, body string
+		code       int
+	}{
+		{"/reservation-groups/5/cancel", `{"reservation_ids":[7]}`, http.StatusOK},
+		{"/reservation-groups/5/cancel", ``, http.StatusOK},
+		{"/reservation-groups/6/cancel", ``, http.StatusNotFound},
+		{"/reservation-groups/5/cancel", `{"reservation_ids":[8]}`, http.StatusConflict},
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", tc.path, strings.NewReader(tc.body))
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, tc.code, w.Code, tc.path+" "+tc.body)
+	}
+	mockSvc.AssertExpectations(t)
+}
+
+// TEST 9: Pretvorba in sprostitev začasne rezervacije
+func TestChangeHold(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSvc := new(MockReservationService)
+	controller := GetReservationController(mockSvc, nil)
+
+	r := gin.Default()
+	r.POST("/reservations/:id/convert", func(c *gin.Context) {
+		c.Set("organization_id", int64(100))
+		controller.ConvertHoldHandler(c)
+	})
+	r.POST("/reservations/:id/release", func(c *gin.Context) {
+		c.Set("organization_id", int64(100))
+		controller.ReleaseHoldHandler(c)
+	})
+
+	mockSvc.On("ConvertHold", 1, int64(100)).Return(&Reservation{ID: 1, Kind: KindBooking, Status: StatusPaymentRequired}, nil)
+	mockSvc.On("ConvertHold", 2, int64(100)).Return(nil, ErrHoldExpired)
+	mockSvc.On("ReleaseHold", 3, int64(100)).Return(nil, ErrNotHold)
+	mockSvc.On("ReleaseHold", 4, int64(100)).Return(nil, ErrReservationNotFound)
+
+	for _, tc := range []struct {
+		path string
+		code int
+	}{
+		{"/reservations/1/convert", http.StatusOK},
+		{"/reservations/2/convert", http.StatusConflict},
+		{"/reservations/3/release", http.StatusConflict},
+		{"/reservations/4/release", http.StatusNotFound},
+		{"/reservations/x/release", http.StatusBadRequest},
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", tc.path, nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, tc.code, w.Code, tc.path)
+	}
+	mockSvc.AssertExpectations(t)
+}
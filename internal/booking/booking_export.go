@@ -0,0 +1,439 @@
+package booking
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Export formats
+const (
+	ExportCSV    = "csv"
+	ExportNDJSON = "ndjson"
+	ExportXLSX   = "xlsx"
+)
+
+// exportFetchSize is the number of rows fetched from the export cursor at a
+// time, which bounds the memory an export needs
+const exportFetchSize = 500
+
+// priceElementsColumn expands to a column per price element value
+const priceElementsColumn = "price_elements"
+
+// ErrInvalidExport is returned for an export query with an unknown column or
+// status
+var ErrInvalidExport = errors.New("invalid export query")
+
+// ExportContentTypes are the content types of the export formats
+var ExportContentTypes = map[string]string{
+	ExportCSV:    "text/csv; charset=utf-8",
+	ExportNDJSON: "application/x-ndjson",
+	ExportXLSX:   "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// exportColumns are the columns of an export in their default order.
+// price_elements is flattened into a column per value, named by its path
+// (e.g. price_elements.cleaning.amount).
+var exportColumns = []string{
+	"id", "property_id", "room_type_id", "unit_id", "group_id", "customer_id", "check_in_date", "check_out_date", "nights", "status",
+	"kind", "hold_expires_at", "total_price", priceElementsColumn, "payment_url", "no_of_guests", "guest_data",
+	"additional_requests", "created_at", "updated_at",
+}
+
+// ExportQuery selects the reservations and columns of an export. Dates are
+// inclusive; lists are comma separated.
+type ExportQuery struct {
+	Format      string `form:"format" binding:"omitempty,oneof=csv ndjson xlsx" example:"csv"`
+	Columns     string `form:"columns" example:"id,check_in_date,status,total_price,price_elements"`
+	Status      string `form:"status" example:"CONFIRMED,COMPLETED"`
+	PropertyID  *int   `form:"property_id" binding:"omitempty,min=1" example:"10"`
+	CustomerID  *int   `form:"customer_id" binding:"omitempty,min=1" example:"100"`
+	CheckInFrom string `form:"check_in_from" binding:"omitempty,datetime=2006-01-02" example:"2026-01-01"`
+	CheckInTo   string `form:"check_in_to" binding:"omitempty,datetime=2006-01-02" example:"2026-12-31"`
+	CreatedFrom string `form:"created_from" binding:"omitempty,datetime=2006-01-02" example:"2026-01-01"`
+	CreatedTo   string `form:"created_to" binding:"omitempty,datetime=2006-01-02" example:"2026-12-31"`
+}
+
+// ExportFilter is a validated export query
+type ExportFilter struct {
+	OrganizationID int64
+	Format         string
+	Columns        []string
+	Statuses       []string
+	PropertyID     *int
+	CustomerID     *int
+	CheckInFrom    *time.Time
+	CheckInBefore  *time.Time
+	CreatedFrom    *time.Time
+	CreatedBefore  *time.Time
+}
+
+// Filter validates the query of an export of the organization. Without
+// columns, all columns are exported; without a format, the export is CSV.
+func (q *ExportQuery) Filter(organizationID int64) (*ExportFilter, error) {
+	f := &ExportFilter{
+		OrganizationID: organizationID,
+		Format:         q.Format,
+		Columns:        exportColumns,
+		PropertyID:     q.PropertyID,
+		CustomerID:     q.CustomerID,
+	}
+	if f.Format == "" {
+		f.Format = ExportCSV
+	}
+
+	if q.Columns != "" {
+		known := map[string]bool{}
+		for _, c := range exportColumns {
+			known[c] = true
+		}
+		f.Columns = nil
+		for _, c := range splitList(q.Columns) {
+			if !known[c] {
+				return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidExport, c)
+			}
+			f.Columns = append(f.Columns, c)
+		}
+	}
+
+	for _, s := range splitList(strings.ToUpper(q.Status)) {
+		if !importStatuses[s] && s != StatusPaymentRequired {
+			return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidExport, s)
+		}
+		f.Statuses = append(f.Statuses, s)
+	}
+
+	var err error
+	if f.CheckInFrom, err = exportDate(q.CheckInFrom, 0); err != nil {
+		return nil, err
+	}
+	if f.CheckInBefore, err = exportDate(q.CheckInTo, 1); err != nil {
+		return nil, err
+	}
+	if f.CreatedFrom, err = exportDate(q.CreatedFrom, 0); err != nil {
+		return nil, err
+	}
+	if f.CreatedBefore, err = exportDate(q.CreatedTo, 1); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// ExportReservations streams the reservations of the filter to w, fetching
+// them from a cursor so that memory doesn't grow with the export. Guest data
+// is decrypted when decrypt is set and redacted otherwise. Once rows are
+// written, a failure can only truncate the export.
+func (s *ReservationService) ExportReservations(f *ExportFilter, decrypt bool, w io.Writer) error {
+	columns := f.Columns
+	var paths []string
+	if contains(columns, priceElementsColumn) {
+		var err error
+		if paths, err = s.repo.GetPriceElementPaths(f); err != nil {
+			return err
+		}
+	}
+
+	header := make([]string, 0, len(columns)+len(paths))
+	for _, c := range columns {
+		if c != priceElementsColumn {
+			header = append(header, c)
+			continue
+		}
+		for _, p := range paths {
+			header = append(header, priceElementsColumn+"."+p)
+		}
+	}
+
+	writer := newExportWriter(f.Format, w)
+	if err := writer.header(header); err != nil {
+		return err
+	}
+
+	values := make([]interface{}, 0, len(header))
+	err := s.repo.ExportReservations(f, exportFetchSize, func(r *Reservation) error {
+		if contains(columns, "guest_data") {
+			s.revealGuestData(r.GuestData, decrypt)
+		}
+
+		values = values[:0]
+		for _, c := range columns {
+			if c != priceElementsColumn {
+				values = append(values, exportValue(r, c))
+				continue
+			}
+			flat := flatten(r.PriceElements)
+			for _, p := range paths {
+				values = append(values, flat[p])
+			}
+		}
+		return writer.row(values)
+	}, writer.flush)
+	if err != nil {
+		return err
+	}
+
+	return writer.close()
+}
+
+// revealGuestData decrypts the guest data of an exported reservation, or
+// redacts it when it may not or can't be decrypted
+func (s *ReservationService) revealGuestData(data map[string]interface{}, decrypt bool) {
+	if decrypt {
+		if err := s.guestData.Decrypt(context.Background(), data); err == nil {
+			return
+		}
+	}
+	s.guestData.Redact(data)
+}
+
+// exportValue returns the value of a column of a reservation: a number, a
+// string or nil
+func exportValue(r *Reservation, column string) interface{} {
+	switch column {
+	case "id":
+		return r.ID
+	case "property_id":
+		return r.PropertyID
+	case "room_type_id":
+		return optionalID(r.RoomTypeID)
+	case "unit_id":
+		return optionalID(r.UnitID)
+	case "group_id":
+		return optionalID(r.GroupID)
+	case "customer_id":
+		return r.CustomerID
+	case "check_in_date":
+		return r.CheckInDate.Format(time.RFC3339)
+	case "check_out_date":
+		return r.CheckOutDate.Format(time.RFC3339)
+	case "nights":
+		return r.Nights()
+	case "status":
+		return r.Status
+	case "kind":
+		return r.Kind
+	case "hold_expires_at":
+		if r.HoldExpiresAt == nil {
+			return nil
+		}
+		return r.HoldExpiresAt.Format(time.RFC3339)
+	case "total_price":
+		return r.TotalPrice
+	case "payment_url":
+		return r.PaymentURL
+	case "no_of_guests":
+		return r.NoOfGuests
+	case "guest_data":
+		return exportJSON(r.GuestData)
+	case "additional_requests":
+		return exportJSON(r.AdditionalRequests)
+	case "created_at":
+		return r.CreatedAt.Format(time.RFC3339)
+	case "updated_at":
+		return r.UpdatedAt.Format(time.RFC3339)
+	}
+	return nil
+}
+
+// optionalID returns an optional id as an int, or nil
+func optionalID(id *int64) interface{} {
+	if id == nil {
+		return nil
+	}
+	return int(*id)
+}
+
+// flatten maps the dotted path of every value nested in objects of m to the
+// value. Arrays are kept whole as JSON.
+func flatten(m map[string]interface{}) map[string]interface{} {
+	flat := map[string]interface{}{}
+	var walk func(prefix string, m map[string]interface{})
+	walk = func(prefix string, m map[string]interface{}) {
+		for k, v := range m {
+			switch v := v.(type) {
+			case map[string]interface{}:
+				walk(prefix+k+".", v)
+			case []interface{}:
+				flat[prefix+k] = exportJSON(v)
+			default:
+				flat[prefix+k] = v
+			}
+		}
+	}
+	walk("", m)
+	return flat
+}
+
+// exportJSON returns v as JSON, or nil for an empty map
+func exportJSON(v interface{}) interface{} {
+	if m, ok := v.(map[string]interface{}); ok && len(m) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return string(b)
+}
+
+// exportDate parses an optional date of an export query, plus days
+func exportDate(value string, days int) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(importDateLayout, value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid date %q", ErrInvalidExport, value)
+	}
+	t = t.AddDate(0, 0, days)
+	return &t, nil
+}
+
+func splitList(value string) []string {
+	var list []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// formatExportValue formats a value for CSV
+func formatExportValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return fmt.Sprint(v)
+}
+
+// exportWriter writes the rows of an export in a format
+type exportWriter interface {
+	header(columns []string) error
+	row(values []interface{}) error
+	// flush writes the buffered rows to the response
+	flush() error
+	close() error
+}
+
+func newExportWriter(format string, w io.Writer) exportWriter {
+	switch format {
+	case ExportNDJSON:
+		return &ndjsonWriter{w: bufio.NewWriter(w), out: w}
+	case ExportXLSX:
+		return newXLSXWriter(w)
+	}
+	return &csvWriter{w: csv.NewWriter(w), out: w}
+}
+
+type csvWriter struct {
+	w      *csv.Writer
+	out    io.Writer
+	record []string
+}
+
+func (c *csvWriter) header(columns []string) error {
+	return c.w.Write(columns)
+}
+
+func (c *csvWriter) row(values []interface{}) error {
+	c.record = c.record[:0]
+	for _, v := range values {
+		c.record = append(c.record, formatExportValue(v))
+	}
+	return c.w.Write(c.record)
+}
+
+func (c *csvWriter) flush() error {
+	c.w.Flush()
+	if err := c.w.Error(); err != nil {
+		return err
+	}
+	return flushResponse(c.out)
+}
+
+func (c *csvWriter) close() error {
+	return c.flush()
+}
+
+// ndjsonWriter writes a JSON object per row, with the keys in column order
+type ndjsonWriter struct {
+	w       *bufio.Writer
+	out     io.Writer
+	columns [][]byte
+}
+
+func (n *ndjsonWriter) header(columns []string) error {
+	for _, c := range columns {
+		key, err := json.Marshal(c)
+		if err != nil {
+			return err
+		}
+		n.columns = append(n.columns, key)
+	}
+	return nil
+}
+
+func (n *ndjsonWriter) row(values []interface{}) error {
+	n.w.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			n.w.WriteByte(',')
+		}
+		n.w.Write(n.columns[i])
+		n.w.WriteByte(':')
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		n.w.Write(b)
+	}
+	n.w.WriteByte('}')
+	return n.w.WriteByte('\n')
+}
+
+func (n *ndjsonWriter) flush() error {
+	if err := n.w.Flush(); err != nil {
+		return err
+	}
+	return flushResponse(n.out)
+}
+
+func (n *ndjsonWriter) close() error {
+	return n.flush()
+}
+
+// flushResponse sends what was written so far to the client, if w is a
+// response
+func flushResponse(w io.Writer) error {
+	if f, ok := w.(interface{ Flush() }); ok {
+		f.Flush()
+	}
+	return nil
+}
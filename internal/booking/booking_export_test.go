@@ -0,0 +1,103 @@
+package booking
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportQueryFilter(t *testing.T) {
+	q := ExportQuery{Columns: "id, status,price_elements", Status: "confirmed,COMPLETED", CheckInTo: "2026-12-31"}
+
+	f, err := q.Filter(100)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(100), f.OrganizationID)
+	assert.Equal(t, ExportCSV, f.Format)
+	assert.Equal(t, []string{"id", "status", "price_elements"}, f.Columns)
+	assert.Equal(t, []string{StatusConfirmed, StatusCompleted}, f.Statuses)
+	assert.Nil(t, f.CheckInFrom)
+	if assert.NotNil(t, f.CheckInBefore) {
+		assert.Equal(t, "2027-01-01", f.CheckInBefore.Format(importDateLayout))
+	}
+
+	f, err = (&ExportQuery{}).Filter(100)
+	require.NoError(t, err)
+	assert.Equal(t, exportColumns, f.Columns)
+	assert.Nil(t, f.Statuses)
+
+	_, err = (&ExportQuery{Columns: "id,password"}).Filter(100)
+	assert.ErrorIs(t, err, ErrInvalidExport)
+
+	_, err = (&ExportQuery{Status: "BOOKED"}).Filter(100)
+	assert.ErrorIs(t, err, ErrInvalidExport)
+}
+
+func TestFlatten(t *testing.T) {
+	flat := flatten(map[string]interface{}{
+		"cleaning":    map[string]interface{}{"amount": 40.0, "vat": map[string]interface{}{"rate": 22.0}},
+		"discount":    -10.0,
+		"extras":      []interface{}{"crib"},
+		"description": "Summer",
+	})
+
+	assert.Equal(t, map[string]interface{}{
+		"cleaning.amount":   40.0,
+		"cleaning.vat.rate": 22.0,
+		"discount":          -10.0,
+		"extras":            `["crib"]`,
+		"description":       "Summer",
+	}, flat)
+}
+
+func writeExport(t *testing.T, format string) []byte {
+	var buf bytes.Buffer
+	w := newExportWriter(format, &buf)
+	require.NoError(t, w.header([]string{"id", "status", "total_price", "price_elements.cleaning.amount"}))
+	require.NoError(t, w.row([]interface{}{7, "CONFIRMED", 480.5, nil}))
+	require.NoError(t, w.flush())
+	require.NoError(t, w.row([]interface{}{8, `A "quoted" <value>`, 0.0, 40.0}))
+	require.NoError(t, w.close())
+	return buf.Bytes()
+}
+
+func TestExportWriters(t *testing.T) {
+	assert.Equal(t,
+		"id,status,total_price,price_elements.cleaning.amount\n"+
+			"7,CONFIRMED,480.5,\n"+
+			"8,\"A \"\"quoted\"\" <value>\",0,40\n",
+		string(writeExport(t, ExportCSV)))
+
+	assert.Equal(t,
+		`{"id":7,"status":"CONFIRMED","total_price":480.5,"price_elements.cleaning.amount":null}`+"\n"+
+			`{"id":8,"status":"A \"quoted\" \u003cvalue\u003e","total_price":0,"price_elements.cleaning.amount":40}`+"\n",
+		string(writeExport(t, ExportNDJSON)))
+}
+
+func TestExportXLSX(t *testing.T) {
+	data := writeExport(t, ExportXLSX)
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	for _, f := range archive.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		files[f.Name] = string(content)
+	}
+
+	assert.Contains(t, files, "[Content_Types].xml")
+	assert.Contains(t, files, "xl/workbook.xml")
+	sheet := files["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<row r="1"><c t="inlineStr"><is><t xml:space="preserve">id</t></is></c>`)
+	assert.Contains(t, sheet, `<row r="2"><c><v>7</v></c><c t="inlineStr"><is><t xml:space="preserve">CONFIRMED</t></is></c><c><v>480.5</v></c><c/></row>`)
+	assert.Contains(t, sheet, `A &#34;quoted&#34; &lt;value&gt;`)
+	assert.True(t, bytes.HasSuffix([]byte(sheet), []byte(`</sheetData></worksheet>`)))
+}
@@ -0,0 +1,382 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hostflow/booking-service/internal/audit"
+	"hostflow/booking-service/internal/guest"
+	"time"
+
+	pb "hostflow/booking-service/internal/communication/proto"
+)
+
+// maxGroupStays is the number of stays a booking group may have
+const maxGroupStays = 20
+
+// Errors returned for booking groups
+var (
+	ErrGroupNotFound  = errors.New("booking group not found")
+	ErrNotInGroup     = errors.New("reservation is not in the booking group")
+	ErrNotCancellable = errors.New("reservation can't be cancelled")
+)
+
+// BookingGroup is a set of stays, possibly at different properties and on
+// different dates, that is booked, priced and paid together. TotalPrice is
+// the sum of the stays that are not cancelled or rejected and Status is
+// their status, or CANCELLED once none is left.
+type BookingGroup struct {
+	ID             int64                 `json:"id" example:"5"`
+	OrganizationID int64                 `json:"organization_id" example:"1"`
+	CustomerID     int                   `json:"customer_id" example:"100"`
+	Status         string                `json:"status" example:"PAYMENT_REQUIRED"`
+	TotalPrice     float64               `json:"total_price" example:"1840.00"`
+	PaymentURL     string                `json:"payment_url"`
+	Reservations   []ReservationResponse `json:"reservations"`
+	CreatedAt      time.Time             `json:"created_at" example:"2024-12-01T09:00:00Z"`
+	UpdatedAt      time.Time             `json:"updated_at" example:"2024-12-01T09:00:00Z"`
+}
+
+// BookingGroupRequest is the body of a booking group creation. All stays
+// are booked for the customer.
+type BookingGroupRequest struct {
+	CustomerID int                `json:"customer_id" binding:"required" example:"100"`
+	Stays      []GroupStayRequest `json:"stays" binding:"required,min=1,max=20,dive"`
+}
+
+// GroupStayRequest is a stay of a booking group
+type GroupStayRequest struct {
+	PropertyID         int                    `json:"property_id" binding:"required" example:"10"`
+	RoomTypeID         *int64                 `json:"room_type_id" binding:"omitempty,min=1" example:"3"`
+	UnitID             *int64                 `json:"unit_id" binding:"omitempty,min=1" example:"12"`
+	CheckInDate        time.Time              `json:"check_in_date" binding:"required" example:"2024-12-20T15:00:00Z"`
+	CheckOutDate       time.Time              `json:"check_out_date" binding:"required" example:"2024-12-25T11:00:00Z"`
+	NoOfGuests         int                    `json:"no_of_guests" binding:"required,min=1" example:"4"`
+	TotalPrice         float64                `json:"total_price" binding:"required,min=0" example:"920.00"`
+	PriceElements      map[string]interface{} `json:"price_elements"`
+	GuestData          map[string]interface{} `json:"guest_data"`
+	AdditionalRequests map[string]interface{} `json:"additional_requests"`
+}
+
+// GroupCancelRequest selects the reservations of a booking group to cancel.
+// Without reservations, the whole group is cancelled.
+type GroupCancelRequest struct {
+	ReservationIDs []int `json:"reservation_ids" example:"48213"`
+}
+
+// CreateBookingGroup books the stays of a group at once: either all stays
+// are available and booked, or none is. A single payment for the total
+// price is then requested for the whole group, and its link is emailed to
+// the customer once.
+func (s *ReservationService) CreateBookingGroup(req *BookingGroupRequest, organizationID int64, actor audit.Actor) (*BookingGroup, error) {
+	if len(req.Stays) > maxGroupStays {
+		return nil, fmt.Errorf("a booking group can have at most %d stays", maxGroupStays)
+	}
+
+	now := time.Now()
+	reservations := make([]*Reservation, len(req.Stays))
+	for i, stay := range req.Stays {
+		res := &Reservation{
+			OrganizationID:     int(organizationID),
+			PropertyID:         stay.PropertyID,
+			RoomTypeID:         stay.RoomTypeID,
+			UnitID:             stay.UnitID,
+			CustomerID:         req.CustomerID,
+			CheckInDate:        stay.CheckInDate,
+			CheckOutDate:       stay.CheckOutDate,
+			Kind:               KindBooking,
+			Status:             StatusCreated,
+			TotalPrice:         stay.TotalPrice,
+			PriceElements:      stay.PriceElements,
+			NoOfGuests:         stay.NoOfGuests,
+			GuestData:          stay.GuestData,
+			AdditionalRequests: stay.AdditionalRequests,
+			CreatedAt:          now,
+			UpdatedAt:          now,
+		}
+		if res.PriceElements == nil {
+			res.PriceElements = make(map[string]interface{})
+		}
+		if res.GuestData == nil {
+			res.GuestData = make(map[string]interface{})
+		}
+		if res.AdditionalRequests == nil {
+			res.AdditionalRequests = make(map[string]interface{})
+		}
+
+		if err := s.guests.Validate(res.GuestData, res.NoOfGuests); err != nil {
+			return nil, prefixGuestErrors(err, fmt.Sprintf("stays[%d].", i))
+		}
+		if err := s.guestData.Encrypt(context.Background(), res.GuestData, nil); err != nil {
+			return nil, err
+		}
+		if err := s.applyTouristTax(res); err != nil {
+			return nil, err
+		}
+		reservations[i] = res
+	}
+
+	group := &BookingGroup{OrganizationID: organizationID, CustomerID: req.CustomerID}
+	err := s.repo.InTx(func(tx *ReservationRepository) error {
+		if err := lockProperties(tx, reservations); err != nil {
+			return err
+		}
+
+		for i, res := range reservations {
+			for j, other := range reservations[:i] {
+				if other.PropertyID == res.PropertyID && sameUnit(res, other) && overlaps(res, other) {
+					return fmt.Errorf("%w: stays %d and %d overlap", ErrPropertyUnavailable, j+1, i+1)
+				}
+			}
+			if err := checkAvailability(tx, res, reservations[:i]); err != nil {
+				return fmt.Errorf("stay %d: %w", i+1, err)
+			}
+		}
+
+		if err := tx.CreateBookingGroup(group); err != nil {
+			return err
+		}
+		if err := s.assignIDs(tx, reservations); err != nil {
+			return err
+		}
+		for i, res := range reservations {
+			res.GroupID = &group.ID
+			created, err := tx.CreateReservation(res)
+			if err != nil {
+				return err
+			}
+			if err := s.recordChange(tx, actor, audit.ActionCreate, nil, created); err != nil {
+				return err
+			}
+			reservations[i] = created
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var total float64
+	for _, res := range reservations {
+		total += res.TotalPrice
+	}
+	paymentURL, err := s.requestPayment(map[string]interface{}{
+		"organizationId": organizationID,
+		"reservationId":  reservations[0].ID,
+		"groupId":        group.ID,
+		"customerId":     req.CustomerID,
+		"amount":         total,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initiate payment: %w", err)
+	}
+
+	err = s.repo.InTx(func(tx *ReservationRepository) error {
+		if err := tx.SetBookingGroupPaymentURL(group.ID, paymentURL); err != nil {
+			return err
+		}
+		for i, res := range reservations {
+			before := *res
+			res.PaymentURL = paymentURL
+			res.Status = StatusPaymentRequired
+			res.UpdatedAt = time.Now()
+
+			saved, err := s.save(tx, res, &before, actor, actionPaymentRequested)
+			if err != nil {
+				return err
+			}
+			reservations[i] = saved
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.sendEmail(reservations[0], pb.EmailType_PAYMENT)
+	for _, res := range reservations {
+		s.stayBooked(res)
+	}
+
+	return s.GetBookingGroup(group.ID, organizationID)
+}
+
+// GetBookingGroup returns a booking group with its reservations
+func (s *ReservationService) GetBookingGroup(id int64, organizationID int64) (*BookingGroup, error) {
+	group, err := s.repo.GetBookingGroup(id, organizationID, false)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, ErrGroupNotFound
+	}
+
+	reservations, err := s.repo.GetGroupReservations(id, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	summarizeGroup(group, reservations)
+
+	return group, nil
+}
+
+// CancelBookingGroup cancels the reservations of a booking group, or all of
+// them when reservationIDs is empty, in a single transaction. Reservations
+// that are already cancelled are skipped. The group total drops by the
+// cancelled stays; refunds are issued with credit notes.
+func (s *ReservationService) CancelBookingGroup(id int64, reservationIDs []int, organizationID int64, actor audit.Actor) (*BookingGroup, error) {
+	var cancelled []Reservation
+	err := s.repo.InTx(func(tx *ReservationRepository) error {
+		group, err := tx.GetBookingGroup(id, organizationID, true)
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			return ErrGroupNotFound
+		}
+
+		members, err := tx.GetGroupReservations(id, organizationID)
+		if err != nil {
+			return err
+		}
+		cancellations, err := groupCancellations(members, reservationIDs)
+		if err != nil {
+			return err
+		}
+
+		for _, res := range cancellations {
+			before := *res
+			res.Status = StatusCancelled
+			res.UpdatedAt = time.Now()
+			if _, err := s.save(tx, res, &before, actor, actionStatusChanged); err != nil {
+				return err
+			}
+			cancelled = append(cancelled, before)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range cancelled {
+		s.releaseDates(&cancelled[i], nil)
+	}
+
+	return s.GetBookingGroup(id, organizationID)
+}
+
+// confirmGroupPayment confirms the reservations of the booking group of a
+// paid reservation that are still waiting for the payment, and sends their
+// confirmations
+func (s *ReservationService) confirmGroupPayment(paid *Reservation) error {
+	var confirmed []*Reservation
+	err := s.repo.InTx(func(tx *ReservationRepository) error {
+		members, err := tx.GetGroupReservations(*paid.GroupID, int64(paid.OrganizationID))
+		if err != nil {
+			return err
+		}
+
+		for i := range members {
+			res := &members[i]
+			if res.Status != StatusCreated && res.Status != StatusPaymentRequired {
+				continue
+			}
+
+			before := *res
+			res.Status = StatusConfirmed
+			res.UpdatedAt = time.Now()
+			saved, err := s.save(tx, res, &before, audit.SystemActor("payments"), actionPaymentConfirmed)
+			if err != nil {
+				return err
+			}
+			confirmed = append(confirmed, saved)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to confirm booking group %d: %w", *paid.GroupID, err)
+	}
+
+	for _, res := range confirmed {
+		s.sendEmail(res, pb.EmailType_CONFIRMATION)
+	}
+	return nil
+}
+
+// summarizeGroup sets the reservations, total price and status of a group
+func summarizeGroup(group *BookingGroup, reservations []Reservation) {
+	group.Status = StatusCancelled
+	group.TotalPrice = 0
+	group.Reservations = make([]ReservationResponse, 0, len(reservations))
+
+	active := false
+	for i := range reservations {
+		r := &reservations[i]
+		group.Reservations = append(group.Reservations, *r.ToResponse())
+		if !occupies(r.Status) {
+			continue
+		}
+		if !active {
+			group.Status = r.Status
+			active = true
+		}
+		group.TotalPrice += r.TotalPrice
+	}
+}
+
+// groupCancellations returns the members of a group to cancel: those with
+// the ids, or all that can be cancelled when ids is empty. Members that are
+// already cancelled or rejected are left out.
+func groupCancellations(members []Reservation, ids []int) ([]*Reservation, error) {
+	byID := make(map[int]*Reservation, len(members))
+	for i := range members {
+		byID[members[i].ID] = &members[i]
+	}
+
+	var cancellations []*Reservation
+	if len(ids) == 0 {
+		for i := range members {
+			if cancellable(members[i].Status) {
+				cancellations = append(cancellations, &members[i])
+			}
+		}
+		return cancellations, nil
+	}
+
+	seen := map[int]bool{}
+	for _, id := range ids {
+		res, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: %d", ErrNotInGroup, id)
+		}
+		if seen[id] || !occupies(res.Status) {
+			continue
+		}
+		if !cancellable(res.Status) {
+			return nil, fmt.Errorf("%w: reservation %d is %s", ErrNotCancellable, id, res.Status)
+		}
+		seen[id] = true
+		cancellations = append(cancellations, res)
+	}
+	return cancellations, nil
+}
+
+// cancellable reports whether a reservation with the status can be
+// cancelled
+func cancellable(status string) bool {
+	return occupies(status) && status != StatusCompleted && status != StatusNoShow
+}
+
+// prefixGuestErrors prefixes the fields of guest data validation errors,
+// to tell which of several reservations they belong to
+func prefixGuestErrors(err error, prefix string) error {
+	var invalid *guest.ValidationError
+	if !errors.As(err, &invalid) {
+		return err
+	}
+	for i := range invalid.Errors {
+		invalid.Errors[i].Field = prefix + invalid.Errors[i].Field
+	}
+	return invalid
+}
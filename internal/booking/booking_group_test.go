@@ -0,0 +1,88 @@
+package booking
+
+import (
+	"testing"
+
+	"hostflow/booking-service/internal/audit"
+	"hostflow/booking-service/internal/dbtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func groupMembers() []Reservation {
+	return []Reservation{
+		{ID: 1, Status: StatusPaymentRequired, TotalPrice: 300},
+		{ID: 2, Status: StatusPaymentRequired, TotalPrice: 450},
+		{ID: 3, Status: StatusCancelled, TotalPrice: 200},
+		{ID: 4, Status: StatusCompleted, TotalPrice: 500},
+	}
+}
+
+func TestGroupCancellations(t *testing.T) {
+	ids := func(reservations []*Reservation) []int {
+		var ids []int
+		for _, r := range reservations {
+			ids = append(ids, r.ID)
+		}
+		return ids
+	}
+
+	all, err := groupCancellations(groupMembers(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, ids(all))
+
+	some, err := groupCancellations(groupMembers(), []int{2, 3, 2})
+	require.NoError(t, err)
+	assert.Equal(t, []int{2}, ids(some))
+
+	_, err = groupCancellations(groupMembers(), []int{9})
+	assert.ErrorIs(t, err, ErrNotInGroup)
+
+	_, err = groupCancellations(groupMembers(), []int{1, 4})
+	assert.ErrorIs(t, err, ErrNotCancellable)
+}
+
+func TestSummarizeGroup(t *testing.T) {
+	group := &BookingGroup{ID: 5}
+	summarizeGroup(group, groupMembers()[:3])
+
+	assert.Equal(t, StatusPaymentRequired, group.Status)
+	assert.Equal(t, 750.0, group.TotalPrice)
+	assert.Len(t, group.Reservations, 3)
+
+	summarizeGroup(group, groupMembers()[2:3])
+	assert.Equal(t, StatusCancelled, group.Status)
+	assert.Zero(t, group.TotalPrice)
+}
+
+func TestCreateBookingGroup_BooksAllStaysOrNone(t *testing.T) {
+	db := dbtest.Open(t)
+	service := newDBService(t, db)
+	stubPayments(service, "https://pay.example.test/group")
+
+	stays := []GroupStayRequest{
+		{PropertyID: 10, CheckInDate: day(2025, 6, 1, 14), CheckOutDate: day(2025, 6, 4, 10), NoOfGuests: 2, TotalPrice: 300},
+		{PropertyID: 11, CheckInDate: day(2025, 6, 1, 14), CheckOutDate: day(2025, 6, 4, 10), NoOfGuests: 2, TotalPrice: 450},
+	}
+	group, err := service.CreateBookingGroup(&BookingGroupRequest{CustomerID: 2, Stays: stays}, 100, audit.SystemActor("test"))
+	require.NoError(t, err)
+	assert.Equal(t, "https://pay.example.test/group", group.PaymentURL)
+	assert.Equal(t, 750.0, group.TotalPrice)
+	require.Len(t, group.Reservations, 2)
+	for _, res := range group.Reservations {
+		assert.Equal(t, StatusPaymentRequired, res.Status)
+	}
+
+	// The second stay is taken, so the free first one isn't booked either
+	stays = []GroupStayRequest{
+		{PropertyID: 12, CheckInDate: day(2025, 6, 1, 14), CheckOutDate: day(2025, 6, 4, 10), NoOfGuests: 2, TotalPrice: 300},
+		{PropertyID: 11, CheckInDate: day(2025, 6, 2, 14), CheckOutDate: day(2025, 6, 3, 10), NoOfGuests: 2, TotalPrice: 150},
+	}
+	_, err = service.CreateBookingGroup(&BookingGroupRequest{CustomerID: 2, Stays: stays}, 100, audit.SystemActor("test"))
+	assert.ErrorIs(t, err, ErrPropertyUnavailable)
+
+	taken, err := service.repo.CheckPropertyAvailability(12, day(2025, 6, 1, 14), day(2025, 6, 4, 10))
+	require.NoError(t, err)
+	assert.False(t, taken)
+}
@@ -0,0 +1,347 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hostflow/booking-service/internal/audit"
+	"hostflow/booking-service/pkg/lib"
+	"strconv"
+	"time"
+
+	pb "hostflow/booking-service/internal/communication/proto"
+
+	"go.uber.org/fx"
+)
+
+const (
+	// defaultHoldHours is used when RESERVATION_HOLD_HOURS is not set.
+	defaultHoldHours = 48
+	// holdSweepInterval is how often expired holds are released.
+	holdSweepInterval = time.Minute
+	// holdSweepBatchSize limits how many holds expire per transaction.
+	holdSweepBatchSize = 100
+	// maxCalendarDays is the longest range the calendar returns.
+	maxCalendarDays = 366
+)
+
+// Types of the events published for holds
+const (
+	EventHoldCreated   = "HoldCreated"
+	EventHoldConverted = "HoldConverted"
+	EventHoldReleased  = "HoldReleased"
+	EventHoldExpired   = "HoldExpired"
+)
+
+// Errors returned for holds
+var (
+	ErrNotHold              = errors.New("reservation is not an active hold")
+	ErrHoldExpired          = errors.New("the hold has expired")
+	ErrHoldActive           = errors.New("the reservation is a hold: convert or release it")
+	ErrInvalidHoldExpiry    = errors.New("expires_at must be in the future")
+	ErrInvalidCalendarRange = errors.New("invalid calendar range")
+)
+
+// HoldRequest is the body of a hold creation: the stay of a reservation and
+// when the hold expires, by default RESERVATION_HOLD_HOURS from now
+type HoldRequest struct {
+	ReservationRequest
+	ExpiresAt *time.Time `json:"expires_at" example:"2024-12-03T09:00:00Z"`
+}
+
+// CalendarFilter selects the calendar of an organization
+type CalendarFilter struct {
+	From       string `form:"from" binding:"required" example:"2024-12-01"`
+	To         string `form:"to" binding:"required" example:"2025-01-01"`
+	PropertyID *int64 `form:"property_id" binding:"omitempty,min=1" example:"10"`
+}
+
+// CalendarEntry is a stay taking dates in the calendar: a booking, or a hold
+// with the time it expires
+type CalendarEntry struct {
+	ReservationID int        `json:"reservation_id" db:"id" example:"1"`
+	PropertyID    int        `json:"property_id" db:"property_id" example:"10"`
+	RoomTypeID    *int64     `json:"room_type_id,omitempty" db:"room_type_id" example:"3"`
+	UnitID        *int64     `json:"unit_id,omitempty" db:"unit_id" example:"12"`
+	CustomerID    int        `json:"customer_id" db:"customer_id" example:"100"`
+	CheckInDate   time.Time  `json:"check_in_date" db:"check_in_date" example:"2024-12-20T15:00:00Z"`
+	CheckOutDate  time.Time  `json:"check_out_date" db:"check_out_date" example:"2024-12-25T11:00:00Z"`
+	Status        string     `json:"status" db:"status" example:"CONFIRMED"`
+	Kind          string     `json:"kind" db:"kind" example:"HOLD" enums:"BOOKING,HOLD"`
+	HoldExpiresAt *time.Time `json:"hold_expires_at,omitempty" db:"hold_expires_at" example:"2024-12-03T09:00:00Z"`
+}
+
+// HoldEvent is the payload of the hold events
+type HoldEvent struct {
+	ReservationID  int        `json:"reservationId"`
+	OrganizationID int        `json:"organizationId"`
+	PropertyID     int        `json:"propertyId"`
+	CustomerID     int        `json:"customerId"`
+	CheckInDate    time.Time  `json:"checkInDate"`
+	CheckOutDate   time.Time  `json:"checkOutDate"`
+	HoldExpiresAt  *time.Time `json:"holdExpiresAt,omitempty"`
+}
+
+// holdDuration parses RESERVATION_HOLD_HOURS, falling back to
+// defaultHoldHours when it is unset or invalid
+func holdDuration(value string) time.Duration {
+	hours := defaultHoldHours
+	if v, err := strconv.Atoi(value); err == nil && v > 0 {
+		hours = v
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// holdExpiry returns when a hold requested at now expires: at the requested
+// time, which must be in the future, or after holdFor
+func holdExpiry(requested *time.Time, now time.Time, holdFor time.Duration) (time.Time, error) {
+	if requested == nil {
+		return now.Add(holdFor), nil
+	}
+	if !requested.After(now) {
+		return time.Time{}, ErrInvalidHoldExpiry
+	}
+	return *requested, nil
+}
+
+// isActiveHold reports whether a reservation is a hold still blocking its
+// stay: it wasn't converted, released or expired yet
+func isActiveHold(res *Reservation) bool {
+	return res.Kind == KindHold && res.Status == StatusCreated
+}
+
+// holdExpired reports whether an active hold is past its expiry at now,
+// although the sweeper may not have released it yet
+func holdExpired(res *Reservation, now time.Time) bool {
+	return res.HoldExpiresAt != nil && !res.HoldExpiresAt.After(now)
+}
+
+// calendarRange parses the dates (YYYY-MM-DD) of a calendar filter
+func calendarRange(f *CalendarFilter) (time.Time, time.Time, error) {
+	from, err := time.Parse(importDateLayout, f.From)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: from must be a date (YYYY-MM-DD)", ErrInvalidCalendarRange)
+	}
+	to, err := time.Parse(importDateLayout, f.To)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: to must be a date (YYYY-MM-DD)", ErrInvalidCalendarRange)
+	}
+	if !to.After(from) || to.After(from.AddDate(0, 0, maxCalendarDays)) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: to must be after from, at most %d days later", ErrInvalidCalendarRange, maxCalendarDays)
+	}
+	return from, to, nil
+}
+
+// ======== HOLDS ========
+
+// CreateHold puts the stay of a request on hold for a prospective guest. The
+// hold blocks availability like a booking, without a payment, until it
+// expires or is converted into a booking or released.
+func (s *ReservationService) CreateHold(req *HoldRequest, organizationID int64, actor audit.Actor) (*Reservation, error) {
+	expiresAt, err := holdExpiry(req.ExpiresAt, time.Now(), s.holdFor)
+	if err != nil {
+		return nil, err
+	}
+
+	hold := newReservation(&req.ReservationRequest, organizationID, KindHold)
+	hold.HoldExpiresAt = &expiresAt
+
+	created, err := s.insertReservation(hold, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishHold(EventHoldCreated, created)
+	return created, nil
+}
+
+// ConvertHold turns an active hold into a booking of the prospective guest,
+// which then asks for the payment like a created reservation
+func (s *ReservationService) ConvertHold(id int, organizationID int64, actor audit.Actor) (*Reservation, error) {
+	converted, err := s.updateHold(id, organizationID, actor, actionHoldConverted, func(res *Reservation) error {
+		if holdExpired(res, time.Now()) {
+			return ErrHoldExpired
+		}
+		res.Kind = KindBooking
+		res.HoldExpiresAt = nil
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.publishHold(EventHoldConverted, converted)
+
+	paymentUrl, err := s.initiatePayment(converted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initiate payment: %w", err)
+	}
+
+	before := *converted
+	converted.PaymentURL = paymentUrl
+	converted.Status = StatusPaymentRequired
+	converted.UpdatedAt = time.Now()
+
+	updated, err := s.saveReservation(converted, &before, actor, actionPaymentRequested)
+	if err != nil {
+		return nil, err
+	}
+
+	s.sendEmail(updated, pb.EmailType_PAYMENT)
+	s.stayBooked(updated)
+
+	return updated, nil
+}
+
+// ReleaseHold cancels an active hold, which frees its stay
+func (s *ReservationService) ReleaseHold(id int, organizationID int64, actor audit.Actor) (*Reservation, error) {
+	released, err := s.updateHold(id, organizationID, actor, actionHoldReleased, func(res *Reservation) error {
+		res.Status = StatusCancelled
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishHold(EventHoldReleased, released)
+	return released, nil
+}
+
+// ExpireHolds cancels the active holds past their expiry, one batch per
+// transaction, and returns how many expired. Their stays are freed.
+func (s *ReservationService) ExpireHolds() (int, error) {
+	var total int
+	for {
+		var expired []*Reservation
+		var before []Reservation
+		err := s.repo.InTx(func(tx *ReservationRepository) error {
+			holds, err := tx.GetExpiredHolds(holdSweepBatchSize)
+			if err != nil {
+				return err
+			}
+
+			before = holds
+			expired = make([]*Reservation, 0, len(holds))
+			for i := range holds {
+				hold := holds[i]
+				hold.Status = StatusCancelled
+				hold.UpdatedAt = time.Now()
+
+				saved, err := s.save(tx, &hold, &holds[i], audit.SystemActor("holds"), actionHoldExpired)
+				if err != nil {
+					return err
+				}
+				expired = append(expired, saved)
+			}
+			return nil
+		})
+		if err != nil {
+			return total, err
+		}
+
+		for i, hold := range expired {
+			s.releaseDates(&before[i], hold)
+			s.publishHold(EventHoldExpired, hold)
+		}
+
+		total += len(expired)
+		if len(expired) < holdSweepBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// updateHold changes an active hold with change and saves it with the audit
+// action, in a single transaction. The hold is locked, so that it can't be
+// converted, released and expired at once.
+func (s *ReservationService) updateHold(id int, organizationID int64, actor audit.Actor, action string, change func(*Reservation) error) (*Reservation, error) {
+	var before, saved *Reservation
+	err := s.repo.InTx(func(tx *ReservationRepository) error {
+		hold, err := tx.GetReservationForUpdate(id, organizationID)
+		if err != nil {
+			return err
+		}
+		if hold == nil {
+			return ErrReservationNotFound
+		}
+		if !isActiveHold(hold) {
+			return ErrNotHold
+		}
+
+		locked := *hold
+		before = &locked
+		if err := change(hold); err != nil {
+			return err
+		}
+		hold.UpdatedAt = time.Now()
+
+		saved, err = s.save(tx, hold, before, actor, action)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.releaseDates(before, saved)
+	return saved, nil
+}
+
+// publishHold publishes an event about a hold. Like emails, a failure never
+// fails the change itself.
+func (s *ReservationService) publishHold(eventType string, hold *Reservation) {
+	err := s.events.Publish(eventType, strconv.Itoa(hold.ID), HoldEvent{
+		ReservationID:  hold.ID,
+		OrganizationID: hold.OrganizationID,
+		PropertyID:     hold.PropertyID,
+		CustomerID:     hold.CustomerID,
+		CheckInDate:    hold.CheckInDate,
+		CheckOutDate:   hold.CheckOutDate,
+		HoldExpiresAt:  hold.HoldExpiresAt,
+	})
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to publish %s for reservation %d:", eventType, hold.ID), err)
+	}
+}
+
+// ======== CALENDAR ========
+
+// GetCalendar returns the bookings and active holds of an organization whose
+// stay overlaps the range of the filter
+func (s *ReservationService) GetCalendar(f *CalendarFilter, organizationID int64) ([]CalendarEntry, error) {
+	from, to, err := calendarRange(f)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetCalendar(organizationID, f.PropertyID, from, to)
+}
+
+// ======== SWEEPER ========
+
+// HoldSweeper releases the holds that expired without being converted
+type HoldSweeper struct {
+	service Service
+	logger  lib.Logger
+}
+
+// GetHoldSweeper returns a HoldSweeper
+func GetHoldSweeper(service Service, logger lib.Logger) *HoldSweeper {
+	return &HoldSweeper{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// tick expires the holds past their expiry
+func (h *HoldSweeper) tick(context.Context) {
+	expired, err := h.service.ExpireHolds()
+	if err != nil {
+		h.logger.Error("Failed to expire holds:", err)
+	}
+	if expired > 0 {
+		h.logger.Info(fmt.Sprintf("Expired %d holds", expired))
+	}
+}
+
+// RegisterHoldSweeperHooks starts the hold sweeper with the application
+func RegisterHoldSweeperHooks(lifecycle fx.Lifecycle, sweeper *HoldSweeper) {
+	lib.NewWorker(holdSweepInterval, sweeper.tick).Start(lifecycle)
+}
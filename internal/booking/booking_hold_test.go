@@ -0,0 +1,124 @@
+package booking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hostflow/booking-service/internal/audit"
+	"hostflow/booking-service/internal/dbtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHoldDuration(t *testing.T) {
+	assert.Equal(t, 48*time.Hour, holdDuration(""))
+	assert.Equal(t, 72*time.Hour, holdDuration("72"))
+	assert.Equal(t, 48*time.Hour, holdDuration("0"))
+	assert.Equal(t, 48*time.Hour, holdDuration("2d"))
+}
+
+func TestHoldExpiry(t *testing.T) {
+	now := day(2025, 6, 1, 12)
+
+	expiresAt, err := holdExpiry(nil, now, 48*time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, day(2025, 6, 3, 12), expiresAt)
+
+	requested := day(2025, 6, 1, 18)
+	expiresAt, err = holdExpiry(&requested, now, 48*time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, requested, expiresAt)
+
+	_, err = holdExpiry(&now, now, 48*time.Hour)
+	assert.ErrorIs(t, err, ErrInvalidHoldExpiry)
+}
+
+func TestActiveHold(t *testing.T) {
+	now := day(2025, 6, 1, 12)
+	expiresAt := day(2025, 6, 2, 12)
+
+	hold := &Reservation{Kind: KindHold, Status: StatusCreated, HoldExpiresAt: &expiresAt}
+	assert.True(t, isActiveHold(hold))
+	assert.False(t, holdExpired(hold, now))
+	// Istekla začasna rezervacija je še aktivna, dokler je ne sprosti čistilec
+	assert.True(t, holdExpired(hold, expiresAt))
+
+	assert.False(t, isActiveHold(&Reservation{Kind: KindHold, Status: StatusCancelled}))
+	assert.False(t, isActiveHold(&Reservation{Kind: KindBooking, Status: StatusCreated}))
+}
+
+func TestCalendarRange(t *testing.T) {
+	from, to, err := calendarRange(&CalendarFilter{From: "2025-06-01", To: "2025-07-01"})
+	assert.NoError(t, err)
+	assert.Equal(t, day(2025, 6, 1, 0), from)
+	assert.Equal(t, day(2025, 7, 1, 0), to)
+
+	for _, f := range []CalendarFilter{
+		{From: "2025-06-01", To: "2025-06-01"},
+		{From: "2025-06-01", To: "2026-06-03"},
+		{From: "1.6.2025", To: "2025-07-01"},
+	} {
+		_, _, err := calendarRange(&f)
+		assert.ErrorIs(t, err, ErrInvalidCalendarRange, f.From+" "+f.To)
+	}
+}
+
+func TestSummarizeCustomerStays_SkipsHolds(t *testing.T) {
+	now := day(2025, 6, 1, 12)
+	reservations := []Reservation{
+		{ID: 1, Kind: KindHold, Status: StatusCreated, CheckInDate: day(2025, 7, 1, 15), CheckOutDate: day(2025, 7, 3, 10)},
+		{ID: 2, Kind: KindHold, Status: StatusCancelled, CheckInDate: day(2025, 8, 1, 15), CheckOutDate: day(2025, 8, 3, 10)},
+		{ID: 3, Kind: KindBooking, Status: StatusConfirmed, CheckInDate: day(2025, 9, 1, 15), CheckOutDate: day(2025, 9, 3, 10)},
+	}
+
+	summary := summarizeCustomerStays(42, reservations, now)
+
+	assert.Zero(t, summary.Cancellations)
+	if assert.Len(t, summary.UpcomingStays, 1) {
+		assert.Equal(t, 3, summary.UpcomingStays[0].ID)
+	}
+}
+
+func TestReleaseAndExpireHolds_FreeTheirStay(t *testing.T) {
+	db := dbtest.Open(t)
+	service := newDBService(t, db)
+	actor := audit.SystemActor("test")
+	checkIn, checkOut := day(2030, 6, 1, 14), day(2030, 6, 4, 10)
+
+	hold := func(propertyID int) (*Reservation, error) {
+		return service.CreateHold(&HoldRequest{ReservationRequest: *stayRequest(propertyID, checkIn, checkOut)}, 100, actor)
+	}
+	available := func(propertyID int) bool {
+		t.Helper()
+		ok, err := service.repo.IsAvailable(100, int64(propertyID), checkIn, checkOut, 2)
+		require.NoError(t, err)
+		return ok
+	}
+
+	released, err := hold(10)
+	require.NoError(t, err)
+	expiring, err := hold(11)
+	require.NoError(t, err)
+	assert.False(t, available(10))
+	assert.False(t, available(11))
+
+	_, err = service.ReleaseHold(released.ID, 100, actor)
+	require.NoError(t, err)
+	assert.True(t, available(10))
+
+	_, err = db.Exec(context.Background(), `UPDATE reservation SET hold_expires_at = NOW() - INTERVAL '1 minute' WHERE id = $1`, expiring.ID)
+	require.NoError(t, err)
+	expired, err := service.ExpireHolds()
+	require.NoError(t, err)
+	assert.Equal(t, 1, expired)
+	assert.True(t, available(11))
+
+	// The freed stays can be booked again
+	for _, propertyID := range []int{10, 11} {
+		_, err := service.insertReservation(newReservation(stayRequest(propertyID, checkIn, checkOut), 100, KindBooking), actor)
+		assert.NoError(t, err, "property %d", propertyID)
+	}
+	assert.Len(t, service.waitlist.(*fakeWaitlist).released, 2)
+}
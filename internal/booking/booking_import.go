@@ -0,0 +1,606 @@
+package booking
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hostflow/booking-service/internal/audit"
+	"hostflow/booking-service/internal/guest"
+	"io"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "hostflow/booking-service/internal/communication/proto"
+	custpb "hostflow/booking-service/internal/customer/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// importBatchSize is the number of reservations copied and committed
+	// per transaction
+	importBatchSize = 500
+	// maxImportRows caps the rows of a single import file
+	maxImportRows = 10000
+	// importDateLayout is accepted for dates next to RFC 3339
+	importDateLayout = "2006-01-02"
+)
+
+// actionImported is the audit action of an imported reservation
+const actionImported = "import"
+
+// ErrInvalidImportFile is returned for a file that isn't a CSV file or a
+// workbook with the required columns, or that has too many rows
+var ErrInvalidImportFile = errors.New("invalid import file")
+
+// importColumns are the columns an import file may have. A row needs either
+// customer_id or customer_email; all other columns not listed in
+// requiredImportColumns are optional.
+var importColumns = map[string]bool{
+	"property_id": true, "customer_id": true, "customer_email": true, "customer_name": true,
+	"check_in_date": true, "check_out_date": true, "no_of_guests": true, "total_price": true,
+	"status": true, "price_elements": true, "guest_data": true, "additional_requests": true,
+	"created_at": true, "room_type_id": true, "unit_id": true,
+}
+
+var requiredImportColumns = []string{"property_id", "check_in_date", "check_out_date", "no_of_guests", "total_price"}
+
+// importStatuses are the statuses an imported reservation may have.
+// PAYMENT_REQUIRED is only set by initiating a payment.
+var importStatuses = map[string]bool{
+	StatusCreated: true, StatusConfirmed: true, StatusRejected: true,
+	StatusCancelled: true, StatusCompleted: true, StatusNoShow: true,
+}
+
+// ImportOptions controls an import
+type ImportOptions struct {
+	// DryRun validates the file without writing anything
+	DryRun bool `form:"dry_run" example:"true"`
+	// SkipPayment leaves CREATED reservations without a payment, for
+	// historical imports
+	SkipPayment bool `form:"skip_payment" example:"false"`
+}
+
+// ImportResult is the outcome of an import, with a result per row. When any
+// row is invalid, nothing is imported.
+type ImportResult struct {
+	DryRun   bool              `json:"dry_run" example:"false"`
+	Total    int               `json:"total" example:"120"`
+	Valid    int               `json:"valid" example:"119"`
+	Invalid  int               `json:"invalid" example:"1"`
+	Imported int               `json:"imported" example:"0"`
+	Rows     []ImportRowResult `json:"rows"`
+}
+
+// ImportRowResult is the outcome of a row of an import file. Line is the
+// line in the file, the header being line 1.
+type ImportRowResult struct {
+	Line          int      `json:"line" example:"2"`
+	ReservationID int      `json:"reservation_id,omitempty" example:"48213"`
+	CustomerID    int      `json:"customer_id,omitempty" example:"100"`
+	NewCustomer   bool     `json:"new_customer,omitempty" example:"false"`
+	Status        string   `json:"status,omitempty" example:"CONFIRMED"`
+	TotalPrice    float64  `json:"total_price,omitempty" example:"500.00"`
+	PaymentURL    string   `json:"payment_url,omitempty"`
+	PaymentError  string   `json:"payment_error,omitempty"`
+	Errors        []string `json:"errors,omitempty" example:"check_out_date must be after check_in_date"`
+}
+
+// importRow is a parsed row of an import file
+type importRow struct {
+	result        *ImportRowResult
+	reservation   *Reservation
+	customerEmail string
+	customerName  string
+}
+
+func (r *importRow) fail(format string, args ...interface{}) {
+	r.result.Errors = append(r.result.Errors, fmt.Sprintf(format, args...))
+}
+
+// ImportReservations imports the reservations of a CSV file or of the first
+// sheet of an XLSX workbook. Every row is validated like a created
+// reservation and against the other rows of the file; only when all rows are
+// valid, and the import is not a dry run, are the reservations copied into
+// the database in batches. CREATED reservations then get a payment unless
+// opts.SkipPayment is set, while reservations imported with any other status
+// are taken as they are.
+func (s *ReservationService) ImportReservations(file io.Reader, opts ImportOptions, organizationID int64, actor audit.Actor) (*ImportResult, error) {
+	rows, err := parseImport(file, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	customers := map[string]int{}
+	booked := map[int][]*importRow{}
+	for _, row := range rows {
+		if err := s.validateImportRow(row, customers, booked); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &ImportResult{DryRun: opts.DryRun, Total: len(rows), Rows: make([]ImportRowResult, len(rows))}
+	for i, row := range rows {
+		if len(row.result.Errors) > 0 {
+			result.Invalid++
+		} else {
+			result.Valid++
+		}
+		result.Rows[i] = *row.result
+	}
+	if opts.DryRun || result.Invalid > 0 {
+		return result, nil
+	}
+
+	if err := s.createImportCustomers(rows, organizationID, customers); err != nil {
+		return nil, err
+	}
+
+	for start := 0; start < len(rows); start += importBatchSize {
+		batch := rows[start:min(start+importBatchSize, len(rows))]
+		if err := s.importBatch(batch, actor); err != nil {
+			return nil, fmt.Errorf("failed to import rows from line %d: %w", batch[0].result.Line, err)
+		}
+		result.Imported += len(batch)
+
+		if !opts.SkipPayment {
+			s.requestImportPayments(batch, actor)
+		}
+	}
+
+	for i, row := range rows {
+		result.Rows[i] = *row.result
+	}
+	return result, nil
+}
+
+// parseImport reads the rows of an import file, a CSV file or a workbook.
+// Values that can't be parsed are reported on their row; only an unreadable
+// file is an error.
+func parseImport(file io.Reader, organizationID int64) ([]*importRow, error) {
+	buffered := bufio.NewReader(file)
+	var read func() (int, []string, error)
+	spreadsheet := false
+	if magic, _ := buffered.Peek(len(xlsxMagic)); string(magic) == xlsxMagic {
+		reader, err := newXLSXReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+		}
+		read = reader.Read
+		spreadsheet = true
+	} else {
+		reader := csv.NewReader(buffered)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		read = func() (int, []string, error) {
+			record, err := reader.Read()
+			if err != nil {
+				return 0, nil, err
+			}
+			line, _ := reader.FieldPos(0)
+			return line, record, nil
+		}
+	}
+
+	_, header, err := read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: missing header: %v", ErrInvalidImportFile, err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !importColumns[name] {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidImportFile, name)
+		}
+		columns[name] = i
+	}
+	for _, name := range requiredImportColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q", ErrInvalidImportFile, name)
+		}
+	}
+	_, hasID := columns["customer_id"]
+	_, hasEmail := columns["customer_email"]
+	if !hasID && !hasEmail {
+		return nil, fmt.Errorf("%w: missing column \"customer_id\" or \"customer_email\"", ErrInvalidImportFile)
+	}
+
+	var rows []*importRow
+	for {
+		line, record, err := read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+		}
+		if len(rows) == maxImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", ErrInvalidImportFile, maxImportRows)
+		}
+
+		value := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			v := strings.TrimSpace(record[i])
+			if spreadsheet && importDateColumns[name] {
+				v = spreadsheetDate(v)
+			}
+			return v
+		}
+		rows = append(rows, parseImportRow(line, value, organizationID))
+	}
+
+	return rows, nil
+}
+
+// parseImportRow builds the reservation of a row from its values
+func parseImportRow(line int, value func(string) string, organizationID int64) *importRow {
+	now := time.Now()
+	row := &importRow{
+		result: &ImportRowResult{Line: line},
+		reservation: &Reservation{
+			OrganizationID: int(organizationID),
+			Kind:           KindBooking,
+			Status:         StatusCreated,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		},
+		customerEmail: strings.ToLower(value("customer_email")),
+		customerName:  value("customer_name"),
+	}
+	res := row.reservation
+
+	parseInt := func(name string, required bool) int {
+		v := value(name)
+		if v == "" {
+			if required {
+				row.fail("%s is required", name)
+			}
+			return 0
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			row.fail("%s must be an integer", name)
+		}
+		return n
+	}
+	parseTime := func(name string, required bool) time.Time {
+		v := value(name)
+		if v == "" {
+			if required {
+				row.fail("%s is required", name)
+			}
+			return time.Time{}
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			if t, err = time.Parse(importDateLayout, v); err != nil {
+				row.fail("%s must be a date (YYYY-MM-DD) or an RFC 3339 time", name)
+			}
+		}
+		return t
+	}
+	parseJSON := func(name string) map[string]interface{} {
+		m := map[string]interface{}{}
+		if v := value(name); v != "" {
+			if err := json.Unmarshal([]byte(v), &m); err != nil {
+				row.fail("%s must be a JSON object", name)
+			}
+		}
+		return m
+	}
+
+	parseID := func(name string) *int64 {
+		if value(name) == "" {
+			return nil
+		}
+		id := int64(parseInt(name, false))
+		if id < 1 {
+			row.fail("%s must be positive", name)
+		}
+		return &id
+	}
+
+	res.PropertyID = parseInt("property_id", true)
+	res.RoomTypeID = parseID("room_type_id")
+	res.UnitID = parseID("unit_id")
+	res.CustomerID = parseInt("customer_id", false)
+	res.CheckInDate = parseTime("check_in_date", true)
+	res.CheckOutDate = parseTime("check_out_date", true)
+	res.NoOfGuests = parseInt("no_of_guests", true)
+	res.PriceElements = parseJSON("price_elements")
+	res.GuestData = parseJSON("guest_data")
+	res.AdditionalRequests = parseJSON("additional_requests")
+	if createdAt := parseTime("created_at", false); !createdAt.IsZero() {
+		res.CreatedAt = createdAt
+	}
+
+	if v := value("total_price"); v == "" {
+		row.fail("total_price is required")
+	} else if price, err := strconv.ParseFloat(v, 64); err != nil {
+		row.fail("total_price must be a number")
+	} else {
+		res.TotalPrice = price
+	}
+
+	if v := strings.ToUpper(value("status")); v != "" {
+		if !importStatuses[v] {
+			row.fail("status %s can't be imported", v)
+		}
+		res.Status = v
+	}
+
+	if res.CustomerID == 0 && row.customerEmail == "" {
+		row.fail("customer_id or customer_email is required")
+	}
+	if value("property_id") != "" && res.PropertyID < 1 {
+		row.fail("property_id must be positive")
+	}
+	if value("no_of_guests") != "" && res.NoOfGuests < 1 {
+		row.fail("no_of_guests must be at least 1")
+	}
+	if res.TotalPrice < 0 {
+		row.fail("total_price must not be negative")
+	}
+	if !res.CheckInDate.IsZero() && !res.CheckOutDate.IsZero() && res.Nights() < 1 {
+		row.fail("check_out_date must be at least one night after check_in_date")
+	}
+
+	row.result.CustomerID = res.CustomerID
+	row.result.Status = res.Status
+	return row
+}
+
+// validateImportRow applies the rules of a created reservation to a parsed
+// row: the customer must exist or be creatable, the guests must be valid
+// and the dates must be free, both in the database and among the rows
+// before it. customers caches the customer of an email and booked the
+// reservations of the rows before, by property. Validation failures are
+// reported on the row; only failures to validate are returned.
+func (s *ReservationService) validateImportRow(row *importRow, customers map[string]int, booked map[int][]*importRow) error {
+	res := row.reservation
+
+	if err := s.resolveImportCustomer(row, customers); err != nil {
+		return err
+	}
+
+	if err := s.guests.Validate(res.GuestData, res.NoOfGuests); err != nil {
+		var invalid *guest.ValidationError
+		if !errors.As(err, &invalid) {
+			return err
+		}
+		for _, e := range invalid.Errors {
+			row.fail("%s: %s", e.Field, e.Message)
+		}
+	}
+
+	if len(row.result.Errors) > 0 || !occupies(res.Status) {
+		return nil
+	}
+
+	pending := make([]*Reservation, 0, len(booked[res.PropertyID]))
+	for _, other := range booked[res.PropertyID] {
+		if sameUnit(res, other.reservation) && overlaps(res, other.reservation) {
+			row.fail("%s (overlaps line %d)", ErrPropertyUnavailable, other.result.Line)
+			return nil
+		}
+		pending = append(pending, other.reservation)
+	}
+	if err := checkAvailability(s.repo, res, pending); err != nil {
+		if !unbookable(err) {
+			return err
+		}
+		row.fail("%s", err.Error())
+		return nil
+	}
+
+	booked[res.PropertyID] = append(booked[res.PropertyID], row)
+	return nil
+}
+
+// resolveImportCustomer finds the customer of a row by id, or else by email.
+// A customer that isn't found by email is created on import, if the row has
+// a name.
+func (s *ReservationService) resolveImportCustomer(row *importRow, customers map[string]int) error {
+	ctx := context.Background()
+	orgID := int64(row.reservation.OrganizationID)
+
+	if id := row.reservation.CustomerID; id != 0 {
+		resp, err := s.customers.GetOrganizationCustomer(ctx, &custpb.GetOrganizationCustomerRequest{
+			Id:             int64(id),
+			OrganizationId: orgID,
+		})
+		if status.Code(err) == codes.NotFound || (err == nil && (resp.Customer == nil || resp.Customer.OrganizationId != orgID)) {
+			row.fail("customer %d not found", id)
+			return nil
+		}
+		return err
+	}
+
+	email := row.customerEmail
+	id, known := customers[email]
+	if !known {
+		resp, err := s.customers.SearchCustomers(ctx, &custpb.SearchCustomersRequest{
+			OrganizationId: orgID,
+			Query:          email,
+			Limit:          10,
+		})
+		if err != nil {
+			return err
+		}
+		for _, c := range resp.Customers {
+			if strings.EqualFold(c.Email, email) && c.OrganizationId == orgID {
+				id = int(c.Id)
+				break
+			}
+		}
+		customers[email] = id
+	}
+
+	if id == 0 {
+		if row.customerName == "" {
+			row.fail("customer %s not found, and customer_name is required to create it", email)
+			return nil
+		}
+		row.result.NewCustomer = true
+	}
+	row.reservation.CustomerID = id
+	row.result.CustomerID = id
+	return nil
+}
+
+// createImportCustomers creates the customers of the rows that don't exist
+// yet, once for each email
+func (s *ReservationService) createImportCustomers(rows []*importRow, organizationID int64, customers map[string]int) error {
+	for _, row := range rows {
+		if !row.result.NewCustomer {
+			continue
+		}
+
+		id := customers[row.customerEmail]
+		if id == 0 {
+			resp, err := s.customers.CreateCustomer(context.Background(), &custpb.CreateCustomerRequest{
+				FullName:       row.customerName,
+				Email:          row.customerEmail,
+				OrganizationId: organizationID,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create customer %s: %w", row.customerEmail, err)
+			}
+			id = int(resp.Customer.Id)
+			customers[row.customerEmail] = id
+		}
+		row.reservation.CustomerID = id
+		row.result.CustomerID = id
+	}
+	return nil
+}
+
+// importBatch encrypts and prices the reservations of a batch like created
+// ones, then copies them in a single transaction. The properties are locked
+// and availability checked again, so that reservations made since
+// validation are not double booked.
+func (s *ReservationService) importBatch(batch []*importRow, actor audit.Actor) error {
+	reservations := make([]*Reservation, len(batch))
+	for i, row := range batch {
+		res := row.reservation
+		if err := s.guestData.Encrypt(context.Background(), res.GuestData, nil); err != nil {
+			return err
+		}
+		if err := s.applyTouristTax(res); err != nil {
+			return err
+		}
+		reservations[i] = res
+	}
+
+	return s.repo.InTx(func(tx *ReservationRepository) error {
+		if err := lockProperties(tx, reservations); err != nil {
+			return err
+		}
+
+		var pending []*Reservation
+		for _, res := range reservations {
+			if !occupies(res.Status) {
+				continue
+			}
+			if err := checkAvailability(tx, res, pending); err != nil {
+				return fmt.Errorf("%w: %s", err, res.CheckInDate.Format(importDateLayout))
+			}
+			pending = append(pending, res)
+		}
+
+		if err := s.assignIDs(tx, reservations); err != nil {
+			return err
+		}
+		if err := tx.CopyReservations(reservations); err != nil {
+			return err
+		}
+
+		for i, res := range reservations {
+			if err := s.recordChange(tx, actor, actionImported, nil, res); err != nil {
+				return err
+			}
+			batch[i].result.ReservationID = res.ID
+			batch[i].result.TotalPrice = res.TotalPrice
+		}
+		return nil
+	})
+}
+
+// assignIDs draws random ids like CreateReservation for reservations created
+// together, redrawing those already taken in the database or by the others
+func (s *ReservationService) assignIDs(tx *ReservationRepository, reservations []*Reservation) error {
+	pending := reservations
+	used := map[int]bool{}
+	for len(pending) > 0 {
+		ids := make([]int, len(pending))
+		for i, res := range pending {
+			res.ID = rand.IntN(1000000)
+			for used[res.ID] {
+				res.ID = rand.IntN(1000000)
+			}
+			used[res.ID] = true
+			ids[i] = res.ID
+		}
+
+		taken, err := tx.TakenReservationIDs(ids)
+		if err != nil {
+			return err
+		}
+		var retry []*Reservation
+		for _, res := range pending {
+			if taken[res.ID] {
+				retry = append(retry, res)
+			}
+		}
+		pending = retry
+	}
+	return nil
+}
+
+// requestImportPayments initiates the payment of the CREATED reservations of
+// a committed batch, as CreateReservation does. A failed payment is
+// reported on its row and leaves the reservation CREATED.
+func (s *ReservationService) requestImportPayments(batch []*importRow, actor audit.Actor) {
+	for _, row := range batch {
+		res := row.reservation
+		if res.Status != StatusCreated {
+			continue
+		}
+
+		paymentURL, err := s.initiatePayment(res)
+		if err != nil {
+			row.result.PaymentError = fmt.Sprintf("failed to initiate payment: %v", err)
+			continue
+		}
+
+		before := *res
+		res.PaymentURL = paymentURL
+		res.Status = StatusPaymentRequired
+		res.UpdatedAt = time.Now()
+
+		updated, err := s.saveReservation(res, &before, actor, actionPaymentRequested)
+		if err != nil {
+			row.result.PaymentError = err.Error()
+			continue
+		}
+		row.result.Status = updated.Status
+		row.result.PaymentURL = updated.PaymentURL
+
+		s.sendEmail(updated, pb.EmailType_PAYMENT)
+	}
+}
+
+// occupies reports whether a reservation with the status takes its dates
+func occupies(status string) bool {
+	return status != StatusCancelled && status != StatusRejected
+}
@@ -0,0 +1,195 @@
+package booking
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"hostflow/booking-service/internal/audit"
+	custpb "hostflow/booking-service/internal/customer/proto"
+	"hostflow/booking-service/internal/dbtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// fakeCustomers knows every customer id of organization 100
+type fakeCustomers struct {
+	custpb.CustomerServiceClient
+}
+
+func (fakeCustomers) GetOrganizationCustomer(ctx context.Context, in *custpb.GetOrganizationCustomerRequest, opts ...grpc.CallOption) (*custpb.CustomerResponse, error) {
+	return &custpb.CustomerResponse{Customer: &custpb.Customer{Id: in.Id, OrganizationId: 100}}, nil
+}
+
+func TestParseImport(t *testing.T) {
+	file := "\ufeffProperty_ID,customer_email,customer_name,check_in_date,check_out_date,no_of_guests,total_price,status,price_elements,created_at\n" +
+		"10,Ana@Example.com,Ana Novak,2024-07-01,2024-07-05,2,480.50,confirmed,\"{\"\"cleaning\"\": {\"\"amount\"\": 40}}\",2024-03-02T10:00:00Z\n" +
+		"10,,,2024-07-05,2024-07-05,0,-1,PAYMENT_REQUIRED,{,\n"
+
+	rows, err := parseImport(strings.NewReader(file), 100)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	valid := rows[0]
+	assert.Equal(t, 2, valid.result.Line)
+	assert.Empty(t, valid.result.Errors)
+	assert.Equal(t, "ana@example.com", valid.customerEmail)
+	assert.Equal(t, "Ana Novak", valid.customerName)
+	assert.Equal(t, 100, valid.reservation.OrganizationID)
+	assert.Equal(t, 10, valid.reservation.PropertyID)
+	assert.Equal(t, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), valid.reservation.CheckInDate)
+	assert.Equal(t, 4, valid.reservation.Nights())
+	assert.Equal(t, 480.5, valid.reservation.TotalPrice)
+	assert.Equal(t, StatusConfirmed, valid.reservation.Status)
+	assert.Equal(t, map[string]interface{}{"amount": 40.0}, valid.reservation.PriceElements["cleaning"])
+	assert.Equal(t, time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC), valid.reservation.CreatedAt)
+
+	invalid := rows[1]
+	assert.Equal(t, 3, invalid.result.Line)
+	assert.ElementsMatch(t, []string{
+		"price_elements must be a JSON object",
+		"status PAYMENT_REQUIRED can't be imported",
+		"customer_id or customer_email is required",
+		"no_of_guests must be at least 1",
+		"total_price must not be negative",
+		"check_out_date must be at least one night after check_in_date",
+	}, invalid.result.Errors)
+}
+
+func TestParseImport_InvalidFile(t *testing.T) {
+	for name, file := range map[string]string{
+		"empty":            "",
+		"unknown column":   "property_id,room\n",
+		"missing column":   "property_id,customer_id,check_in_date,check_out_date,no_of_guests\n",
+		"missing customer": "property_id,check_in_date,check_out_date,no_of_guests,total_price\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseImport(strings.NewReader(file), 100)
+			assert.ErrorIs(t, err, ErrInvalidImportFile)
+		})
+	}
+}
+
+// workbook builds an XLSX workbook with the given sheet and, unless empty,
+// shared strings
+func workbook(t *testing.T, sheet, sharedStrings string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	parts := append(xlsxParts[:len(xlsxParts):len(xlsxParts)],
+		struct{ name, content string }{"xl/worksheets/sheet1.xml", sheet})
+	if sharedStrings != "" {
+		parts = append(parts, struct{ name, content string }{"xl/sharedStrings.xml", sharedStrings})
+	}
+	for _, part := range parts {
+		f, err := archive.Create(part.name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(part.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, archive.Close())
+	return buf.Bytes()
+}
+
+func TestParseImport_XLSX(t *testing.T) {
+	var buf bytes.Buffer
+	writer := newXLSXWriter(&buf)
+	require.NoError(t, writer.header([]string{"property_id", "customer_id", "check_in_date", "check_out_date", "no_of_guests", "total_price", "price_elements"}))
+	require.NoError(t, writer.row([]interface{}{10, 7, "2024-07-01", "2024-07-05", 2, 480.5, `{"cleaning": {"amount": 40}}`}))
+	require.NoError(t, writer.close())
+
+	rows, err := parseImport(&buf, 100)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+
+	row := rows[0]
+	assert.Equal(t, 2, row.result.Line)
+	assert.Empty(t, row.result.Errors)
+	assert.Equal(t, 10, row.reservation.PropertyID)
+	assert.Equal(t, 7, row.reservation.CustomerID)
+	assert.Equal(t, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), row.reservation.CheckInDate)
+	assert.Equal(t, 480.5, row.reservation.TotalPrice)
+	assert.Equal(t, map[string]interface{}{"amount": 40.0}, row.reservation.PriceElements["cleaning"])
+}
+
+func TestParseImport_XLSXSharedStringsAndDates(t *testing.T) {
+	// As saved by a spreadsheet application: shared strings, dates typed
+	// into cells stored as serials, empty cells left out and an empty row
+	sheet := `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+		`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>2</v></c>` +
+		`<c r="D1" t="s"><v>3</v></c><c r="E1" t="s"><v>4</v></c><c r="F1" t="s"><v>5</v></c><c r="G1" t="s"><v>6</v></c></row>` +
+		`<row r="3"><c r="A3"><v>10</v></c><c r="B3" t="s"><v>7</v></c><c r="D3"><v>45474</v></c>` +
+		`<c r="E3"><v>45478.416666666664</v></c><c r="F3"><v>2</v></c><c r="G3"><v>480.5</v></c></row>` +
+		`</sheetData></worksheet>`
+	sharedStrings := `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<si><t>property_id</t></si><si><t>customer_email</t></si><si><t>customer_name</t></si>` +
+		`<si><t>check_in_date</t></si><si><t>check_out_date</t></si><si><t>no_of_guests</t></si>` +
+		`<si><r><t>total_</t></r><r><t>price</t></r></si><si><t>Ana@Example.com</t></si></sst>`
+
+	rows, err := parseImport(bytes.NewReader(workbook(t, sheet, sharedStrings)), 100)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+
+	row := rows[0]
+	assert.Equal(t, 3, row.result.Line)
+	assert.Empty(t, row.result.Errors)
+	assert.Equal(t, "ana@example.com", row.customerEmail)
+	assert.Empty(t, row.customerName)
+	assert.Equal(t, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), row.reservation.CheckInDate)
+	assert.Equal(t, time.Date(2024, 7, 5, 10, 0, 0, 0, time.UTC), row.reservation.CheckOutDate)
+	assert.Equal(t, 480.5, row.reservation.TotalPrice)
+}
+
+func TestParseImport_InvalidXLSX(t *testing.T) {
+	for name, file := range map[string][]byte{
+		"not a zip":             []byte(xlsxMagic + "broken"),
+		"empty sheet":           workbook(t, `<worksheet><sheetData/></worksheet>`, ""),
+		"missing shared string": workbook(t, `<worksheet><sheetData><row r="1"><c r="A1" t="s"><v>3</v></c></row></sheetData></worksheet>`, ""),
+		"unknown column":        workbook(t, `<worksheet><sheetData><row r="1"><c r="A1" t="inlineStr"><is><t>room</t></is></c></row></sheetData></worksheet>`, ""),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseImport(bytes.NewReader(file), 100)
+			assert.ErrorIs(t, err, ErrInvalidImportFile)
+		})
+	}
+}
+
+func TestImportReservations_StoresRows(t *testing.T) {
+	db := dbtest.Open(t)
+	service := newDBService(t, db)
+	service.customers = fakeCustomers{}
+
+	// A cancelled reservation doesn't block the imported stays, a
+	// confirmed one does
+	seedReservation(t, db, 1, 10, 3, day(2024, 7, 1, 0), day(2024, 7, 5, 0), StatusCancelled)
+	seedReservation(t, db, 2, 11, 3, day(2024, 7, 1, 0), day(2024, 7, 5, 0), StatusConfirmed)
+
+	file := "property_id,customer_id,check_in_date,check_out_date,no_of_guests,total_price,status\n" +
+		"10,2,2024-07-01,2024-07-05,2,480,confirmed\n" +
+		"10,2,2024-07-05,2024-07-08,2,300,completed\n"
+
+	result, err := service.ImportReservations(strings.NewReader(file), ImportOptions{SkipPayment: true}, 100, audit.SystemActor("test"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Imported)
+	for _, row := range result.Rows {
+		assert.NotZero(t, row.ReservationID)
+	}
+
+	stored, err := service.GetReservationsByCustomer(2, 100)
+	require.NoError(t, err)
+	assert.Len(t, stored, 2)
+
+	file = "property_id,customer_id,check_in_date,check_out_date,no_of_guests,total_price,status\n" +
+		"11,2,2024-07-03,2024-07-06,2,300,confirmed\n"
+
+	result, err = service.ImportReservations(strings.NewReader(file), ImportOptions{SkipPayment: true}, 100, audit.SystemActor("test"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Invalid)
+	assert.Zero(t, result.Imported)
+}
@@ -0,0 +1,287 @@
+package booking
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxImportXLSXSize caps the size of an uploaded workbook, which is read
+// into memory as a zip archive can't be read as a stream
+const maxImportXLSXSize = 32 << 20
+
+// xlsxMagic starts every zip archive, and so every workbook
+const xlsxMagic = "PK\x03\x04"
+
+// importDateColumns are the columns spreadsheets may store as date serials
+var importDateColumns = map[string]bool{"check_in_date": true, "check_out_date": true, "created_at": true}
+
+// spreadsheetEpoch is day zero of the date serials of spreadsheets
+var spreadsheetEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+// String returns the text of a shared or inline string, which is either a
+// plain text or a list of formatted runs
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var b strings.Builder
+	for _, r := range t.Runs {
+		b.WriteString(r.Text)
+	}
+	return b.String()
+}
+
+type xlsxCell struct {
+	Ref    string   `xml:"r,attr"`
+	Type   string   `xml:"t,attr"`
+	Value  string   `xml:"v"`
+	Inline xlsxText `xml:"is"`
+}
+
+type xlsxRow struct {
+	Number int        `xml:"r,attr"`
+	Cells  []xlsxCell `xml:"c"`
+}
+
+// xlsxReader reads the rows of the first sheet of a workbook one by one
+type xlsxReader struct {
+	sheet   *xml.Decoder
+	close   func() error
+	strings []string
+	rows    int
+}
+
+// newXLSXReader opens the first sheet of a workbook
+func newXLSXReader(file io.Reader) (*xlsxReader, error) {
+	data, err := io.ReadAll(io.LimitReader(file, maxImportXLSXSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxImportXLSXSize {
+		return nil, fmt.Errorf("workbook is larger than %d MB", maxImportXLSXSize>>20)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	files := map[string]*zip.File{}
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	sheetName, err := firstSheet(files)
+	if err != nil {
+		return nil, err
+	}
+	sheet, ok := files[sheetName]
+	if !ok {
+		return nil, fmt.Errorf("missing sheet %s", sheetName)
+	}
+
+	x := &xlsxReader{}
+	if shared, ok := files["xl/sharedStrings.xml"]; ok {
+		if x.strings, err = readSharedStrings(shared); err != nil {
+			return nil, err
+		}
+	}
+
+	r, err := sheet.Open()
+	if err != nil {
+		return nil, err
+	}
+	x.sheet = xml.NewDecoder(r)
+	x.close = r.Close
+	return x, nil
+}
+
+// Read returns the number and the values of the next row, or io.EOF after
+// the last one. Missing cells are returned as empty values.
+func (x *xlsxReader) Read() (int, []string, error) {
+	for {
+		token, err := x.sheet.Token()
+		if errors.Is(err, io.EOF) {
+			x.close()
+			return 0, nil, io.EOF
+		}
+		if err != nil {
+			return 0, nil, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+
+		var row xlsxRow
+		if err := x.sheet.DecodeElement(&row, &start); err != nil {
+			return 0, nil, err
+		}
+		x.rows++
+		if row.Number == 0 {
+			row.Number = x.rows
+		}
+		x.rows = row.Number
+
+		var values []string
+		for i, cell := range row.Cells {
+			column := i
+			if cell.Ref != "" {
+				if column, err = xlsxColumn(cell.Ref); err != nil {
+					return 0, nil, err
+				}
+			}
+			if column < len(values) {
+				return 0, nil, fmt.Errorf("cell %s is out of order", cell.Ref)
+			}
+			for len(values) < column {
+				values = append(values, "")
+			}
+			value, err := x.value(cell)
+			if err != nil {
+				return 0, nil, err
+			}
+			values = append(values, value)
+		}
+		return row.Number, values, nil
+	}
+}
+
+func (x *xlsxReader) value(cell xlsxCell) (string, error) {
+	switch cell.Type {
+	case "s":
+		i, err := strconv.Atoi(cell.Value)
+		if err != nil || i < 0 || i >= len(x.strings) {
+			return "", fmt.Errorf("cell %s refers to a missing shared string", cell.Ref)
+		}
+		return x.strings[i], nil
+	case "inlineStr":
+		return cell.Inline.String(), nil
+	case "b":
+		if cell.Value == "1" {
+			return "true", nil
+		}
+		return "false", nil
+	default:
+		return cell.Value, nil
+	}
+}
+
+// firstSheet returns the path of the first sheet of a workbook, following
+// the relationship of the workbook to it
+func firstSheet(files map[string]*zip.File) (string, error) {
+	var workbook struct {
+		Sheets []struct {
+			ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodeXLSXPart(files, "xl/workbook.xml", &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", errors.New("workbook has no sheets")
+	}
+
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodeXLSXPart(files, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return "", err
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].ID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return "", fmt.Errorf("missing relationship %s of the first sheet", workbook.Sheets[0].ID)
+}
+
+func readSharedStrings(f *zip.File) ([]string, error) {
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var values []string
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return values, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == "si" {
+			var text xlsxText
+			if err := decoder.DecodeElement(&text, &start); err != nil {
+				return nil, err
+			}
+			values = append(values, text.String())
+		}
+	}
+}
+
+func decodeXLSXPart(files map[string]*zip.File, name string, v interface{}) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("missing %s", name)
+	}
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return xml.NewDecoder(r).Decode(v)
+}
+
+// xlsxColumn returns the zero based column of a cell reference like AB12
+func xlsxColumn(ref string) (int, error) {
+	column := 0
+	for i, c := range ref {
+		if c < 'A' || c > 'Z' {
+			if i == 0 {
+				break
+			}
+			return column - 1, nil
+		}
+		column = column*26 + int(c-'A') + 1
+	}
+	return 0, fmt.Errorf("invalid cell reference %q", ref)
+}
+
+// spreadsheetDate converts a date serial, as spreadsheets store the dates
+// typed into them, to a date or an RFC 3339 time. Other values are
+// returned as they are.
+func spreadsheetDate(value string) string {
+	serial, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+	t := spreadsheetEpoch.Add(time.Duration(serial * float64(24*time.Hour))).Round(time.Second)
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+		return t.Format(importDateLayout)
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,234 @@
+package booking
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// checkAvailability returns an error unless the stay of res is free,
+// ignoring res itself. On a property without room types the stay takes the
+// whole property. On a property with room types it needs a room type or a
+// unit, whose room type is set on res: every night, the reservations of the
+// type, including those in pending that are not stored yet, must fit in its
+// active units, and a unit can't be assigned twice.
+func checkAvailability(tx *ReservationRepository, res *Reservation, pending []*Reservation) error {
+	if res.RoomTypeID == nil && res.UnitID == nil {
+		hasRoomTypes, err := tx.PropertyHasRoomTypes(res.PropertyID, res.OrganizationID)
+		if err != nil {
+			return err
+		}
+		if hasRoomTypes {
+			return ErrRoomTypeRequired
+		}
+
+		hasConflict, err := tx.CheckPropertyAvailabilityExcluding(res.ID, res.PropertyID, res.CheckInDate, res.CheckOutDate)
+		if err != nil {
+			return err
+		}
+		if hasConflict {
+			return ErrPropertyUnavailable
+		}
+		return nil
+	}
+
+	if res.UnitID != nil {
+		unit, err := tx.GetRoomUnit(*res.UnitID, res.OrganizationID)
+		if err != nil {
+			return err
+		}
+		if unit == nil || unit.PropertyID != res.PropertyID || (res.RoomTypeID != nil && *res.RoomTypeID != unit.RoomTypeID) {
+			return ErrInvalidRoom
+		}
+		if !unit.Active {
+			return fmt.Errorf("%w: unit %d is inactive", ErrPropertyUnavailable, unit.ID)
+		}
+		res.RoomTypeID = &unit.RoomTypeID
+
+		booked, err := tx.IsUnitBooked(unit.ID, res.ID, res.CheckInDate, res.CheckOutDate)
+		if err != nil {
+			return err
+		}
+		if booked {
+			return ErrPropertyUnavailable
+		}
+	}
+
+	inventory, err := tx.GetRoomInventory(*res.RoomTypeID, res.OrganizationID)
+	if err != nil {
+		return err
+	}
+	if inventory == nil || inventory.PropertyID != res.PropertyID {
+		return ErrInvalidRoom
+	}
+	if inventory.MaxGuests > 0 && res.NoOfGuests > inventory.MaxGuests {
+		return ErrTooManyGuests
+	}
+
+	untyped, err := tx.HasUntypedReservations(res.PropertyID, res.ID, res.CheckInDate, res.CheckOutDate)
+	if err != nil {
+		return err
+	}
+	if untyped {
+		return ErrPropertyUnavailable
+	}
+
+	occupancy, err := tx.GetRoomTypeOccupancy(inventory.RoomTypeID, res.ID, res.CheckInDate, res.CheckOutDate)
+	if err != nil {
+		return err
+	}
+	if !fitsInventory(res, occupancy, pending, inventory.Units) {
+		return ErrPropertyUnavailable
+	}
+
+	return nil
+}
+
+// IsAvailable reports whether a stay of guests from checkIn to checkOut could
+// be booked at the property: in the whole property if it has no room types,
+// or else in any of its room types. It implements
+// interfaces.AvailabilityChecker.
+func (r *ReservationRepository) IsAvailable(organizationID, propertyID int64, checkIn, checkOut time.Time, guests int) (bool, error) {
+	stay := &Reservation{
+		OrganizationID: int(organizationID),
+		PropertyID:     int(propertyID),
+		CheckInDate:    checkIn,
+		CheckOutDate:   checkOut,
+		NoOfGuests:     guests,
+	}
+
+	roomTypes, err := r.GetRoomTypeIDs(stay.PropertyID, stay.OrganizationID)
+	if err != nil {
+		return false, err
+	}
+	if len(roomTypes) == 0 {
+		return bookable(checkAvailability(r, stay, nil))
+	}
+	for _, roomTypeID := range roomTypes {
+		stay.RoomTypeID = &roomTypeID
+		if ok, err := bookable(checkAvailability(r, stay, nil)); ok || err != nil {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+// bookable turns the result of checkAvailability into whether the stay can be
+// booked, keeping only failures to check it as errors
+func bookable(err error) (bool, error) {
+	if unbookable(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// fitsInventory reports whether res fits in the units of its room type on
+// every night, next to the stored reservations counted in occupancy (by
+// night, YYYY-MM-DD) and the pending reservations of the same room type
+func fitsInventory(res *Reservation, occupancy map[string]int, pending []*Reservation, units int) bool {
+	checkOut := calendarDay(res.CheckOutDate)
+	for night := calendarDay(res.CheckInDate); night.Before(checkOut); night = night.AddDate(0, 0, 1) {
+		taken := occupancy[night.Format(importDateLayout)]
+		for _, other := range pending {
+			if other.RoomTypeID == nil || *other.RoomTypeID != *res.RoomTypeID {
+				continue
+			}
+			if !calendarDay(other.CheckInDate).After(night) && calendarDay(other.CheckOutDate).After(night) {
+				taken++
+			}
+		}
+		if taken >= units {
+			return false
+		}
+	}
+	return true
+}
+
+// calendarDay returns the calendar date of t, as Nights counts them
+func calendarDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// sameUnit reports whether two reservations of a property take the same
+// unit: the whole property when neither has a room type or unit, or the
+// same assigned unit
+func sameUnit(a, b *Reservation) bool {
+	if a.UnitID != nil || b.UnitID != nil {
+		return a.UnitID != nil && b.UnitID != nil && *a.UnitID == *b.UnitID
+	}
+	return a.RoomTypeID == nil && b.RoomTypeID == nil
+}
+
+// sameStay reports whether two versions of a reservation take the same stay:
+// the same dates in the same property and room type
+func sameStay(a, b *Reservation) bool {
+	return a.PropertyID == b.PropertyID && sameID(a.RoomTypeID, b.RoomTypeID) &&
+		a.CheckInDate.Equal(b.CheckInDate) && a.CheckOutDate.Equal(b.CheckOutDate)
+}
+
+// unbookable reports whether err is a reason a stay can't be booked, as
+// opposed to a failure to check it
+func unbookable(err error) bool {
+	return errors.Is(err, ErrPropertyUnavailable) || errors.Is(err, ErrRoomTypeRequired) ||
+		errors.Is(err, ErrInvalidRoom) || errors.Is(err, ErrTooManyGuests)
+}
+
+// assignUnit assigns the first free unit of its room type to a confirmed
+// reservation without one. The property is locked so that concurrent
+// confirmations can't take the same unit. When the free nights of the type
+// are spread over several units, no unit is free for the whole stay and the
+// reservation is left unassigned, to be moved or assigned by hand.
+func (s *ReservationService) assignUnit(tx *ReservationRepository, res *Reservation) error {
+	if res.Status != StatusConfirmed || res.RoomTypeID == nil || res.UnitID != nil {
+		return nil
+	}
+
+	if err := tx.LockProperty(res.PropertyID); err != nil {
+		return err
+	}
+	unitID, err := tx.GetFreeUnit(*res.RoomTypeID, res.ID, res.CheckInDate, res.CheckOutDate)
+	if err != nil {
+		return err
+	}
+	if unitID == nil {
+		s.logger.Info(fmt.Sprintf("No unit of room type %d is free for all nights of reservation %d; it is left unassigned", *res.RoomTypeID, res.ID))
+		return nil
+	}
+
+	res.UnitID = unitID
+	return nil
+}
+
+// sameID reports whether two optional ids are equal
+func sameID(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// overlaps reports whether the stays of two reservations overlap
+func overlaps(a, b *Reservation) bool {
+	return a.CheckInDate.Before(b.CheckOutDate) && b.CheckInDate.Before(a.CheckOutDate)
+}
+
+// lockProperties locks the properties of reservations made together, in
+// order of their ids so that concurrent transactions can't deadlock
+func lockProperties(tx *ReservationRepository, reservations []*Reservation) error {
+	properties := map[int]bool{}
+	for _, res := range reservations {
+		properties[res.PropertyID] = true
+	}
+	locks := make([]int, 0, len(properties))
+	for id := range properties {
+		locks = append(locks, id)
+	}
+	sort.Ints(locks)
+	for _, id := range locks {
+		if err := tx.LockProperty(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
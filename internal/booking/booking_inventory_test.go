@@ -0,0 +1,200 @@
+package booking
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"hostflow/booking-service/internal/audit"
+	"hostflow/booking-service/internal/dbtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stay(roomTypeID int64, checkIn, checkOut string) *Reservation {
+	in, _ := time.Parse(importDateLayout, checkIn)
+	out, _ := time.Parse(importDateLayout, checkOut)
+	return &Reservation{RoomTypeID: &roomTypeID, CheckInDate: in, CheckOutDate: out}
+}
+
+func TestFitsInventory(t *testing.T) {
+	res := stay(3, "2026-07-01", "2026-07-04")
+
+	t.Run("free", func(t *testing.T) {
+		assert.True(t, fitsInventory(res, map[string]int{}, nil, 1))
+	})
+
+	t.Run("one night full", func(t *testing.T) {
+		occupancy := map[string]int{"2026-07-01": 1, "2026-07-03": 2}
+		assert.False(t, fitsInventory(res, occupancy, nil, 2))
+		assert.True(t, fitsInventory(res, occupancy, nil, 3))
+	})
+
+	t.Run("check-out night is free", func(t *testing.T) {
+		assert.True(t, fitsInventory(res, map[string]int{"2026-07-04": 1}, nil, 1))
+	})
+
+	t.Run("pending reservations of the type", func(t *testing.T) {
+		pending := []*Reservation{
+			stay(3, "2026-06-28", "2026-07-02"),
+			stay(3, "2026-07-04", "2026-07-06"),
+			stay(4, "2026-07-01", "2026-07-04"),
+		}
+		assert.False(t, fitsInventory(res, map[string]int{"2026-07-01": 1}, pending, 2))
+		assert.True(t, fitsInventory(res, map[string]int{"2026-07-02": 1}, pending, 2))
+	})
+}
+
+func TestSameUnit(t *testing.T) {
+	unit := func(id int64) *Reservation {
+		r := stay(3, "2026-07-01", "2026-07-04")
+		r.UnitID = &id
+		return r
+	}
+
+	assert.True(t, sameUnit(&Reservation{}, &Reservation{}))
+	assert.True(t, sameUnit(unit(7), unit(7)))
+	assert.False(t, sameUnit(unit(7), unit(8)))
+	assert.False(t, sameUnit(unit(7), stay(3, "2026-07-01", "2026-07-04")))
+	assert.False(t, sameUnit(stay(3, "2026-07-01", "2026-07-04"), stay(3, "2026-07-01", "2026-07-04")))
+	assert.False(t, sameUnit(&Reservation{}, unit(7)))
+}
+
+func TestSameStay(t *testing.T) {
+	res := stay(3, "2026-07-01", "2026-07-04")
+
+	same := *res
+	same.Status = StatusCancelled
+	assert.True(t, sameStay(res, &same))
+
+	assert.False(t, sameStay(res, stay(3, "2026-07-01", "2026-07-05")))
+	assert.False(t, sameStay(res, stay(4, "2026-07-01", "2026-07-04")))
+
+	moved := *res
+	moved.PropertyID = 11
+	assert.False(t, sameStay(res, &moved))
+}
+
+func TestConfirmPayment_AssignsDistinctUnits(t *testing.T) {
+	db := dbtest.Open(t)
+	service := newDBService(t, db)
+	ctx := context.Background()
+
+	var roomTypeID int64
+	require.NoError(t, db.QueryRow(ctx, `
+        INSERT INTO room_type (organization_id, property_id, name, max_guests)
+        VALUES (100, 10, 'Double', 2)
+        RETURNING id
+    `).Scan(&roomTypeID))
+	_, err := db.Exec(ctx, `
+        INSERT INTO room_unit (organization_id, property_id, room_type_id, name)
+        VALUES (100, 10, $1, '101'), (100, 10, $1, '102')
+    `, roomTypeID)
+	require.NoError(t, err)
+
+	var ids []int
+	for i := 0; i < 2; i++ {
+		req := stayRequest(10, day(2025, 6, 1, 14), day(2025, 6, 4, 10))
+		req.RoomTypeID = &roomTypeID
+		created, err := service.insertReservation(newReservation(req, 100, KindBooking), audit.SystemActor("test"))
+		require.NoError(t, err)
+		ids = append(ids, created.ID)
+	}
+
+	// Both units are booked for the stay
+	req := stayRequest(10, day(2025, 6, 2, 14), day(2025, 6, 3, 10))
+	req.RoomTypeID = &roomTypeID
+	_, err = service.insertReservation(newReservation(req, 100, KindBooking), audit.SystemActor("test"))
+	assert.ErrorIs(t, err, ErrPropertyUnavailable)
+
+	// Confirmations at the same time still get a unit each
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, service.ConfirmPayment(id))
+		}()
+	}
+	wg.Wait()
+
+	units := map[int64]bool{}
+	for _, id := range ids {
+		confirmed, err := service.GetReservationByID(id, 100)
+		require.NoError(t, err)
+		assert.Equal(t, StatusConfirmed, confirmed.Status)
+		require.NotNil(t, confirmed.UnitID)
+		units[*confirmed.UnitID] = true
+	}
+	assert.Len(t, units, 2)
+}
+
+func TestUpdateReservation_ChecksInventoryUnderLock(t *testing.T) {
+	db := dbtest.Open(t)
+	service := newDBService(t, db)
+	actor := audit.SystemActor("test")
+	ctx := context.Background()
+
+	var roomTypeID int64
+	require.NoError(t, db.QueryRow(ctx, `
+        INSERT INTO room_type (organization_id, property_id, name, max_guests)
+        VALUES (100, 10, 'Double', 2)
+        RETURNING id
+    `).Scan(&roomTypeID))
+	_, err := db.Exec(ctx, `
+        INSERT INTO room_unit (organization_id, property_id, room_type_id, name)
+        VALUES (100, 10, $1, '101')
+    `, roomTypeID)
+	require.NoError(t, err)
+
+	request := func(checkIn, checkOut time.Time) *ReservationRequest {
+		req := stayRequest(10, checkIn, checkOut)
+		req.OrganizationID = 100
+		req.Status = StatusCreated
+		req.RoomTypeID = &roomTypeID
+		return req
+	}
+
+	moved, err := service.insertReservation(newReservation(request(day(2025, 6, 10, 14), day(2025, 6, 13, 10)), 100, KindBooking), actor)
+	require.NoError(t, err)
+
+	// While another transaction holds the property, moving the reservation
+	// waits for it, then sees the stay booked in that transaction
+	locked := make(chan struct{})
+	release := make(chan struct{})
+	booked := make(chan error, 1)
+	go func() {
+		booked <- service.repo.InTx(func(tx *ReservationRepository) error {
+			if err := tx.LockProperty(10); err != nil {
+				return err
+			}
+			close(locked)
+			<-release
+			_, err := tx.CreateReservation(newReservation(request(day(2025, 6, 1, 14), day(2025, 6, 4, 10)), 100, KindBooking))
+			return err
+		})
+	}()
+	<-locked
+
+	updated := make(chan error, 1)
+	go func() {
+		_, err := service.UpdateReservation(moved.ID, request(day(2025, 6, 2, 14), day(2025, 6, 5, 10)), 100, actor)
+		updated <- err
+	}()
+
+	select {
+	case err := <-updated:
+		t.Fatalf("update checked availability while the property was locked: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	close(release)
+	require.NoError(t, <-booked)
+	assert.ErrorIs(t, <-updated, ErrPropertyUnavailable)
+
+	unchanged, err := service.GetReservationByID(moved.ID, 100)
+	require.NoError(t, err)
+	assert.True(t, day(2025, 6, 10, 14).Equal(unchanged.CheckInDate))
+}
@@ -1,14 +1,54 @@
 package booking
 
 import (
+	"hostflow/booking-service/pkg/common"
 	"time"
 )
 
+// Reservation statuses
+const (
+	StatusCreated         = "CREATED"
+	StatusPaymentRequired = "PAYMENT_REQUIRED"
+	StatusConfirmed       = "CONFIRMED"
+	StatusRejected        = "REJECTED"
+	StatusCancelled       = "CANCELLED"
+	StatusCompleted       = "COMPLETED"
+	StatusNoShow          = "NO_SHOW"
+)
+
+// Reservation kinds
+const (
+	// KindBooking reservations are bookings of guests
+	KindBooking = "BOOKING"
+	// KindHold reservations block their stay for a prospective guest without
+	// a payment until they expire, are converted into a booking or are
+	// released
+	KindHold = "HOLD"
+)
+
+// Audit log actions specific to reservations, next to the generic
+// create, update and delete
+const (
+	actionStatusChanged    = "status_changed"
+	actionPaymentRequested = "payment_requested"
+	actionPaymentConfirmed = "payment_confirmed"
+	actionRestored         = "restore"
+	actionPurged           = "purge"
+	actionHoldConverted    = "hold_converted"
+	actionHoldReleased     = "hold_released"
+	actionHoldExpired      = "hold_expired"
+)
+
 // Reservation represents a reservation entity
 type Reservation struct {
 	ID                 int                    `json:"id" db:"id"`
 	OrganizationID     int                    `json:"organization_id" db:"organization_id"`
 	PropertyID         int                    `json:"property_id" db:"property_id"`
+	RoomTypeID         *int64                 `json:"room_type_id" db:"room_type_id"`
+	UnitID             *int64                 `json:"unit_id" db:"unit_id"`
+	GroupID            *int64                 `json:"group_id" db:"group_id"`
+	Kind               string                 `json:"kind" db:"kind"`
+	HoldExpiresAt      *time.Time             `json:"hold_expires_at" db:"hold_expires_at"`
 	CustomerID         int                    `json:"customer_id" db:"customer_id"`
 	CheckInDate        time.Time              `json:"check_in_date" db:"check_in_date"`
 	CheckOutDate       time.Time              `json:"check_out_date" db:"check_out_date"`
@@ -27,6 +67,8 @@ type Reservation struct {
 type ReservationRequest struct {
 	OrganizationID     int                    `json:"organization_id" binding:"required" example:"1"`
 	PropertyID         int                    `json:"property_id" binding:"required" example:"10"`
+	RoomTypeID         *int64                 `json:"room_type_id" binding:"omitempty,min=1" example:"3"`
+	UnitID             *int64                 `json:"unit_id" binding:"omitempty,min=1" example:"12"`
 	CustomerID         int                    `json:"customer_id" binding:"required" example:"100"`
 	CheckInDate        time.Time              `json:"check_in_date" binding:"required" example:"2024-12-20T15:00:00Z"`
 	CheckOutDate       time.Time              `json:"check_out_date" binding:"required" example:"2024-12-25T11:00:00Z"`
@@ -43,10 +85,15 @@ type ReservationResponse struct {
 	ID                 int                    `json:"id" example:"1"`
 	OrganizationID     int                    `json:"organization_id" example:"1"`
 	PropertyID         int                    `json:"property_id" example:"10"`
+	RoomTypeID         *int64                 `json:"room_type_id,omitempty" example:"3"`
+	UnitID             *int64                 `json:"unit_id,omitempty" example:"12"`
+	GroupID            *int64                 `json:"group_id,omitempty" example:"5"`
+	Kind               string                 `json:"kind" example:"BOOKING" enums:"BOOKING,HOLD"`
+	HoldExpiresAt      *time.Time             `json:"hold_expires_at,omitempty" example:"2024-12-03T09:00:00Z"`
 	CustomerID         int                    `json:"customer_id" example:"100"`
 	CheckInDate        time.Time              `json:"check_in_date" example:"2024-12-20T15:00:00Z"`
 	CheckOutDate       time.Time              `json:"check_out_date" example:"2024-12-25T11:00:00Z"`
-	Status             string                 `json:"status" example:"CREATED" enums:"CREATED CONFIRMED PAYMENT_REQUIRED REJECTED CANCELLED COMPLETED"`
+	Status             string                 `json:"status" example:"CREATED" enums:"CREATED CONFIRMED PAYMENT_REQUIRED REJECTED CANCELLED COMPLETED NO_SHOW"`
 	TotalPrice         float64                `json:"total_price" example:"500.00"`
 	PriceElements      map[string]interface{} `json:"price_elements"`
 	NoOfGuests         int                    `json:"no_of_guests" example:"2"`
@@ -62,9 +109,40 @@ type ErrorResponse struct {
 	Message string `json:"message,omitempty" example:"The provided data is invalid"`
 }
 
+// ValidationErrorResponse is returned for guest data that doesn't match the
+// guest model, in the format of common.Validation
+type ValidationErrorResponse struct {
+	Errors []common.ValidationErrorMessage `json:"errors"`
+}
+
 // StatusUpdateRequest (ostane nespremenjen)
 type StatusUpdateRequest struct {
-	Status string `json:"status" binding:"required,oneof=CREATED CONFIRMED PAYMENT_REQUIRED REJECTED CANCELLED COMPLETED" example:"CONFIRMED"`
+	Status string `json:"status" binding:"required,oneof=CREATED CONFIRMED PAYMENT_REQUIRED REJECTED CANCELLED COMPLETED NO_SHOW" example:"CONFIRMED"`
+}
+
+// CustomerSummary represents a customer's stay history within an organization
+type CustomerSummary struct {
+	CustomerID    int                   `json:"customer_id" example:"100"`
+	TotalStays    int                   `json:"total_stays" example:"3"`
+	TotalNights   int                   `json:"total_nights" example:"11"`
+	TotalRevenue  float64               `json:"total_revenue" example:"1450.00"`
+	Cancellations int                   `json:"cancellations" example:"1"`
+	NoShows       int                   `json:"no_shows" example:"0"`
+	LastStay      *time.Time            `json:"last_stay,omitempty" example:"2024-08-10T15:00:00Z"`
+	IsRepeatGuest bool                  `json:"is_repeat_guest" example:"true"`
+	UpcomingStays []ReservationResponse `json:"upcoming_stays"`
+}
+
+// Nights returns the number of nights between the check-in and check-out
+// calendar dates, ignoring the time of day
+func (r *Reservation) Nights() int {
+	in := time.Date(r.CheckInDate.Year(), r.CheckInDate.Month(), r.CheckInDate.Day(), 0, 0, 0, 0, time.UTC)
+	out := time.Date(r.CheckOutDate.Year(), r.CheckOutDate.Month(), r.CheckOutDate.Day(), 0, 0, 0, 0, time.UTC)
+	nights := int(out.Sub(in).Hours() / 24)
+	if nights < 0 {
+		return 0
+	}
+	return nights
 }
 
 // ToResponse (ostane nespremenjen)
@@ -73,6 +151,11 @@ func (r *Reservation) ToResponse() *ReservationResponse {
 		ID:                 r.ID,
 		OrganizationID:     r.OrganizationID,
 		PropertyID:         r.PropertyID,
+		RoomTypeID:         r.RoomTypeID,
+		UnitID:             r.UnitID,
+		GroupID:            r.GroupID,
+		Kind:               r.Kind,
+		HoldExpiresAt:      r.HoldExpiresAt,
 		CustomerID:         r.CustomerID,
 		CheckInDate:        r.CheckInDate,
 		CheckOutDate:       r.CheckOutDate,
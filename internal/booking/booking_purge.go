@@ -0,0 +1,113 @@
+package booking
+
+import (
+	"context"
+	"fmt"
+	"hostflow/booking-service/internal/audit"
+	"hostflow/booking-service/pkg/lib"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/fx"
+)
+
+const (
+	// purgeInterval is how often soft-deleted reservations are purged.
+	purgeInterval = time.Hour
+	// purgeBatchSize limits how many reservations are purged per transaction.
+	purgeBatchSize = 100
+)
+
+// Purger permanently removes reservations that were soft-deleted longer than
+// the retention period ago
+type Purger struct {
+	repo      *ReservationRepository
+	audit     *audit.Repository
+	logger    lib.Logger
+	retention time.Duration
+}
+
+// GetPurger returns a Purger. The retention is read from
+// RESERVATION_PURGE_AFTER_DAYS; when it is unset or 0, nothing is purged.
+func GetPurger(repo *ReservationRepository, auditLog *audit.Repository, logger lib.Logger) (*Purger, error) {
+	retention, err := parsePurgeRetention(os.Getenv("RESERVATION_PURGE_AFTER_DAYS"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Purger{
+		repo:      repo,
+		audit:     auditLog,
+		logger:    logger,
+		retention: retention,
+	}, nil
+}
+
+// parsePurgeRetention parses a number of days
+func parsePurgeRetention(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 0 {
+		return 0, fmt.Errorf("invalid RESERVATION_PURGE_AFTER_DAYS %q, expected a number of days", value)
+	}
+
+	return time.Duration(days) * 24 * time.Hour, nil
+}
+
+// tick purges every reservation past retention, one batch per transaction.
+// Each purge is recorded in the audit log, which outlives the reservation.
+func (p *Purger) tick(ctx context.Context) {
+	var total int
+	for ctx.Err() == nil {
+		var purged []PurgedReservation
+		err := p.repo.InTx(func(tx *ReservationRepository) error {
+			var err error
+			purged, err = tx.PurgeDeletedReservations(p.retention, purgeBatchSize)
+			if err != nil {
+				return err
+			}
+
+			for _, reservation := range purged {
+				entry, err := audit.NewEntry(audit.SystemActor("purge"), int64(reservation.OrganizationID), actionPurged,
+					audit.EntityReservation, strconv.Itoa(reservation.ID), nil, nil)
+				if err != nil {
+					return err
+				}
+				if err := p.audit.Record(tx.db, entry); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			p.logger.Error("Failed to purge deleted reservations:", err)
+			break
+		}
+
+		total += len(purged)
+		if len(purged) < purgeBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		p.logger.Info(fmt.Sprintf("Purged %d deleted reservations", total))
+	}
+}
+
+// RegisterPurgerHooks starts the purge job with the application, if a
+// retention period is configured
+func RegisterPurgerHooks(lifecycle fx.Lifecycle, purger *Purger) {
+	if purger.retention == 0 {
+		purger.logger.Info("RESERVATION_PURGE_AFTER_DAYS is not set, deleted reservations are kept")
+		return
+	}
+
+	lib.NewWorker(purgeInterval, purger.tick).Start(lifecycle)
+}
@@ -4,14 +4,28 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
+	"hostflow/booking-service/pkg/interfaces"
+
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// dbtx is satisfied by both the pool and a transaction, so the repository
+// can run its queries inside a transaction opened with InTx
+type dbtx interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
 type ReservationRepository struct {
-	db *pgxpool.Pool
+	db dbtx
 }
 
 func GetReservationRepository(db *pgxpool.Pool) *ReservationRepository {
@@ -20,14 +34,33 @@ func GetReservationRepository(db *pgxpool.Pool) *ReservationRepository {
 	}
 }
 
+// InTx runs fn with a repository bound to a new transaction, which is
+// committed if fn returns nil and rolled back otherwise
+func (r *ReservationRepository) InTx(fn func(tx *ReservationRepository) error) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(&ReservationRepository{db: tx}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
 // GetReservations returns all reservations ordered by creation date
 func (r *ReservationRepository) GetReservations(organizationID int64) ([]Reservation, error) {
 	query := `
         SELECT id, organization_id, property_id, customer_id, check_in_date, status, 
                total_price, payment_url, price_elements, no_of_guests, guest_data, additional_requests, 
-               check_out_date, created_at, update_at
+               check_out_date, room_type_id, unit_id, group_id, kind, hold_expires_at, created_at, update_at
         FROM reservation
         WHERE organization_id = $1
+          AND deleted_at IS NULL
         ORDER BY created_at DESC
     `
 
@@ -50,9 +83,10 @@ func (r *ReservationRepository) GetReservationByIDInternal(id int) (*Reservation
 	query := `
         SELECT id, organization_id, property_id, customer_id, check_in_date, status, 
                total_price, payment_url, price_elements, no_of_guests, guest_data, additional_requests, 
-               check_out_date, created_at, update_at
+               check_out_date, room_type_id, unit_id, group_id, kind, hold_expires_at, created_at, update_at
         FROM reservation
         WHERE id = $1
+          AND deleted_at IS NULL
     `
 
 	rows, err := r.db.Query(context.Background(), query, id)
@@ -77,10 +111,11 @@ func (r *ReservationRepository) GetReservationByID(id int, organizationID int64)
 	query := `
         SELECT id, organization_id, property_id, customer_id, check_in_date, status, 
                total_price, payment_url, price_elements, no_of_guests, guest_data, additional_requests, 
-               check_out_date, created_at, update_at
+               check_out_date, room_type_id, unit_id, group_id, kind, hold_expires_at, created_at, update_at
         FROM reservation
         WHERE id = $1
         AND organization_id = $2
+        AND deleted_at IS NULL
     `
 
 	rows, err := r.db.Query(context.Background(), query, id, organizationID)
@@ -105,12 +140,14 @@ func (r *ReservationRepository) CreateReservation(reservation *Reservation) (*Re
         INSERT INTO reservation (
             id, organization_id, property_id, customer_id, check_in_date, check_out_date,
             status, total_price, payment_url, price_elements, no_of_guests, 
-            guest_data, additional_requests, created_at, update_at
+            guest_data, additional_requests, created_at, update_at, room_type_id, unit_id, group_id,
+            kind, hold_expires_at
         )
-        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
         RETURNING id, organization_id, property_id, customer_id, check_in_date, check_out_date,
                   status, total_price, payment_url, price_elements, no_of_guests, 
-                  guest_data, additional_requests, created_at, update_at
+                  guest_data, additional_requests, created_at, update_at, room_type_id, unit_id, group_id,
+                  kind, hold_expires_at
     `
 
 	// Create a new instance to scan into
@@ -132,6 +169,11 @@ func (r *ReservationRepository) CreateReservation(reservation *Reservation) (*Re
 		reservation.AdditionalRequests,
 		reservation.CreatedAt,
 		reservation.UpdatedAt, // $15
+		reservation.RoomTypeID,
+		reservation.UnitID,
+		reservation.GroupID,
+		reservation.Kind,
+		reservation.HoldExpiresAt,
 	).Scan(
 		&res.ID,
 		&res.OrganizationID,
@@ -148,6 +190,11 @@ func (r *ReservationRepository) CreateReservation(reservation *Reservation) (*Re
 		&res.AdditionalRequests,
 		&res.CreatedAt,
 		&res.UpdatedAt,
+		&res.RoomTypeID,
+		&res.UnitID,
+		&res.GroupID,
+		&res.Kind,
+		&res.HoldExpiresAt,
 	)
 
 	if err != nil {
@@ -172,11 +219,18 @@ func (r *ReservationRepository) UpdateReservation(reservation *Reservation) (*Re
             guest_data = $11,
             additional_requests = $12,
             check_out_date = $13,
-            update_at = $14           -- Changed update_at to updated_at
+            update_at = $14,          -- Changed update_at to updated_at
+            room_type_id = $15,
+            unit_id = $16,
+            group_id = $17,
+            kind = $18,
+            hold_expires_at = $19
         WHERE id = $1
+          AND deleted_at IS NULL
         RETURNING id, organization_id, property_id, customer_id, check_in_date, 
                   check_out_date, status, total_price, payment_url, price_elements, 
-                  no_of_guests, guest_data, additional_requests, created_at, update_at
+                  no_of_guests, guest_data, additional_requests, created_at, update_at,
+                  room_type_id, unit_id, group_id, kind, hold_expires_at
     `
 
 	rows, err := r.db.Query(
@@ -196,6 +250,11 @@ func (r *ReservationRepository) UpdateReservation(reservation *Reservation) (*Re
 		reservation.AdditionalRequests, // $12
 		reservation.CheckOutDate,       // $13
 		reservation.UpdatedAt,          // $14
+		reservation.RoomTypeID,         // $15
+		reservation.UnitID,             // $16
+		reservation.GroupID,            // $17
+		reservation.Kind,               // $18
+		reservation.HoldExpiresAt,      // $19
 	)
 	if err != nil {
 		return nil, err
@@ -206,7 +265,7 @@ func (r *ReservationRepository) UpdateReservation(reservation *Reservation) (*Re
 	updated, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[Reservation])
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, errors.New("reservation not found")
+			return nil, ErrReservationNotFound
 		}
 		return nil, err
 	}
@@ -214,22 +273,350 @@ func (r *ReservationRepository) UpdateReservation(reservation *Reservation) (*Re
 	return &updated, nil
 }
 
-// DeleteReservation deletes a reservation by ID
-func (r *ReservationRepository) DeleteReservation(id int) error {
-	query := `DELETE FROM reservation WHERE id = $1`
+// exportScope limits an export query to the reservations of the filter.
+// Its parameters are $1 to $9 of exportArgs.
+const exportScope = `
+    organization_id = $1
+    AND deleted_at IS NULL
+    AND ($2::text[] IS NULL OR status = ANY($2))
+    AND ($3::bigint IS NULL OR property_id = $3)
+    AND ($4::bigint IS NULL OR customer_id = $4)
+    AND ($5::timestamptz IS NULL OR check_in_date >= $5)
+    AND ($6::timestamptz IS NULL OR check_in_date < $6)
+    AND ($7::timestamptz IS NULL OR created_at >= $7)
+    AND ($8::timestamptz IS NULL OR created_at < $8)
+`
+
+func exportArgs(f *ExportFilter) []any {
+	return []any{
+		f.OrganizationID, f.Statuses, f.PropertyID, f.CustomerID,
+		f.CheckInFrom, f.CheckInBefore, f.CreatedFrom, f.CreatedBefore,
+	}
+}
+
+// GetPriceElementPaths returns the dotted paths of the values in the price
+// elements of the reservations of an export, in order
+func (r *ReservationRepository) GetPriceElementPaths(f *ExportFilter) ([]string, error) {
+	query := `
+        WITH RECURSIVE element(path, value) AS (
+            SELECT e.key, e.value
+            FROM reservation r
+            CROSS JOIN LATERAL jsonb_each(CASE WHEN jsonb_typeof(r.price_elements) = 'object' THEN r.price_elements ELSE '{}'::jsonb END) e
+            WHERE ` + exportScope + `
+            UNION
+            SELECT element.path || '.' || e.key, e.value
+            FROM element
+            CROSS JOIN LATERAL jsonb_each(element.value) e
+            WHERE jsonb_typeof(element.value) = 'object'
+        )
+        SELECT DISTINCT path
+        FROM element
+        WHERE jsonb_typeof(value) <> 'object'
+        ORDER BY path
+    `
+
+	rows, err := r.db.Query(context.Background(), query, exportArgs(f)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// ExportReservations calls fn with each reservation of an export, oldest
+// first. The reservations are fetched from a cursor, size at a time, and
+// flush is called after each fetch.
+func (r *ReservationRepository) ExportReservations(f *ExportFilter, size int, fn func(*Reservation) error, flush func() error) error {
+	return r.InTx(func(tx *ReservationRepository) error {
+		ctx := context.Background()
+
+		query := `
+            DECLARE reservation_export NO SCROLL CURSOR FOR
+            SELECT id, organization_id, property_id, customer_id, check_in_date, status,
+                   total_price, payment_url, price_elements, no_of_guests, guest_data, additional_requests,
+                   check_out_date, room_type_id, unit_id, group_id, kind, hold_expires_at, created_at, update_at
+            FROM reservation
+            WHERE ` + exportScope + `
+            ORDER BY created_at, id
+        `
+		if _, err := tx.db.Exec(ctx, query, exportArgs(f)...); err != nil {
+			return err
+		}
+
+		for {
+			rows, err := tx.db.Query(ctx, fmt.Sprintf("FETCH FORWARD %d FROM reservation_export", size))
+			if err != nil {
+				return err
+			}
+
+			fetched := 0
+			for rows.Next() {
+				reservation, err := pgx.RowToStructByName[Reservation](rows)
+				if err != nil {
+					rows.Close()
+					return err
+				}
+				fetched++
+				if err := fn(&reservation); err != nil {
+					rows.Close()
+					return err
+				}
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return err
+			}
+
+			if err := flush(); err != nil {
+				return err
+			}
+			if fetched < size {
+				return nil
+			}
+		}
+	})
+}
+
+// CopyReservations inserts reservations with the COPY protocol, for imports
+// of many reservations at once
+func (r *ReservationRepository) CopyReservations(reservations []*Reservation) error {
+	columns := []string{
+		"id", "organization_id", "property_id", "customer_id", "check_in_date", "check_out_date",
+		"status", "total_price", "payment_url", "price_elements", "no_of_guests",
+		"guest_data", "additional_requests", "created_at", "update_at", "room_type_id", "unit_id", "group_id",
+		"kind", "hold_expires_at",
+	}
+
+	_, err := r.db.CopyFrom(context.Background(), pgx.Identifier{"reservation"}, columns,
+		pgx.CopyFromSlice(len(reservations), func(i int) ([]any, error) {
+			res := reservations[i]
+			return []any{
+				res.ID, res.OrganizationID, res.PropertyID, res.CustomerID, res.CheckInDate, res.CheckOutDate,
+				res.Status, res.TotalPrice, res.PaymentURL, res.PriceElements, res.NoOfGuests,
+				res.GuestData, res.AdditionalRequests, res.CreatedAt, res.UpdatedAt, res.RoomTypeID, res.UnitID, res.GroupID,
+				res.Kind, res.HoldExpiresAt,
+			}, nil
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to copy reservations: %w", err)
+	}
+
+	return nil
+}
+
+// TakenReservationIDs returns which of the ids are used by a reservation,
+// including deleted ones
+func (r *ReservationRepository) TakenReservationIDs(ids []int) (map[int]bool, error) {
+	rows, err := r.db.Query(context.Background(), `SELECT id FROM reservation WHERE id = ANY($1)`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	taken := map[int]bool{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		taken[id] = true
+	}
+
+	return taken, rows.Err()
+}
+
+// DeleteReservation soft-deletes a reservation. It is excluded from reads and
+// availability checks until it is restored or purged.
+func (r *ReservationRepository) DeleteReservation(id int, organizationID int64, deletedBy string) error {
+	query := `
+        UPDATE reservation
+        SET deleted_at = NOW(),
+            deleted_by = $3
+        WHERE id = $1
+          AND organization_id = $2
+          AND deleted_at IS NULL
+    `
+
+	result, err := r.db.Exec(context.Background(), query, id, organizationID, deletedBy)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrReservationNotFound
+	}
+
+	return nil
+}
+
+// GetDeletedReservationByID returns a soft-deleted reservation and locks it
+// for the rest of the transaction
+func (r *ReservationRepository) GetDeletedReservationByID(id int, organizationID int64) (*Reservation, error) {
+	query := `
+        SELECT id, organization_id, property_id, customer_id, check_in_date, status, 
+               total_price, payment_url, price_elements, no_of_guests, guest_data, additional_requests, 
+               check_out_date, room_type_id, unit_id, group_id, kind, hold_expires_at, created_at, update_at
+        FROM reservation
+        WHERE id = $1
+          AND organization_id = $2
+          AND deleted_at IS NOT NULL
+        FOR UPDATE
+    `
+
+	rows, err := r.db.Query(context.Background(), query, id, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reservation, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[Reservation])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &reservation, nil
+}
+
+// RestoreReservation clears the deletion of a soft-deleted reservation
+func (r *ReservationRepository) RestoreReservation(id int, organizationID int64) error {
+	query := `
+        UPDATE reservation
+        SET deleted_at = NULL,
+            deleted_by = NULL,
+            update_at = NOW()
+        WHERE id = $1
+          AND organization_id = $2
+          AND deleted_at IS NOT NULL
+    `
 
-	result, err := r.db.Exec(context.Background(), query, id)
+	result, err := r.db.Exec(context.Background(), query, id, organizationID)
 	if err != nil {
 		return err
 	}
 
 	if result.RowsAffected() == 0 {
-		return errors.New("reservation not found")
+		return ErrReservationNotFound
 	}
 
 	return nil
 }
 
+// LockProperty serializes availability checks and the writes depending on
+// them for a property until the end of the transaction
+func (r *ReservationRepository) LockProperty(propertyID int) error {
+	_, err := r.db.Exec(context.Background(), `SELECT pg_advisory_xact_lock(hashtext($1))`, propertyLockKey(propertyID))
+	return err
+}
+
+// propertyLockKey names the lock LockProperty takes on the reservations of a
+// property
+func propertyLockKey(propertyID int) string {
+	return "reservation_property:" + strconv.Itoa(propertyID)
+}
+
+// PurgedReservation identifies a reservation removed by PurgeDeletedReservations
+type PurgedReservation struct {
+	ID             int
+	OrganizationID int
+}
+
+// PurgeDeletedReservations permanently removes up to limit reservations that
+// were soft-deleted more than retention ago. Rows locked by another replica
+// are skipped.
+func (r *ReservationRepository) PurgeDeletedReservations(retention time.Duration, limit int) ([]PurgedReservation, error) {
+	query := `
+        DELETE FROM reservation
+        WHERE id IN (
+            SELECT id
+            FROM reservation
+            WHERE deleted_at < NOW() - $1::interval
+            ORDER BY deleted_at
+            LIMIT $2
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING id, organization_id
+    `
+
+	rows, err := r.db.Query(context.Background(), query, retention.String(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var purged []PurgedReservation
+	for rows.Next() {
+		var p PurgedReservation
+		if err := rows.Scan(&p.ID, &p.OrganizationID); err != nil {
+			return nil, err
+		}
+		purged = append(purged, p)
+	}
+
+	return purged, rows.Err()
+}
+
+// ReassignCustomer moves all reservations of a customer to another customer
+// of the same organization and queues the deletion of the old customer, in
+// a single transaction
+func (r *ReservationRepository) ReassignCustomer(organizationID, fromCustomerID, toCustomerID int64) (int64, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+        UPDATE reservation
+        SET customer_id = $3,
+            update_at = NOW()
+        WHERE organization_id = $1
+          AND customer_id = $2
+    `
+
+	result, err := tx.Exec(ctx, query, organizationID, fromCustomerID, toCustomerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reassign reservations: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+        INSERT INTO customer_deletion (organization_id, customer_id)
+        VALUES ($1, $2)
+    `, organizationID, fromCustomerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to queue customer deletion: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// GetReservationContact implements interfaces.ReservationLookup
+func (r *ReservationRepository) GetReservationContact(reservationID, organizationID int64) (*interfaces.ReservationContact, error) {
+	reservation, err := r.GetReservationByID(int(reservationID), organizationID)
+	if err != nil || reservation == nil {
+		return nil, err
+	}
+
+	return &interfaces.ReservationContact{
+		ID:             int64(reservation.ID),
+		OrganizationID: int64(reservation.OrganizationID),
+		CustomerID:     int64(reservation.CustomerID),
+		PropertyID:     int64(reservation.PropertyID),
+		Status:         reservation.Status,
+		PaymentURL:     reservation.PaymentURL,
+	}, nil
+}
+
 // CheckPropertyAvailability checks if a property is available for the given dates
 func (r *ReservationRepository) CheckPropertyAvailability(propertyID int, checkIn, checkOut time.Time) (bool, error) {
 	query := `
@@ -237,7 +624,8 @@ func (r *ReservationRepository) CheckPropertyAvailability(propertyID int, checkI
             SELECT 1
             FROM reservation
             WHERE property_id = $1
-              AND status NOT IN ('cancelled', 'rejected')
+              AND deleted_at IS NULL
+              AND status NOT IN ('CANCELLED', 'REJECTED')
               AND (
                 (check_in_date <= $2 AND check_out_date > $2) OR
                 (check_in_date < $3 AND check_out_date >= $3) OR
@@ -262,8 +650,9 @@ func (r *ReservationRepository) CheckPropertyAvailabilityExcluding(excludeID int
             SELECT 1
             FROM reservation
             WHERE property_id = $1
+              AND deleted_at IS NULL
               AND id != $2
-              AND status NOT IN ('cancelled', 'rejected')
+              AND status NOT IN ('CANCELLED', 'REJECTED')
               AND (
                 (check_in_date <= $3 AND check_out_date > $3) OR
                 (check_in_date < $4 AND check_out_date >= $4) OR
@@ -281,18 +670,20 @@ func (r *ReservationRepository) CheckPropertyAvailabilityExcluding(excludeID int
 	return exists, nil
 }
 
-// GetReservationsByCustomer returns all reservations for a customer
-func (r *ReservationRepository) GetReservationsByCustomer(customerID int) ([]Reservation, error) {
+// GetReservationsByCustomer returns all reservations of a customer within an organization
+func (r *ReservationRepository) GetReservationsByCustomer(customerID int, organizationID int64) ([]Reservation, error) {
 	query := `
         SELECT id, organization_id, property_id, customer_id, check_in_date, status, 
-               total_price, price_elements, no_of_guests, guest_data, additional_requests, 
-               check_out_date, created_at, update_at
+               total_price, payment_url, price_elements, no_of_guests, guest_data, additional_requests, 
+               check_out_date, room_type_id, unit_id, group_id, kind, hold_expires_at, created_at, update_at
         FROM reservation
         WHERE customer_id = $1
-        ORDER BY created_at DESC
+          AND deleted_at IS NULL
+          AND organization_id = $2
+        ORDER BY check_in_date DESC
     `
 
-	rows, err := r.db.Query(context.Background(), query, customerID)
+	rows, err := r.db.Query(context.Background(), query, customerID, organizationID)
 	if err != nil {
 		return nil, err
 	}
@@ -311,9 +702,10 @@ func (r *ReservationRepository) GetReservationsByProperty(propertyID int) ([]Res
 	query := `
         SELECT id, organization_id, property_id, customer_id, check_in_date, status, 
                total_price, price_elements, no_of_guests, guest_data, additional_requests, 
-               check_out_date, created_at, update_at
+               check_out_date, room_type_id, unit_id, group_id, kind, hold_expires_at, created_at, update_at
         FROM reservation
         WHERE property_id = $1
+          AND deleted_at IS NULL
         ORDER BY check_in_date DESC
     `
 
@@ -336,9 +728,10 @@ func (r *ReservationRepository) GetReservationsByOrganization(organizationID int
 	query := `
         SELECT id, organization_id, property_id, customer_id, check_in_date, status, 
                total_price, price_elements, no_of_guests, guest_data, additional_requests, 
-               check_out_date, created_at, update_at
+               check_out_date, room_type_id, unit_id, group_id, kind, hold_expires_at, created_at, update_at
         FROM reservation
         WHERE organization_id = $1
+          AND deleted_at IS NULL
         ORDER BY created_at DESC
     `
 
@@ -361,9 +754,10 @@ func (r *ReservationRepository) GetReservationsByStatus(status string) ([]Reserv
 	query := `
         SELECT id, organization_id, property_id, customer_id, check_in_date, status, 
                total_price, price_elements, no_of_guests, guest_data, additional_requests, 
-               check_out_date, created_at, update_at
+               check_out_date, room_type_id, unit_id, group_id, kind, hold_expires_at, created_at, update_at
         FROM reservation
         WHERE status = $1
+          AND deleted_at IS NULL
         ORDER BY created_at DESC
     `
 
@@ -386,10 +780,11 @@ func (r *ReservationRepository) GetUpcomingReservations() ([]Reservation, error)
 	query := `
         SELECT id, organization_id, property_id, customer_id, check_in_date, status, 
                total_price, price_elements, no_of_guests, guest_data, additional_requests, 
-               check_out_date, created_at, update_at
+               check_out_date, room_type_id, unit_id, group_id, kind, hold_expires_at, created_at, update_at
         FROM reservation
         WHERE check_in_date > NOW()
-          AND status NOT IN ('cancelled', 'rejected', 'completed')
+          AND deleted_at IS NULL
+          AND status NOT IN ('CANCELLED', 'REJECTED', 'COMPLETED')
         ORDER BY check_in_date ASC
     `
 
@@ -412,9 +807,10 @@ func (r *ReservationRepository) GetReservationsByDateRange(startDate, endDate ti
 	query := `
         SELECT id, organization_id, property_id, customer_id, check_in_date, status, 
                total_price, price_elements, no_of_guests, guest_data, additional_requests, 
-               check_out_date, created_at, update_at
+               check_out_date, room_type_id, unit_id, group_id, kind, hold_expires_at, created_at, update_at
         FROM reservation
         WHERE check_in_date >= $1 AND check_in_date <= $2
+          AND deleted_at IS NULL
         ORDER BY check_in_date ASC
     `
 
@@ -431,3 +827,364 @@ func (r *ReservationRepository) GetReservationsByDateRange(startDate, endDate ti
 
 	return reservations, nil
 }
+
+// RoomInventory is a room type of a property with the number of its active
+// units
+type RoomInventory struct {
+	RoomTypeID int64
+	PropertyID int
+	MaxGuests  int
+	Units      int
+}
+
+// RoomUnit is a unit of a room type
+type RoomUnit struct {
+	ID         int64
+	RoomTypeID int64
+	PropertyID int
+	Active     bool
+}
+
+// PropertyHasRoomTypes reports whether a property of the organization is
+// divided into room types
+func (r *ReservationRepository) PropertyHasRoomTypes(propertyID, organizationID int) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM room_type WHERE property_id = $1 AND organization_id = $2)`
+
+	var exists bool
+	err := r.db.QueryRow(context.Background(), query, propertyID, organizationID).Scan(&exists)
+	return exists, err
+}
+
+// GetRoomInventory returns a room type of the organization with its active
+// units, or nil if it doesn't exist
+func (r *ReservationRepository) GetRoomInventory(roomTypeID int64, organizationID int) (*RoomInventory, error) {
+	query := `
+        SELECT t.id, t.property_id, t.max_guests,
+               (SELECT COUNT(*) FROM room_unit u WHERE u.room_type_id = t.id AND u.active)
+        FROM room_type t
+        WHERE t.id = $1
+          AND t.organization_id = $2
+    `
+
+	var inv RoomInventory
+	err := r.db.QueryRow(context.Background(), query, roomTypeID, organizationID).
+		Scan(&inv.RoomTypeID, &inv.PropertyID, &inv.MaxGuests, &inv.Units)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &inv, nil
+}
+
+// GetRoomUnit returns a unit of the organization, or nil if it doesn't exist
+func (r *ReservationRepository) GetRoomUnit(unitID int64, organizationID int) (*RoomUnit, error) {
+	query := `SELECT id, room_type_id, property_id, active FROM room_unit WHERE id = $1 AND organization_id = $2`
+
+	var unit RoomUnit
+	err := r.db.QueryRow(context.Background(), query, unitID, organizationID).
+		Scan(&unit.ID, &unit.RoomTypeID, &unit.PropertyID, &unit.Active)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &unit, nil
+}
+
+// GetRoomTypeIDs returns the ids of the room types of a property
+func (r *ReservationRepository) GetRoomTypeIDs(propertyID, organizationID int) ([]int64, error) {
+	query := `SELECT id FROM room_type WHERE property_id = $1 AND organization_id = $2 ORDER BY id`
+
+	rows, err := r.db.Query(context.Background(), query, propertyID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowTo[int64])
+}
+
+// GetRoomTypeOccupancy returns the number of reservations of a room type on
+// each night (YYYY-MM-DD) from checkIn to checkOut, excluding a specific
+// reservation. Nights without reservations are left out.
+func (r *ReservationRepository) GetRoomTypeOccupancy(roomTypeID int64, excludeID int, checkIn, checkOut time.Time) (map[string]int, error) {
+	query := `
+        SELECT to_char(night, 'YYYY-MM-DD'), COUNT(*)
+        FROM generate_series($3::date, $4::date - 1, interval '1 day') night
+        JOIN reservation r
+          ON r.room_type_id = $1
+         AND r.id != $2
+         AND r.deleted_at IS NULL
+         AND r.status NOT IN ('CANCELLED', 'REJECTED')
+         AND r.check_in_date::date <= night
+         AND r.check_out_date::date > night
+        GROUP BY night
+    `
+
+	rows, err := r.db.Query(context.Background(), query, roomTypeID, excludeID, checkIn, checkOut)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	occupancy := map[string]int{}
+	for rows.Next() {
+		var night string
+		var count int
+		if err := rows.Scan(&night, &count); err != nil {
+			return nil, err
+		}
+		occupancy[night] = count
+	}
+
+	return occupancy, rows.Err()
+}
+
+// IsUnitBooked reports whether a unit is assigned to another reservation
+// overlapping the dates
+func (r *ReservationRepository) IsUnitBooked(unitID int64, excludeID int, checkIn, checkOut time.Time) (bool, error) {
+	query := `
+        SELECT EXISTS(
+            SELECT 1
+            FROM reservation
+            WHERE unit_id = $1
+              AND id != $2
+              AND deleted_at IS NULL
+              AND status NOT IN ('CANCELLED', 'REJECTED')
+              AND check_in_date < $4
+              AND check_out_date > $3
+        )
+    `
+
+	var exists bool
+	err := r.db.QueryRow(context.Background(), query, unitID, excludeID, checkIn, checkOut).Scan(&exists)
+	return exists, err
+}
+
+// HasUntypedReservations reports whether a property has reservations
+// without a room type overlapping the dates. They were made before the
+// property was divided into room types and take the whole property.
+func (r *ReservationRepository) HasUntypedReservations(propertyID, excludeID int, checkIn, checkOut time.Time) (bool, error) {
+	query := `
+        SELECT EXISTS(
+            SELECT 1
+            FROM reservation
+            WHERE property_id = $1
+              AND id != $2
+              AND room_type_id IS NULL
+              AND deleted_at IS NULL
+              AND status NOT IN ('CANCELLED', 'REJECTED')
+              AND check_in_date < $4
+              AND check_out_date > $3
+        )
+    `
+
+	var exists bool
+	err := r.db.QueryRow(context.Background(), query, propertyID, excludeID, checkIn, checkOut).Scan(&exists)
+	return exists, err
+}
+
+// GetFreeUnit returns the first active unit of a room type, by name, that
+// no other reservation overlapping the dates is assigned to, or nil if all
+// are taken
+func (r *ReservationRepository) GetFreeUnit(roomTypeID int64, excludeID int, checkIn, checkOut time.Time) (*int64, error) {
+	query := `
+        SELECT u.id
+        FROM room_unit u
+        WHERE u.room_type_id = $1
+          AND u.active
+          AND NOT EXISTS(
+              SELECT 1
+              FROM reservation r
+              WHERE r.unit_id = u.id
+                AND r.id != $2
+                AND r.deleted_at IS NULL
+                AND r.status NOT IN ('CANCELLED', 'REJECTED')
+                AND r.check_in_date < $4
+                AND r.check_out_date > $3
+          )
+        ORDER BY u.name, u.id
+        LIMIT 1
+    `
+
+	var id int64
+	err := r.db.QueryRow(context.Background(), query, roomTypeID, excludeID, checkIn, checkOut).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &id, nil
+}
+
+// CreateBookingGroup inserts a booking group and sets its id and timestamps
+func (r *ReservationRepository) CreateBookingGroup(group *BookingGroup) error {
+	query := `
+        INSERT INTO booking_group (organization_id, customer_id)
+        VALUES ($1, $2)
+        RETURNING id, created_at, updated_at
+    `
+
+	err := r.db.QueryRow(context.Background(), query, group.OrganizationID, group.CustomerID).
+		Scan(&group.ID, &group.CreatedAt, &group.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert booking group: %w", err)
+	}
+
+	return nil
+}
+
+// GetBookingGroup returns a booking group of the organization without its
+// reservations, or nil if it doesn't exist. With lock set, the group is
+// locked for the rest of the transaction.
+func (r *ReservationRepository) GetBookingGroup(id, organizationID int64, lock bool) (*BookingGroup, error) {
+	query := `
+        SELECT id, organization_id, customer_id, payment_url, created_at, updated_at
+        FROM booking_group
+        WHERE id = $1
+          AND organization_id = $2
+    `
+	if lock {
+		query += ` FOR UPDATE`
+	}
+
+	group := &BookingGroup{}
+	err := r.db.QueryRow(context.Background(), query, id, organizationID).Scan(
+		&group.ID, &group.OrganizationID, &group.CustomerID, &group.PaymentURL, &group.CreatedAt, &group.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// SetBookingGroupPaymentURL stores the payment link of a booking group
+func (r *ReservationRepository) SetBookingGroupPaymentURL(id int64, paymentURL string) error {
+	query := `
+        UPDATE booking_group
+        SET payment_url = $2,
+            updated_at = NOW()
+        WHERE id = $1
+    `
+
+	_, err := r.db.Exec(context.Background(), query, id, paymentURL)
+	return err
+}
+
+// GetGroupReservations returns the reservations of a booking group that are
+// not deleted, by check-in date
+func (r *ReservationRepository) GetGroupReservations(groupID, organizationID int64) ([]Reservation, error) {
+	query := `
+        SELECT id, organization_id, property_id, customer_id, check_in_date, status, 
+               total_price, payment_url, price_elements, no_of_guests, guest_data, additional_requests, 
+               check_out_date, room_type_id, unit_id, group_id, kind, hold_expires_at, created_at, update_at
+        FROM reservation
+        WHERE group_id = $1
+          AND organization_id = $2
+          AND deleted_at IS NULL
+        ORDER BY check_in_date, id
+    `
+
+	rows, err := r.db.Query(context.Background(), query, groupID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[Reservation])
+}
+
+// GetReservationForUpdate returns a reservation of the organization and locks
+// it for the rest of the transaction, or nil if it doesn't exist
+func (r *ReservationRepository) GetReservationForUpdate(id int, organizationID int64) (*Reservation, error) {
+	query := `
+        SELECT id, organization_id, property_id, customer_id, check_in_date, status, 
+               total_price, payment_url, price_elements, no_of_guests, guest_data, additional_requests, 
+               check_out_date, room_type_id, unit_id, group_id, kind, hold_expires_at, created_at, update_at
+        FROM reservation
+        WHERE id = $1
+          AND organization_id = $2
+          AND deleted_at IS NULL
+        FOR UPDATE
+    `
+
+	rows, err := r.db.Query(context.Background(), query, id, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reservation, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[Reservation])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &reservation, nil
+}
+
+// GetExpiredHolds returns up to limit active holds past their expiry, oldest
+// first, and locks them for the rest of the transaction. Rows locked by
+// another replica are skipped.
+func (r *ReservationRepository) GetExpiredHolds(limit int) ([]Reservation, error) {
+	query := `
+        SELECT id, organization_id, property_id, customer_id, check_in_date, status, 
+               total_price, payment_url, price_elements, no_of_guests, guest_data, additional_requests, 
+               check_out_date, room_type_id, unit_id, group_id, kind, hold_expires_at, created_at, update_at
+        FROM reservation
+        WHERE kind = 'HOLD'
+          AND status = 'CREATED'
+          AND hold_expires_at <= NOW()
+          AND deleted_at IS NULL
+        ORDER BY hold_expires_at
+        LIMIT $1
+        FOR UPDATE SKIP LOCKED
+    `
+
+	rows, err := r.db.Query(context.Background(), query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[Reservation])
+}
+
+// GetCalendar returns the bookings and active holds of an organization, or
+// of one of its properties, whose stay overlaps from to to, by property and
+// check-in date
+func (r *ReservationRepository) GetCalendar(organizationID int64, propertyID *int64, from, to time.Time) ([]CalendarEntry, error) {
+	query := `
+        SELECT id, property_id, room_type_id, unit_id, customer_id, check_in_date, check_out_date,
+               status, kind, hold_expires_at
+        FROM reservation
+        WHERE organization_id = $1
+          AND ($2::bigint IS NULL OR property_id = $2)
+          AND deleted_at IS NULL
+          AND status NOT IN ('CANCELLED', 'REJECTED')
+          AND check_in_date < $4
+          AND check_out_date > $3
+        ORDER BY property_id, check_in_date, id
+    `
+
+	rows, err := r.db.Query(context.Background(), query, organizationID, propertyID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[CalendarEntry])
+}
@@ -0,0 +1,125 @@
+package booking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hostflow/booking-service/internal/dbtest"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seedReservation stores a reservation of organization 100 directly in the
+// test database
+func seedReservation(t *testing.T, db *pgxpool.Pool, id, propertyID, customerID int, checkIn, checkOut time.Time, status string) {
+	t.Helper()
+
+	_, err := db.Exec(context.Background(), `
+        INSERT INTO reservation (id, organization_id, property_id, customer_id, check_in_date, check_out_date, status)
+        VALUES ($1, 100, $2, $3, $4, $5, $6)
+    `, id, propertyID, customerID, checkIn, checkOut, status)
+	require.NoError(t, err)
+}
+
+func TestReassignCustomer_QueuesDeletionWithTheMove(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := GetReservationRepository(db)
+
+	seedReservation(t, db, 1, 10, 2, day(2025, 6, 1, 0), day(2025, 6, 3, 0), StatusConfirmed)
+	seedReservation(t, db, 2, 10, 2, day(2025, 7, 1, 0), day(2025, 7, 3, 0), StatusCreated)
+	seedReservation(t, db, 3, 10, 3, day(2025, 8, 1, 0), day(2025, 8, 3, 0), StatusCreated)
+
+	moved, err := repo.ReassignCustomer(100, 2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), moved)
+
+	var left int
+	require.NoError(t, db.QueryRow(context.Background(), `SELECT COUNT(*) FROM reservation WHERE customer_id = 2`).Scan(&left))
+	assert.Zero(t, left)
+
+	var status string
+	require.NoError(t, db.QueryRow(context.Background(), `
+        SELECT status FROM customer_deletion WHERE organization_id = 100 AND customer_id = 2
+    `).Scan(&status))
+	assert.Equal(t, "PENDING", status)
+}
+
+func TestLockProperty_SerializesTransactions(t *testing.T) {
+	repo := GetReservationRepository(dbtest.Open(t))
+
+	locked := make(chan struct{})
+	release := make(chan struct{})
+	first := make(chan error, 1)
+	go func() {
+		first <- repo.InTx(func(tx *ReservationRepository) error {
+			if err := tx.LockProperty(10); err != nil {
+				return err
+			}
+			close(locked)
+			<-release
+			return nil
+		})
+	}()
+	<-locked
+
+	acquired := make(chan error, 1)
+	go func() {
+		acquired <- repo.InTx(func(tx *ReservationRepository) error {
+			return tx.LockProperty(10)
+		})
+	}()
+
+	// Another property is not blocked
+	require.NoError(t, repo.InTx(func(tx *ReservationRepository) error {
+		return tx.LockProperty(11)
+	}))
+
+	select {
+	case err := <-acquired:
+		t.Fatalf("lock acquired while held by another transaction: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	close(release)
+	require.NoError(t, <-first)
+	require.NoError(t, <-acquired)
+}
+
+func TestCheckPropertyAvailability_IgnoresFreedReservations(t *testing.T) {
+	tests := []struct {
+		status  string
+		deleted bool
+		blocks  bool
+	}{
+		{status: StatusCreated, blocks: true},
+		{status: StatusConfirmed, blocks: true},
+		{status: StatusCompleted, blocks: true},
+		{status: StatusCancelled, blocks: false},
+		{status: StatusRejected, blocks: false},
+		{status: StatusConfirmed, deleted: true, blocks: false},
+	}
+
+	db := dbtest.Open(t)
+	repo := GetReservationRepository(db)
+	checkIn, checkOut := day(2025, 6, 1, 14), day(2025, 6, 4, 10)
+
+	for i, tt := range tests {
+		propertyID := 10 + i
+		seedReservation(t, db, 1+i, propertyID, 2, checkIn, checkOut, tt.status)
+		if tt.deleted {
+			_, err := db.Exec(context.Background(), `UPDATE reservation SET deleted_at = NOW() WHERE id = $1`, 1+i)
+			require.NoError(t, err)
+		}
+
+		taken, err := repo.CheckPropertyAvailability(propertyID, day(2025, 6, 2, 14), day(2025, 6, 3, 10))
+		require.NoError(t, err)
+		assert.Equal(t, tt.blocks, taken, "%s deleted=%v", tt.status, tt.deleted)
+
+		taken, err = repo.CheckPropertyAvailabilityExcluding(999, propertyID, day(2025, 6, 2, 14), day(2025, 6, 3, 10))
+		require.NoError(t, err)
+		assert.Equal(t, tt.blocks, taken, "excluding: %s deleted=%v", tt.status, tt.deleted)
+	}
+}
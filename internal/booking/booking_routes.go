@@ -20,6 +20,7 @@ type ReservationRoutes struct {
 	customerController      *customer.CustomerController
 	communicationController *communication.CommunicationController
 	authMiddleware          middlewares.AuthMiddleware
+	rateLimitMiddleware     middlewares.RateLimitMiddleware
 }
 
 // SetReservationRoutes returns a ReservationRoutes struct
@@ -30,6 +31,7 @@ func SetReservationRoutes(
 	customerController *customer.CustomerController,
 	authMiddleware middlewares.AuthMiddleware,
 	communicationController *communication.CommunicationController,
+	rateLimitMiddleware middlewares.RateLimitMiddleware,
 ) ReservationRoutes {
 	return ReservationRoutes{
 		logger:                  logger,
@@ -38,6 +40,7 @@ func SetReservationRoutes(
 		customerController:      customerController,
 		authMiddleware:          authMiddleware,
 		communicationController: communicationController,
+		rateLimitMiddleware:     rateLimitMiddleware,
 	}
 }
 
@@ -49,26 +52,46 @@ func (route ReservationRoutes) Setup() {
 	reservations := route.router.Group("/reservations")
 	reservations.Use(route.authMiddleware.Handler())
 	{
-		reservations.GET("", route.reservationController.GetReservationsHandler)
-		reservations.POST("/", route.reservationController.CreateReservationHandler)
-		reservations.GET("/:id", route.reservationController.GetReservationByIDHandler)
-		reservations.PUT("/:id", route.reservationController.UpdateReservationHandler)
-		reservations.DELETE("/:id", route.reservationController.DeleteReservationHandler)
+		reservations.GET("", route.rateLimitMiddleware.Limit(middlewares.BudgetSearch), middlewares.RequirePermission(middlewares.ReservationsRead), route.reservationController.GetReservationsHandler)
+		reservations.POST("/", route.rateLimitMiddleware.Limit(middlewares.BudgetCreate), middlewares.RequirePermission(middlewares.ReservationsCreate), route.reservationController.CreateReservationHandler)
+		reservations.POST("/holds", route.rateLimitMiddleware.Limit(middlewares.BudgetCreate), middlewares.RequirePermission(middlewares.ReservationsCreate), route.reservationController.CreateHoldHandler)
+		reservations.GET("/calendar", route.rateLimitMiddleware.Limit(middlewares.BudgetSearch), middlewares.RequirePermission(middlewares.ReservationsRead), route.reservationController.GetCalendarHandler)
+		reservations.GET("/export", route.rateLimitMiddleware.Limit(middlewares.BudgetSearch), middlewares.RequirePermission(middlewares.ReservationsExport), route.reservationController.ExportReservationsHandler)
+		reservations.POST("/import", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), middlewares.RequirePermission(middlewares.ReservationsImport), route.reservationController.ImportReservationsHandler)
+		reservations.GET("/:id", route.rateLimitMiddleware.Limit(middlewares.BudgetDefault), middlewares.RequirePermission(middlewares.ReservationsRead), route.reservationController.GetReservationByIDHandler)
+		reservations.PUT("/:id", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), middlewares.RequirePermission(middlewares.ReservationsUpdate), route.reservationController.UpdateReservationHandler)
+		reservations.DELETE("/:id", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), middlewares.RequirePermission(middlewares.ReservationsDelete), route.reservationController.DeleteReservationHandler)
+		reservations.POST("/:id/restore", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), middlewares.RequirePermission(middlewares.ReservationsDelete), route.reservationController.RestoreReservationHandler)
+		reservations.POST("/:id/convert", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), middlewares.RequirePermission(middlewares.ReservationsCreate), route.reservationController.ConvertHoldHandler)
+		reservations.POST("/:id/release", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), middlewares.RequirePermission(middlewares.ReservationsUpdate), route.reservationController.ReleaseHoldHandler)
+		reservations.GET("/:id/emails", route.rateLimitMiddleware.Limit(middlewares.BudgetDefault), middlewares.RequirePermission(middlewares.CommunicationRead), route.communicationController.GetReservationDeliveriesHandler)
+	}
+
+	groups := route.router.Group("/reservation-groups")
+	groups.Use(route.authMiddleware.Handler())
+	{
+		groups.POST("", route.rateLimitMiddleware.Limit(middlewares.BudgetCreate), middlewares.RequirePermission(middlewares.ReservationsCreate), route.reservationController.CreateBookingGroupHandler)
+		groups.GET("/:id", route.rateLimitMiddleware.Limit(middlewares.BudgetDefault), middlewares.RequirePermission(middlewares.ReservationsRead), route.reservationController.GetBookingGroupHandler)
+		groups.POST("/:id/cancel", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), middlewares.RequirePermission(middlewares.ReservationsUpdate), route.reservationController.CancelBookingGroupHandler)
 	}
 
 	customers := route.router.Group("/customer")
 	customers.Use(route.authMiddleware.Handler())
 	{
-		customers.GET("", route.customerController.GetCustomerHandler)
-		customers.POST("/", route.customerController.CreateCustomerHandler)
-		customers.GET("/:id", route.customerController.GetCustomerByIDHandler)
-		//customers.PUT("/:id", route.customerController.UpdateCustomerHandler)
-		customers.DELETE("/:id", route.customerController.DeleteCustomerHandler)
+		customers.GET("", route.rateLimitMiddleware.Limit(middlewares.BudgetSearch), middlewares.RequirePermission(middlewares.CustomersRead), route.customerController.GetCustomerHandler)
+		customers.POST("/", route.rateLimitMiddleware.Limit(middlewares.BudgetCreate), middlewares.RequirePermission(middlewares.CustomersCreate), route.customerController.CreateCustomerHandler)
+		customers.POST("/merge", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), middlewares.RequirePermission(middlewares.CustomersMerge), route.customerController.MergeCustomersHandler)
+		customers.GET("/:id", route.rateLimitMiddleware.Limit(middlewares.BudgetDefault), middlewares.RequirePermission(middlewares.CustomersRead), route.customerController.GetCustomerByIDHandler)
+		customers.PUT("/:id", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), middlewares.RequirePermission(middlewares.CustomersUpdate), route.customerController.UpdateCustomerHandler)
+		customers.GET("/:id/reservations", route.rateLimitMiddleware.Limit(middlewares.BudgetDefault), middlewares.RequirePermission(middlewares.CustomersRead, middlewares.ReservationsRead), route.reservationController.GetCustomerReservationsHandler)
+		customers.GET("/:id/summary", route.rateLimitMiddleware.Limit(middlewares.BudgetDefault), middlewares.RequirePermission(middlewares.CustomersRead, middlewares.ReservationsRead), route.reservationController.GetCustomerSummaryHandler)
+		customers.DELETE("/:id", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), middlewares.RequirePermission(middlewares.CustomersDelete), route.customerController.DeleteCustomerHandler)
 	}
 
 	communications := route.router.Group("/communication")
+	communications.Use(route.authMiddleware.Handler())
 	{
-		communications.POST("/email", route.communicationController.SendEmailHandler)
+		communications.POST("/email", route.rateLimitMiddleware.Limit(middlewares.BudgetCreate), middlewares.RequirePermission(middlewares.CommunicationSend), route.communicationController.SendEmailHandler)
 	}
 
 	metrics := route.router.Group("/metrics")
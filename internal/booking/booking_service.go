@@ -2,35 +2,107 @@ package booking
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hostflow/booking-service/internal/audit"
+	"hostflow/booking-service/internal/communication"
+	"hostflow/booking-service/internal/encryption"
+	"hostflow/booking-service/internal/guest"
+	"hostflow/booking-service/internal/touristtax"
+	"hostflow/booking-service/pkg/interfaces"
+	"hostflow/booking-service/pkg/lib"
 	"io"
 	"math/rand/v2"
 	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+
+	pb "hostflow/booking-service/internal/communication/proto"
+	custpb "hostflow/booking-service/internal/customer/proto"
+)
+
+// Errors returned by the reservation service
+var (
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrPropertyUnavailable = errors.New("property is not available for the selected dates")
+	ErrRoomTypeRequired    = errors.New("the property has room types: select a room type or unit")
+	ErrInvalidRoom         = errors.New("room type or unit not found for the property")
+	ErrTooManyGuests       = errors.New("too many guests for the room type")
 )
 
 // ReservationService handles business logic for reservations
 type ReservationService struct {
-	repo *ReservationRepository
+	repo       *ReservationRepository
+	emails     *communication.EmailDispatcher
+	audit      *audit.Repository
+	guestData  *encryption.FieldEncryptor
+	guests     *guest.Rules
+	touristTax *touristtax.Service
+	customers  custpb.CustomerServiceClient
+	waitlist   interfaces.WaitlistNotifier
+	events     interfaces.EventPublisher
+	logger     lib.Logger
+	holdFor    time.Duration
+	// payments sends the requests to the payment service
+	payments *http.Client
 }
 
 type Service interface {
 	GetReservations(orgID int64) ([]Reservation, error)
 	GetReservationByID(id int, orgID int64) (*Reservation, error)
-	CreateReservation(req *ReservationRequest, orgID int64) (*Reservation, error)
-	UpdateReservation(id int, req *ReservationRequest, orgID int64) (*Reservation, error)
-	DeleteReservation(id int, orgID int64) error
-	UpdateReservationStatus(id int, status string, orgID int64) (*Reservation, error)
-	CancelReservation(id int, orgID int64) (*Reservation, error)
+	CreateReservation(req *ReservationRequest, orgID int64, actor audit.Actor) (*Reservation, error)
+	UpdateReservation(id int, req *ReservationRequest, orgID int64, actor audit.Actor) (*Reservation, error)
+	DeleteReservation(id int, orgID int64, actor audit.Actor) error
+	UpdateReservationStatus(id int, status string, orgID int64, actor audit.Actor) (*Reservation, error)
+	CancelReservation(id int, orgID int64, actor audit.Actor) (*Reservation, error)
+	RestoreReservation(id int, orgID int64, actor audit.Actor) (*Reservation, error)
 	ConfirmPayment(reservationID int) error
+	GetReservationsByCustomer(customerID int, orgID int64) ([]Reservation, error)
+	GetCustomerSummary(customerID int, orgID int64) (*CustomerSummary, error)
+	ImportReservations(file io.Reader, opts ImportOptions, orgID int64, actor audit.Actor) (*ImportResult, error)
+	ExportReservations(f *ExportFilter, decrypt bool, w io.Writer) error
+	CreateBookingGroup(req *BookingGroupRequest, orgID int64, actor audit.Actor) (*BookingGroup, error)
+	GetBookingGroup(id int64, orgID int64) (*BookingGroup, error)
+	CancelBookingGroup(id int64, reservationIDs []int, orgID int64, actor audit.Actor) (*BookingGroup, error)
+	CreateHold(req *HoldRequest, orgID int64, actor audit.Actor) (*Reservation, error)
+	ConvertHold(id int, orgID int64, actor audit.Actor) (*Reservation, error)
+	ReleaseHold(id int, orgID int64, actor audit.Actor) (*Reservation, error)
+	ExpireHolds() (int, error)
+	GetCalendar(f *CalendarFilter, orgID int64) ([]CalendarEntry, error)
 }
 
-// GetReservationService creates a new ReservationService
-func GetReservationService(repo *ReservationRepository) *ReservationService {
+// GetReservationService creates a new ReservationService. Holds expire
+// after RESERVATION_HOLD_HOURS unless they are created with an expiry.
+func GetReservationService(
+	repo *ReservationRepository,
+	emails *communication.EmailDispatcher,
+	auditLog *audit.Repository,
+	guestData *encryption.FieldEncryptor,
+	guests *guest.Rules,
+	touristTax *touristtax.Service,
+	customers custpb.CustomerServiceClient,
+	waitlist interfaces.WaitlistNotifier,
+	events interfaces.EventPublisher,
+	logger lib.Logger,
+) *ReservationService {
 	return &ReservationService{
-		repo: repo,
+		repo:       repo,
+		emails:     emails,
+		audit:      auditLog,
+		guestData:  guestData,
+		guests:     guests,
+		touristTax: touristTax,
+		customers:  customers,
+		waitlist:   waitlist,
+		events:     events,
+		logger:     logger,
+		holdFor:    holdDuration(os.Getenv("RESERVATION_HOLD_HOURS")),
+		payments:   http.DefaultClient,
 	}
 }
 
@@ -51,36 +123,60 @@ func (s *ReservationService) GetReservationByID(id int, organizationID int64) (*
 	}
 
 	if reservation == nil {
-		return nil, errors.New("reservation not found")
+		return nil, ErrReservationNotFound
 	}
 
 	return reservation, nil
 }
 
 // CreateReservation creates a new reservation
-func (s *ReservationService) CreateReservation(req *ReservationRequest, organizationID int64) (*Reservation, error) {
+func (s *ReservationService) CreateReservation(req *ReservationRequest, organizationID int64, actor audit.Actor) (*Reservation, error) {
 	/*	// Validate request
 		if err := s.validateReservationRequest(req); err != nil {
 			return nil, err
 		}*/
 
-	// Check property availability
-	hasConflict, err := s.repo.CheckPropertyAvailability(req.PropertyID, req.CheckInDate, req.CheckOutDate)
+	createdReservation, err := s.insertReservation(newReservation(req, organizationID, KindBooking), actor)
 	if err != nil {
 		return nil, err
 	}
-	if hasConflict {
-		return nil, errors.New("property is not available for the selected dates")
+
+	// 5. Call Payment Service
+	paymentUrl, err := s.initiatePayment(createdReservation)
+	if err != nil {
+		// Log error, but you might want to handle failures (e.g., mark as FAILED)
+		return nil, fmt.Errorf("failed to initiate payment: %w", err)
 	}
 
-	// Create reservation entity
+	before := *createdReservation
+	createdReservation.PaymentURL = paymentUrl
+	createdReservation.Status = "PAYMENT_REQUIRED"
+	createdReservation.UpdatedAt = time.Now()
+
+	updatedReservation, err := s.saveReservation(createdReservation, &before, actor, actionPaymentRequested)
+	if err != nil {
+		return nil, err
+	}
+
+	s.sendEmail(updatedReservation, pb.EmailType_PAYMENT)
+	s.stayBooked(updatedReservation)
+
+	return updatedReservation, nil
+}
+
+// newReservation returns a new reservation of the kind for the stay of a
+// request, not stored yet
+func newReservation(req *ReservationRequest, organizationID int64, kind string) *Reservation {
 	reservation := &Reservation{
 		ID:                 rand.IntN(1000000),
 		OrganizationID:     int(organizationID),
 		PropertyID:         req.PropertyID,
+		RoomTypeID:         req.RoomTypeID,
+		UnitID:             req.UnitID,
 		CustomerID:         req.CustomerID,
 		CheckInDate:        req.CheckInDate,
 		CheckOutDate:       req.CheckOutDate,
+		Kind:               kind,
 		Status:             "CREATED",
 		TotalPrice:         req.TotalPrice,
 		PriceElements:      req.PriceElements,
@@ -102,29 +198,47 @@ func (s *ReservationService) CreateReservation(req *ReservationRequest, organiza
 		reservation.AdditionalRequests = make(map[string]interface{})
 	}
 
-	// Save to repository
-	createdReservation, err := s.repo.CreateReservation(reservation)
-	if err != nil {
+	return reservation
+}
+
+// insertReservation validates, encrypts and prices a new reservation, and
+// stores it if its stay is available
+func (s *ReservationService) insertReservation(reservation *Reservation, actor audit.Actor) (*Reservation, error) {
+	// Validate the registered guests, then encrypt the configured guest data
+	// fields before they are stored
+	if err := s.guests.Validate(reservation.GuestData, reservation.NoOfGuests); err != nil {
 		return nil, err
 	}
-
-	// 5. Call Payment Service
-	paymentUrl, err := s.initiatePayment(createdReservation)
-	if err != nil {
-		// Log error, but you might want to handle failures (e.g., mark as FAILED)
-		return nil, fmt.Errorf("failed to initiate payment: %w", err)
+	if err := s.guestData.Encrypt(context.Background(), reservation.GuestData, nil); err != nil {
+		return nil, err
+	}
+	if err := s.applyTouristTax(reservation); err != nil {
+		return nil, err
 	}
 
-	createdReservation.PaymentURL = paymentUrl
-	createdReservation.Status = "PAYMENT_REQUIRED"
-	createdReservation.UpdatedAt = time.Now()
+	// Check property availability and save to repository. The property is
+	// locked so that a concurrent create or restore can't take the same dates.
+	var createdReservation *Reservation
+	err := s.repo.InTx(func(tx *ReservationRepository) error {
+		if err := tx.LockProperty(reservation.PropertyID); err != nil {
+			return err
+		}
+		if err := checkAvailability(tx, reservation, nil); err != nil {
+			return err
+		}
 
-	updatedReservation, err := s.repo.UpdateReservation(createdReservation)
+		var err error
+		createdReservation, err = tx.CreateReservation(reservation)
+		if err != nil {
+			return err
+		}
+		return s.recordChange(tx, actor, audit.ActionCreate, nil, createdReservation)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return updatedReservation, nil
+	return createdReservation, nil
 }
 
 func (s *ReservationService) ConfirmPayment(reservationID int) error {
@@ -132,21 +246,149 @@ func (s *ReservationService) ConfirmPayment(reservationID int) error {
 	if err != nil {
 		return fmt.Errorf("could not find reservation %d to confirm: %w", reservationID, err)
 	}
+	if existing == nil {
+		return fmt.Errorf("could not find reservation %d to confirm", reservationID)
+	}
+
+	// A booking group is paid with a single payment, made for its first
+	// reservation
+	if existing.GroupID != nil {
+		return s.confirmGroupPayment(existing)
+	}
+
+	// Payment events can be redelivered; don't confirm (and email) twice.
+	if existing.Status == StatusConfirmed {
+		return nil
+	}
 
-	existing.Status = "CONFIRMED"
+	before := *existing
+	existing.Status = StatusConfirmed
 	existing.UpdatedAt = time.Now()
 
-	_, err = s.repo.UpdateReservation(existing)
+	confirmed, err := s.saveReservation(existing, &before, audit.SystemActor("payments"), actionPaymentConfirmed)
 	if err != nil {
 		return fmt.Errorf("failed to update status for reservation %d: %w", reservationID, err)
 	}
 
+	s.sendEmail(confirmed, pb.EmailType_CONFIRMATION)
+
 	fmt.Printf("Reservation %d status set to 'CONFIMED'\n", reservationID)
 	return nil
 }
 
+// saveReservation updates the reservation and records the change from before
+// in the audit log, in a single transaction. A confirmed reservation of a
+// room type is assigned a unit.
+func (s *ReservationService) saveReservation(reservation, before *Reservation, actor audit.Actor, action string) (*Reservation, error) {
+	var saved *Reservation
+	err := s.repo.InTx(func(tx *ReservationRepository) error {
+		var err error
+		saved, err = s.save(tx, reservation, before, actor, action)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.releaseDates(before, saved)
+	return saved, nil
+}
+
+// save is saveReservation with the transaction of tx
+func (s *ReservationService) save(tx *ReservationRepository, reservation, before *Reservation, actor audit.Actor, action string) (*Reservation, error) {
+	if err := s.assignUnit(tx, reservation); err != nil {
+		return nil, err
+	}
+
+	saved, err := tx.UpdateReservation(reservation)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.recordChange(tx, actor, action, before, saved); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// recordChange writes the audit entry of a reservation change with the
+// transaction of tx. before is nil for creations and after for deletions.
+func (s *ReservationService) recordChange(tx *ReservationRepository, actor audit.Actor, action string, before, after *Reservation) error {
+	subject := after
+	if subject == nil {
+		subject = before
+	}
+
+	entry, err := audit.NewEntry(
+		actor,
+		int64(subject.OrganizationID),
+		action,
+		audit.EntityReservation,
+		strconv.Itoa(subject.ID),
+		before,
+		after,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to build audit entry: %w", err)
+	}
+
+	if err := s.audit.Record(tx.db, entry); err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+// sendEmail queues a guest email for the reservation. Delivery happens in the
+// background, so a failure here never fails the reservation itself.
+func (s *ReservationService) sendEmail(reservation *Reservation, emailType pb.EmailType) {
+	_, err := s.emails.Enqueue(communication.EmailJob{
+		OrganizationID: int64(reservation.OrganizationID),
+		ReservationID:  int64(reservation.ID),
+		CustomerID:     int64(reservation.CustomerID),
+		PropertyID:     int64(reservation.PropertyID),
+		Type:           emailType,
+		PaymentURL:     reservation.PaymentURL,
+	})
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to queue %s email for reservation %d:", emailType, reservation.ID), err)
+	}
+}
+
+// releaseDates tells the waitlist when a change frees the stay of before:
+// the reservation was cancelled, rejected or deleted, when after is nil, or
+// moved to other dates, property or room type. Like emails, a failure never
+// fails the change itself.
+func (s *ReservationService) releaseDates(before, after *Reservation) {
+	// Status updates store lowercase statuses
+	if !occupies(strings.ToUpper(before.Status)) {
+		return
+	}
+	if after != nil && occupies(strings.ToUpper(after.Status)) && sameStay(before, after) {
+		return
+	}
+
+	err := s.waitlist.DatesReleased(int64(before.OrganizationID), int64(before.PropertyID), before.CheckInDate, before.CheckOutDate)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to release the dates of reservation %d to the waitlist:", before.ID), err)
+	}
+}
+
+// stayBooked tells the waitlist that the customer of a new reservation
+// booked its stay
+func (s *ReservationService) stayBooked(reservation *Reservation) {
+	err := s.waitlist.StayBooked(
+		int64(reservation.OrganizationID),
+		int64(reservation.CustomerID),
+		int64(reservation.PropertyID),
+		reservation.CheckInDate,
+		reservation.CheckOutDate,
+	)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to update the waitlist for reservation %d:", reservation.ID), err)
+	}
+}
+
 // UpdateReservation updates an existing reservation
-func (s *ReservationService) UpdateReservation(id int, req *ReservationRequest, organizationID int64) (*Reservation, error) {
+func (s *ReservationService) UpdateReservation(id int, req *ReservationRequest, organizationID int64, actor audit.Actor) (*Reservation, error) {
 	// Validate request
 	/*	if err := s.validateReservationRequest(req); err != nil {
 		return nil, err
@@ -158,7 +400,7 @@ func (s *ReservationService) UpdateReservation(id int, req *ReservationRequest,
 		return nil, err
 	}
 	if existingReservation == nil {
-		return nil, errors.New("reservation not found")
+		return nil, ErrReservationNotFound
 	}
 
 	// Check if reservation can be updated
@@ -166,16 +408,8 @@ func (s *ReservationService) UpdateReservation(id int, req *ReservationRequest,
 		return nil, errors.New("cannot update a completed or cancelled reservation")
 	}
 
-	// Check property availability (excluding current reservation)
-	hasConflict, err := s.repo.CheckPropertyAvailabilityExcluding(id, req.PropertyID, req.CheckInDate, req.CheckOutDate)
-	if err != nil {
-		return nil, err
-	}
-	if hasConflict {
-		return nil, errors.New("property is not available for the selected dates")
-	}
-
 	// Update reservation fields
+	before := *existingReservation
 	existingReservation.OrganizationID = req.OrganizationID
 	existingReservation.PropertyID = req.PropertyID
 	existingReservation.CustomerID = req.CustomerID
@@ -187,8 +421,25 @@ func (s *ReservationService) UpdateReservation(id int, req *ReservationRequest,
 	existingReservation.NoOfGuests = req.NoOfGuests
 	existingReservation.GuestData = req.GuestData
 	existingReservation.AdditionalRequests = req.AdditionalRequests
+	existingReservation.RoomTypeID = req.RoomTypeID
+	existingReservation.UnitID = req.UnitID
 	existingReservation.UpdatedAt = time.Now()
 
+	// An active hold keeps its status until it is converted or released
+	if isActiveHold(&before) {
+		existingReservation.Status = before.Status
+	}
+
+	// A request without a room type or unit keeps those of the reservation,
+	// and the unit is kept while the stay doesn't change
+	if req.RoomTypeID == nil && req.UnitID == nil && req.PropertyID == before.PropertyID {
+		existingReservation.RoomTypeID = before.RoomTypeID
+	}
+	if req.UnitID == nil && sameID(existingReservation.RoomTypeID, before.RoomTypeID) &&
+		req.CheckInDate.Equal(before.CheckInDate) && req.CheckOutDate.Equal(before.CheckOutDate) {
+		existingReservation.UnitID = before.UnitID
+	}
+
 	// Initialize empty maps if nil
 	if existingReservation.PriceElements == nil {
 		existingReservation.PriceElements = make(map[string]interface{})
@@ -200,29 +451,81 @@ func (s *ReservationService) UpdateReservation(id int, req *ReservationRequest,
 		existingReservation.AdditionalRequests = make(map[string]interface{})
 	}
 
-	// Save updates
-	updatedReservation, err := s.repo.UpdateReservation(existingReservation)
+	// Validate the registered guests, then encrypt the configured guest data
+	// fields. Values that are unchanged or sent back redacted keep their
+	// stored ciphertext.
+	if err := s.guests.Validate(existingReservation.GuestData, existingReservation.NoOfGuests); err != nil {
+		return nil, err
+	}
+	if err := s.guestData.Encrypt(context.Background(), existingReservation.GuestData, before.GuestData); err != nil {
+		return nil, err
+	}
+	if err := s.applyTouristTax(existingReservation); err != nil {
+		return nil, err
+	}
+
+	// Check availability, excluding the reservation itself, and save the
+	// updates under the lock of the property, like a create, so that a move
+	// to other dates or another room type can't take a stay booked meanwhile
+	var updatedReservation *Reservation
+	err = s.repo.InTx(func(tx *ReservationRepository) error {
+		if err := tx.LockProperty(existingReservation.PropertyID); err != nil {
+			return err
+		}
+		if err := checkAvailability(tx, existingReservation, nil); err != nil {
+			return err
+		}
+
+		var err error
+		updatedReservation, err = s.save(tx, existingReservation, &before, actor, audit.ActionUpdate)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	s.releaseDates(&before, updatedReservation)
 	return updatedReservation, nil
 }
 
+// applyTouristTax charges the tourist tax of the stay: the tourist tax line
+// of the price elements is recalculated and the total price adjusted
+func (s *ReservationService) applyTouristTax(res *Reservation) error {
+	stay := touristtax.Stay{
+		OrganizationID: int64(res.OrganizationID),
+		PropertyID:     int64(res.PropertyID),
+		CheckIn:        res.CheckInDate,
+		CheckOut:       res.CheckOutDate,
+		NoOfGuests:     res.NoOfGuests,
+		GuestData:      res.GuestData,
+	}
+
+	total, err := s.touristTax.Apply(stay, res.PriceElements, res.TotalPrice)
+	if err != nil {
+		return err
+	}
+	res.TotalPrice = total
+	return nil
+}
+
 func (s *ReservationService) initiatePayment(res *Reservation) (string, error) {
-	paymentReq := map[string]interface{}{
+	return s.requestPayment(map[string]interface{}{
 		"organizationId": res.OrganizationID,
 		"reservationId":  res.ID,
 		"customerId":     res.CustomerID,
 		"amount":         res.TotalPrice,
-	}
+	})
+}
 
+// requestPayment creates a payment with the payment service and returns its
+// payment link
+func (s *ReservationService) requestPayment(paymentReq map[string]interface{}) (string, error) {
 	body, err := json.Marshal(paymentReq)
 	if err != nil {
 		return "", err
 	}
 
-	resp, err := http.Post("https://hostflow.software/payment/payments", "application/json", bytes.NewBuffer(body))
+	resp, err := s.payments.Post("https://hostflow.software/payment/payments", "application/json", bytes.NewBuffer(body))
 	if err != nil {
 		return "", err
 	}
@@ -241,33 +544,79 @@ func (s *ReservationService) initiatePayment(res *Reservation) (string, error) {
 	return string(respBody), nil
 }
 
-// DeleteReservation deletes a reservation by ID
-func (s *ReservationService) DeleteReservation(id int, organizationID int64) error {
+// DeleteReservation soft-deletes a reservation. Its financial history is
+// kept until the purge job removes it after the retention period.
+func (s *ReservationService) DeleteReservation(id int, organizationID int64, actor audit.Actor) error {
 	// Check if reservation exists
 	reservation, err := s.repo.GetReservationByID(id, organizationID)
 	if err != nil {
 		return err
 	}
 	if reservation == nil {
-		return errors.New("reservation not found")
+		return ErrReservationNotFound
 	}
 
 	// Business rule: cannot delete completed reservations
-	if reservation.Status == "completed" {
+	if reservation.Status == StatusCompleted {
 		return errors.New("cannot delete a completed reservation")
 	}
 
 	// Delete the reservation
-	err = s.repo.DeleteReservation(id)
+	err = s.repo.InTx(func(tx *ReservationRepository) error {
+		if err := tx.DeleteReservation(id, organizationID, actor.Type+":"+actor.ID); err != nil {
+			return err
+		}
+		return s.recordChange(tx, actor, audit.ActionDelete, reservation, nil)
+	})
 	if err != nil {
 		return err
 	}
 
+	s.releaseDates(reservation, nil)
 	return nil
 }
 
+// RestoreReservation undoes the soft deletion of a reservation. Unless it
+// was cancelled or rejected, its dates must still be available.
+func (s *ReservationService) RestoreReservation(id int, organizationID int64, actor audit.Actor) (*Reservation, error) {
+	var restored *Reservation
+	err := s.repo.InTx(func(tx *ReservationRepository) error {
+		reservation, err := tx.GetDeletedReservationByID(id, organizationID)
+		if err != nil {
+			return err
+		}
+		if reservation == nil {
+			return ErrReservationNotFound
+		}
+
+		if reservation.Status != StatusCancelled && reservation.Status != StatusRejected {
+			if err := tx.LockProperty(reservation.PropertyID); err != nil {
+				return err
+			}
+			if err := checkAvailability(tx, reservation, nil); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.RestoreReservation(id, organizationID); err != nil {
+			return err
+		}
+
+		restored, err = tx.GetReservationByID(id, organizationID)
+		if err != nil {
+			return err
+		}
+		return s.recordChange(tx, actor, actionRestored, nil, restored)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return restored, nil
+}
+
 // UpdateReservationStatus updates only the status of a reservation
-func (s *ReservationService) UpdateReservationStatus(id int, status string, organizationID int64) (*Reservation, error) {
+func (s *ReservationService) UpdateReservationStatus(id int, status string, organizationID int64, actor audit.Actor) (*Reservation, error) {
 	// Validate status
 	validStatuses := map[string]bool{
 		"pending": true, "confirmed": true, "checked_in": true,
@@ -283,7 +632,11 @@ func (s *ReservationService) UpdateReservationStatus(id int, status string, orga
 		return nil, err
 	}
 	if reservation == nil {
-		return nil, errors.New("reservation not found")
+		return nil, ErrReservationNotFound
+	}
+
+	if isActiveHold(reservation) {
+		return nil, ErrHoldActive
 	}
 
 	// Validate status transition
@@ -292,10 +645,11 @@ func (s *ReservationService) UpdateReservationStatus(id int, status string, orga
 	}
 
 	// Update status
+	before := *reservation
 	reservation.Status = status
 	reservation.UpdatedAt = time.Now()
 
-	updatedReservation, err := s.repo.UpdateReservation(reservation)
+	updatedReservation, err := s.saveReservation(reservation, &before, actor, actionStatusChanged)
 	if err != nil {
 		return nil, err
 	}
@@ -304,13 +658,13 @@ func (s *ReservationService) UpdateReservationStatus(id int, status string, orga
 }
 
 // CancelReservation cancels a reservation
-func (s *ReservationService) CancelReservation(id int, organizationID int64) (*Reservation, error) {
-	return s.UpdateReservationStatus(id, "CANCELLED", organizationID)
+func (s *ReservationService) CancelReservation(id int, organizationID int64, actor audit.Actor) (*Reservation, error) {
+	return s.UpdateReservationStatus(id, "CANCELLED", organizationID, actor)
 }
 
 // ConfirmReservation confirms a pending reservation
-func (s *ReservationService) ConfirmReservation(id int, organizationID int64) (*Reservation, error) {
-	return s.UpdateReservationStatus(id, "CONFIRMED", organizationID)
+func (s *ReservationService) ConfirmReservation(id int, organizationID int64, actor audit.Actor) (*Reservation, error) {
+	return s.UpdateReservationStatus(id, "CONFIRMED", organizationID, actor)
 }
 
 /*// CheckInReservation marks a reservation as checked in
@@ -323,15 +677,24 @@ func (s *ReservationService) CheckOutReservation(id int, organizationID int64) (
 	return s.UpdateReservationStatus(id, "checked_out", organizationID)
 }*/
 
-// GetReservationsByCustomer returns all reservations for a customer
-func (s *ReservationService) GetReservationsByCustomer(customerID int) ([]Reservation, error) {
-	reservations, err := s.repo.GetReservationsByCustomer(customerID)
+// GetReservationsByCustomer returns all reservations of a customer within an organization
+func (s *ReservationService) GetReservationsByCustomer(customerID int, organizationID int64) ([]Reservation, error) {
+	reservations, err := s.repo.GetReservationsByCustomer(customerID, organizationID)
 	if err != nil {
 		return nil, err
 	}
 	return reservations, nil
 }
 
+// GetCustomerSummary returns the stay history and lifetime value of a customer
+func (s *ReservationService) GetCustomerSummary(customerID int, organizationID int64) (*CustomerSummary, error) {
+	reservations, err := s.repo.GetReservationsByCustomer(customerID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	return summarizeCustomerStays(customerID, reservations, time.Now()), nil
+}
+
 // GetReservationsByProperty returns all reservations for a property
 func (s *ReservationService) GetReservationsByProperty(propertyID int) ([]Reservation, error) {
 	reservations, err := s.repo.GetReservationsByProperty(propertyID)
@@ -350,6 +713,54 @@ func (s *ReservationService) GetReservationsByOrganization(organizationID int) (
 	return reservations, nil
 }
 
+// summarizeCustomerStays aggregates a customer's reservations. A stay is a
+// confirmed or completed reservation that has already started; revenue and
+// nights are only counted for stays. Holds are left out: they are not stays
+// the customer booked, even once released or expired.
+func summarizeCustomerStays(customerID int, reservations []Reservation, now time.Time) *CustomerSummary {
+	summary := &CustomerSummary{
+		CustomerID:    customerID,
+		UpcomingStays: []ReservationResponse{},
+	}
+
+	for i := range reservations {
+		r := &reservations[i]
+		if r.Kind == KindHold {
+			continue
+		}
+
+		switch r.Status {
+		case StatusCancelled:
+			summary.Cancellations++
+		case StatusNoShow:
+			summary.NoShows++
+		case StatusConfirmed, StatusCompleted:
+			if r.CheckInDate.After(now) {
+				summary.UpcomingStays = append(summary.UpcomingStays, *r.ToResponse())
+				continue
+			}
+			summary.TotalStays++
+			summary.TotalNights += r.Nights()
+			summary.TotalRevenue += r.TotalPrice
+			if summary.LastStay == nil || r.CheckInDate.After(*summary.LastStay) {
+				lastStay := r.CheckInDate
+				summary.LastStay = &lastStay
+			}
+		case StatusCreated, StatusPaymentRequired:
+			if r.CheckInDate.After(now) {
+				summary.UpcomingStays = append(summary.UpcomingStays, *r.ToResponse())
+			}
+		}
+	}
+
+	sort.Slice(summary.UpcomingStays, func(i, j int) bool {
+		return summary.UpcomingStays[i].CheckInDate.Before(summary.UpcomingStays[j].CheckInDate)
+	})
+	summary.IsRepeatGuest = summary.TotalStays > 1
+
+	return summary
+}
+
 /*// validateReservationRequest validates the reservation request
 func (s *ReservationService) validateReservationRequest(req *ReservationRequest) error {
 	if req.OrganizationID == uuid.Nil {
@@ -0,0 +1,236 @@
+package booking
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"hostflow/booking-service/internal/audit"
+	"hostflow/booking-service/internal/communication"
+	"hostflow/booking-service/internal/dbtest"
+	"hostflow/booking-service/internal/encryption"
+	"hostflow/booking-service/internal/guest"
+	"hostflow/booking-service/internal/touristtax"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func day(year int, month time.Month, d, hour int) time.Time {
+	return time.Date(year, month, d, hour, 0, 0, 0, time.UTC)
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Info(args ...interface{})  {}
+func (nopLogger) Fatal(args ...interface{}) {}
+func (nopLogger) Error(args ...interface{}) {}
+
+// fakeWaitlist records the stays released to the waitlist
+type fakeWaitlist struct {
+	mu       sync.Mutex
+	released []time.Time
+}
+
+func (f *fakeWaitlist) DatesReleased(organizationID, propertyID int64, checkIn, checkOut time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.released = append(f.released, checkIn)
+	return nil
+}
+
+func (f *fakeWaitlist) StayBooked(organizationID, customerID, propertyID int64, checkIn, checkOut time.Time) error {
+	return nil
+}
+
+// fakeEvents records the types of the published events
+type fakeEvents struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (f *fakeEvents) Publish(eventType, key string, payload any) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, eventType)
+	return nil
+}
+
+// newDBService returns a ReservationService backed by the test database,
+// with fakes for the services it calls over the network
+func newDBService(t *testing.T, db *pgxpool.Pool) *ReservationService {
+	t.Helper()
+
+	guestData, err := encryption.NewFieldEncryptor(nil, nil)
+	require.NoError(t, err)
+	rules, err := guest.ParseRules("")
+	require.NoError(t, err)
+
+	return GetReservationService(
+		GetReservationRepository(db),
+		communication.NewEmailDispatcher(nil, communication.NewDeliveryRepository(db), nopLogger{}),
+		audit.NewRepository(db),
+		guestData,
+		rules,
+		touristtax.NewService(touristtax.NewRepository(db), guestData, nopLogger{}),
+		nil,
+		&fakeWaitlist{},
+		&fakeEvents{},
+		nopLogger{},
+	)
+}
+
+// roundTripFunc lets a function stand in for an http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// stubPayments makes the payment service of service answer every payment
+// request with paymentURL
+func stubPayments(service *ReservationService, paymentURL string) {
+	service.payments = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(strings.NewReader(paymentURL)),
+			Header:     make(http.Header),
+			Request:    req,
+		}, nil
+	})}
+}
+
+func stayRequest(propertyID int, checkIn, checkOut time.Time) *ReservationRequest {
+	return &ReservationRequest{
+		PropertyID:   propertyID,
+		CustomerID:   2,
+		CheckInDate:  checkIn,
+		CheckOutDate: checkOut,
+		NoOfGuests:   2,
+		TotalPrice:   300,
+	}
+}
+
+func TestInsertReservation_LocksPropertyAndChecksAvailability(t *testing.T) {
+	db := dbtest.Open(t)
+	service := newDBService(t, db)
+	actor := audit.SystemActor("test")
+
+	created, err := service.insertReservation(newReservation(stayRequest(10, day(2025, 6, 1, 14), day(2025, 6, 4, 10)), 100, KindBooking), actor)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCreated, created.Status)
+
+	_, err = service.insertReservation(newReservation(stayRequest(10, day(2025, 6, 3, 14), day(2025, 6, 5, 10)), 100, KindBooking), actor)
+	assert.ErrorIs(t, err, ErrPropertyUnavailable)
+
+	// Concurrent requests for the same dates: exactly one gets them
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := service.insertReservation(newReservation(stayRequest(11, day(2025, 6, 1, 14), day(2025, 6, 4, 10)), 100, KindBooking), actor)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	var succeeded int
+	for err := range errs {
+		if err == nil {
+			succeeded++
+		} else {
+			assert.ErrorIs(t, err, ErrPropertyUnavailable)
+		}
+	}
+	assert.Equal(t, 1, succeeded)
+}
+
+func TestRestoreReservation_ChecksAvailabilityUnderLock(t *testing.T) {
+	db := dbtest.Open(t)
+	service := newDBService(t, db)
+	actor := audit.SystemActor("test")
+
+	deleted, err := service.insertReservation(newReservation(stayRequest(10, day(2025, 6, 1, 14), day(2025, 6, 4, 10)), 100, KindBooking), actor)
+	require.NoError(t, err)
+	require.NoError(t, service.DeleteReservation(deleted.ID, 100, actor))
+
+	// The dates of a deleted reservation are free again
+	taken, err := service.insertReservation(newReservation(stayRequest(10, day(2025, 6, 2, 14), day(2025, 6, 3, 10)), 100, KindBooking), actor)
+	require.NoError(t, err)
+
+	_, err = service.RestoreReservation(deleted.ID, 100, actor)
+	assert.ErrorIs(t, err, ErrPropertyUnavailable)
+
+	require.NoError(t, service.DeleteReservation(taken.ID, 100, actor))
+	restored, err := service.RestoreReservation(deleted.ID, 100, actor)
+	require.NoError(t, err)
+	assert.Equal(t, deleted.ID, restored.ID)
+}
+
+func TestReservationNights(t *testing.T) {
+	// Prihod ob 15:00 in odhod ob 11:00 je še vedno 5 noči
+	r := Reservation{CheckInDate: day(2024, 12, 20, 15), CheckOutDate: day(2024, 12, 25, 11)}
+	assert.Equal(t, 5, r.Nights())
+
+	r = Reservation{CheckInDate: day(2024, 12, 25, 15), CheckOutDate: day(2024, 12, 20, 11)}
+	assert.Equal(t, 0, r.Nights())
+}
+
+func TestSummarizeCustomerStays(t *testing.T) {
+	now := day(2025, 6, 1, 12)
+	reservations := []Reservation{
+		{ID: 1, Status: StatusCompleted, TotalPrice: 400, CheckInDate: day(2024, 7, 1, 15), CheckOutDate: day(2024, 7, 5, 10)},
+		{ID: 2, Status: StatusCompleted, TotalPrice: 300, CheckInDate: day(2025, 2, 10, 15), CheckOutDate: day(2025, 2, 13, 10)},
+		{ID: 3, Status: StatusCancelled, TotalPrice: 900, CheckInDate: day(2025, 3, 1, 15), CheckOutDate: day(2025, 3, 8, 10)},
+		{ID: 4, Status: StatusNoShow, TotalPrice: 100, CheckInDate: day(2025, 4, 1, 15), CheckOutDate: day(2025, 4, 2, 10)},
+		{ID: 5, Status: StatusConfirmed, TotalPrice: 500, CheckInDate: day(2025, 8, 1, 15), CheckOutDate: day(2025, 8, 6, 10)},
+		{ID: 6, Status: StatusPaymentRequired, TotalPrice: 200, CheckInDate: day(2025, 7, 1, 15), CheckOutDate: day(2025, 7, 3, 10)},
+		{ID: 7, Status: StatusPaymentRequired, TotalPrice: 200, CheckInDate: day(2025, 1, 1, 15), CheckOutDate: day(2025, 1, 3, 10)},
+	}
+
+	summary := summarizeCustomerStays(42, reservations, now)
+
+	assert.Equal(t, 42, summary.CustomerID)
+	assert.Equal(t, 2, summary.TotalStays)
+	assert.Equal(t, 7, summary.TotalNights)
+	assert.Equal(t, 700.0, summary.TotalRevenue)
+	assert.Equal(t, 1, summary.Cancellations)
+	assert.Equal(t, 1, summary.NoShows)
+	assert.True(t, summary.IsRepeatGuest)
+	if assert.NotNil(t, summary.LastStay) {
+		assert.Equal(t, day(2025, 2, 10, 15), *summary.LastStay)
+	}
+	if assert.Len(t, summary.UpcomingStays, 2) {
+		assert.Equal(t, 6, summary.UpcomingStays[0].ID)
+		assert.Equal(t, 5, summary.UpcomingStays[1].ID)
+	}
+}
+
+func TestSummarizeCustomerStays_NoReservations(t *testing.T) {
+	summary := summarizeCustomerStays(42, nil, time.Now())
+
+	assert.Equal(t, 0, summary.TotalStays)
+	assert.Nil(t, summary.LastStay)
+	assert.False(t, summary.IsRepeatGuest)
+	assert.NotNil(t, summary.UpcomingStays)
+}
+
+func TestParsePurgeRetention(t *testing.T) {
+	retention, err := parsePurgeRetention("")
+	assert.NoError(t, err)
+	assert.Zero(t, retention)
+
+	retention, err = parsePurgeRetention("365")
+	assert.NoError(t, err)
+	assert.Equal(t, 365*24*time.Hour, retention)
+
+	_, err = parsePurgeRetention("1y")
+	assert.Error(t, err)
+}
@@ -32,7 +32,17 @@ SOFTWARE.
 package bootstrap
 
 import (
+	"hostflow/booking-service/internal/apikey"
+	"hostflow/booking-service/internal/audit"
 	"hostflow/booking-service/internal/booking"
+	"hostflow/booking-service/internal/invoice"
+	"hostflow/booking-service/internal/portal"
+	"hostflow/booking-service/internal/privacy"
+	"hostflow/booking-service/internal/report"
+	"hostflow/booking-service/internal/room"
+	"hostflow/booking-service/internal/scheduler"
+	"hostflow/booking-service/internal/touristtax"
+	"hostflow/booking-service/internal/waitlist"
 )
 
 // ======== TYPES ========
@@ -50,9 +60,29 @@ type Routes []Route
 // GetRoutes provides all the routes
 func GetRoutes(
 	bookingRoutes booking.ReservationRoutes,
+	schedulerRoutes scheduler.Routes,
+	apiKeyRoutes apikey.Routes,
+	auditRoutes audit.Routes,
+	privacyRoutes privacy.Routes,
+	portalRoutes portal.Routes,
+	touristTaxRoutes touristtax.Routes,
+	invoiceRoutes invoice.Routes,
+	reportRoutes report.Routes,
+	roomRoutes room.Routes,
+	waitlistRoutes waitlist.Routes,
 ) Routes {
 	return Routes{
 		bookingRoutes,
+		schedulerRoutes,
+		apiKeyRoutes,
+		auditRoutes,
+		privacyRoutes,
+		portalRoutes,
+		touristTaxRoutes,
+		invoiceRoutes,
+		reportRoutes,
+		roomRoutes,
+		waitlistRoutes,
 	}
 }
 
@@ -33,4 +33,8 @@ func NewCustomerClient(lc fx.Lifecycle) (pb.CommunicationServiceClient, error) {
 	return client, nil
 }
 
-var Module = fx.Provide(NewCustomerClient, NewController)
+var Module = fx.Options(
+	fx.Provide(NewCustomerClient, NewController),
+	fx.Provide(NewDeliveryRepository, NewEmailDispatcher),
+	fx.Invoke(RegisterDispatcherHooks),
+)
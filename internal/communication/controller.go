@@ -1,64 +1,176 @@
 package communication
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"os"
+	"strconv"
+	"time"
 
 	pb "hostflow/booking-service/internal/communication/proto"
+	"hostflow/booking-service/pkg/interfaces"
 
 	"github.com/gin-gonic/gin"
 )
 
-// CommunicationController wraps the gRPC client
+// defaultManualEmailLimit is used when EMAIL_MANUAL_LIMIT_PER_HOUR is not set.
+const defaultManualEmailLimit = 30
+
+// manualEmailWindow is the window the manual email limit applies to.
+const manualEmailWindow = time.Hour
+
+// SendEmailRequest represents a manually requested reservation email. The
+// recipient and payment link are taken from the stored reservation.
+type SendEmailRequest struct {
+	ReservationID int64  `json:"reservation_id" binding:"required,min=1" example:"1"`
+	Type          string `json:"type" binding:"required,oneof=PAYMENT CONFIRMATION PRE_ARRIVAL CHECK_IN_INSTRUCTIONS REVIEW_REQUEST" example:"PAYMENT"`
+}
+
+// CommunicationController handles reservation email requests
 type CommunicationController struct {
-	client pb.CommunicationServiceClient
+	deliveries   *DeliveryRepository
+	dispatcher   *EmailDispatcher
+	reservations interfaces.ReservationLookup
+	manualLimit  int
 }
 
 // NewController returns a new CommunicationController
-func NewController(client pb.CommunicationServiceClient) *CommunicationController {
-	return &CommunicationController{client: client}
+func NewController(
+	deliveries *DeliveryRepository,
+	dispatcher *EmailDispatcher,
+	reservations interfaces.ReservationLookup,
+) *CommunicationController {
+	manualLimit := defaultManualEmailLimit
+	if v, err := strconv.Atoi(os.Getenv("EMAIL_MANUAL_LIMIT_PER_HOUR")); err == nil && v > 0 {
+		manualLimit = v
+	}
+
+	return &CommunicationController{
+		deliveries:   deliveries,
+		dispatcher:   dispatcher,
+		reservations: reservations,
+		manualLimit:  manualLimit,
+	}
 }
 
-// SendEmailHandler handles POST requests to trigger email sending via gRPC
+// getOrgID returns the organization of the caller set by the auth
+// middleware
+func (c *CommunicationController) getOrgID(ctx *gin.Context) (int64, bool) {
+	val, exists := ctx.Get("organization_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return 0, false
+	}
+	orgID, ok := val.(int64)
+	if !ok || orgID <= 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid organization ID"})
+		return 0, false
+	}
+	return orgID, true
+}
+
+// SendEmailHandler godoc
+// @Summary Send a reservation email
+// @Description Queues an email to the guest of a reservation of the organization. The payment link is the one stored on the reservation. Each request is recorded and counted against a per-organization hourly limit.
+// @Tags communication
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param email body SendEmailRequest true "Email"
+// @Success 202 {object} Delivery
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /communication/email [post]
 func (c *CommunicationController) SendEmailHandler(ctx *gin.Context) {
-	// 1. Define the expected JSON body from the frontend/caller
-	var req struct {
-		CustomerID int64  `json:"customer_id"`
-		PropertyID string `json:"property_id"`
-		Type       int32  `json:"type"` // 1: PAYMENT, 2: CONFIRMATION
-		PaymentURL string `json:"payment_url"`
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
 	}
 
-	// 2. Bind incoming JSON to the struct
+	var req SendEmailRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
 		return
 	}
 
-	// 3. Call the gRPC SendEmail method
-	resp, err := c.client.SendEmail(ctx, &pb.SendEmailRequest{
-		CustomerId: req.CustomerID,
-		PropertyId: req.PropertyID,
-		Type:       pb.EmailType(req.Type),
-		PaymentUrl: req.PaymentURL,
-	})
+	// Scoping the reservation to the organization also scopes its customer
+	// and property, which were checked when the reservation was created.
+	reservation, err := c.reservations.GetReservationContact(req.ReservationID, orgID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if reservation == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Reservation not found"})
+		return
+	}
+
+	emailType := pb.EmailType(pb.EmailType_value[req.Type])
+	if emailType == pb.EmailType_PAYMENT && reservation.PaymentURL == "" {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Reservation has no payment link"})
+		return
+	}
+
+	requester := Requester{
+		UserID:   ctx.GetString("user_id"),
+		APIKeyID: ctx.GetInt64("api_key_id"),
+		ClientIP: ctx.ClientIP(),
+	}
 
-	// 4. Handle gRPC communication errors (e.g., service down)
+	delivery, retryAfter, err := c.dispatcher.EnqueueManual(EmailJob{
+		OrganizationID: orgID,
+		ReservationID:  reservation.ID,
+		CustomerID:     reservation.CustomerID,
+		PropertyID:     reservation.PropertyID,
+		Type:           emailType,
+		PaymentURL:     reservation.PaymentURL,
+	}, requester, c.manualLimit, manualEmailWindow)
+	if errors.Is(err, ErrRateLimited) {
+		ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		ctx.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many emails requested for this organization, try again later"})
+		return
+	}
 	if err != nil {
-		ctx.JSON(http.StatusBadGateway, gin.H{"error": "Failed to communicate with email service: " + err.Error()})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// 5. Return the result from the communication service
-	if !resp.Success {
-		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
-			"success": false,
-			"message": resp.Message,
-		})
+	ctx.JSON(http.StatusAccepted, delivery)
+}
+
+// GetReservationDeliveriesHandler godoc
+// @Summary Get email deliveries of a reservation
+// @Description Returns the delivery status of every email sent automatically for the reservation, newest first
+// @Tags reservations
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Reservation ID"
+// @Success 200 {array} Delivery
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /reservations/{id}/emails [get]
+func (c *CommunicationController) GetReservationDeliveriesHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	reservationID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid reservation id"})
+		return
+	}
+
+	deliveries, err := c.deliveries.GetDeliveriesByReservation(reservationID, orgID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": resp.Message,
-	})
+	ctx.JSON(http.StatusOK, deliveries)
 }
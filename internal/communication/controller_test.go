@@ -0,0 +1,106 @@
+package communication
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hostflow/booking-service/pkg/interfaces"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeReservations map[int64]*interfaces.ReservationContact
+
+func (f fakeReservations) GetReservationContact(reservationID, organizationID int64) (*interfaces.ReservationContact, error) {
+	if r, ok := f[reservationID]; ok && r.OrganizationID == organizationID {
+		return r, nil
+	}
+	return nil, nil
+}
+
+func newSendEmailRouter(reservations interfaces.ReservationLookup) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	controller := &CommunicationController{reservations: reservations, manualLimit: defaultManualEmailLimit}
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("organization_id", int64(100)) })
+	r.POST("/communication/email", controller.SendEmailHandler)
+	return r
+}
+
+func postEmail(r *gin.Engine, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/communication/email", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestSendEmailHandler_InvalidType(t *testing.T) {
+	r := newSendEmailRouter(fakeReservations{})
+
+	w := postEmail(r, `{"reservation_id": 1, "type": "NEWSLETTER"}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSendEmailHandler_ReservationOfOtherOrganization(t *testing.T) {
+	r := newSendEmailRouter(fakeReservations{
+		1: {ID: 1, OrganizationID: 200, CustomerID: 5, PropertyID: 10, PaymentURL: "https://pay.example.test/1"},
+	})
+
+	w := postEmail(r, `{"reservation_id": 1, "type": "PAYMENT"}`)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSendEmailHandler_PaymentWithoutStoredLink(t *testing.T) {
+	r := newSendEmailRouter(fakeReservations{
+		1: {ID: 1, OrganizationID: 100, CustomerID: 5, PropertyID: 10},
+	})
+
+	w := postEmail(r, `{"reservation_id": 1, "type": "PAYMENT", "payment_url": "https://evil.example.test"}`)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestSendEmailHandler_RejectsMissingOrganization(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := &CommunicationController{reservations: fakeReservations{}, manualLimit: defaultManualEmailLimit}
+
+	for name, setOrg := range map[string]gin.HandlerFunc{
+		"missing":    func(c *gin.Context) {},
+		"wrong type": func(c *gin.Context) { c.Set("organization_id", 100) },
+	} {
+		r := gin.New()
+		r.Use(setOrg)
+		r.POST("/communication/email", controller.SendEmailHandler)
+
+		w := postEmail(r, `{"reservation_id": 1, "type": "PAYMENT"}`)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code, name)
+	}
+}
+
+func TestGetReservationDeliveriesHandler_RejectsMissingOrganization(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := &CommunicationController{}
+
+	for name, setOrg := range map[string]gin.HandlerFunc{
+		"missing":    func(c *gin.Context) {},
+		"wrong type": func(c *gin.Context) { c.Set("organization_id", "100") },
+	} {
+		r := gin.New()
+		r.Use(setOrg)
+		r.GET("/reservations/:id/emails", controller.GetReservationDeliveriesHandler)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/reservations/1/emails", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code, name)
+	}
+}
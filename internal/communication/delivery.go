@@ -0,0 +1,293 @@
+package communication
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Delivery statuses
+const (
+	DeliveryPending  = "PENDING"
+	DeliverySending  = "SENDING"
+	DeliveryRetrying = "RETRYING"
+	DeliverySent     = "SENT"
+	DeliveryFailed   = "FAILED"
+)
+
+// Delivery sources
+const (
+	SourceAutomatic = "AUTOMATIC"
+	SourceManual    = "MANUAL"
+)
+
+// deliveryColumns are the columns scanned into Delivery
+const deliveryColumns = `
+    id, organization_id, reservation_id, customer_id, property_id,
+    email_type, payment_url, offer, status, attempts, last_error,
+    next_attempt_at, sent_at, source, requested_by, api_key_id, client_ip,
+    created_at, updated_at
+`
+
+// ErrRateLimited is returned when an organization has used up its manual email budget
+var ErrRateLimited = errors.New("manual email limit reached")
+
+// Delivery represents the delivery status of a single reservation email
+type Delivery struct {
+	ID             int64      `json:"id" db:"id"`
+	OrganizationID int64      `json:"organization_id" db:"organization_id"`
+	ReservationID  int64      `json:"reservation_id" db:"reservation_id"`
+	CustomerID     int64      `json:"customer_id" db:"customer_id"`
+	PropertyID     int64      `json:"property_id" db:"property_id"`
+	EmailType      string     `json:"email_type" db:"email_type" example:"PAYMENT"`
+	PaymentURL     string     `json:"-" db:"payment_url"`
+	Offer          *Offer     `json:"offer,omitempty" db:"offer"`
+	Status         string     `json:"status" db:"status" example:"SENT" enums:"PENDING SENDING RETRYING SENT FAILED"`
+	Attempts       int        `json:"attempts" db:"attempts" example:"1"`
+	LastError      string     `json:"last_error,omitempty" db:"last_error"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at" db:"next_attempt_at"`
+	SentAt         *time.Time `json:"sent_at,omitempty" db:"sent_at"`
+	Source         string     `json:"source" db:"source" example:"AUTOMATIC" enums:"AUTOMATIC MANUAL"`
+	RequestedBy    *string    `json:"requested_by,omitempty" db:"requested_by"`
+	APIKeyID       *int64     `json:"api_key_id,omitempty" db:"api_key_id"`
+	ClientIP       *string    `json:"client_ip,omitempty" db:"client_ip"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Offer is the stay offered to a waitlisted customer by a WAITLIST_OFFER
+// email, which is not about a reservation
+type Offer struct {
+	WaitlistEntryID int64     `json:"waitlist_entry_id" example:"12"`
+	CheckInDate     time.Time `json:"check_in_date"`
+	CheckOutDate    time.Time `json:"check_out_date"`
+	ExpiresAt       time.Time `json:"expires_at"`
+}
+
+// DeliveryRepository persists email deliveries
+type DeliveryRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewDeliveryRepository returns a DeliveryRepository
+func NewDeliveryRepository(db *pgxpool.Pool) *DeliveryRepository {
+	return &DeliveryRepository{
+		db: db,
+	}
+}
+
+// CreateDelivery stores a new pending delivery
+func (r *DeliveryRepository) CreateDelivery(d *Delivery) (*Delivery, error) {
+	query := `
+        INSERT INTO email_delivery (
+            organization_id, reservation_id, customer_id, property_id,
+            email_type, payment_url, offer, status
+        )
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING ` + deliveryColumns
+
+	rows, err := r.db.Query(context.Background(), query,
+		d.OrganizationID,
+		d.ReservationID,
+		d.CustomerID,
+		d.PropertyID,
+		d.EmailType,
+		d.PaymentURL,
+		d.Offer,
+		DeliveryPending,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	created, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[Delivery])
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// manualLockKey names the lock CreateManualDelivery holds while it counts and
+// stores the manual emails of an organization
+func manualLockKey(organizationID int64) string {
+	return "email_delivery_manual:" + strconv.FormatInt(organizationID, 10)
+}
+
+// CreateManualDelivery stores a delivery requested by a user or integration,
+// unless the organization already requested limit manual emails within the
+// window. In that case ErrRateLimited is returned together with the time
+// until the oldest counted email leaves the window. The organization is
+// locked for the duration of the check, so concurrent requests on several
+// replicas can't exceed the limit.
+func (r *DeliveryRepository) CreateManualDelivery(d *Delivery, limit int, window time.Duration) (*Delivery, time.Duration, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, manualLockKey(d.OrganizationID)); err != nil {
+		return nil, 0, err
+	}
+
+	var count int
+	var oldest *time.Time
+	err = tx.QueryRow(ctx, `
+        SELECT COUNT(*), MIN(created_at)
+        FROM email_delivery
+        WHERE organization_id = $1
+          AND source = $2
+          AND created_at > NOW() - $3::interval
+    `, d.OrganizationID, SourceManual, window.String()).Scan(&count, &oldest)
+	if err != nil {
+		return nil, 0, err
+	}
+	if count >= limit {
+		retryAfter := window
+		if oldest != nil {
+			retryAfter = time.Until(oldest.Add(window))
+		}
+		return nil, retryAfter, ErrRateLimited
+	}
+
+	query := `
+        INSERT INTO email_delivery (
+            organization_id, reservation_id, customer_id, property_id,
+            email_type, payment_url, status, source, requested_by, api_key_id, client_ip
+        )
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+        RETURNING ` + deliveryColumns
+
+	rows, err := tx.Query(ctx, query,
+		d.OrganizationID,
+		d.ReservationID,
+		d.CustomerID,
+		d.PropertyID,
+		d.EmailType,
+		d.PaymentURL,
+		DeliveryPending,
+		SourceManual,
+		d.RequestedBy,
+		d.APIKeyID,
+		d.ClientIP,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	created, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[Delivery])
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	return &created, 0, nil
+}
+
+// ClaimDueDeliveries marks up to limit deliveries that are due as SENDING and
+// returns them. Rows locked by another replica are skipped, and deliveries
+// stuck in SENDING for longer than staleAfter (e.g. after a crash) are
+// picked up again.
+func (r *DeliveryRepository) ClaimDueDeliveries(limit int, staleAfter time.Duration) ([]Delivery, error) {
+	query := `
+        UPDATE email_delivery
+        SET status = $1,
+            attempts = attempts + 1,
+            updated_at = NOW()
+        WHERE id IN (
+            SELECT id
+            FROM email_delivery
+            WHERE (status IN ($2, $3) AND next_attempt_at <= NOW())
+               OR (status = $1 AND updated_at < NOW() - $4::interval)
+            ORDER BY next_attempt_at
+            LIMIT $5
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING ` + deliveryColumns
+
+	rows, err := r.db.Query(context.Background(), query,
+		DeliverySending,
+		DeliveryPending,
+		DeliveryRetrying,
+		staleAfter.String(),
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[Delivery])
+}
+
+// MarkSent records a successful delivery
+func (r *DeliveryRepository) MarkSent(id int64) error {
+	query := `
+        UPDATE email_delivery
+        SET status = $2,
+            last_error = '',
+            sent_at = NOW(),
+            updated_at = NOW()
+        WHERE id = $1
+    `
+
+	_, err := r.db.Exec(context.Background(), query, id, DeliverySent)
+	return err
+}
+
+// MarkRetry records a failed attempt that will be retried at nextAttempt
+func (r *DeliveryRepository) MarkRetry(id int64, lastError string, nextAttempt time.Time) error {
+	query := `
+        UPDATE email_delivery
+        SET status = $2,
+            last_error = $3,
+            next_attempt_at = $4,
+            updated_at = NOW()
+        WHERE id = $1
+    `
+
+	_, err := r.db.Exec(context.Background(), query, id, DeliveryRetrying, lastError, nextAttempt)
+	return err
+}
+
+// MarkFailed records a delivery that ran out of attempts
+func (r *DeliveryRepository) MarkFailed(id int64, lastError string) error {
+	query := `
+        UPDATE email_delivery
+        SET status = $2,
+            last_error = $3,
+            updated_at = NOW()
+        WHERE id = $1
+    `
+
+	_, err := r.db.Exec(context.Background(), query, id, DeliveryFailed, lastError)
+	return err
+}
+
+// GetDeliveriesByReservation returns all deliveries of a reservation, newest first
+func (r *DeliveryRepository) GetDeliveriesByReservation(reservationID, organizationID int64) ([]Delivery, error) {
+	query := `SELECT ` + deliveryColumns + `
+        FROM email_delivery
+        WHERE reservation_id = $1
+          AND organization_id = $2
+        ORDER BY created_at DESC
+    `
+
+	rows, err := r.db.Query(context.Background(), query, reservationID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[Delivery])
+}
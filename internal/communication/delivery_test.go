@@ -0,0 +1,69 @@
+package communication
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"hostflow/booking-service/internal/dbtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func manualDelivery() *Delivery {
+	return &Delivery{
+		OrganizationID: 100,
+		ReservationID:  1,
+		CustomerID:     5,
+		PropertyID:     10,
+		EmailType:      "CONFIRMATION",
+	}
+}
+
+func TestCreateManualDelivery_AppliesLimit(t *testing.T) {
+	repo := NewDeliveryRepository(dbtest.Open(t))
+
+	for i := 0; i < 2; i++ {
+		created, _, err := repo.CreateManualDelivery(manualDelivery(), 2, time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, SourceManual, created.Source)
+	}
+
+	_, retryAfter, err := repo.CreateManualDelivery(manualDelivery(), 2, time.Hour)
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Greater(t, retryAfter, 59*time.Minute)
+}
+
+func TestCreateManualDelivery_ConcurrentRequestsRespectLimit(t *testing.T) {
+	repo := NewDeliveryRepository(dbtest.Open(t))
+
+	const requests, limit = 10, 3
+	var wg sync.WaitGroup
+	errs := make(chan error, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := repo.CreateManualDelivery(manualDelivery(), limit, time.Hour)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	var created, limited int
+	for err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case errors.Is(err, ErrRateLimited):
+			limited++
+		default:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	assert.Equal(t, limit, created)
+	assert.Equal(t, requests-limit, limited)
+}
@@ -0,0 +1,229 @@
+package communication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hostflow/booking-service/pkg/lib"
+	"os"
+	"strconv"
+	"time"
+
+	pb "hostflow/booking-service/internal/communication/proto"
+
+	"go.uber.org/fx"
+)
+
+const (
+	// defaultMaxAttempts is used when EMAIL_MAX_ATTEMPTS is not set.
+	defaultMaxAttempts = 5
+	// retryBaseDelay is the delay before the first retry; it doubles on
+	// every further attempt up to retryMaxDelay.
+	retryBaseDelay = 30 * time.Second
+	retryMaxDelay  = 30 * time.Minute
+	// pollInterval is how often the queue is checked when nobody nudges it.
+	pollInterval = 15 * time.Second
+	// staleSendAfter is how long a delivery may stay in SENDING before it is
+	// considered abandoned by a crashed replica.
+	staleSendAfter = 5 * time.Minute
+	// sendTimeout bounds a single gRPC call to the communication service.
+	sendTimeout = 10 * time.Second
+	// claimBatchSize is how many deliveries are claimed per query.
+	claimBatchSize = 20
+)
+
+// EmailJob describes an email that should be sent for a reservation, or for
+// a waitlist offer, which has no reservation and carries its Offer instead
+type EmailJob struct {
+	OrganizationID int64
+	ReservationID  int64
+	CustomerID     int64
+	PropertyID     int64
+	Type           pb.EmailType
+	PaymentURL     string
+	Offer          *Offer
+}
+
+// Requester identifies who asked for a manually requested email
+type Requester struct {
+	UserID   string
+	APIKeyID int64
+	ClientIP string
+}
+
+// EmailDispatcher sends reservation emails asynchronously. Every job is
+// persisted as a Delivery first, so the email_delivery table acts as a
+// durable queue: failed sends are retried with exponential backoff and
+// pending sends survive restarts.
+type EmailDispatcher struct {
+	client      pb.CommunicationServiceClient
+	repo        *DeliveryRepository
+	logger      lib.Logger
+	maxAttempts int
+	worker      *lib.Worker
+}
+
+// NewEmailDispatcher returns an EmailDispatcher
+func NewEmailDispatcher(
+	client pb.CommunicationServiceClient,
+	repo *DeliveryRepository,
+	logger lib.Logger,
+) *EmailDispatcher {
+	maxAttempts := defaultMaxAttempts
+	if v, err := strconv.Atoi(os.Getenv("EMAIL_MAX_ATTEMPTS")); err == nil && v > 0 {
+		maxAttempts = v
+	}
+
+	d := &EmailDispatcher{
+		client:      client,
+		repo:        repo,
+		logger:      logger,
+		maxAttempts: maxAttempts,
+	}
+	d.worker = lib.NewWorker(pollInterval, d.processDue)
+	return d
+}
+
+// Enqueue records the email as pending and wakes the worker. It never blocks
+// on the communication service.
+func (d *EmailDispatcher) Enqueue(job EmailJob) (*Delivery, error) {
+	delivery, err := d.repo.CreateDelivery(&Delivery{
+		OrganizationID: job.OrganizationID,
+		ReservationID:  job.ReservationID,
+		CustomerID:     job.CustomerID,
+		PropertyID:     job.PropertyID,
+		EmailType:      job.Type.String(),
+		PaymentURL:     job.PaymentURL,
+		Offer:          job.Offer,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to queue %s email: %w", job.Type, err)
+	}
+
+	d.Wake()
+
+	return delivery, nil
+}
+
+// EnqueueManual queues an email requested through the API, recording who
+// requested it. It returns ErrRateLimited and the time to wait once the
+// organization reached limit manual emails within the window.
+func (d *EmailDispatcher) EnqueueManual(job EmailJob, requester Requester, limit int, window time.Duration) (*Delivery, time.Duration, error) {
+	delivery := &Delivery{
+		OrganizationID: job.OrganizationID,
+		ReservationID:  job.ReservationID,
+		CustomerID:     job.CustomerID,
+		PropertyID:     job.PropertyID,
+		EmailType:      job.Type.String(),
+		PaymentURL:     job.PaymentURL,
+	}
+	if requester.UserID != "" {
+		delivery.RequestedBy = &requester.UserID
+	}
+	if requester.APIKeyID != 0 {
+		delivery.APIKeyID = &requester.APIKeyID
+	}
+	if requester.ClientIP != "" {
+		delivery.ClientIP = &requester.ClientIP
+	}
+
+	created, retryAfter, err := d.repo.CreateManualDelivery(delivery, limit, window)
+	if err != nil {
+		return nil, retryAfter, err
+	}
+
+	d.Wake()
+
+	return created, 0, nil
+}
+
+// Wake asks the worker to look for due deliveries right away, e.g. after
+// deliveries were inserted directly into the queue table.
+func (d *EmailDispatcher) Wake() {
+	d.worker.Wake()
+}
+
+// processDue claims and sends batches of due deliveries until none are left.
+func (d *EmailDispatcher) processDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		deliveries, err := d.repo.ClaimDueDeliveries(claimBatchSize, staleSendAfter)
+		if err != nil {
+			d.logger.Error("Failed to claim email deliveries:", err)
+			return
+		}
+		if len(deliveries) == 0 {
+			return
+		}
+
+		for i := range deliveries {
+			d.deliver(ctx, &deliveries[i])
+		}
+	}
+}
+
+// deliver performs a single send attempt and records its outcome.
+func (d *EmailDispatcher) deliver(ctx context.Context, delivery *Delivery) {
+	err := d.send(ctx, delivery)
+
+	switch {
+	case err == nil:
+		err = d.repo.MarkSent(delivery.ID)
+	case delivery.Attempts >= d.maxAttempts:
+		d.logger.Error(fmt.Sprintf("Giving up on %s email for reservation %d:", delivery.EmailType, delivery.ReservationID), err)
+		err = d.repo.MarkFailed(delivery.ID, err.Error())
+	default:
+		err = d.repo.MarkRetry(delivery.ID, err.Error(), time.Now().Add(retryDelay(delivery.Attempts)))
+	}
+
+	if err != nil {
+		d.logger.Error(fmt.Sprintf("Failed to record delivery %d:", delivery.ID), err)
+	}
+}
+
+// send calls the communication service.
+func (d *EmailDispatcher) send(ctx context.Context, delivery *Delivery) error {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	req := &pb.SendEmailRequest{
+		CustomerId:    delivery.CustomerID,
+		PropertyId:    strconv.FormatInt(delivery.PropertyID, 10),
+		Type:          pb.EmailType(pb.EmailType_value[delivery.EmailType]),
+		PaymentUrl:    delivery.PaymentURL,
+		ReservationId: delivery.ReservationID,
+	}
+	if offer := delivery.Offer; offer != nil {
+		req.WaitlistEntryId = offer.WaitlistEntryID
+		req.CheckInDate = offer.CheckInDate.Format(time.DateOnly)
+		req.CheckOutDate = offer.CheckOutDate.Format(time.DateOnly)
+		req.OfferExpiresAt = offer.ExpiresAt.Format(time.RFC3339)
+	}
+
+	resp, err := d.client.SendEmail(ctx, req)
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return errors.New(resp.Message)
+	}
+
+	return nil
+}
+
+// retryDelay returns the backoff before the next attempt, given how many
+// attempts have already been made.
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+	return delay
+}
+
+// RegisterDispatcherHooks starts the dispatcher worker with the application
+func RegisterDispatcherHooks(lifecycle fx.Lifecycle, dispatcher *EmailDispatcher) {
+	dispatcher.worker.Start(lifecycle)
+}
@@ -0,0 +1,17 @@
+package communication
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, retryDelay(1))
+	assert.Equal(t, time.Minute, retryDelay(2))
+	assert.Equal(t, 2*time.Minute, retryDelay(3))
+	assert.Equal(t, 16*time.Minute, retryDelay(6))
+	assert.Equal(t, retryMaxDelay, retryDelay(7))
+	assert.Equal(t, retryMaxDelay, retryDelay(50))
+}
@@ -27,6 +27,13 @@ const (
 	EmailType_UNKNOWN      EmailType = 0
 	EmailType_PAYMENT      EmailType = 1
 	EmailType_CONFIRMATION EmailType = 2
+	// Scheduled guest communications, sent relative to the stay dates.
+	EmailType_PRE_ARRIVAL           EmailType = 3
+	EmailType_CHECK_IN_INSTRUCTIONS EmailType = 4
+	EmailType_REVIEW_REQUEST        EmailType = 5
+	// Offer of dates that freed up to a waitlisted customer; not about a
+	// reservation.
+	EmailType_WAITLIST_OFFER EmailType = 6
 )
 
 // Enum value maps for EmailType.
@@ -35,11 +42,19 @@ var (
 		0: "UNKNOWN",
 		1: "PAYMENT",
 		2: "CONFIRMATION",
+		3: "PRE_ARRIVAL",
+		4: "CHECK_IN_INSTRUCTIONS",
+		5: "REVIEW_REQUEST",
+		6: "WAITLIST_OFFER",
 	}
 	EmailType_value = map[string]int32{
-		"UNKNOWN":      0,
-		"PAYMENT":      1,
-		"CONFIRMATION": 2,
+		"UNKNOWN":               0,
+		"PAYMENT":               1,
+		"CONFIRMATION":          2,
+		"PRE_ARRIVAL":           3,
+		"CHECK_IN_INSTRUCTIONS": 4,
+		"REVIEW_REQUEST":        5,
+		"WAITLIST_OFFER":        6,
 	}
 )
 
@@ -71,13 +86,21 @@ func (EmailType) EnumDescriptor() ([]byte, []int) {
 }
 
 type SendEmailRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	CustomerId    int64                  `protobuf:"varint,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
-	PropertyId    string                 `protobuf:"bytes,2,opt,name=property_id,json=propertyId,proto3" json:"property_id,omitempty"`
-	Type          EmailType              `protobuf:"varint,3,opt,name=type,proto3,enum=communication.v1.EmailType" json:"type,omitempty"`
-	PaymentUrl    string                 `protobuf:"bytes,4,opt,name=payment_url,json=paymentUrl,proto3" json:"payment_url,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	CustomerId int64                  `protobuf:"varint,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	PropertyId string                 `protobuf:"bytes,2,opt,name=property_id,json=propertyId,proto3" json:"property_id,omitempty"`
+	Type       EmailType              `protobuf:"varint,3,opt,name=type,proto3,enum=communication.v1.EmailType" json:"type,omitempty"`
+	PaymentUrl string                 `protobuf:"bytes,4,opt,name=payment_url,json=paymentUrl,proto3" json:"payment_url,omitempty"`
+	// Reservation the email is about; templates use it to render stay details.
+	ReservationId int64 `protobuf:"varint,5,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	// Stay offered by a WAITLIST_OFFER email. Dates are YYYY-MM-DD, the
+	// expiry RFC 3339.
+	WaitlistEntryId int64  `protobuf:"varint,6,opt,name=waitlist_entry_id,json=waitlistEntryId,proto3" json:"waitlist_entry_id,omitempty"`
+	CheckInDate     string `protobuf:"bytes,7,opt,name=check_in_date,json=checkInDate,proto3" json:"check_in_date,omitempty"`
+	CheckOutDate    string `protobuf:"bytes,8,opt,name=check_out_date,json=checkOutDate,proto3" json:"check_out_date,omitempty"`
+	OfferExpiresAt  string `protobuf:"bytes,9,opt,name=offer_expires_at,json=offerExpiresAt,proto3" json:"offer_expires_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *SendEmailRequest) Reset() {
@@ -138,6 +161,41 @@ func (x *SendEmailRequest) GetPaymentUrl() string {
 	return ""
 }
 
+func (x *SendEmailRequest) GetReservationId() int64 {
+	if x != nil {
+		return x.ReservationId
+	}
+	return 0
+}
+
+func (x *SendEmailRequest) GetWaitlistEntryId() int64 {
+	if x != nil {
+		return x.WaitlistEntryId
+	}
+	return 0
+}
+
+func (x *SendEmailRequest) GetCheckInDate() string {
+	if x != nil {
+		return x.CheckInDate
+	}
+	return ""
+}
+
+func (x *SendEmailRequest) GetCheckOutDate() string {
+	if x != nil {
+		return x.CheckOutDate
+	}
+	return ""
+}
+
+func (x *SendEmailRequest) GetOfferExpiresAt() string {
+	if x != nil {
+		return x.OfferExpiresAt
+	}
+	return ""
+}
+
 type SendEmailResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
@@ -194,7 +252,7 @@ var File_communication_proto protoreflect.FileDescriptor
 
 const file_communication_proto_rawDesc = "" +
 	"\n" +
-	"\x13communication.proto\x12\x10communication.v1\"\xa6\x01\n" +
+	"\x13communication.proto\x12\x10communication.v1\"\xed\x02\n" +
 	"\x10SendEmailRequest\x12\x1f\n" +
 	"\vcustomer_id\x18\x01 \x01(\x03R\n" +
 	"customerId\x12\x1f\n" +
@@ -202,14 +260,23 @@ const file_communication_proto_rawDesc = "" +
 	"propertyId\x12/\n" +
 	"\x04type\x18\x03 \x01(\x0e2\x1b.communication.v1.EmailTypeR\x04type\x12\x1f\n" +
 	"\vpayment_url\x18\x04 \x01(\tR\n" +
-	"paymentUrl\"G\n" +
+	"paymentUrl\x12%\n" +
+	"\x0ereservation_id\x18\x05 \x01(\x03R\rreservationId\x12*\n" +
+	"\x11waitlist_entry_id\x18\x06 \x01(\x03R\x0fwaitlistEntryId\x12\"\n" +
+	"\rcheck_in_date\x18\a \x01(\tR\vcheckInDate\x12$\n" +
+	"\x0echeck_out_date\x18\b \x01(\tR\fcheckOutDate\x12(\n" +
+	"\x10offer_expires_at\x18\t \x01(\tR\x0eofferExpiresAt\"G\n" +
 	"\x11SendEmailResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage*7\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage*\x8b\x01\n" +
 	"\tEmailType\x12\v\n" +
 	"\aUNKNOWN\x10\x00\x12\v\n" +
 	"\aPAYMENT\x10\x01\x12\x10\n" +
-	"\fCONFIRMATION\x10\x022l\n" +
+	"\fCONFIRMATION\x10\x02\x12\x0f\n" +
+	"\vPRE_ARRIVAL\x10\x03\x12\x19\n" +
+	"\x15CHECK_IN_INSTRUCTIONS\x10\x04\x12\x12\n" +
+	"\x0eREVIEW_REQUEST\x10\x05\x12\x12\n" +
+	"\x0eWAITLIST_OFFER\x10\x062l\n" +
 	"\x14CommunicationService\x12T\n" +
 	"\tSendEmail\x12\".communication.v1.SendEmailRequest\x1a#.communication.v1.SendEmailResponseB,Z*hostflow/extra/communication;communicationb\x06proto3"
 
@@ -1,73 +1,99 @@
 package customer
 
 import (
-	_ "context"
+	"context"
 	pb "hostflow/booking-service/internal/customer/proto"
+	"hostflow/booking-service/pkg/interfaces"
 	"net/http"
 	"strconv"
 
-	//pb "github.com/hostflow/extra/customerpb"
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// duplicateDeleter deletes the duplicate customer queued by a merge
+type duplicateDeleter interface {
+	DeleteNow(ctx context.Context, organizationID, customerID int64) error
+}
+
 // CustomerController Controller wraps the gRPC client
 type CustomerController struct {
-	client pb.CustomerServiceClient
+	client       pb.CustomerServiceClient
+	reservations interfaces.ReservationReassigner
+	deletions    duplicateDeleter
 }
 
 // NewController returns a CustomerController
-func NewController(client pb.CustomerServiceClient) *CustomerController {
-	return &CustomerController{client: client}
+func NewController(
+	client pb.CustomerServiceClient,
+	reservations interfaces.ReservationReassigner,
+	deletions *Deleter,
+) *CustomerController {
+	return &CustomerController{
+		client:       client,
+		reservations: reservations,
+		deletions:    deletions,
+	}
 }
 
 // GetCustomerHandler godoc
 // @Summary Get all customers
-// @Description Returns a list of customers filtered by organization ID
+// @Description Returns a page of the organization's customers, optionally filtered by name or email
 // @Tags customers
 // @Produce json
-// @Param limit query int false "Pagination limit"
+// @Param q query string false "Search by full name or email"
+// @Param limit query int false "Pagination limit (default 20, max 100)"
 // @Param offset query int false "Pagination offset"
-// @Success 200 {array} pb.Customer
+// @Success 200 {object} CustomerPage
+// @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Router /customers [get]
 func (c *CustomerController) GetCustomerHandler(ctx *gin.Context) {
-	val, exists := ctx.Get("organization_id")
-
-	if !exists {
-		ctx.JSON(401, gin.H{"error": "Organization ID not found in session"})
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
 		return
 	}
 
-	// 2. Type-assert the value to int64
-	orgID, ok := val.(int64)
+	limit, offset, ok := c.getPagination(ctx)
 	if !ok {
-		ctx.JSON(500, gin.H{"error": "Internal server error: ID format mismatch"})
 		return
 	}
 
-	limitStr := ctx.Query("limit")
-	offsetStr := ctx.Query("offset")
-
-	limit, _ := strconv.Atoi(limitStr)
-	offset, _ := strconv.Atoi(offsetStr)
-
-	resp, err := c.client.ListCustomers(ctx, &pb.ListCustomersRequest{
-		Limit:  int32(limit),
-		Offset: int32(offset),
-	})
+	var (
+		resp *pb.ListCustomersResponse
+		err  error
+	)
+	if query := ctx.Query("q"); query != "" {
+		resp, err = c.client.SearchCustomers(ctx, &pb.SearchCustomersRequest{
+			OrganizationId: orgID,
+			Query:          query,
+			Limit:          limit,
+			Offset:         offset,
+		})
+	} else {
+		resp, err = c.client.ListOrganizationCustomers(ctx, &pb.ListOrganizationCustomersRequest{
+			OrganizationId: orgID,
+			Limit:          limit,
+			Offset:         offset,
+		})
+	}
 	if err != nil {
-		ctx.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		ctx.JSON(grpcHTTPStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	var filteredCustomers []*pb.Customer
-	for _, cust := range resp.Customers {
-		if cust.OrganizationId == orgID {
-			filteredCustomers = append(filteredCustomers, cust)
-		}
+	items := resp.Customers
+	if items == nil {
+		items = []*pb.Customer{}
 	}
 
-	ctx.JSON(http.StatusOK, filteredCustomers)
+	ctx.JSON(http.StatusOK, CustomerPage{
+		Items:  items,
+		Total:  resp.Total,
+		Limit:  limit,
+		Offset: offset,
+	})
 }
 
 // CreateCustomerHandler godoc
@@ -123,47 +149,123 @@ func (c *CustomerController) GetCustomerByIDHandler(ctx *gin.Context) {
 		return
 	}
 
-	idStr := ctx.Param("id")
-	id, err := strconv.ParseInt(idStr, 10, 64)
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid customer id"})
 		return
 	}
 
-	limitStr := ctx.Query("limit")
-	offsetStr := ctx.Query("offset")
+	cust, ok := c.getOrganizationCustomer(ctx, id, orgID)
+	if !ok {
+		return
+	}
 
-	limit, _ := strconv.Atoi(limitStr)
-	offset, _ := strconv.Atoi(offsetStr)
+	ctx.JSON(http.StatusOK, cust)
+}
 
-	resp, err := c.client.ListCustomers(ctx, &pb.ListCustomersRequest{
-		Limit:  int32(limit),
-		Offset: int32(offset),
-	})
+// UpdateCustomerHandler godoc
+// @Summary Update a customer
+// @Description Replaces the name and email of a customer within the organization
+// @Tags customers
+// @Accept json
+// @Produce json
+// @Param id path int true "Customer ID"
+// @Param customer body UpdateCustomerRequest true "Customer details"
+// @Success 200 {object} pb.Customer
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /customers/{id} [put]
+func (c *CustomerController) UpdateCustomerHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
 	if err != nil {
-		ctx.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid customer id"})
 		return
 	}
 
-	for _, cust := range resp.Customers {
-		if cust.OrganizationId == orgID && cust.Id == id {
-			ctx.JSON(http.StatusOK, cust)
-			return
-		}
+	var req UpdateCustomerRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, ok := c.getOrganizationCustomer(ctx, id, orgID); !ok {
+		return
+	}
+
+	resp, err := c.client.UpdateCustomer(ctx, &pb.UpdateCustomerRequest{
+		Id:             id,
+		OrganizationId: orgID,
+		FullName:       req.FullName,
+		Email:          req.Email,
+	})
+	if err != nil {
+		ctx.JSON(grpcHTTPStatus(err), gin.H{"error": err.Error()})
+		return
 	}
 
-	ctx.JSON(http.StatusNotFound, gin.H{"error": "customer not found or access denied"})
+	ctx.JSON(http.StatusOK, resp.Customer)
 }
 
-/*// UpdateCustomerHandler updates a customer (assuming you implement Update RPC)
-func (c *CustomerController) UpdateCustomerHandler(ctx *gin.Context) {
+// MergeCustomersHandler godoc
+// @Summary Merge duplicate customers
+// @Description Moves every reservation of the duplicate customer to the surviving customer and deletes the duplicate. Both customers must belong to the organization. The duplicate is deleted once the reservations are moved; if the customer service can't delete it right away, duplicate_deleted is false and the deletion is retried in the background.
+// @Tags customers
+// @Accept json
+// @Produce json
+// @Param merge body MergeCustomersRequest true "Customers to merge"
+// @Success 200 {object} MergeCustomersResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /customers/merge [post]
+func (c *CustomerController) MergeCustomersHandler(ctx *gin.Context) {
 	orgID, ok := c.getOrgID(ctx)
 	if !ok {
 		return
 	}
 
-	ctx.JSON(http.StatusNotImplemented, gin.H{"error": "UpdateCustomer RPC not implemented in proto"})
-}*/
+	var req MergeCustomersRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.SurvivingID == req.DuplicateID {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "cannot merge a customer into itself"})
+		return
+	}
+
+	surviving, ok := c.getOrganizationCustomer(ctx, req.SurvivingID, orgID)
+	if !ok {
+		return
+	}
+	if _, ok := c.getOrganizationCustomer(ctx, req.DuplicateID, orgID); !ok {
+		return
+	}
+
+	// The reservations are moved and the duplicate's deletion is queued in
+	// one transaction; the duplicate is deleted after the commit, and a
+	// failed delete is retried in the background.
+	moved, err := c.reservations.ReassignCustomer(orgID, req.DuplicateID, req.SurvivingID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	deleted := c.deletions.DeleteNow(ctx, orgID, req.DuplicateID) == nil
+
+	ctx.JSON(http.StatusOK, MergeCustomersResponse{
+		Customer:          surviving,
+		MergedCustomerID:  req.DuplicateID,
+		ReservationsMoved: moved,
+		DuplicateDeleted:  deleted,
+	})
+}
 
 // DeleteCustomerHandler godoc
 // @Summary Delete a customer
@@ -177,22 +279,18 @@ func (c *CustomerController) DeleteCustomerHandler(ctx *gin.Context) {
 		return
 	}
 
-	id, _ := strconv.ParseInt(ctx.Param("id"), 10, 64)
-
-	// STEP 1: Fetch the customer first to check ownership
-	checkResp, err := c.client.GetCustomer(ctx, &pb.GetCustomerRequest{Id: id})
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid customer id"})
 		return
 	}
 
-	// STEP 2: Verify OrgID
-	if checkResp.Customer.OrganizationId != orgID {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "Unauthorized delete attempt"})
+	// STEP 1: Make sure the customer belongs to the caller's organization
+	if _, ok := c.getOrganizationCustomer(ctx, id, orgID); !ok {
 		return
 	}
 
-	// STEP 3: Proceed with delete
+	// STEP 2: Proceed with delete
 	resp, err := c.client.DeleteCustomer(ctx, &pb.DeleteCustomerRequest{Id: id})
 	if err != nil {
 		ctx.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
@@ -202,6 +300,57 @@ func (c *CustomerController) DeleteCustomerHandler(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"success": resp.Success})
 }
 
+// getOrganizationCustomer fetches a single customer scoped to the organization
+// and writes a 404 response when it does not exist or belongs to someone else.
+func (c *CustomerController) getOrganizationCustomer(ctx *gin.Context, id, orgID int64) (*pb.Customer, bool) {
+	resp, err := c.client.GetOrganizationCustomer(ctx, &pb.GetOrganizationCustomerRequest{
+		Id:             id,
+		OrganizationId: orgID,
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "customer not found"})
+			return nil, false
+		}
+		ctx.JSON(grpcHTTPStatus(err), gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	// Never trust the upstream filter blindly; a customer from another
+	// organization is reported exactly like a missing one.
+	if resp.Customer == nil || resp.Customer.OrganizationId != orgID {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "customer not found"})
+		return nil, false
+	}
+
+	return resp.Customer, true
+}
+
+// getPagination parses the limit and offset query parameters.
+func (c *CustomerController) getPagination(ctx *gin.Context) (int32, int32, bool) {
+	limit := defaultPageLimit
+	if limitStr := ctx.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return 0, 0, false
+		}
+		limit = min(parsed, maxPageLimit)
+	}
+
+	offset := 0
+	if offsetStr := ctx.Query("offset"); offsetStr != "" {
+		parsed, err := strconv.Atoi(offsetStr)
+		if err != nil || parsed < 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative integer"})
+			return 0, 0, false
+		}
+		offset = parsed
+	}
+
+	return int32(limit), int32(offset), true
+}
+
 func (c *CustomerController) getOrgID(ctx *gin.Context) (int64, bool) {
 	val, exists := ctx.Get("organization_id")
 	if !exists {
@@ -215,3 +364,18 @@ func (c *CustomerController) getOrgID(ctx *gin.Context) (int64, bool) {
 	}
 	return orgID, true
 }
+
+// grpcHTTPStatus maps a gRPC error returned by the customer service to the
+// closest HTTP status code.
+func grpcHTTPStatus(err error) int {
+	switch status.Code(err) {
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	default:
+		return http.StatusBadGateway
+	}
+}
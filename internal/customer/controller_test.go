@@ -0,0 +1,295 @@
+package customer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pb "hostflow/booking-service/internal/customer/proto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MockCustomerClient only implements the RPCs exercised by the tests; any
+// other call panics through the embedded nil interface.
+type MockCustomerClient struct {
+	pb.CustomerServiceClient
+	mock.Mock
+}
+
+func (m *MockCustomerClient) ListOrganizationCustomers(ctx context.Context, in *pb.ListOrganizationCustomersRequest, opts ...grpc.CallOption) (*pb.ListCustomersResponse, error) {
+	args := m.Called(in.OrganizationId, in.Limit, in.Offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.ListCustomersResponse), args.Error(1)
+}
+
+func (m *MockCustomerClient) SearchCustomers(ctx context.Context, in *pb.SearchCustomersRequest, opts ...grpc.CallOption) (*pb.ListCustomersResponse, error) {
+	args := m.Called(in.OrganizationId, in.Query, in.Limit, in.Offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.ListCustomersResponse), args.Error(1)
+}
+
+func (m *MockCustomerClient) GetOrganizationCustomer(ctx context.Context, in *pb.GetOrganizationCustomerRequest, opts ...grpc.CallOption) (*pb.CustomerResponse, error) {
+	args := m.Called(in.Id, in.OrganizationId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.CustomerResponse), args.Error(1)
+}
+
+func (m *MockCustomerClient) UpdateCustomer(ctx context.Context, in *pb.UpdateCustomerRequest, opts ...grpc.CallOption) (*pb.CustomerResponse, error) {
+	args := m.Called(in.Id, in.OrganizationId, in.FullName, in.Email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.CustomerResponse), args.Error(1)
+}
+
+func (m *MockCustomerClient) DeleteCustomer(ctx context.Context, in *pb.DeleteCustomerRequest, opts ...grpc.CallOption) (*pb.DeleteCustomerResponse, error) {
+	args := m.Called(in.Id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.DeleteCustomerResponse), args.Error(1)
+}
+
+// fakeReassigner mimics the reservation repository: the reservations are
+// moved and the duplicate's deletion is queued in one commit.
+type fakeReassigner struct {
+	reservations int64
+	err          error
+	committed    bool
+}
+
+func (f *fakeReassigner) ReassignCustomer(organizationID, fromCustomerID, toCustomerID int64) (int64, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+	f.committed = true
+	return f.reservations, nil
+}
+
+// fakeDeleter records the deletions attempted after a merge
+type fakeDeleter struct {
+	err     error
+	deleted []int64
+}
+
+func (f *fakeDeleter) DeleteNow(ctx context.Context, organizationID, customerID int64) error {
+	f.deleted = append(f.deleted, customerID)
+	return f.err
+}
+
+func newMergeController(client pb.CustomerServiceClient, reservations *fakeReassigner, deletions *fakeDeleter) *CustomerController {
+	return &CustomerController{
+		client:       client,
+		reservations: reservations,
+		deletions:    deletions,
+	}
+}
+
+func newTestRouter(controller *CustomerController) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("organization_id", int64(100))
+	})
+	r.GET("/customer", controller.GetCustomerHandler)
+	r.GET("/customer/:id", controller.GetCustomerByIDHandler)
+	r.PUT("/customer/:id", controller.UpdateCustomerHandler)
+	r.POST("/customer/merge", controller.MergeCustomersHandler)
+	return r
+}
+
+func TestGetCustomerHandler_PaginatesByOrganization(t *testing.T) {
+	client := new(MockCustomerClient)
+	r := newTestRouter(NewController(client, nil, nil))
+
+	client.On("ListOrganizationCustomers", int64(100), int32(10), int32(20)).Return(&pb.ListCustomersResponse{
+		Customers: []*pb.Customer{{Id: 1, FullName: "Ana Novak", OrganizationId: 100}},
+		Total:     21,
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/customer?limit=10&offset=20", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"items":[{"id":1,"full_name":"Ana Novak","organization_id":100}],"total":21,"limit":10,"offset":20}`, w.Body.String())
+	client.AssertExpectations(t)
+}
+
+func TestGetCustomerHandler_SearchCapsLimit(t *testing.T) {
+	client := new(MockCustomerClient)
+	r := newTestRouter(NewController(client, nil, nil))
+
+	client.On("SearchCustomers", int64(100), "novak", int32(maxPageLimit), int32(0)).Return(&pb.ListCustomersResponse{}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/customer?q=novak&limit=5000", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"items":[],"total":0,"limit":100,"offset":0}`, w.Body.String())
+	client.AssertExpectations(t)
+}
+
+func TestGetCustomerHandler_InvalidLimit(t *testing.T) {
+	client := new(MockCustomerClient)
+	r := newTestRouter(NewController(client, nil, nil))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/customer?limit=-1", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	client.AssertNotCalled(t, "ListOrganizationCustomers")
+}
+
+func TestGetCustomerByIDHandler_NotFound(t *testing.T) {
+	client := new(MockCustomerClient)
+	r := newTestRouter(NewController(client, nil, nil))
+
+	client.On("GetOrganizationCustomer", int64(7), int64(100)).Return(nil, status.Error(codes.NotFound, "no such customer"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/customer/7", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	client.AssertExpectations(t)
+}
+
+func TestGetCustomerByIDHandler_OtherOrganization(t *testing.T) {
+	client := new(MockCustomerClient)
+	r := newTestRouter(NewController(client, nil, nil))
+
+	// A misbehaving upstream must not leak another tenant's customer.
+	client.On("GetOrganizationCustomer", int64(7), int64(100)).Return(&pb.CustomerResponse{
+		Customer: &pb.Customer{Id: 7, OrganizationId: 200},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/customer/7", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.NotContains(t, w.Body.String(), `"organization_id":200`)
+}
+
+func TestUpdateCustomerHandler_Success(t *testing.T) {
+	client := new(MockCustomerClient)
+	r := newTestRouter(NewController(client, nil, nil))
+
+	client.On("GetOrganizationCustomer", int64(7), int64(100)).Return(&pb.CustomerResponse{
+		Customer: &pb.Customer{Id: 7, OrganizationId: 100},
+	}, nil)
+	client.On("UpdateCustomer", int64(7), int64(100), "Ana Kovač", "ana@example.com").Return(&pb.CustomerResponse{
+		Customer: &pb.Customer{Id: 7, FullName: "Ana Kovač", Email: "ana@example.com", OrganizationId: 100},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	body := bytes.NewBufferString(`{"full_name":"Ana Kovač","email":"ana@example.com"}`)
+	req, _ := http.NewRequest("PUT", "/customer/7", body)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"full_name":"Ana Kovač"`)
+	client.AssertExpectations(t)
+}
+
+func TestMergeCustomersHandler_Success(t *testing.T) {
+	client := new(MockCustomerClient)
+	reassigner := &fakeReassigner{reservations: 3}
+	deleter := &fakeDeleter{}
+	r := newTestRouter(newMergeController(client, reassigner, deleter))
+
+	client.On("GetOrganizationCustomer", int64(1), int64(100)).Return(&pb.CustomerResponse{
+		Customer: &pb.Customer{Id: 1, OrganizationId: 100},
+	}, nil)
+	client.On("GetOrganizationCustomer", int64(2), int64(100)).Return(&pb.CustomerResponse{
+		Customer: &pb.Customer{Id: 2, OrganizationId: 100},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	body := bytes.NewBufferString(`{"surviving_id":1,"duplicate_id":2}`)
+	req, _ := http.NewRequest("POST", "/customer/merge", body)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"reservations_moved":3`)
+	assert.Contains(t, w.Body.String(), `"duplicate_deleted":true`)
+	assert.True(t, reassigner.committed)
+	assert.Equal(t, []int64{2}, deleter.deleted)
+	client.AssertExpectations(t)
+}
+
+func TestMergeCustomersHandler_DeleteFailureIsRetriedLater(t *testing.T) {
+	client := new(MockCustomerClient)
+	reassigner := &fakeReassigner{reservations: 3}
+	deleter := &fakeDeleter{err: errors.New("connection refused")}
+	r := newTestRouter(newMergeController(client, reassigner, deleter))
+
+	client.On("GetOrganizationCustomer", int64(1), int64(100)).Return(&pb.CustomerResponse{
+		Customer: &pb.Customer{Id: 1, OrganizationId: 100},
+	}, nil)
+	client.On("GetOrganizationCustomer", int64(2), int64(100)).Return(&pb.CustomerResponse{
+		Customer: &pb.Customer{Id: 2, OrganizationId: 100},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	body := bytes.NewBufferString(`{"surviving_id":1,"duplicate_id":2}`)
+	req, _ := http.NewRequest("POST", "/customer/merge", body)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"duplicate_deleted":false`)
+	assert.True(t, reassigner.committed)
+}
+
+func TestMergeCustomersHandler_ReassignFailureKeepsDuplicate(t *testing.T) {
+	client := new(MockCustomerClient)
+	reassigner := &fakeReassigner{err: errors.New("commit failed")}
+	deleter := &fakeDeleter{}
+	r := newTestRouter(newMergeController(client, reassigner, deleter))
+
+	client.On("GetOrganizationCustomer", int64(1), int64(100)).Return(&pb.CustomerResponse{
+		Customer: &pb.Customer{Id: 1, OrganizationId: 100},
+	}, nil)
+	client.On("GetOrganizationCustomer", int64(2), int64(100)).Return(&pb.CustomerResponse{
+		Customer: &pb.Customer{Id: 2, OrganizationId: 100},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	body := bytes.NewBufferString(`{"surviving_id":1,"duplicate_id":2}`)
+	req, _ := http.NewRequest("POST", "/customer/merge", body)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Empty(t, deleter.deleted)
+	client.AssertNotCalled(t, "DeleteCustomer", mock.Anything)
+}
+
+func TestMergeCustomersHandler_SameCustomer(t *testing.T) {
+	client := new(MockCustomerClient)
+	r := newTestRouter(newMergeController(client, &fakeReassigner{}, &fakeDeleter{}))
+
+	w := httptest.NewRecorder()
+	body := bytes.NewBufferString(`{"surviving_id":1,"duplicate_id":1}`)
+	req, _ := http.NewRequest("POST", "/customer/merge", body)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
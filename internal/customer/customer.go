@@ -33,4 +33,7 @@ func NewCustomerClient(lc fx.Lifecycle) (pb.CustomerServiceClient, error) {
 	return client, nil
 }
 
-var Module = fx.Provide(NewCustomerClient, NewController)
+var Module = fx.Options(
+	fx.Provide(NewCustomerClient, NewDeletionRepository, NewDeleter, NewController),
+	fx.Invoke(RegisterDeleterHooks),
+)
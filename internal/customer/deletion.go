@@ -0,0 +1,281 @@
+package customer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "hostflow/booking-service/internal/customer/proto"
+	"hostflow/booking-service/pkg/lib"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/fx"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// Customer deletion statuses
+	DeletionPending  = "PENDING"
+	DeletionDeleting = "DELETING"
+	DeletionRetrying = "RETRYING"
+	DeletionDone     = "DELETED"
+
+	// deletionInterval is how often queued deletions are retried.
+	deletionInterval = time.Minute
+	// deletionRetryBaseDelay is the delay before the first retry; it doubles
+	// on every further attempt up to deletionRetryMaxDelay.
+	deletionRetryBaseDelay = 30 * time.Second
+	deletionRetryMaxDelay  = 30 * time.Minute
+	// staleDeletionAfter is how long a deletion may stay in DELETING before
+	// it is considered abandoned by a crashed replica.
+	staleDeletionAfter = 5 * time.Minute
+	// deleteTimeout bounds a single gRPC call to the customer service.
+	deleteTimeout = 10 * time.Second
+	// deletionBatchSize is how many deletions are claimed per query.
+	deletionBatchSize = 20
+)
+
+// ErrDeletionInProgress is returned when a queued deletion is already being
+// processed by the background worker.
+var ErrDeletionInProgress = errors.New("customer deletion is already in progress")
+
+// Deletion is a queued deletion of a customer merged into another one
+type Deletion struct {
+	ID             int64      `db:"id"`
+	OrganizationID int64      `db:"organization_id"`
+	CustomerID     int64      `db:"customer_id"`
+	Status         string     `db:"status"`
+	Attempts       int        `db:"attempts"`
+	LastError      string     `db:"last_error"`
+	NextAttemptAt  time.Time  `db:"next_attempt_at"`
+	DeletedAt      *time.Time `db:"deleted_at"`
+	CreatedAt      time.Time  `db:"created_at"`
+	UpdatedAt      time.Time  `db:"updated_at"`
+}
+
+const deletionColumns = `id, organization_id, customer_id, status, attempts, last_error,
+        next_attempt_at, deleted_at, created_at, updated_at`
+
+// DeletionRepository stores the customer_deletion queue. Rows are inserted
+// by the reservation repository together with the merge they belong to.
+type DeletionRepository struct {
+	db *lib.Database
+}
+
+// NewDeletionRepository returns a DeletionRepository
+func NewDeletionRepository(db *lib.Database) *DeletionRepository {
+	return &DeletionRepository{db: db}
+}
+
+// ClaimDueDeletions marks due deletions, and deletions abandoned in
+// DELETING for longer than staleAfter, as DELETING and returns them. Rows
+// locked by another replica are skipped.
+func (r *DeletionRepository) ClaimDueDeletions(limit int, staleAfter time.Duration) ([]Deletion, error) {
+	query := `
+        UPDATE customer_deletion
+        SET status = $1,
+            attempts = attempts + 1,
+            updated_at = NOW()
+        WHERE id IN (
+            SELECT id
+            FROM customer_deletion
+            WHERE (status IN ($2, $3) AND next_attempt_at <= NOW())
+               OR (status = $1 AND updated_at < NOW() - $4::interval)
+            ORDER BY next_attempt_at
+            LIMIT $5
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING ` + deletionColumns
+
+	rows, err := r.db.Query(context.Background(), query,
+		DeletionDeleting,
+		DeletionPending,
+		DeletionRetrying,
+		staleAfter.String(),
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[Deletion])
+}
+
+// ClaimCustomerDeletion marks the queued deletion of a customer as DELETING
+// without waiting for its next attempt. It returns nil when there is no
+// queued deletion or another replica is processing it.
+func (r *DeletionRepository) ClaimCustomerDeletion(organizationID, customerID int64) (*Deletion, error) {
+	query := `
+        UPDATE customer_deletion
+        SET status = $3,
+            attempts = attempts + 1,
+            updated_at = NOW()
+        WHERE id IN (
+            SELECT id
+            FROM customer_deletion
+            WHERE organization_id = $1
+              AND customer_id = $2
+              AND status IN ($4, $5)
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING ` + deletionColumns
+
+	rows, err := r.db.Query(context.Background(), query,
+		organizationID,
+		customerID,
+		DeletionDeleting,
+		DeletionPending,
+		DeletionRetrying,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	deletion, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[Deletion])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return deletion, err
+}
+
+// MarkDeleted records a customer that was deleted
+func (r *DeletionRepository) MarkDeleted(id int64) error {
+	query := `
+        UPDATE customer_deletion
+        SET status = $2,
+            last_error = '',
+            deleted_at = NOW(),
+            updated_at = NOW()
+        WHERE id = $1
+    `
+
+	_, err := r.db.Exec(context.Background(), query, id, DeletionDone)
+	return err
+}
+
+// MarkRetry records a failed attempt that will be retried at nextAttempt
+func (r *DeletionRepository) MarkRetry(id int64, lastError string, nextAttempt time.Time) error {
+	query := `
+        UPDATE customer_deletion
+        SET status = $2,
+            last_error = $3,
+            next_attempt_at = $4,
+            updated_at = NOW()
+        WHERE id = $1
+    `
+
+	_, err := r.db.Exec(context.Background(), query, id, DeletionRetrying, lastError, nextAttempt)
+	return err
+}
+
+// Deleter deletes merged duplicate customers from the customer service.
+// The merge tries the deletion right away; deletions that fail are retried
+// with exponential backoff by a background worker until they succeed.
+type Deleter struct {
+	client pb.CustomerServiceClient
+	repo   *DeletionRepository
+	logger lib.Logger
+	worker *lib.Worker
+}
+
+// NewDeleter returns a Deleter
+func NewDeleter(client pb.CustomerServiceClient, repo *DeletionRepository, logger lib.Logger) *Deleter {
+	d := &Deleter{
+		client: client,
+		repo:   repo,
+		logger: logger,
+	}
+	d.worker = lib.NewWorker(deletionInterval, d.processDue)
+	return d
+}
+
+// DeleteNow attempts the queued deletion of a customer immediately. When the
+// attempt fails the deletion stays queued and the error is returned.
+func (d *Deleter) DeleteNow(ctx context.Context, organizationID, customerID int64) error {
+	deletion, err := d.repo.ClaimCustomerDeletion(organizationID, customerID)
+	if err != nil {
+		return err
+	}
+	if deletion == nil {
+		return ErrDeletionInProgress
+	}
+
+	return d.delete(ctx, deletion)
+}
+
+// processDue claims and retries batches of due deletions until none are left.
+func (d *Deleter) processDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		deletions, err := d.repo.ClaimDueDeletions(deletionBatchSize, staleDeletionAfter)
+		if err != nil {
+			d.logger.Error("Failed to claim customer deletions:", err)
+			return
+		}
+		if len(deletions) == 0 {
+			return
+		}
+
+		for i := range deletions {
+			d.delete(ctx, &deletions[i])
+		}
+	}
+}
+
+// delete performs a single delete attempt and records its outcome.
+func (d *Deleter) delete(ctx context.Context, deletion *Deletion) error {
+	err := d.send(ctx, deletion.CustomerID)
+
+	var recordErr error
+	if err == nil {
+		recordErr = d.repo.MarkDeleted(deletion.ID)
+	} else {
+		d.logger.Error(fmt.Sprintf("Failed to delete merged customer %d, attempt %d:", deletion.CustomerID, deletion.Attempts), err)
+		recordErr = d.repo.MarkRetry(deletion.ID, err.Error(), time.Now().Add(deletionRetryDelay(deletion.Attempts)))
+	}
+	if recordErr != nil {
+		d.logger.Error(fmt.Sprintf("Failed to record customer deletion %d:", deletion.ID), recordErr)
+	}
+
+	return err
+}
+
+// send calls the customer service. A customer that no longer exists counts
+// as deleted.
+func (d *Deleter) send(ctx context.Context, customerID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	resp, err := d.client.DeleteCustomer(ctx, &pb.DeleteCustomerRequest{Id: customerID})
+	if status.Code(err) == codes.NotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return errors.New("customer service refused to delete the customer")
+	}
+
+	return nil
+}
+
+// deletionRetryDelay returns the backoff before the next attempt, given how
+// many attempts have already been made.
+func deletionRetryDelay(attempts int) time.Duration {
+	delay := deletionRetryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= deletionRetryMaxDelay {
+			return deletionRetryMaxDelay
+		}
+	}
+	return delay
+}
+
+// RegisterDeleterHooks starts the deletion worker with the application
+func RegisterDeleterHooks(lifecycle fx.Lifecycle, deleter *Deleter) {
+	deleter.worker.Start(lifecycle)
+}
@@ -0,0 +1,91 @@
+package customer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "hostflow/booking-service/internal/customer/proto"
+	"hostflow/booking-service/internal/dbtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Info(args ...interface{})  {}
+func (nopLogger) Fatal(args ...interface{}) {}
+func (nopLogger) Error(args ...interface{}) {}
+
+func TestDeletionRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, deletionRetryDelay(1))
+	assert.Equal(t, time.Minute, deletionRetryDelay(2))
+	assert.Equal(t, 16*time.Minute, deletionRetryDelay(6))
+	assert.Equal(t, deletionRetryMaxDelay, deletionRetryDelay(7))
+}
+
+func TestDeleterSend(t *testing.T) {
+	tests := []struct {
+		name    string
+		resp    *pb.DeleteCustomerResponse
+		err     error
+		wantErr bool
+	}{
+		{name: "deleted", resp: &pb.DeleteCustomerResponse{Success: true}},
+		{name: "already gone", err: status.Error(codes.NotFound, "customer not found")},
+		{name: "refused", resp: &pb.DeleteCustomerResponse{Success: false}, wantErr: true},
+		{name: "unavailable", err: status.Error(codes.Unavailable, "connection refused"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(MockCustomerClient)
+			client.On("DeleteCustomer", int64(2)).Return(tt.resp, tt.err)
+			deleter := NewDeleter(client, nil, nopLogger{})
+
+			err := deleter.send(context.Background(), 2)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestDeleter_RetriesFailedDeletion(t *testing.T) {
+	db := dbtest.Open(t)
+	ctx := context.Background()
+
+	_, err := db.Exec(ctx, `INSERT INTO customer_deletion (organization_id, customer_id) VALUES (100, 2)`)
+	require.NoError(t, err)
+
+	client := new(MockCustomerClient)
+	client.On("DeleteCustomer", int64(2)).Return(nil, errors.New("connection refused")).Once()
+	client.On("DeleteCustomer", int64(2)).Return(&pb.DeleteCustomerResponse{Success: true}, nil).Once()
+	deleter := NewDeleter(client, NewDeletionRepository(db), nopLogger{})
+
+	// The immediate attempt fails and leaves the deletion queued
+	require.Error(t, deleter.DeleteNow(ctx, 100, 2))
+
+	var state string
+	require.NoError(t, db.QueryRow(ctx, `SELECT status FROM customer_deletion WHERE customer_id = 2`).Scan(&state))
+	assert.Equal(t, DeletionRetrying, state)
+
+	// Not due yet
+	deleter.processDue(ctx)
+	client.AssertNumberOfCalls(t, "DeleteCustomer", 1)
+
+	_, err = db.Exec(ctx, `UPDATE customer_deletion SET next_attempt_at = NOW() - INTERVAL '1 second'`)
+	require.NoError(t, err)
+	deleter.processDue(ctx)
+
+	var attempts int
+	require.NoError(t, db.QueryRow(ctx, `SELECT status, attempts FROM customer_deletion WHERE customer_id = 2`).Scan(&state, &attempts))
+	assert.Equal(t, DeletionDone, state)
+	assert.Equal(t, 2, attempts)
+	client.AssertExpectations(t)
+
+	// A finished deletion is not attempted again
+	assert.ErrorIs(t, deleter.DeleteNow(ctx, 100, 2), ErrDeletionInProgress)
+}
@@ -0,0 +1,44 @@
+package customer
+
+import (
+	pb "hostflow/booking-service/internal/customer/proto"
+)
+
+const (
+	// defaultPageLimit is used when the caller does not provide a limit.
+	defaultPageLimit = 20
+	// maxPageLimit caps the page size so a single request cannot pull the
+	// whole customer table through the gRPC connection.
+	maxPageLimit = 100
+)
+
+// CustomerPage is the paginated envelope returned by the customer list endpoint
+type CustomerPage struct {
+	Items  []*pb.Customer `json:"items"`
+	Total  int64          `json:"total" example:"42"`
+	Limit  int32          `json:"limit" example:"20"`
+	Offset int32          `json:"offset" example:"0"`
+}
+
+// UpdateCustomerRequest represents the customer update request
+type UpdateCustomerRequest struct {
+	FullName string `json:"full_name" binding:"required" example:"Ana Novak"`
+	Email    string `json:"email" binding:"required,email" example:"ana.novak@example.com"`
+}
+
+// MergeCustomersRequest represents a request to fold a duplicate customer
+// into the customer that should be kept
+type MergeCustomersRequest struct {
+	SurvivingID int64 `json:"surviving_id" binding:"required" example:"12"`
+	DuplicateID int64 `json:"duplicate_id" binding:"required" example:"34"`
+}
+
+// MergeCustomersResponse represents the result of a merge
+type MergeCustomersResponse struct {
+	Customer          *pb.Customer `json:"customer"`
+	MergedCustomerID  int64        `json:"merged_customer_id" example:"34"`
+	ReservationsMoved int64        `json:"reservations_moved" example:"3"`
+	// DuplicateDeleted is false when the duplicate could not be deleted yet;
+	// the deletion is retried in the background.
+	DuplicateDeleted bool `json:"duplicate_deleted" example:"true"`
+}
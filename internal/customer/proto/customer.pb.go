@@ -238,8 +238,10 @@ func (x *ListCustomersRequest) GetOffset() int32 {
 }
 
 type ListCustomersResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Customers     []*Customer            `protobuf:"bytes,1,rep,name=customers,proto3" json:"customers,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Customers []*Customer            `protobuf:"bytes,1,rep,name=customers,proto3" json:"customers,omitempty"`
+	// Total number of customers matching the request, ignoring limit/offset.
+	Total         int64 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -281,6 +283,197 @@ func (x *ListCustomersResponse) GetCustomers() []*Customer {
 	return nil
 }
 
+func (x *ListCustomersResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type ListOrganizationCustomersRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	OrganizationId int64                  `protobuf:"varint,1,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	Limit          int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset         int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListOrganizationCustomersRequest) Reset() {
+	*x = ListOrganizationCustomersRequest{}
+	mi := &file_customer_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrganizationCustomersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrganizationCustomersRequest) ProtoMessage() {}
+
+func (x *ListOrganizationCustomersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrganizationCustomersRequest.ProtoReflect.Descriptor instead.
+func (*ListOrganizationCustomersRequest) Descriptor() ([]byte, []int) {
+	return file_customer_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListOrganizationCustomersRequest) GetOrganizationId() int64 {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return 0
+}
+
+func (x *ListOrganizationCustomersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListOrganizationCustomersRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+// SearchCustomersRequest matches query against full_name and email
+// (case-insensitive, substring).
+type SearchCustomersRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	OrganizationId int64                  `protobuf:"varint,1,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	Query          string                 `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	Limit          int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset         int32                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SearchCustomersRequest) Reset() {
+	*x = SearchCustomersRequest{}
+	mi := &file_customer_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchCustomersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchCustomersRequest) ProtoMessage() {}
+
+func (x *SearchCustomersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchCustomersRequest.ProtoReflect.Descriptor instead.
+func (*SearchCustomersRequest) Descriptor() ([]byte, []int) {
+	return file_customer_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SearchCustomersRequest) GetOrganizationId() int64 {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return 0
+}
+
+func (x *SearchCustomersRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchCustomersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchCustomersRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+// GetOrganizationCustomerRequest returns NOT_FOUND when the customer does not
+// exist or belongs to a different organization.
+type GetOrganizationCustomerRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrganizationId int64                  `protobuf:"varint,2,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetOrganizationCustomerRequest) Reset() {
+	*x = GetOrganizationCustomerRequest{}
+	mi := &file_customer_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrganizationCustomerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrganizationCustomerRequest) ProtoMessage() {}
+
+func (x *GetOrganizationCustomerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrganizationCustomerRequest.ProtoReflect.Descriptor instead.
+func (*GetOrganizationCustomerRequest) Descriptor() ([]byte, []int) {
+	return file_customer_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetOrganizationCustomerRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *GetOrganizationCustomerRequest) GetOrganizationId() int64 {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return 0
+}
+
 type CreateCustomerRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	FullName       string                 `protobuf:"bytes,1,opt,name=full_name,json=fullName,proto3" json:"full_name,omitempty"`
@@ -292,7 +485,7 @@ type CreateCustomerRequest struct {
 
 func (x *CreateCustomerRequest) Reset() {
 	*x = CreateCustomerRequest{}
-	mi := &file_customer_proto_msgTypes[5]
+	mi := &file_customer_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -304,7 +497,7 @@ func (x *CreateCustomerRequest) String() string {
 func (*CreateCustomerRequest) ProtoMessage() {}
 
 func (x *CreateCustomerRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_customer_proto_msgTypes[5]
+	mi := &file_customer_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -317,7 +510,7 @@ func (x *CreateCustomerRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateCustomerRequest.ProtoReflect.Descriptor instead.
 func (*CreateCustomerRequest) Descriptor() ([]byte, []int) {
-	return file_customer_proto_rawDescGZIP(), []int{5}
+	return file_customer_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *CreateCustomerRequest) GetFullName() string {
@@ -341,6 +534,76 @@ func (x *CreateCustomerRequest) GetOrganizationId() int64 {
 	return 0
 }
 
+// UpdateCustomerRequest replaces the customer's details. The server returns
+// NOT_FOUND when the customer belongs to a different organization.
+type UpdateCustomerRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrganizationId int64                  `protobuf:"varint,2,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	FullName       string                 `protobuf:"bytes,3,opt,name=full_name,json=fullName,proto3" json:"full_name,omitempty"`
+	Email          string                 `protobuf:"bytes,4,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UpdateCustomerRequest) Reset() {
+	*x = UpdateCustomerRequest{}
+	mi := &file_customer_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCustomerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCustomerRequest) ProtoMessage() {}
+
+func (x *UpdateCustomerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCustomerRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCustomerRequest) Descriptor() ([]byte, []int) {
+	return file_customer_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UpdateCustomerRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateCustomerRequest) GetOrganizationId() int64 {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return 0
+}
+
+func (x *UpdateCustomerRequest) GetFullName() string {
+	if x != nil {
+		return x.FullName
+	}
+	return ""
+}
+
+func (x *UpdateCustomerRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
 type DeleteCustomerRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -350,7 +613,7 @@ type DeleteCustomerRequest struct {
 
 func (x *DeleteCustomerRequest) Reset() {
 	*x = DeleteCustomerRequest{}
-	mi := &file_customer_proto_msgTypes[6]
+	mi := &file_customer_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -362,7 +625,7 @@ func (x *DeleteCustomerRequest) String() string {
 func (*DeleteCustomerRequest) ProtoMessage() {}
 
 func (x *DeleteCustomerRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_customer_proto_msgTypes[6]
+	mi := &file_customer_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -375,7 +638,7 @@ func (x *DeleteCustomerRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteCustomerRequest.ProtoReflect.Descriptor instead.
 func (*DeleteCustomerRequest) Descriptor() ([]byte, []int) {
-	return file_customer_proto_rawDescGZIP(), []int{6}
+	return file_customer_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *DeleteCustomerRequest) GetId() int64 {
@@ -394,7 +657,7 @@ type DeleteCustomerResponse struct {
 
 func (x *DeleteCustomerResponse) Reset() {
 	*x = DeleteCustomerResponse{}
-	mi := &file_customer_proto_msgTypes[7]
+	mi := &file_customer_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -406,7 +669,7 @@ func (x *DeleteCustomerResponse) String() string {
 func (*DeleteCustomerResponse) ProtoMessage() {}
 
 func (x *DeleteCustomerResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_customer_proto_msgTypes[7]
+	mi := &file_customer_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -419,7 +682,7 @@ func (x *DeleteCustomerResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteCustomerResponse.ProtoReflect.Descriptor instead.
 func (*DeleteCustomerResponse) Descriptor() ([]byte, []int) {
-	return file_customer_proto_rawDescGZIP(), []int{7}
+	return file_customer_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *DeleteCustomerResponse) GetSuccess() bool {
@@ -447,22 +710,44 @@ const file_customer_proto_rawDesc = "" +
 	"\bcustomer\x18\x01 \x01(\v2\x12.customer.CustomerR\bcustomer\"D\n" +
 	"\x14ListCustomersRequest\x12\x14\n" +
 	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x02 \x01(\x05R\x06offset\"I\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\"_\n" +
 	"\x15ListCustomersResponse\x120\n" +
-	"\tcustomers\x18\x01 \x03(\v2\x12.customer.CustomerR\tcustomers\"s\n" +
+	"\tcustomers\x18\x01 \x03(\v2\x12.customer.CustomerR\tcustomers\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\"y\n" +
+	" ListOrganizationCustomersRequest\x12'\n" +
+	"\x0forganization_id\x18\x01 \x01(\x03R\x0eorganizationId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\"\x85\x01\n" +
+	"\x16SearchCustomersRequest\x12'\n" +
+	"\x0forganization_id\x18\x01 \x01(\x03R\x0eorganizationId\x12\x14\n" +
+	"\x05query\x18\x02 \x01(\tR\x05query\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x05R\x06offset\"Y\n" +
+	"\x1eGetOrganizationCustomerRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12'\n" +
+	"\x0forganization_id\x18\x02 \x01(\x03R\x0eorganizationId\"s\n" +
 	"\x15CreateCustomerRequest\x12\x1b\n" +
 	"\tfull_name\x18\x01 \x01(\tR\bfullName\x12\x14\n" +
 	"\x05email\x18\x02 \x01(\tR\x05email\x12'\n" +
-	"\x0forganization_id\x18\x03 \x01(\x03R\x0eorganizationId\"'\n" +
+	"\x0forganization_id\x18\x03 \x01(\x03R\x0eorganizationId\"\x83\x01\n" +
+	"\x15UpdateCustomerRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12'\n" +
+	"\x0forganization_id\x18\x02 \x01(\x03R\x0eorganizationId\x12\x1b\n" +
+	"\tfull_name\x18\x03 \x01(\tR\bfullName\x12\x14\n" +
+	"\x05email\x18\x04 \x01(\tR\x05email\"'\n" +
 	"\x15DeleteCustomerRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\"2\n" +
 	"\x16DeleteCustomerResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess2\xd0\x02\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess2\xc0\x05\n" +
 	"\x0fCustomerService\x12G\n" +
 	"\vGetCustomer\x12\x1c.customer.GetCustomerRequest\x1a\x1a.customer.CustomerResponse\x12P\n" +
 	"\rListCustomers\x12\x1e.customer.ListCustomersRequest\x1a\x1f.customer.ListCustomersResponse\x12M\n" +
 	"\x0eCreateCustomer\x12\x1f.customer.CreateCustomerRequest\x1a\x1a.customer.CustomerResponse\x12S\n" +
-	"\x0eDeleteCustomer\x12\x1f.customer.DeleteCustomerRequest\x1a .customer.DeleteCustomerResponseB\"Z hostflow/extra/customer;customerb\x06proto3"
+	"\x0eDeleteCustomer\x12\x1f.customer.DeleteCustomerRequest\x1a .customer.DeleteCustomerResponse\x12h\n" +
+	"\x19ListOrganizationCustomers\x12*.customer.ListOrganizationCustomersRequest\x1a\x1f.customer.ListCustomersResponse\x12T\n" +
+	"\x0fSearchCustomers\x12 .customer.SearchCustomersRequest\x1a\x1f.customer.ListCustomersResponse\x12_\n" +
+	"\x17GetOrganizationCustomer\x12(.customer.GetOrganizationCustomerRequest\x1a\x1a.customer.CustomerResponse\x12M\n" +
+	"\x0eUpdateCustomer\x12\x1f.customer.UpdateCustomerRequest\x1a\x1a.customer.CustomerResponseB\"Z hostflow/extra/customer;customerb\x06proto3"
 
 var (
 	file_customer_proto_rawDescOnce sync.Once
@@ -476,33 +761,45 @@ func file_customer_proto_rawDescGZIP() []byte {
 	return file_customer_proto_rawDescData
 }
 
-var file_customer_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_customer_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
 var file_customer_proto_goTypes = []any{
-	(*Customer)(nil),               // 0: customer.Customer
-	(*GetCustomerRequest)(nil),     // 1: customer.GetCustomerRequest
-	(*CustomerResponse)(nil),       // 2: customer.CustomerResponse
-	(*ListCustomersRequest)(nil),   // 3: customer.ListCustomersRequest
-	(*ListCustomersResponse)(nil),  // 4: customer.ListCustomersResponse
-	(*CreateCustomerRequest)(nil),  // 5: customer.CreateCustomerRequest
-	(*DeleteCustomerRequest)(nil),  // 6: customer.DeleteCustomerRequest
-	(*DeleteCustomerResponse)(nil), // 7: customer.DeleteCustomerResponse
+	(*Customer)(nil),                         // 0: customer.Customer
+	(*GetCustomerRequest)(nil),               // 1: customer.GetCustomerRequest
+	(*CustomerResponse)(nil),                 // 2: customer.CustomerResponse
+	(*ListCustomersRequest)(nil),             // 3: customer.ListCustomersRequest
+	(*ListCustomersResponse)(nil),            // 4: customer.ListCustomersResponse
+	(*ListOrganizationCustomersRequest)(nil), // 5: customer.ListOrganizationCustomersRequest
+	(*SearchCustomersRequest)(nil),           // 6: customer.SearchCustomersRequest
+	(*GetOrganizationCustomerRequest)(nil),   // 7: customer.GetOrganizationCustomerRequest
+	(*CreateCustomerRequest)(nil),            // 8: customer.CreateCustomerRequest
+	(*UpdateCustomerRequest)(nil),            // 9: customer.UpdateCustomerRequest
+	(*DeleteCustomerRequest)(nil),            // 10: customer.DeleteCustomerRequest
+	(*DeleteCustomerResponse)(nil),           // 11: customer.DeleteCustomerResponse
 }
 var file_customer_proto_depIdxs = []int32{
-	0, // 0: customer.CustomerResponse.customer:type_name -> customer.Customer
-	0, // 1: customer.ListCustomersResponse.customers:type_name -> customer.Customer
-	1, // 2: customer.CustomerService.GetCustomer:input_type -> customer.GetCustomerRequest
-	3, // 3: customer.CustomerService.ListCustomers:input_type -> customer.ListCustomersRequest
-	5, // 4: customer.CustomerService.CreateCustomer:input_type -> customer.CreateCustomerRequest
-	6, // 5: customer.CustomerService.DeleteCustomer:input_type -> customer.DeleteCustomerRequest
-	2, // 6: customer.CustomerService.GetCustomer:output_type -> customer.CustomerResponse
-	4, // 7: customer.CustomerService.ListCustomers:output_type -> customer.ListCustomersResponse
-	2, // 8: customer.CustomerService.CreateCustomer:output_type -> customer.CustomerResponse
-	7, // 9: customer.CustomerService.DeleteCustomer:output_type -> customer.DeleteCustomerResponse
-	6, // [6:10] is the sub-list for method output_type
-	2, // [2:6] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	0,  // 0: customer.CustomerResponse.customer:type_name -> customer.Customer
+	0,  // 1: customer.ListCustomersResponse.customers:type_name -> customer.Customer
+	1,  // 2: customer.CustomerService.GetCustomer:input_type -> customer.GetCustomerRequest
+	3,  // 3: customer.CustomerService.ListCustomers:input_type -> customer.ListCustomersRequest
+	8,  // 4: customer.CustomerService.CreateCustomer:input_type -> customer.CreateCustomerRequest
+	10, // 5: customer.CustomerService.DeleteCustomer:input_type -> customer.DeleteCustomerRequest
+	5,  // 6: customer.CustomerService.ListOrganizationCustomers:input_type -> customer.ListOrganizationCustomersRequest
+	6,  // 7: customer.CustomerService.SearchCustomers:input_type -> customer.SearchCustomersRequest
+	7,  // 8: customer.CustomerService.GetOrganizationCustomer:input_type -> customer.GetOrganizationCustomerRequest
+	9,  // 9: customer.CustomerService.UpdateCustomer:input_type -> customer.UpdateCustomerRequest
+	2,  // 10: customer.CustomerService.GetCustomer:output_type -> customer.CustomerResponse
+	4,  // 11: customer.CustomerService.ListCustomers:output_type -> customer.ListCustomersResponse
+	2,  // 12: customer.CustomerService.CreateCustomer:output_type -> customer.CustomerResponse
+	11, // 13: customer.CustomerService.DeleteCustomer:output_type -> customer.DeleteCustomerResponse
+	4,  // 14: customer.CustomerService.ListOrganizationCustomers:output_type -> customer.ListCustomersResponse
+	4,  // 15: customer.CustomerService.SearchCustomers:output_type -> customer.ListCustomersResponse
+	2,  // 16: customer.CustomerService.GetOrganizationCustomer:output_type -> customer.CustomerResponse
+	2,  // 17: customer.CustomerService.UpdateCustomer:output_type -> customer.CustomerResponse
+	10, // [10:18] is the sub-list for method output_type
+	2,  // [2:10] is the sub-list for method input_type
+	2,  // [2:2] is the sub-list for extension type_name
+	2,  // [2:2] is the sub-list for extension extendee
+	0,  // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_customer_proto_init() }
@@ -516,7 +813,7 @@ func file_customer_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_customer_proto_rawDesc), len(file_customer_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   12,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
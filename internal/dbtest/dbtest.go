@@ -0,0 +1,122 @@
+// Package dbtest gives tests a real Postgres database with the service's
+// schema. Tests using it are skipped unless TEST_DATABASE_URL points to a
+// database the tests may create schemas in.
+package dbtest
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+
+	"hostflow/booking-service/pkg/lib"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// baseline creates the reservation table as it exists before the
+// migrations in this repository; the table itself is owned by the platform
+// schema and the migrations only extend it.
+const baseline = `
+CREATE TABLE reservation (
+    id                  BIGINT PRIMARY KEY,
+    organization_id     BIGINT           NOT NULL,
+    property_id         BIGINT           NOT NULL,
+    customer_id         BIGINT           NOT NULL,
+    check_in_date       TIMESTAMPTZ      NOT NULL,
+    check_out_date      TIMESTAMPTZ      NOT NULL,
+    status              TEXT             NOT NULL,
+    total_price         DOUBLE PRECISION NOT NULL DEFAULT 0,
+    payment_url         TEXT             NOT NULL DEFAULT '',
+    price_elements      JSONB,
+    no_of_guests        INT              NOT NULL DEFAULT 1,
+    guest_data          JSONB,
+    additional_requests JSONB,
+    created_at          TIMESTAMPTZ      NOT NULL DEFAULT NOW(),
+    update_at           TIMESTAMPTZ      NOT NULL DEFAULT NOW()
+);
+`
+
+// Open returns a pool connected to a fresh schema with every migration
+// applied. The pool uses the same configuration as the service, with UTC
+// sessions like the production database, and the schema is dropped when the
+// test ends.
+func Open(t testing.TB) *pgxpool.Pool {
+	t.Helper()
+	return OpenInTimeZone(t, "UTC")
+}
+
+// OpenInTimeZone is Open with sessions in the given time zone, for tests
+// checking that queries don't depend on it.
+func OpenInTimeZone(t testing.TB, timeZone string) *pgxpool.Pool {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+	schema := fmt.Sprintf("test_%d", rand.Uint64())
+
+	admin, err := pgxpool.New(ctx, url)
+	if err != nil {
+		t.Fatalf("connect to test database: %v", err)
+	}
+	defer admin.Close()
+
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		admin, err := pgxpool.New(context.Background(), url)
+		if err != nil {
+			return
+		}
+		defer admin.Close()
+		admin.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE")
+	})
+
+	config, err := lib.NewPoolConfig(url)
+	if err != nil {
+		t.Fatalf("parse TEST_DATABASE_URL: %v", err)
+	}
+	config.ConnConfig.RuntimeParams["search_path"] = schema
+	config.ConnConfig.RuntimeParams["timezone"] = timeZone
+
+	db, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("connect to test schema: %v", err)
+	}
+	t.Cleanup(db.Close)
+
+	if _, err := db.Exec(ctx, baseline); err != nil {
+		t.Fatalf("create baseline schema: %v", err)
+	}
+	for _, file := range migrations(t) {
+		sql, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("read migration: %v", err)
+		}
+		if _, err := db.Exec(ctx, string(sql)); err != nil {
+			t.Fatalf("apply %s: %v", filepath.Base(file), err)
+		}
+	}
+
+	return db
+}
+
+// migrations returns the migration files in the order they are applied.
+func migrations(t testing.TB) []string {
+	_, file, _, _ := runtime.Caller(0)
+	files, err := filepath.Glob(filepath.Join(filepath.Dir(file), "..", "..", "migrations", "*.sql"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("find migrations: %v", err)
+	}
+	sort.Strings(files)
+	return files
+}
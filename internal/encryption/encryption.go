@@ -0,0 +1,71 @@
+package encryption
+
+import (
+	"errors"
+	"hostflow/booking-service/pkg/lib"
+	"os"
+	"strings"
+
+	"go.uber.org/fx"
+)
+
+// ======== TYPES ========
+
+// keyProviderParams are the dependencies of the key provider. A KMSClient
+// is only needed, and only provided by deployments, for the kms provider.
+type keyProviderParams struct {
+	fx.In
+
+	Logger lib.Logger
+	KMS    KMSClient `optional:"true"`
+}
+
+// ======== PUBLIC METHODS ========
+
+// GetKeyProvider returns the provider selected by ENCRYPTION_KEY_PROVIDER:
+// "local" (the default) reads master keys from ENCRYPTION_KEYFILE, "kms"
+// wraps keys with ENCRYPTION_KMS_KEY_ID. Without a keyfile, the local
+// provider is nil and nothing is encrypted.
+func GetKeyProvider(params keyProviderParams) (KeyProvider, error) {
+	switch os.Getenv("ENCRYPTION_KEY_PROVIDER") {
+	case "", "local":
+		path := os.Getenv("ENCRYPTION_KEYFILE")
+		if path == "" {
+			return nil, nil
+		}
+		params.Logger.Info("Using the local keyfile encryption key provider")
+		return NewLocalKeyProvider(path)
+	case "kms":
+		if params.KMS == nil {
+			return nil, errors.New("ENCRYPTION_KEY_PROVIDER is kms but no KMS client is provided")
+		}
+		keyID := os.Getenv("ENCRYPTION_KMS_KEY_ID")
+		if keyID == "" {
+			return nil, errors.New("ENCRYPTION_KMS_KEY_ID is not set")
+		}
+		return NewKMSKeyProvider(params.KMS, keyID), nil
+	default:
+		return nil, errors.New("unknown ENCRYPTION_KEY_PROVIDER " + os.Getenv("ENCRYPTION_KEY_PROVIDER"))
+	}
+}
+
+// GetGuestDataEncryptor returns the encryptor of reservation guest data for
+// the paths in ENCRYPTION_GUEST_DATA_PATHS, e.g.
+// "passport_number,address,guests.*.document_number"
+func GetGuestDataEncryptor(provider KeyProvider) (*FieldEncryptor, error) {
+	var paths []string
+	for _, path := range strings.Split(os.Getenv("ENCRYPTION_GUEST_DATA_PATHS"), ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+
+	return NewFieldEncryptor(provider, paths)
+}
+
+// ======== EXPORTS ========
+
+// Module exports the field encryption
+var Module = fx.Options(
+	fx.Provide(GetKeyProvider, GetGuestDataEncryptor),
+)
@@ -0,0 +1,445 @@
+package encryption
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces encrypted values for callers that may not read them
+const Redacted = "[redacted]"
+
+// Envelope fields. An encrypted value is replaced in the JSON document by
+//
+//	{"$enc": "aes-256-gcm", "kid": "<key version>", "dk": "<wrapped data key>", "ct": "<ciphertext>"}
+//
+// where ct is the JSON encoding of the original value, sealed with the data
+// key, and dk is the data key wrapped by the key provider.
+const (
+	envelopeMarker    = "$enc"
+	envelopeAlgorithm = "aes-256-gcm"
+)
+
+// dataKeyCacheSize bounds the unwrapped data keys kept in memory, so that
+// listing reservations doesn't unwrap the same keys over and over
+const dataKeyCacheSize = 10000
+
+// FieldEncryptor encrypts the values at configured paths of JSON documents,
+// such as the guest data of a reservation. Every document gets its own data
+// key, which is wrapped by the KeyProvider.
+type FieldEncryptor struct {
+	provider KeyProvider
+	paths    [][]string
+
+	mu    sync.Mutex
+	cache map[string][]byte
+}
+
+// NewFieldEncryptor returns a FieldEncryptor for paths such as
+// "passport_number", "address" or "guests.*.document_number", where * matches
+// every key of an object or element of an array. provider may be nil when no
+// paths are configured.
+func NewFieldEncryptor(provider KeyProvider, paths []string) (*FieldEncryptor, error) {
+	e := &FieldEncryptor{
+		provider: provider,
+		cache:    make(map[string][]byte),
+	}
+
+	for _, path := range paths {
+		segments := strings.Split(strings.TrimSpace(path), ".")
+		for _, segment := range segments {
+			if segment == "" {
+				return nil, fmt.Errorf("invalid encrypted path %q", path)
+			}
+		}
+		e.paths = append(e.paths, segments)
+	}
+
+	if len(e.paths) > 0 && provider == nil {
+		return nil, errors.New("encrypted paths are configured without a key provider")
+	}
+
+	return e, nil
+}
+
+// Enabled reports whether any path is encrypted
+func (e *FieldEncryptor) Enabled() bool {
+	return e != nil && len(e.paths) > 0
+}
+
+// Encrypt encrypts the plaintext values at the configured paths of data in
+// place. previous is the stored version of the document, if any: values
+// sent back as Redacted keep their previous encrypted value, and unchanged
+// values keep their previous ciphertext so that updates don't show up as
+// changes in the audit log.
+func (e *FieldEncryptor) Encrypt(ctx context.Context, data, previous map[string]interface{}) error {
+	if !e.Enabled() || data == nil {
+		return nil
+	}
+
+	var key *documentKey
+	return e.eachPath(data, func(path []string, value interface{}) (interface{}, error) {
+		if isEnvelope(value) {
+			return value, nil
+		}
+
+		old := lookup(previous, path)
+		if s, ok := value.(string); ok && s == Redacted {
+			return old, nil
+		}
+		if isEnvelope(old) {
+			if plain, err := e.open(ctx, old.(map[string]interface{})); err == nil && reflect.DeepEqual(plain, value) {
+				return old, nil
+			}
+		}
+
+		if key == nil {
+			var err error
+			if key, err = e.newDocumentKey(ctx); err != nil {
+				return nil, err
+			}
+		}
+		return key.seal(value)
+	})
+}
+
+// Decrypt replaces every encrypted value of data with its plaintext, in place
+func (e *FieldEncryptor) Decrypt(ctx context.Context, data map[string]interface{}) error {
+	if e == nil {
+		return nil
+	}
+
+	return walkEnvelopes(data, func(envelope map[string]interface{}) (interface{}, error) {
+		if e.provider == nil {
+			return nil, errors.New("encrypted value found without a key provider")
+		}
+		return e.open(ctx, envelope)
+	})
+}
+
+// Redact replaces every encrypted value of data, and every value at an
+// encrypted path that is still in plaintext, with Redacted
+func (e *FieldEncryptor) Redact(data map[string]interface{}) {
+	if e == nil {
+		return
+	}
+
+	_ = walkEnvelopes(data, func(map[string]interface{}) (interface{}, error) {
+		return Redacted, nil
+	})
+	_ = e.eachPath(data, func(_ []string, value interface{}) (interface{}, error) {
+		return Redacted, nil
+	})
+}
+
+// Rotate rewraps the data keys of data that were not wrapped with the
+// current key version, and encrypts plaintext values at the configured
+// paths. It reports whether data changed.
+func (e *FieldEncryptor) Rotate(ctx context.Context, data map[string]interface{}) (bool, error) {
+	if e == nil || e.provider == nil {
+		return false, nil
+	}
+
+	current := e.provider.CurrentVersion()
+	rewrapped := make(map[string]string)
+	changed := false
+
+	err := walkEnvelopes(data, func(envelope map[string]interface{}) (interface{}, error) {
+		version, _ := envelope["kid"].(string)
+		if version == current {
+			return envelope, nil
+		}
+
+		wrapped, _ := envelope["dk"].(string)
+		if _, ok := rewrapped[wrapped]; !ok {
+			dataKey, err := e.unwrap(ctx, version, wrapped)
+			if err != nil {
+				return nil, err
+			}
+			_, newWrapped, err := e.provider.WrapKey(ctx, dataKey)
+			if err != nil {
+				return nil, err
+			}
+			rewrapped[wrapped] = base64.StdEncoding.EncodeToString(newWrapped)
+		}
+
+		changed = true
+		return map[string]interface{}{
+			envelopeMarker: envelopeAlgorithm,
+			"kid":          current,
+			"dk":           rewrapped[wrapped],
+			"ct":           envelope["ct"],
+		}, nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	var key *documentKey
+	err = e.eachPath(data, func(_ []string, value interface{}) (interface{}, error) {
+		if isEnvelope(value) {
+			return value, nil
+		}
+		if key == nil {
+			var err error
+			if key, err = e.newDocumentKey(ctx); err != nil {
+				return nil, err
+			}
+		}
+		changed = true
+		return key.seal(value)
+	})
+
+	return changed, err
+}
+
+// ======== DOCUMENT KEYS ========
+
+// documentKey is the data key of a single document
+type documentKey struct {
+	version string
+	wrapped string
+	key     []byte
+}
+
+// newDocumentKey generates and wraps a new data key
+func (e *FieldEncryptor) newDocumentKey(ctx context.Context) (*documentKey, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	version, wrapped, err := e.provider.WrapKey(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	return &documentKey{
+		version: version,
+		wrapped: base64.StdEncoding.EncodeToString(wrapped),
+		key:     key,
+	}, nil
+}
+
+// seal returns the envelope of a value
+func (k *documentKey) seal(value interface{}) (interface{}, error) {
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(k.key)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := seal(aead, plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		envelopeMarker: envelopeAlgorithm,
+		"kid":          k.version,
+		"dk":           k.wrapped,
+		"ct":           base64.StdEncoding.EncodeToString(ciphertext),
+	}, nil
+}
+
+// open returns the plaintext value of an envelope
+func (e *FieldEncryptor) open(ctx context.Context, envelope map[string]interface{}) (interface{}, error) {
+	version, _ := envelope["kid"].(string)
+	wrapped, _ := envelope["dk"].(string)
+	encoded, _ := envelope["ct"].(string)
+
+	dataKey, err := e.unwrap(ctx, version, wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := open(aead, ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt value: %w", err)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(plaintext, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// unwrap returns a data key, from the cache if it was unwrapped before
+func (e *FieldEncryptor) unwrap(ctx context.Context, version, wrapped string) ([]byte, error) {
+	cacheKey := version + ":" + wrapped
+
+	e.mu.Lock()
+	dataKey, ok := e.cache[cacheKey]
+	e.mu.Unlock()
+	if ok {
+		return dataKey, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(wrapped)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err = e.provider.UnwrapKey(ctx, version, raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+
+	e.mu.Lock()
+	if len(e.cache) >= dataKeyCacheSize {
+		e.cache = make(map[string][]byte)
+	}
+	e.cache[cacheKey] = dataKey
+	e.mu.Unlock()
+
+	return dataKey, nil
+}
+
+// IsEncrypted reports whether a JSON value is an encrypted value, as stored
+// at the configured paths
+func IsEncrypted(value interface{}) bool {
+	return isEnvelope(value)
+}
+
+// ======== JSON WALKING ========
+
+// isEnvelope reports whether a JSON value is an encrypted value
+func isEnvelope(value interface{}) bool {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	_, marked := m[envelopeMarker].(string)
+	_, sealed := m["ct"].(string)
+	return marked && sealed
+}
+
+// eachPath calls fn for every non-null value at a configured path and
+// replaces the value with the result
+func (e *FieldEncryptor) eachPath(data map[string]interface{}, fn func(path []string, value interface{}) (interface{}, error)) error {
+	for _, pattern := range e.paths {
+		if err := walkPath(data, pattern, nil, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkPath follows pattern from node
+func walkPath(node interface{}, pattern, prefix []string, fn func(path []string, value interface{}) (interface{}, error)) error {
+	segment, rest := pattern[0], pattern[1:]
+
+	visit := func(key string, value interface{}, set func(interface{})) error {
+		if value == nil {
+			return nil
+		}
+		path := append(append([]string{}, prefix...), key)
+		if len(rest) > 0 {
+			if isEnvelope(value) {
+				return nil
+			}
+			return walkPath(value, rest, path, fn)
+		}
+		replaced, err := fn(path, value)
+		if err != nil {
+			return err
+		}
+		set(replaced)
+		return nil
+	}
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if segment != "*" {
+			return visit(segment, n[segment], func(v interface{}) { n[segment] = v })
+		}
+		for key, value := range n {
+			if err := visit(key, value, func(v interface{}) { n[key] = v }); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, value := range n {
+			if segment != "*" && segment != strconv.Itoa(i) {
+				continue
+			}
+			if err := visit(strconv.Itoa(i), value, func(v interface{}) { n[i] = v }); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// walkEnvelopes replaces every envelope below node with the result of fn
+func walkEnvelopes(node interface{}, fn func(envelope map[string]interface{}) (interface{}, error)) error {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for key, value := range n {
+			if isEnvelope(value) {
+				replaced, err := fn(value.(map[string]interface{}))
+				if err != nil {
+					return err
+				}
+				n[key] = replaced
+				continue
+			}
+			if err := walkEnvelopes(value, fn); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, value := range n {
+			if isEnvelope(value) {
+				replaced, err := fn(value.(map[string]interface{}))
+				if err != nil {
+					return err
+				}
+				n[i] = replaced
+				continue
+			}
+			if err := walkEnvelopes(value, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// lookup returns the value at a concrete path, or nil
+func lookup(node interface{}, path []string) interface{} {
+	for _, segment := range path {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			node = n[segment]
+		case []interface{}:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(n) {
+				return nil
+			}
+			node = n[i]
+		default:
+			return nil
+		}
+	}
+	return node
+}
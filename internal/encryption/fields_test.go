@@ -0,0 +1,145 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testProvider(t *testing.T, current string, versions ...string) KeyProvider {
+	keys := make(map[string][]byte, len(versions))
+	for i, version := range versions {
+		keys[version] = bytes.Repeat([]byte{byte(i + 1)}, 32)
+	}
+	provider, err := NewStaticKeyProvider(current, keys)
+	require.NoError(t, err)
+	return provider
+}
+
+func testEncryptor(t *testing.T, provider KeyProvider) *FieldEncryptor {
+	e, err := NewFieldEncryptor(provider, []string{"passport_number", "guests.*.document_number"})
+	require.NoError(t, err)
+	return e
+}
+
+func guestData() map[string]interface{} {
+	return map[string]interface{}{
+		"passport_number": "PB1234567",
+		"nationality":     "SI",
+		"guests": []interface{}{
+			map[string]interface{}{"name": "Ana", "document_number": "ID1"},
+			map[string]interface{}{"name": "Jan", "document_number": "ID2"},
+		},
+	}
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	e := testEncryptor(t, testProvider(t, "v1", "v1"))
+
+	data := guestData()
+	require.NoError(t, e.Encrypt(ctx, data, nil))
+
+	assert.True(t, isEnvelope(data["passport_number"]))
+	assert.Equal(t, "v1", data["passport_number"].(map[string]interface{})["kid"])
+	assert.Equal(t, "SI", data["nationality"])
+	guest := data["guests"].([]interface{})[0].(map[string]interface{})
+	assert.True(t, isEnvelope(guest["document_number"]))
+	assert.Equal(t, "Ana", guest["name"])
+
+	require.NoError(t, e.Decrypt(ctx, data))
+	assert.Equal(t, guestData(), data)
+}
+
+func TestRedactHidesEncryptedAndPlaintextValues(t *testing.T) {
+	ctx := context.Background()
+	e := testEncryptor(t, testProvider(t, "v1", "v1"))
+
+	data := guestData()
+	require.NoError(t, e.Encrypt(ctx, data, nil))
+	// Stored before the path was configured
+	data["guests"].([]interface{})[1].(map[string]interface{})["document_number"] = "ID2"
+
+	e.Redact(data)
+
+	assert.Equal(t, Redacted, data["passport_number"])
+	assert.Equal(t, "SI", data["nationality"])
+	for _, g := range data["guests"].([]interface{}) {
+		assert.Equal(t, Redacted, g.(map[string]interface{})["document_number"])
+	}
+}
+
+func TestEncryptKeepsPreviousCiphertext(t *testing.T) {
+	ctx := context.Background()
+	e := testEncryptor(t, testProvider(t, "v1", "v1"))
+
+	previous := guestData()
+	require.NoError(t, e.Encrypt(ctx, previous, nil))
+
+	// Unchanged and redacted values keep the stored envelope
+	update := guestData()
+	update["passport_number"] = Redacted
+	update["guests"].([]interface{})[1].(map[string]interface{})["document_number"] = "ID3"
+	require.NoError(t, e.Encrypt(ctx, update, previous))
+
+	assert.Equal(t, previous["passport_number"], update["passport_number"])
+	assert.Equal(t,
+		previous["guests"].([]interface{})[0].(map[string]interface{})["document_number"],
+		update["guests"].([]interface{})[0].(map[string]interface{})["document_number"],
+	)
+	assert.NotEqual(t,
+		previous["guests"].([]interface{})[1].(map[string]interface{})["document_number"],
+		update["guests"].([]interface{})[1].(map[string]interface{})["document_number"],
+	)
+
+	require.NoError(t, e.Decrypt(ctx, update))
+	assert.Equal(t, "PB1234567", update["passport_number"])
+	assert.Equal(t, "ID3", update["guests"].([]interface{})[1].(map[string]interface{})["document_number"])
+}
+
+func TestRotateRewrapsOldKeyVersions(t *testing.T) {
+	ctx := context.Background()
+
+	data := guestData()
+	require.NoError(t, testEncryptor(t, testProvider(t, "v1", "v1")).Encrypt(ctx, data, nil))
+
+	rotated := testEncryptor(t, testProvider(t, "v2", "v1", "v2"))
+	changed, err := rotated.Rotate(ctx, data)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "v2", data["passport_number"].(map[string]interface{})["kid"])
+
+	changed, err = rotated.Rotate(ctx, data)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// The old key is no longer needed
+	onlyV2, err := NewStaticKeyProvider("v2", map[string][]byte{"v2": bytes.Repeat([]byte{2}, 32)})
+	require.NoError(t, err)
+	require.NoError(t, testEncryptor(t, onlyV2).Decrypt(ctx, data))
+	assert.Equal(t, guestData(), data)
+}
+
+func TestDecryptFailsForUnknownKeyVersion(t *testing.T) {
+	ctx := context.Background()
+
+	data := guestData()
+	require.NoError(t, testEncryptor(t, testProvider(t, "v1", "v1")).Encrypt(ctx, data, nil))
+
+	err := testEncryptor(t, testProvider(t, "v2", "v2")).Decrypt(ctx, data)
+	assert.ErrorIs(t, err, ErrUnknownKeyVersion)
+}
+
+func TestDisabledEncryptorLeavesDataUnchanged(t *testing.T) {
+	ctx := context.Background()
+	var e *FieldEncryptor
+
+	data := guestData()
+	require.NoError(t, e.Encrypt(ctx, data, nil))
+	require.NoError(t, e.Decrypt(ctx, data))
+	e.Redact(data)
+	assert.Equal(t, guestData(), data)
+}
@@ -0,0 +1,188 @@
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// KeyProvider wraps and unwraps the data keys that encrypt individual
+// values. Keys are identified by a version, which is stored next to every
+// wrapped data key so that older versions can still be unwrapped after a
+// rotation.
+type KeyProvider interface {
+	// CurrentVersion returns the version new data keys are wrapped with
+	CurrentVersion() string
+	// WrapKey encrypts a data key with the current key
+	WrapKey(ctx context.Context, dataKey []byte) (version string, wrapped []byte, err error)
+	// UnwrapKey decrypts a data key wrapped with the given version
+	UnwrapKey(ctx context.Context, version string, wrapped []byte) ([]byte, error)
+}
+
+// ErrUnknownKeyVersion is returned for data keys wrapped with a key the
+// provider doesn't have
+var ErrUnknownKeyVersion = errors.New("unknown encryption key version")
+
+// ======== LOCAL KEYFILE ========
+
+// keyfile is the format of a local keyfile:
+//
+//	{"current": "v2", "keys": {"v1": "<base64 32 bytes>", "v2": "<base64 32 bytes>"}}
+type keyfile struct {
+	Current string            `json:"current"`
+	Keys    map[string]string `json:"keys"`
+}
+
+// LocalKeyProvider wraps data keys with AES-256-GCM master keys read from a
+// keyfile. It is meant for development and tests; production should keep
+// the master keys in a KMS.
+type LocalKeyProvider struct {
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+// NewLocalKeyProvider reads master keys from a keyfile
+func NewLocalKeyProvider(path string) (*LocalKeyProvider, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keyfile: %w", err)
+	}
+
+	var file keyfile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("invalid keyfile: %w", err)
+	}
+
+	keys := make(map[string][]byte, len(file.Keys))
+	for version, encoded := range file.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q in keyfile: %w", version, err)
+		}
+		keys[version] = key
+	}
+
+	return NewStaticKeyProvider(file.Current, keys)
+}
+
+// NewStaticKeyProvider returns a LocalKeyProvider for in-memory master keys
+// of 32 bytes
+func NewStaticKeyProvider(current string, keys map[string][]byte) (*LocalKeyProvider, error) {
+	provider := &LocalKeyProvider{
+		current: current,
+		keys:    make(map[string]cipher.AEAD, len(keys)),
+	}
+
+	for version, key := range keys {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("key %q must be 32 bytes", version)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		provider.keys[version] = aead
+	}
+
+	if _, ok := provider.keys[current]; !ok {
+		return nil, fmt.Errorf("current key %q is missing", current)
+	}
+
+	return provider, nil
+}
+
+// CurrentVersion implements KeyProvider
+func (p *LocalKeyProvider) CurrentVersion() string {
+	return p.current
+}
+
+// WrapKey implements KeyProvider
+func (p *LocalKeyProvider) WrapKey(_ context.Context, dataKey []byte) (string, []byte, error) {
+	wrapped, err := seal(p.keys[p.current], dataKey)
+	return p.current, wrapped, err
+}
+
+// UnwrapKey implements KeyProvider
+func (p *LocalKeyProvider) UnwrapKey(_ context.Context, version string, wrapped []byte) ([]byte, error) {
+	aead, ok := p.keys[version]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKeyVersion, version)
+	}
+	return open(aead, wrapped)
+}
+
+// ======== KMS ========
+
+// KMSClient is the part of a key management service the KMS provider needs.
+// Deployments provide an implementation backed by their cloud's KMS.
+type KMSClient interface {
+	Encrypt(ctx context.Context, keyID string, plaintext []byte) ([]byte, error)
+	Decrypt(ctx context.Context, keyID string, ciphertext []byte) ([]byte, error)
+}
+
+// KMSKeyProvider wraps data keys with a key held by a KMS. The key id is
+// used as the version, so rotating means pointing ENCRYPTION_KMS_KEY_ID at a
+// new key while the old one stays available for decryption.
+type KMSKeyProvider struct {
+	client KMSClient
+	keyID  string
+}
+
+// NewKMSKeyProvider returns a KMSKeyProvider
+func NewKMSKeyProvider(client KMSClient, keyID string) *KMSKeyProvider {
+	return &KMSKeyProvider{
+		client: client,
+		keyID:  keyID,
+	}
+}
+
+// CurrentVersion implements KeyProvider
+func (p *KMSKeyProvider) CurrentVersion() string {
+	return p.keyID
+}
+
+// WrapKey implements KeyProvider
+func (p *KMSKeyProvider) WrapKey(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	wrapped, err := p.client.Encrypt(ctx, p.keyID, dataKey)
+	return p.keyID, wrapped, err
+}
+
+// UnwrapKey implements KeyProvider
+func (p *KMSKeyProvider) UnwrapKey(ctx context.Context, version string, wrapped []byte) ([]byte, error) {
+	return p.client.Decrypt(ctx, version, wrapped)
+}
+
+// ======== AES-GCM ========
+
+// newAEAD returns AES-256-GCM for a 32 byte key
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts plaintext and prepends the random nonce
+func seal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts the output of seal
+func open(aead cipher.AEAD, sealed []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
@@ -0,0 +1,123 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"hostflow/booking-service/pkg/lib"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Reencrypter brings the guest data of every reservation to the current key
+// version, after a key rotation or after paths were added to
+// ENCRYPTION_GUEST_DATA_PATHS
+type Reencrypter struct {
+	db        *pgxpool.Pool
+	guestData *FieldEncryptor
+	logger    lib.Logger
+}
+
+// NewReencrypter returns a Reencrypter
+func NewReencrypter(db *pgxpool.Pool, guestData *FieldEncryptor, logger lib.Logger) *Reencrypter {
+	return &Reencrypter{
+		db:        db,
+		guestData: guestData,
+		logger:    logger,
+	}
+}
+
+// ReencryptResult counts the reservations a run went through
+type ReencryptResult struct {
+	Scanned int
+	Updated int
+}
+
+// Run re-encrypts the guest data of all reservations, batchSize rows per
+// transaction. Rows are locked while they are rewritten, so the command can
+// run while the service is serving requests. With dryRun nothing is written.
+func (r *Reencrypter) Run(ctx context.Context, batchSize int, dryRun bool) (ReencryptResult, error) {
+	var result ReencryptResult
+	lastID := int64(-1)
+
+	for {
+		scanned, updated, last, err := r.batch(ctx, lastID, batchSize, dryRun)
+		if err != nil {
+			return result, err
+		}
+
+		result.Scanned += scanned
+		result.Updated += updated
+		lastID = last
+
+		if scanned < batchSize {
+			return result, nil
+		}
+		r.logger.Info(fmt.Sprintf("Re-encrypted %d of %d reservations so far", result.Updated, result.Scanned))
+	}
+}
+
+// batch re-encrypts the batchSize reservations following afterID
+func (r *Reencrypter) batch(ctx context.Context, afterID int64, batchSize int, dryRun bool) (int, int, int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, 0, afterID, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+        SELECT id, guest_data
+        FROM reservation
+        WHERE id > $1
+        ORDER BY id
+        LIMIT $2
+        FOR UPDATE
+    `, afterID, batchSize)
+	if err != nil {
+		return 0, 0, afterID, err
+	}
+
+	type row struct {
+		id        int64
+		guestData map[string]interface{}
+	}
+	var batch []row
+	for rows.Next() {
+		var rw row
+		if err := rows.Scan(&rw.id, &rw.guestData); err != nil {
+			rows.Close()
+			return 0, 0, afterID, err
+		}
+		batch = append(batch, rw)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, afterID, err
+	}
+
+	updated := 0
+	for _, rw := range batch {
+		afterID = rw.id
+
+		changed, err := r.guestData.Rotate(ctx, rw.guestData)
+		if err != nil {
+			return 0, 0, afterID, fmt.Errorf("reservation %d: %w", rw.id, err)
+		}
+		if !changed {
+			continue
+		}
+
+		updated++
+		if dryRun {
+			continue
+		}
+		if _, err := tx.Exec(ctx, `UPDATE reservation SET guest_data = $2 WHERE id = $1`, rw.id, rw.guestData); err != nil {
+			return 0, 0, afterID, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, 0, afterID, err
+	}
+
+	return len(batch), updated, afterID, nil
+}
@@ -0,0 +1,12 @@
+package guest
+
+import (
+	"go.uber.org/fx"
+)
+
+// ======== EXPORTS ========
+
+// Module exports the guest registration rules
+var Module = fx.Options(
+	fx.Provide(GetRules),
+)
@@ -0,0 +1,180 @@
+package guest
+
+import (
+	"encoding/json"
+	"errors"
+	"hostflow/booking-service/internal/encryption"
+	"time"
+)
+
+// GuestsKey is the key of guest_data the registered guests are stored under
+const GuestsKey = "guests"
+
+// Fields of a guest, as used in guest data and in the required fields rules
+const (
+	FieldFirstName      = "first_name"
+	FieldLastName       = "last_name"
+	FieldBirthDate      = "birth_date"
+	FieldNationality    = "nationality"
+	FieldDocumentType   = "document_type"
+	FieldDocumentNumber = "document_number"
+	FieldAddress        = "address"
+)
+
+// Fields of an address
+const (
+	FieldStreet     = "street"
+	FieldCity       = "city"
+	FieldPostalCode = "postal_code"
+	FieldCountry    = "country"
+)
+
+// Document types
+const (
+	DocumentPassport        = "PASSPORT"
+	DocumentIDCard          = "ID_CARD"
+	DocumentDrivingLicence  = "DRIVING_LICENCE"
+	DocumentResidencePermit = "RESIDENCE_PERMIT"
+	DocumentOther           = "OTHER"
+)
+
+// BirthDateLayout is the format of birth dates
+const BirthDateLayout = "2006-01-02"
+
+// Guest is a single registered guest of a reservation. Guests are stored as
+// a list under guest_data.guests.
+type Guest struct {
+	FirstName      string   `json:"first_name,omitempty" example:"Ana"`
+	LastName       string   `json:"last_name,omitempty" example:"Novak"`
+	BirthDate      string   `json:"birth_date,omitempty" example:"1990-04-21"`
+	Nationality    string   `json:"nationality,omitempty" example:"SI"`
+	DocumentType   string   `json:"document_type,omitempty" example:"PASSPORT" enums:"PASSPORT ID_CARD DRIVING_LICENCE RESIDENCE_PERMIT OTHER"`
+	DocumentNumber string   `json:"document_number,omitempty" example:"PB1234567"`
+	Address        *Address `json:"address,omitempty"`
+}
+
+// Address is the permanent address of a guest
+type Address struct {
+	Street     string `json:"street,omitempty" example:"Slovenska cesta 1"`
+	City       string `json:"city,omitempty" example:"Ljubljana"`
+	PostalCode string `json:"postal_code,omitempty" example:"1000"`
+	Country    string `json:"country,omitempty" example:"SI"`
+}
+
+// RedactedAddress stands in for an address that is redacted as a whole,
+// e.g. when the address is an encrypted path, since Address can't hold the
+// Redacted string. Sent back through SetGuests, it keeps the stored address.
+var RedactedAddress = Address{
+	Street:     encryption.Redacted,
+	City:       encryption.Redacted,
+	PostalCode: encryption.Redacted,
+	Country:    encryption.Redacted,
+}
+
+// guestFields and addressFields are the fields a guest and an address may have
+var (
+	guestFields = []string{
+		FieldFirstName, FieldLastName, FieldBirthDate, FieldNationality,
+		FieldDocumentType, FieldDocumentNumber, FieldAddress,
+	}
+	addressFields = []string{FieldStreet, FieldCity, FieldPostalCode, FieldCountry}
+)
+
+// documentTypes are the valid document types
+var documentTypes = map[string]bool{
+	DocumentPassport:        true,
+	DocumentIDCard:          true,
+	DocumentDrivingLicence:  true,
+	DocumentResidencePermit: true,
+	DocumentOther:           true,
+}
+
+// ======== PUBLIC METHODS ========
+
+// Guests returns the registered guests of guest data. Guest data must be in
+// plaintext, i.e. decrypted, for every field to be set; an address redacted
+// as a whole is returned as RedactedAddress.
+func Guests(data map[string]interface{}) ([]Guest, error) {
+	raw, ok := data[GuestsKey]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &list); err != nil {
+		return nil, errors.New("guest data doesn't contain a valid list of guests")
+	}
+
+	guests := make([]Guest, len(list))
+	for i, item := range list {
+		address, redacted := item[FieldAddress], false
+		if isRedactedValue(address) {
+			delete(item, FieldAddress)
+			redacted = true
+		}
+
+		fields, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(fields, &guests[i]); err != nil {
+			return nil, errors.New("guest data doesn't contain a valid list of guests")
+		}
+		if redacted {
+			placeholder := RedactedAddress
+			guests[i].Address = &placeholder
+		}
+	}
+	return guests, nil
+}
+
+// SetGuests replaces the registered guests of guest data. A RedactedAddress
+// is stored as Redacted, so that the stored address is kept when the guest
+// data is encrypted.
+func SetGuests(data map[string]interface{}, guests []Guest) error {
+	encoded, err := json.Marshal(guests)
+	if err != nil {
+		return err
+	}
+
+	var raw []interface{}
+	if err := json.Unmarshal(encoded, &raw); err != nil {
+		return err
+	}
+	for i, g := range guests {
+		if g.Address != nil && *g.Address == RedactedAddress {
+			raw[i].(map[string]interface{})[FieldAddress] = encryption.Redacted
+		}
+	}
+	data[GuestsKey] = raw
+	return nil
+}
+
+// isRedactedValue reports whether a raw JSON value is the Redacted string
+func isRedactedValue(raw json.RawMessage) bool {
+	var s string
+	return json.Unmarshal(raw, &s) == nil && s == encryption.Redacted
+}
+
+// Age returns the age of the guest in whole years on the given day. It is
+// false if the birth date is unknown.
+func (g Guest) Age(on time.Time) (int, bool) {
+	born, err := time.Parse(BirthDateLayout, g.BirthDate)
+	if err != nil {
+		return 0, false
+	}
+
+	age := on.Year() - born.Year()
+	if on.Month() < born.Month() || (on.Month() == born.Month() && on.Day() < born.Day()) {
+		age--
+	}
+	if age < 0 {
+		return 0, true
+	}
+	return age, true
+}
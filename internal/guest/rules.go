@@ -0,0 +1,100 @@
+package guest
+
+import (
+	"fmt"
+	"hostflow/booking-service/pkg/lib"
+	"os"
+	"strings"
+)
+
+// DefaultCountry is the rules key that applies to guests whose nationality
+// has no rule of its own
+const DefaultCountry = "default"
+
+// defaultRequired are the required fields when GUEST_REQUIRED_FIELDS doesn't
+// set a default
+var defaultRequired = []string{FieldFirstName, FieldLastName, FieldBirthDate, FieldNationality}
+
+// Rules are the fields every registered guest must have, by nationality.
+// Domestic guests usually need fewer fields for police registration than
+// foreign guests, who also need a travel document.
+type Rules struct {
+	required map[string][]string
+}
+
+// ======== PUBLIC METHODS ========
+
+// GetRules returns the rules configured with GUEST_REQUIRED_FIELDS
+func GetRules(logger lib.Logger) (*Rules, error) {
+	rules, err := ParseRules(os.Getenv("GUEST_REQUIRED_FIELDS"))
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Required guest fields: ", rules.String())
+	return rules, nil
+}
+
+// ParseRules parses rules of the form
+// "default=first_name,last_name;SI=first_name,last_name,address", where
+// countries are ISO 3166-1 alpha-2 codes and "default" applies to every
+// other nationality. Without a default, first name, last name, birth date
+// and nationality are required.
+func ParseRules(value string) (*Rules, error) {
+	rules := &Rules{
+		required: map[string][]string{DefaultCountry: defaultRequired},
+	}
+
+	for _, item := range strings.Split(value, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		country, list, found := strings.Cut(item, "=")
+		country = strings.TrimSpace(country)
+		if !found || country == "" {
+			return nil, fmt.Errorf("invalid guest fields rule %q, expected country=field,field", item)
+		}
+		if country != DefaultCountry {
+			country = strings.ToUpper(country)
+			if !isCountryCode(country) {
+				return nil, fmt.Errorf("invalid country %q in guest fields rule, expected an ISO 3166-1 alpha-2 code or default", country)
+			}
+		}
+
+		fields := []string{}
+		for _, field := range strings.Split(list, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			if !contains(guestFields, field) {
+				return nil, fmt.Errorf("unknown guest field %q in guest fields rule, expected one of %s", field, strings.Join(guestFields, ", "))
+			}
+			fields = append(fields, field)
+		}
+		rules.required[country] = fields
+	}
+
+	return rules, nil
+}
+
+// Required returns the fields a guest of the nationality must have
+func (r *Rules) Required(nationality string) []string {
+	if fields, ok := r.required[strings.ToUpper(nationality)]; ok {
+		return fields
+	}
+	return r.required[DefaultCountry]
+}
+
+// String returns the rules in the format ParseRules accepts
+func (r *Rules) String() string {
+	items := []string{DefaultCountry + "=" + strings.Join(r.required[DefaultCountry], ",")}
+	for _, country := range sortedKeys(r.required) {
+		if country != DefaultCountry {
+			items = append(items, country+"="+strings.Join(r.required[country], ","))
+		}
+	}
+	return strings.Join(items, ";")
+}
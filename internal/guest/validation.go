@@ -0,0 +1,225 @@
+package guest
+
+import (
+	"fmt"
+	"hostflow/booking-service/internal/encryption"
+	"hostflow/booking-service/pkg/common"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Validation messages, worded like the ones of common.Validation
+const (
+	messageRequired     = "This field is required."
+	messageUnknown      = "This field is not allowed."
+	messageString       = "This field should be a string."
+	messageObject       = "This field should be an object."
+	messageList         = "This field should be a list of guests."
+	messageBirthDate    = "This field should be a date in the format YYYY-MM-DD, not in the future."
+	messageCountry      = "This field should be an ISO 3166-1 alpha-2 country code, e.g. SI."
+	messageDocumentType = "This field should be one of PASSPORT, ID_CARD, DRIVING_LICENCE, RESIDENCE_PERMIT, OTHER."
+)
+
+// requiredAddressFields are the fields a required address must have
+var requiredAddressFields = []string{FieldStreet, FieldCity, FieldCountry}
+
+// ValidationError is returned for guest data that doesn't match the guest
+// model or the rules. Errors use the format of common.Validation.
+type ValidationError struct {
+	Errors []common.ValidationErrorMessage
+}
+
+// Error implements error
+func (e *ValidationError) Error() string {
+	fields := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		fields[i] = fe.Field
+	}
+	return "invalid guest data: " + strings.Join(fields, ", ")
+}
+
+// ======== PUBLIC METHODS ========
+
+// Validate checks the guests registered in guest data against the guest
+// model and the required fields of their nationality, and that their number
+// matches noOfGuests. Guest data without guests is valid, since guests are
+// often registered only at check-in. Encrypted and redacted values count as
+// present: they are kept from the stored reservation.
+func (r *Rules) Validate(data map[string]interface{}, noOfGuests int) error {
+	v := &validator{}
+
+	raw, ok := data[GuestsKey]
+	if !ok || raw == nil {
+		return nil
+	}
+
+	list, ok := raw.([]interface{})
+	if !ok {
+		v.add("guest_data.guests", messageList)
+		return v.err()
+	}
+	if len(list) > 0 && len(list) != noOfGuests {
+		v.add("no_of_guests", fmt.Sprintf("Must be equal to the number of registered guests (%d).", len(list)))
+	}
+
+	for i, item := range list {
+		prefix := fmt.Sprintf("guest_data.guests[%d]", i)
+
+		guest, ok := item.(map[string]interface{})
+		if !ok {
+			v.add(prefix, messageObject)
+			continue
+		}
+		v.unknownFields(prefix, guest, guestFields)
+
+		nationality, _ := guest[FieldNationality].(string)
+		required := r.Required(nationality)
+		for _, field := range guestFields {
+			if field == FieldAddress {
+				v.address(prefix+"."+field, guest[field], contains(required, field))
+				continue
+			}
+			v.value(prefix+"."+field, field, guest[field], contains(required, field))
+		}
+	}
+
+	return v.err()
+}
+
+// ======== PRIVATE METHODS ========
+
+// validator collects validation errors
+type validator struct {
+	errors []common.ValidationErrorMessage
+}
+
+// add records an error of a field
+func (v *validator) add(field, message string) {
+	v.errors = append(v.errors, common.ValidationErrorMessage{Field: field, Message: message})
+}
+
+// err returns the collected errors, if any
+func (v *validator) err() error {
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: v.errors}
+}
+
+// unknownFields records every field of object that is not allowed
+func (v *validator) unknownFields(prefix string, object map[string]interface{}, allowed []string) {
+	for _, key := range sortedKeys(object) {
+		if !contains(allowed, key) {
+			v.add(prefix+"."+key, messageUnknown)
+		}
+	}
+}
+
+// value checks a string field of a guest or an address
+func (v *validator) value(path, field string, value interface{}, required bool) {
+	if isKept(value) {
+		return
+	}
+	if value == nil {
+		if required {
+			v.add(path, messageRequired)
+		}
+		return
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		v.add(path, messageString)
+		return
+	}
+	if strings.TrimSpace(s) == "" {
+		if required {
+			v.add(path, messageRequired)
+		}
+		return
+	}
+
+	switch field {
+	case FieldBirthDate:
+		born, err := time.Parse(BirthDateLayout, s)
+		if err != nil || born.After(time.Now()) {
+			v.add(path, messageBirthDate)
+		}
+	case FieldNationality, FieldCountry:
+		if !isCountryCode(s) {
+			v.add(path, messageCountry)
+		}
+	case FieldDocumentType:
+		if !documentTypes[s] {
+			v.add(path, messageDocumentType)
+		}
+	}
+}
+
+// address checks the address of a guest. A required address must have a
+// street, a city and a country.
+func (v *validator) address(path string, value interface{}, required bool) {
+	if isKept(value) {
+		return
+	}
+	if value == nil {
+		if required {
+			v.add(path, messageRequired)
+		}
+		return
+	}
+
+	address, ok := value.(map[string]interface{})
+	if !ok {
+		v.add(path, messageObject)
+		return
+	}
+	v.unknownFields(path, address, addressFields)
+
+	for _, field := range addressFields {
+		v.value(path+"."+field, field, address[field], required && contains(requiredAddressFields, field))
+	}
+}
+
+// isKept reports whether a value is encrypted, or redacted and so kept from
+// the stored reservation, in which case it can't be checked
+func isKept(value interface{}) bool {
+	if s, ok := value.(string); ok && s == encryption.Redacted {
+		return true
+	}
+	return encryption.IsEncrypted(value)
+}
+
+// isCountryCode reports whether s is formatted as an ISO 3166-1 alpha-2 code
+func isCountryCode(s string) bool {
+	if len(s) != 2 {
+		return false
+	}
+	for _, c := range s {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// contains reports whether list contains s
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
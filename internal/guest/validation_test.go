@@ -0,0 +1,150 @@
+package guest
+
+import (
+	"hostflow/booking-service/pkg/common"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validationErrors(t *testing.T, err error) []common.ValidationErrorMessage {
+	var invalid *ValidationError
+	require.ErrorAs(t, err, &invalid)
+	return invalid.Errors
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules("default=first_name,last_name,document_number; si=first_name,last_name,address")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"first_name", "last_name", "address"}, rules.Required("SI"))
+	assert.Equal(t, []string{"first_name", "last_name", "document_number"}, rules.Required("DE"))
+	assert.Equal(t, "default=first_name,last_name,document_number;SI=first_name,last_name,address", rules.String())
+
+	rules, err = ParseRules("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first_name", "last_name", "birth_date", "nationality"}, rules.Required(""))
+
+	_, err = ParseRules("SI=first_name,shoe_size")
+	assert.Error(t, err)
+	_, err = ParseRules("Slovenia=first_name")
+	assert.Error(t, err)
+	_, err = ParseRules("first_name,last_name")
+	assert.Error(t, err)
+}
+
+func TestValidateRequiredFieldsByNationality(t *testing.T) {
+	rules, err := ParseRules("default=first_name,last_name,document_type,document_number;SI=first_name,last_name,address")
+	require.NoError(t, err)
+
+	data := map[string]interface{}{
+		"guests": []interface{}{
+			map[string]interface{}{"first_name": "Ana", "last_name": "Novak", "nationality": "SI"},
+			map[string]interface{}{"first_name": "Hans", "last_name": "Müller", "nationality": "DE", "document_type": "PASSPORT"},
+		},
+	}
+
+	assert.Equal(t, []common.ValidationErrorMessage{
+		{Field: "guest_data.guests[0].address", Message: "This field is required."},
+		{Field: "guest_data.guests[1].document_number", Message: "This field is required."},
+	}, validationErrors(t, rules.Validate(data, 2)))
+}
+
+func TestValidateFormats(t *testing.T) {
+	rules, err := ParseRules("default=first_name,address")
+	require.NoError(t, err)
+
+	data := map[string]interface{}{
+		"guests": []interface{}{
+			map[string]interface{}{
+				"first_name":    "Ana",
+				"birth_date":    "21.04.1990",
+				"nationality":   "si",
+				"document_type": "VISA",
+				"shoe_size":     42,
+				"address":       map[string]interface{}{"street": "Slovenska cesta 1", "country": "Slovenia"},
+			},
+			"Jan",
+		},
+	}
+
+	assert.Equal(t, []common.ValidationErrorMessage{
+		{Field: "guest_data.guests[0].shoe_size", Message: messageUnknown},
+		{Field: "guest_data.guests[0].birth_date", Message: messageBirthDate},
+		{Field: "guest_data.guests[0].nationality", Message: messageCountry},
+		{Field: "guest_data.guests[0].document_type", Message: messageDocumentType},
+		{Field: "guest_data.guests[0].address.city", Message: messageRequired},
+		{Field: "guest_data.guests[0].address.country", Message: messageCountry},
+		{Field: "guest_data.guests[1]", Message: messageObject},
+	}, validationErrors(t, rules.Validate(data, 2)))
+}
+
+func TestValidateNoOfGuests(t *testing.T) {
+	rules, err := ParseRules("default=first_name")
+	require.NoError(t, err)
+
+	data := map[string]interface{}{
+		"guests": []interface{}{
+			map[string]interface{}{"first_name": "Ana"},
+		},
+	}
+
+	assert.NoError(t, rules.Validate(data, 1))
+	assert.Equal(t, []common.ValidationErrorMessage{
+		{Field: "no_of_guests", Message: "Must be equal to the number of registered guests (1)."},
+	}, validationErrors(t, rules.Validate(data, 3)))
+
+	// Guests are registered later
+	assert.NoError(t, rules.Validate(map[string]interface{}{"notes": "late arrival"}, 3))
+	assert.NoError(t, rules.Validate(map[string]interface{}{"guests": []interface{}{}}, 3))
+}
+
+func TestValidateKeepsRedactedAndEncryptedValues(t *testing.T) {
+	rules, err := ParseRules("default=first_name,document_number,birth_date")
+	require.NoError(t, err)
+
+	data := map[string]interface{}{
+		"guests": []interface{}{
+			map[string]interface{}{
+				"first_name":      "Ana",
+				"document_number": "[redacted]",
+				"birth_date":      map[string]interface{}{"$enc": "aes-256-gcm", "kid": "v1", "dk": "a2V5", "ct": "Y3Q="},
+			},
+		},
+	}
+
+	assert.NoError(t, rules.Validate(data, 1))
+}
+
+func TestGuestsRoundTrip(t *testing.T) {
+	data := map[string]interface{}{"notes": "late arrival"}
+	guests := []Guest{
+		{FirstName: "Ana", LastName: "Novak", BirthDate: "1990-04-21", Nationality: "SI", Address: &Address{City: "Ljubljana", Country: "SI"}},
+	}
+
+	require.NoError(t, SetGuests(data, guests))
+	assert.Equal(t, "late arrival", data["notes"])
+
+	parsed, err := Guests(data)
+	require.NoError(t, err)
+	assert.Equal(t, guests, parsed)
+
+	_, err = Guests(map[string]interface{}{"guests": "Ana"})
+	assert.Error(t, err)
+}
+
+func TestGuestAge(t *testing.T) {
+	g := Guest{BirthDate: "2010-06-15"}
+
+	age, ok := g.Age(time.Date(2024, 6, 14, 0, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, 13, age)
+
+	age, _ = g.Age(time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, 14, age)
+
+	_, ok = Guest{}.Age(time.Now())
+	assert.False(t, ok)
+}
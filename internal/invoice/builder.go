@@ -0,0 +1,226 @@
+package invoice
+
+import (
+	"encoding/json"
+	"fmt"
+	"hostflow/booking-service/internal/touristtax"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Codes of lines that don't come from a single price element
+const (
+	codeAccommodation = "accommodation"
+	codeOther         = "other"
+)
+
+// BuildLines returns the invoice lines of a reservation. Every price element
+// with an amount, either a number or an object with an "amount", becomes a
+// line; objects may set their own "description" and "vat_rate". Whatever
+// the price elements don't explain of the total price is invoiced as
+// accommodation, or as other charges next to other lines. Tourist tax is not
+// subject to VAT.
+func BuildLines(priceElements map[string]interface{}, totalPrice, vatRate float64) ([]Line, error) {
+	keys := make([]string, 0, len(priceElements))
+	for key := range priceElements {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var lines []Line
+	var sum float64
+	for _, key := range keys {
+		line, ok, err := elementLine(key, priceElements[key], vatRate)
+		if err != nil {
+			return nil, err
+		}
+		if !ok || line.Total == 0 {
+			continue
+		}
+		lines = append(lines, line)
+		sum += line.Total
+	}
+
+	if rest := round(totalPrice - sum); rest != 0 {
+		line := Line{Code: codeOther, Description: "Other charges", VatRate: vatRate, Total: rest}
+		if len(lines) == 0 {
+			line.Code, line.Description = codeAccommodation, "Accommodation"
+		}
+		lines = append(lines, line)
+	}
+
+	for i := range lines {
+		lines[i].split()
+	}
+	return lines, nil
+}
+
+// CreditLines returns the lines of a credit note of amount on an invoice.
+// Crediting the whole invoice reverses its lines; anything less is split
+// between its VAT rates in proportion to their totals.
+func CreditLines(invoice *Document, amount float64, reason string) []Line {
+	if round(amount) == round(invoice.Total) {
+		lines := make([]Line, 0, len(invoice.Lines))
+		for _, line := range invoice.Lines {
+			lines = append(lines, Line{
+				Code:        line.Code,
+				Description: line.Description,
+				VatRate:     line.VatRate,
+				Net:         -line.Net,
+				Vat:         -line.Vat,
+				Total:       -line.Total,
+			})
+		}
+		return lines
+	}
+
+	description := "Refund"
+	if reason == ReasonCancellation {
+		description = "Cancellation"
+	}
+
+	var lines []Line
+	remaining := round(amount)
+	for i, vat := range invoice.VatBreakdown {
+		share := round(amount * vat.Total / invoice.Total)
+		if i == len(invoice.VatBreakdown)-1 {
+			share = remaining
+		}
+		remaining = round(remaining - share)
+		if share == 0 {
+			continue
+		}
+
+		line := Line{
+			Code:        reason,
+			Description: fmt.Sprintf("%s (VAT %s%%)", description, formatRate(vat.Rate)),
+			VatRate:     vat.Rate,
+			Total:       -share,
+		}
+		line.split()
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Totals sets the VAT breakdown and the totals of a document from its lines
+func (d *Document) Totals() {
+	byRate := make(map[float64]*VatAmount)
+	var rates []float64
+	d.NetTotal, d.VatTotal, d.Total = 0, 0, 0
+
+	for _, line := range d.Lines {
+		vat, ok := byRate[line.VatRate]
+		if !ok {
+			vat = &VatAmount{Rate: line.VatRate}
+			byRate[line.VatRate] = vat
+			rates = append(rates, line.VatRate)
+		}
+		vat.Net = round(vat.Net + line.Net)
+		vat.Vat = round(vat.Vat + line.Vat)
+		vat.Total = round(vat.Total + line.Total)
+
+		d.NetTotal = round(d.NetTotal + line.Net)
+		d.VatTotal = round(d.VatTotal + line.Vat)
+		d.Total = round(d.Total + line.Total)
+	}
+
+	sort.Float64s(rates)
+	d.VatBreakdown = make([]VatAmount, 0, len(rates))
+	for _, rate := range rates {
+		d.VatBreakdown = append(d.VatBreakdown, *byRate[rate])
+	}
+}
+
+// ======== PRIVATE METHODS ========
+
+// elementLine converts a price element to a line. It is false for elements
+// without an amount.
+func elementLine(key string, value interface{}, vatRate float64) (Line, bool, error) {
+	line := Line{Code: key, Description: describe(key), VatRate: vatRate}
+
+	if key == touristtax.PriceElementKey {
+		tax, err := touristtax.LineOf(map[string]interface{}{key: value})
+		if err != nil || tax == nil {
+			return line, false, err
+		}
+		line.Description = "Tourist tax"
+		if tax.MunicipalityName != "" {
+			line.Description += " (" + tax.MunicipalityName + ")"
+		}
+		line.VatRate = 0
+		line.Total = round(tax.Amount)
+		return line, true, nil
+	}
+
+	if amount, ok := number(value); ok {
+		line.Total = round(amount)
+		return line, true, nil
+	}
+
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return line, false, nil
+	}
+	amount, ok := number(object["amount"])
+	if !ok {
+		return line, false, nil
+	}
+	line.Total = round(amount)
+	if description, ok := object["description"].(string); ok && description != "" {
+		line.Description = description
+	} else if name, ok := object["name"].(string); ok && name != "" {
+		line.Description = name
+	}
+	if rate, ok := number(object["vat_rate"]); ok && rate >= 0 && rate < 100 {
+		line.VatRate = rate
+	}
+	return line, true, nil
+}
+
+// split sets the net amount and VAT of a line from its total
+func (l *Line) split() {
+	l.Net = round(l.Total / (1 + l.VatRate/100))
+	l.Vat = round(l.Total - l.Net)
+}
+
+// number returns the value of a JSON number
+func number(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// describe turns a price element key into a description, e.g. cleaning_fee
+// into "Cleaning fee"
+func describe(key string) string {
+	words := strings.Fields(strings.NewReplacer("_", " ", "-", " ").Replace(key))
+	if len(words) == 0 {
+		return key
+	}
+	text := []rune(strings.Join(words, " "))
+	text[0] = unicode.ToUpper(text[0])
+	return string(text)
+}
+
+// formatRate formats a VAT rate without trailing zeros
+func formatRate(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', -1, 64)
+}
+
+// round rounds an amount to cents
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
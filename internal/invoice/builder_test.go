@@ -0,0 +1,96 @@
+package invoice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildLines(t *testing.T) {
+	priceElements := map[string]interface{}{
+		"cleaning_fee": 50.0,
+		"breakfast":    map[string]interface{}{"amount": 44.0, "description": "Breakfast for two", "vat_rate": 22.0},
+		"tourist_tax": map[string]interface{}{
+			"amount":            25.04,
+			"municipality_name": "Ljubljana",
+		},
+		"discount_code": "SUMMER",
+	}
+
+	lines, err := BuildLines(priceElements, 519.04, 9.5)
+	require.NoError(t, err)
+	require.Len(t, lines, 4)
+
+	assert.Equal(t, "Breakfast for two", lines[0].Description)
+	assert.Equal(t, 22.0, lines[0].VatRate)
+	assert.Equal(t, 36.07, lines[0].Net)
+	assert.Equal(t, 7.93, lines[0].Vat)
+
+	assert.Equal(t, "Cleaning fee", lines[1].Description)
+	assert.Equal(t, 9.5, lines[1].VatRate)
+	assert.Equal(t, 45.66, lines[1].Net)
+
+	assert.Equal(t, "Tourist tax (Ljubljana)", lines[2].Description)
+	assert.Equal(t, 0.0, lines[2].VatRate)
+	assert.Equal(t, 25.04, lines[2].Net)
+
+	// The rest of the total price
+	assert.Equal(t, codeOther, lines[3].Code)
+	assert.Equal(t, 400.0, lines[3].Total)
+}
+
+func TestBuildLines_OnlyTotal(t *testing.T) {
+	lines, err := BuildLines(map[string]interface{}{}, 500, 9.5)
+	require.NoError(t, err)
+	require.Len(t, lines, 1)
+	assert.Equal(t, codeAccommodation, lines[0].Code)
+	assert.Equal(t, 456.62, lines[0].Net)
+	assert.Equal(t, 43.38, lines[0].Vat)
+}
+
+func TestDocumentTotals(t *testing.T) {
+	lines, err := BuildLines(map[string]interface{}{"tourist_tax": map[string]interface{}{"amount": 20.0}}, 520, 9.5)
+	require.NoError(t, err)
+
+	doc := &Document{Lines: lines}
+	doc.Totals()
+
+	assert.Equal(t, 520.0, doc.Total)
+	assert.Equal(t, 43.38, doc.VatTotal)
+	assert.Equal(t, 476.62, doc.NetTotal)
+	require.Len(t, doc.VatBreakdown, 2)
+	assert.Equal(t, 0.0, doc.VatBreakdown[0].Rate)
+	assert.Equal(t, 20.0, doc.VatBreakdown[0].Total)
+	assert.Equal(t, 9.5, doc.VatBreakdown[1].Rate)
+	assert.Equal(t, 500.0, doc.VatBreakdown[1].Total)
+}
+
+func TestCreditLines(t *testing.T) {
+	lines, err := BuildLines(map[string]interface{}{"tourist_tax": map[string]interface{}{"amount": 20.0}}, 520, 9.5)
+	require.NoError(t, err)
+	invoice := &Document{Lines: lines}
+	invoice.Totals()
+
+	// The whole invoice reverses every line
+	full := &Document{Lines: CreditLines(invoice, 520, ReasonCancellation)}
+	full.Totals()
+	assert.Len(t, full.Lines, 2)
+	assert.Equal(t, -520.0, full.Total)
+	assert.Equal(t, -43.38, full.VatTotal)
+
+	// A part is split between the VAT rates
+	partial := &Document{Lines: CreditLines(invoice, 52, ReasonRefund)}
+	partial.Totals()
+	require.Len(t, partial.Lines, 2)
+	assert.Equal(t, "Refund (VAT 0%)", partial.Lines[0].Description)
+	assert.Equal(t, -2.0, partial.Lines[0].Total)
+	assert.Equal(t, "Refund (VAT 9.5%)", partial.Lines[1].Description)
+	assert.Equal(t, -50.0, partial.Lines[1].Total)
+	assert.Equal(t, -52.0, partial.Total)
+}
+
+func TestFormatNumber(t *testing.T) {
+	assert.Equal(t, "INV-2026-000017", formatNumber(seriesInvoice, 2026, 17))
+	assert.Equal(t, "CN-2027-000001", formatNumber(seriesCreditNote, 2027, 1))
+}
@@ -0,0 +1,307 @@
+package invoice
+
+import (
+	"errors"
+	"hostflow/booking-service/internal/audit"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Controller handles HTTP requests for invoices
+type Controller struct {
+	service *Service
+}
+
+// NewController returns a Controller
+func NewController(service *Service) *Controller {
+	return &Controller{
+		service: service,
+	}
+}
+
+func (c *Controller) getOrgID(ctx *gin.Context) (int64, bool) {
+	val, exists := ctx.Get("organization_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return 0, false
+	}
+	return val.(int64), true
+}
+
+func (c *Controller) getID(ctx *gin.Context, param string) (int64, bool) {
+	id, err := strconv.ParseInt(ctx.Param(param), 10, 64)
+	if err != nil || id <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return 0, false
+	}
+	return id, true
+}
+
+// ======== SETTINGS ========
+
+// GetSettingsHandler godoc
+// @Summary Get the invoice settings
+// @Description Returns the issuer details and default VAT rate printed on the invoices of the organization
+// @Tags invoices
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} Settings
+// @Failure 404 {object} map[string]string
+// @Router /invoices/settings [get]
+func (c *Controller) GetSettingsHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	settings, err := c.service.GetSettings(orgID)
+	if errors.Is(err, ErrNotConfigured) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, settings)
+}
+
+// SaveSettingsHandler godoc
+// @Summary Save the invoice settings
+// @Description Creates or replaces the issuer details and default VAT rate of the organization. Invoices already issued keep the details they were issued with.
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body SettingsRequest true "Settings"
+// @Success 200 {object} Settings
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /invoices/settings [put]
+func (c *Controller) SaveSettingsHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	var req SettingsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	settings, err := c.service.SaveSettings(orgID, req)
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, settings)
+}
+
+// ======== INVOICES ========
+
+// GetInvoicesHandler godoc
+// @Summary Get the invoices of a reservation
+// @Description Returns the invoices and credit notes of the reservation in the order they were issued
+// @Tags invoices
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Reservation ID"
+// @Success 200 {array} Invoice
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /reservations/{id}/invoices [get]
+func (c *Controller) GetInvoicesHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+	reservationID, ok := c.getID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	invoices, err := c.service.GetInvoices(reservationID, orgID)
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, invoices)
+}
+
+// IssueInvoiceHandler godoc
+// @Summary Issue the invoice of a reservation
+// @Description Issues an invoice with the next number of the organization in the current fiscal year, built from the price elements and total price of the reservation. Amounts include VAT at the rate of the settings, unless a price element sets its own vat_rate; tourist tax is not subject to VAT. Confirmed and completed reservations are invoiced as paid. A reservation can only be invoiced again once its invoice is fully credited. Invoices can't be changed once issued.
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Reservation ID"
+// @Param request body IssueRequest false "Buyer details and notes"
+// @Success 201 {object} Invoice
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string "Already invoiced, not invoiceable, a hold or settings not configured"
+// @Router /reservations/{id}/invoices [post]
+func (c *Controller) IssueInvoiceHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+	reservationID, ok := c.getID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var req IssueRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	invoice, err := c.service.Issue(reservationID, orgID, req, audit.ActorFromContext(ctx))
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, invoice)
+}
+
+// GetInvoiceHandler godoc
+// @Summary Get an invoice
+// @Description Returns an invoice or credit note of the reservation, with its document as issued
+// @Tags invoices
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Reservation ID"
+// @Param invoiceId path int true "Invoice ID"
+// @Success 200 {object} Invoice
+// @Failure 404 {object} map[string]string
+// @Router /reservations/{id}/invoices/{invoiceId} [get]
+func (c *Controller) GetInvoiceHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+	reservationID, ok := c.getID(ctx, "id")
+	if !ok {
+		return
+	}
+	invoiceID, ok := c.getID(ctx, "invoiceId")
+	if !ok {
+		return
+	}
+
+	invoice, err := c.service.GetInvoice(invoiceID, reservationID, orgID)
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, invoice)
+}
+
+// GetPDFHandler godoc
+// @Summary Download an invoice as PDF
+// @Description Returns the PDF of an invoice or credit note, as rendered when it was issued
+// @Tags invoices
+// @Produce application/pdf
+// @Security ApiKeyAuth
+// @Param id path int true "Reservation ID"
+// @Param invoiceId path int true "Invoice ID"
+// @Success 200 {file} file
+// @Failure 404 {object} map[string]string
+// @Router /reservations/{id}/invoices/{invoiceId}/pdf [get]
+func (c *Controller) GetPDFHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+	reservationID, ok := c.getID(ctx, "id")
+	if !ok {
+		return
+	}
+	invoiceID, ok := c.getID(ctx, "invoiceId")
+	if !ok {
+		return
+	}
+
+	number, pdf, err := c.service.GetPDF(invoiceID, reservationID, orgID)
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", "attachment; filename="+number+".pdf")
+	ctx.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// CreditNoteHandler godoc
+// @Summary Issue a credit note
+// @Description Credits an invoice for a cancellation or a refund with a credit note from the organization's own number series. Without an amount, everything not yet credited is credited; a partial amount is split between the VAT rates of the invoice.
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Reservation ID"
+// @Param invoiceId path int true "Invoice ID"
+// @Param request body CreditNoteRequest true "Reason and amount"
+// @Success 201 {object} Invoice
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /reservations/{id}/invoices/{invoiceId}/credit-notes [post]
+func (c *Controller) CreditNoteHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+	reservationID, ok := c.getID(ctx, "id")
+	if !ok {
+		return
+	}
+	invoiceID, ok := c.getID(ctx, "invoiceId")
+	if !ok {
+		return
+	}
+
+	var req CreditNoteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	creditNote, err := c.service.CreditNote(invoiceID, reservationID, orgID, req, audit.ActorFromContext(ctx))
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, creditNote)
+}
+
+// ======== PRIVATE METHODS ========
+
+// fail responds with the status of a service error
+func (c *Controller) fail(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrReservationNotFound), errors.Is(err, ErrInvoiceNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrNotConfigured), errors.Is(err, ErrNotInvoiceable), errors.Is(err, ErrHoldNotInvoiceable),
+		errors.Is(err, ErrAlreadyInvoiced),
+		errors.Is(err, ErrNotCreditable), errors.Is(err, ErrFullyCredited):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrInvalidAmount):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,12 @@
+package invoice
+
+import (
+	"go.uber.org/fx"
+)
+
+// ======== EXPORTS ========
+
+// Module exports invoicing
+var Module = fx.Options(
+	fx.Provide(NewRepository, NewService, NewController, SetRoutes),
+)
@@ -0,0 +1,240 @@
+package invoice
+
+import (
+	"errors"
+	"time"
+)
+
+// Kinds of invoices
+const (
+	KindInvoice    = "INVOICE"
+	KindCreditNote = "CREDIT_NOTE"
+)
+
+// Number series of each kind. Every series is numbered from 1 in every
+// fiscal year.
+const (
+	seriesInvoice    = "INV"
+	seriesCreditNote = "CN"
+)
+
+// Reasons for a credit note
+const (
+	ReasonCancellation = "cancellation"
+	ReasonRefund       = "refund"
+)
+
+// Payment statuses of an invoice
+const (
+	PaymentPaid = "PAID"
+	PaymentDue  = "DUE"
+)
+
+// Reservation statuses that matter for invoicing
+const (
+	statusConfirmed = "CONFIRMED"
+	statusCompleted = "COMPLETED"
+	statusCancelled = "CANCELLED"
+	statusRejected  = "REJECTED"
+)
+
+// kindHold reservations block a stay for a prospective guest; they are not
+// invoiced until they are converted into a booking
+const kindHold = "HOLD"
+
+// Audit log actions, recorded on the reservation
+const (
+	actionInvoiceIssued    = "invoice_issued"
+	actionCreditNoteIssued = "credit_note_issued"
+)
+
+// DateLayout is the format of invoice dates
+const DateLayout = "2006-01-02"
+
+// invoiceColumns are the columns scanned into Invoice. The PDF is only
+// read on its own.
+const invoiceColumns = `
+    id, organization_id, reservation_id, kind, series, fiscal_year,
+    sequence_number, number, credited_invoice_id, currency, net_total,
+    vat_total, total, document, issued_by, issued_at
+`
+
+// settingsColumns are the columns scanned into Settings
+const settingsColumns = `
+    organization_id, issuer_name, issuer_address, issuer_tax_id, issuer_email,
+    vat_rate, currency, footer, updated_at
+`
+
+// reservationColumns are the columns scanned into Reservation
+const reservationColumns = `
+    id, organization_id, property_id, customer_id, check_in_date,
+    check_out_date, status, kind, total_price, price_elements, no_of_guests
+`
+
+// Errors returned by the invoice service
+var (
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrInvoiceNotFound     = errors.New("invoice not found")
+	ErrNotConfigured       = errors.New("the invoice settings of the organization are not configured")
+	ErrNotInvoiceable      = errors.New("cancelled and rejected reservations can't be invoiced")
+	ErrHoldNotInvoiceable  = errors.New("holds can't be invoiced until they are converted into a booking")
+	ErrAlreadyInvoiced     = errors.New("the reservation has an invoice that is not fully credited")
+	ErrNotCreditable       = errors.New("only invoices can be credited")
+	ErrFullyCredited       = errors.New("the invoice is already fully credited")
+	ErrInvalidAmount       = errors.New("amount must be positive and at most the amount not yet credited")
+)
+
+// Settings are the issuer details of an organization
+type Settings struct {
+	OrganizationID int64     `json:"organization_id" db:"organization_id"`
+	IssuerName     string    `json:"issuer_name" db:"issuer_name" example:"Apartmaji Bled d.o.o."`
+	IssuerAddress  string    `json:"issuer_address" db:"issuer_address" example:"Cesta svobode 1, 4260 Bled"`
+	IssuerTaxID    string    `json:"issuer_tax_id" db:"issuer_tax_id" example:"SI12345678"`
+	IssuerEmail    *string   `json:"issuer_email,omitempty" db:"issuer_email" example:"info@example.com"`
+	VatRate        float64   `json:"vat_rate" db:"vat_rate" example:"9.5"`
+	Currency       string    `json:"currency" db:"currency" example:"EUR"`
+	Footer         *string   `json:"footer,omitempty" db:"footer" example:"Thank you for your stay"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// SettingsRequest is the body of a settings update
+type SettingsRequest struct {
+	IssuerName    string  `json:"issuer_name" binding:"required,max=200" example:"Apartmaji Bled d.o.o."`
+	IssuerAddress string  `json:"issuer_address" binding:"required,max=500" example:"Cesta svobode 1, 4260 Bled"`
+	IssuerTaxID   string  `json:"issuer_tax_id" binding:"required,max=50" example:"SI12345678"`
+	IssuerEmail   *string `json:"issuer_email" binding:"omitempty,email" example:"info@example.com"`
+	VatRate       float64 `json:"vat_rate" binding:"min=0,lt=100" example:"9.5"`
+	Currency      string  `json:"currency" binding:"omitempty,len=3" example:"EUR"`
+	Footer        *string `json:"footer" binding:"omitempty,max=500" example:"Thank you for your stay"`
+}
+
+// IssueRequest is the body of an invoice issue
+type IssueRequest struct {
+	Buyer   *Buyer `json:"buyer"`
+	DueDate string `json:"due_date" binding:"omitempty,datetime=2006-01-02" example:"2026-07-10"`
+	Notes   string `json:"notes" binding:"max=1000" example:"Booking reference 42"`
+}
+
+// CreditNoteRequest is the body of a credit note issue. Without an amount,
+// everything not yet credited is credited.
+type CreditNoteRequest struct {
+	Reason string   `json:"reason" binding:"required,oneof=cancellation refund" example:"refund"`
+	Amount *float64 `json:"amount" binding:"omitempty,gt=0" example:"50.00"`
+	Notes  string   `json:"notes" binding:"max=1000" example:"Early departure"`
+}
+
+// Invoice is an issued invoice or credit note
+type Invoice struct {
+	ID                int64     `json:"id" db:"id"`
+	OrganizationID    int64     `json:"organization_id" db:"organization_id"`
+	ReservationID     int64     `json:"reservation_id" db:"reservation_id"`
+	Kind              string    `json:"kind" db:"kind" enums:"INVOICE,CREDIT_NOTE" example:"INVOICE"`
+	Series            string    `json:"series" db:"series" example:"INV"`
+	FiscalYear        int       `json:"fiscal_year" db:"fiscal_year" example:"2026"`
+	SequenceNumber    int64     `json:"sequence_number" db:"sequence_number" example:"17"`
+	Number            string    `json:"number" db:"number" example:"INV-2026-000017"`
+	CreditedInvoiceID *int64    `json:"credited_invoice_id,omitempty" db:"credited_invoice_id"`
+	Currency          string    `json:"currency" db:"currency" example:"EUR"`
+	NetTotal          float64   `json:"net_total" db:"net_total" example:"456.62"`
+	VatTotal          float64   `json:"vat_total" db:"vat_total" example:"43.38"`
+	Total             float64   `json:"total" db:"total" example:"500.00"`
+	Document          Document  `json:"document" db:"document"`
+	IssuedBy          string    `json:"issued_by" db:"issued_by" example:"user:7d1c3f5e-5b8e-4a43-9d0b-2f6b1f0c9a11"`
+	IssuedAt          time.Time `json:"issued_at" db:"issued_at"`
+}
+
+// Document is the content of an invoice as issued. Amounts include VAT;
+// on credit notes they are negative.
+type Document struct {
+	Kind            string          `json:"kind" example:"INVOICE"`
+	Number          string          `json:"number" example:"INV-2026-000017"`
+	IssueDate       string          `json:"issue_date" example:"2026-07-01"`
+	Issuer          Issuer          `json:"issuer"`
+	Buyer           Buyer           `json:"buyer"`
+	Reservation     ReservationInfo `json:"reservation"`
+	Currency        string          `json:"currency" example:"EUR"`
+	Lines           []Line          `json:"lines"`
+	VatBreakdown    []VatAmount     `json:"vat_breakdown"`
+	NetTotal        float64         `json:"net_total" example:"456.62"`
+	VatTotal        float64         `json:"vat_total" example:"43.38"`
+	Total           float64         `json:"total" example:"500.00"`
+	Payment         *Payment        `json:"payment,omitempty"`
+	CreditedInvoice *CreditedRef    `json:"credited_invoice,omitempty"`
+	Reason          string          `json:"reason,omitempty" example:"refund"`
+	Notes           string          `json:"notes,omitempty"`
+	Footer          string          `json:"footer,omitempty"`
+}
+
+// Issuer is the seller on an invoice
+type Issuer struct {
+	Name    string `json:"name" example:"Apartmaji Bled d.o.o."`
+	Address string `json:"address" example:"Cesta svobode 1, 4260 Bled"`
+	TaxID   string `json:"tax_id" example:"SI12345678"`
+	Email   string `json:"email,omitempty" example:"info@example.com"`
+}
+
+// Buyer is the customer on an invoice
+type Buyer struct {
+	CustomerID int64  `json:"customer_id" example:"100"`
+	Name       string `json:"name,omitempty" binding:"max=200" example:"Ana Novak"`
+	Address    string `json:"address,omitempty" binding:"max=500" example:"Slovenska cesta 1, 1000 Ljubljana"`
+	TaxID      string `json:"tax_id,omitempty" binding:"max=50" example:"SI87654321"`
+}
+
+// ReservationInfo is the stay an invoice is for
+type ReservationInfo struct {
+	ID           int64  `json:"id" example:"42"`
+	PropertyID   int64  `json:"property_id" example:"10"`
+	CheckInDate  string `json:"check_in_date" example:"2026-07-10"`
+	CheckOutDate string `json:"check_out_date" example:"2026-07-14"`
+	Nights       int    `json:"nights" example:"4"`
+	NoOfGuests   int    `json:"no_of_guests" example:"2"`
+}
+
+// Line is an invoice line, built from a price element
+type Line struct {
+	Code        string  `json:"code" example:"accommodation"`
+	Description string  `json:"description" example:"Accommodation"`
+	VatRate     float64 `json:"vat_rate" example:"9.5"`
+	Net         float64 `json:"net" example:"431.51"`
+	Vat         float64 `json:"vat" example:"40.99"`
+	Total       float64 `json:"total" example:"472.50"`
+}
+
+// VatAmount is the VAT of the lines with the same rate
+type VatAmount struct {
+	Rate  float64 `json:"rate" example:"9.5"`
+	Net   float64 `json:"net" example:"431.51"`
+	Vat   float64 `json:"vat" example:"40.99"`
+	Total float64 `json:"total" example:"472.50"`
+}
+
+// Payment is the payment state of a reservation when it was invoiced
+type Payment struct {
+	Status    string  `json:"status" enums:"PAID,DUE" example:"PAID"`
+	Paid      float64 `json:"paid" example:"500.00"`
+	AmountDue float64 `json:"amount_due" example:"0"`
+	DueDate   string  `json:"due_date,omitempty" example:"2026-07-10"`
+}
+
+// CreditedRef is the invoice a credit note credits
+type CreditedRef struct {
+	ID        int64  `json:"id" example:"17"`
+	Number    string `json:"number" example:"INV-2026-000017"`
+	IssueDate string `json:"issue_date" example:"2026-07-01"`
+}
+
+// Reservation is a reservation as read for invoicing
+type Reservation struct {
+	ID             int64                  `db:"id"`
+	OrganizationID int64                  `db:"organization_id"`
+	PropertyID     int64                  `db:"property_id"`
+	CustomerID     int64                  `db:"customer_id"`
+	CheckInDate    time.Time              `db:"check_in_date"`
+	CheckOutDate   time.Time              `db:"check_out_date"`
+	Status         string                 `db:"status"`
+	Kind           string                 `db:"kind"`
+	TotalPrice     float64                `db:"total_price"`
+	PriceElements  map[string]interface{} `db:"price_elements"`
+	NoOfGuests     int                    `db:"no_of_guests"`
+}
@@ -0,0 +1,288 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Page geometry in points (A4)
+const (
+	pageWidth    = 595.0
+	pageHeight   = 842.0
+	marginLeft   = 50.0
+	marginRight  = 545.0
+	marginTop    = 790.0
+	marginBottom = 60.0
+)
+
+// Fonts of the rendered PDF. Amounts use a monospaced font so that they can
+// be right aligned without font metrics.
+const (
+	fontRegular = "F1"
+	fontBold    = "F2"
+	fontMono    = "F3"
+)
+
+// monoWidth is the width of a Courier glyph relative to the font size
+const monoWidth = 0.6
+
+// maxDescription is how many characters of a line description are printed
+const maxDescription = 55
+
+// winAnsi maps the characters outside Latin-1 that the standard fonts can
+// print to their WinAnsiEncoding codes
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92,
+	'“': 0x93, '”': 0x94, '–': 0x96, '—': 0x97, 'Š': 0x8A, 'š': 0x9A,
+	'Ž': 0x8E, 'ž': 0x9E, 'Œ': 0x8C, 'œ': 0x9C,
+}
+
+// fallback replaces characters the standard fonts can't print
+var fallback = map[rune]byte{
+	'Č': 'C', 'č': 'c', 'Ć': 'C', 'ć': 'c', 'Đ': 0xD0, 'đ': 'd',
+}
+
+// RenderPDF renders an invoice as a PDF document. The rendering only depends
+// on the document, so rendering it again gives the same bytes.
+func RenderPDF(doc *Document) []byte {
+	p := newPDF()
+
+	title := "INVOICE"
+	if doc.Kind == KindCreditNote {
+		title = "CREDIT NOTE"
+	}
+	p.text(marginLeft, p.y, fontBold, 18, title+" "+doc.Number)
+	p.down(26)
+
+	p.text(marginLeft, p.y, fontRegular, 10, "Issue date: "+doc.IssueDate)
+	if doc.Payment != nil && doc.Payment.DueDate != "" {
+		p.text(300, p.y, fontRegular, 10, "Due date: "+doc.Payment.DueDate)
+	}
+	p.down(24)
+
+	// Issuer and buyer side by side
+	top := p.y
+	p.text(marginLeft, p.y, fontBold, 10, "Issuer")
+	p.down(14)
+	for _, line := range nonEmpty(doc.Issuer.Name, doc.Issuer.Address, prefixed("Tax ID: ", doc.Issuer.TaxID), doc.Issuer.Email) {
+		p.text(marginLeft, p.y, fontRegular, 10, line)
+		p.down(13)
+	}
+	left := p.y
+
+	p.y = top
+	p.text(300, p.y, fontBold, 10, "Bill to")
+	p.down(14)
+	for _, line := range nonEmpty(doc.Buyer.Name, doc.Buyer.Address, prefixed("Tax ID: ", doc.Buyer.TaxID),
+		"Customer #"+strconv.FormatInt(doc.Buyer.CustomerID, 10)) {
+		p.text(300, p.y, fontRegular, 10, line)
+		p.down(13)
+	}
+	p.y = min(p.y, left)
+	p.down(12)
+
+	r := doc.Reservation
+	p.text(marginLeft, p.y, fontRegular, 10, fmt.Sprintf("Reservation #%d, property #%d: %s – %s, %d night(s), %d guest(s)",
+		r.ID, r.PropertyID, r.CheckInDate, r.CheckOutDate, r.Nights, r.NoOfGuests))
+	p.down(14)
+	if doc.CreditedInvoice != nil {
+		p.text(marginLeft, p.y, fontRegular, 10, fmt.Sprintf("Credits invoice %s of %s (%s)",
+			doc.CreditedInvoice.Number, doc.CreditedInvoice.IssueDate, doc.Reason))
+		p.down(14)
+	}
+	p.down(10)
+
+	// Lines
+	p.row(fontBold, "Description", "VAT %", "Net", "VAT", "Total "+doc.Currency)
+	p.rule()
+	for _, line := range doc.Lines {
+		p.row(fontRegular, line.Description, formatRate(line.VatRate), formatAmount(line.Net),
+			formatAmount(line.Vat), formatAmount(line.Total))
+	}
+	p.rule()
+	p.down(6)
+
+	// VAT breakdown and totals
+	for _, vat := range doc.VatBreakdown {
+		p.row(fontRegular, "VAT "+formatRate(vat.Rate)+"%", "", formatAmount(vat.Net), formatAmount(vat.Vat), formatAmount(vat.Total))
+	}
+	p.rule()
+	p.row(fontBold, "Total", "", formatAmount(doc.NetTotal), formatAmount(doc.VatTotal), formatAmount(doc.Total))
+	p.down(14)
+
+	if doc.Payment != nil {
+		status := "Paid in full."
+		if doc.Payment.Status == PaymentDue {
+			status = "Amount due: " + formatAmount(doc.Payment.AmountDue) + " " + doc.Currency
+		}
+		p.text(marginLeft, p.y, fontBold, 10, status)
+		p.down(18)
+	}
+
+	for _, text := range nonEmpty(doc.Notes, doc.Footer) {
+		for _, line := range strings.Split(text, "\n") {
+			p.text(marginLeft, p.y, fontRegular, 9, line)
+			p.down(12)
+		}
+		p.down(6)
+	}
+
+	return p.bytes()
+}
+
+// ======== PDF WRITER ========
+
+// pdf writes text pages with the standard fonts, which every reader has
+type pdf struct {
+	pages []*bytes.Buffer
+	y     float64
+}
+
+func newPDF() *pdf {
+	p := &pdf{}
+	p.newPage()
+	return p
+}
+
+func (p *pdf) newPage() {
+	p.pages = append(p.pages, &bytes.Buffer{})
+	p.y = marginTop
+}
+
+// down moves the cursor down, continuing on a new page at the bottom margin
+func (p *pdf) down(points float64) {
+	p.y -= points
+	if p.y < marginBottom {
+		p.newPage()
+	}
+}
+
+func (p *pdf) text(x, y float64, font string, size float64, s string) {
+	fmt.Fprintf(p.pages[len(p.pages)-1], "BT /%s %s Tf %s %s Td (%s) Tj ET\n",
+		font, num(size), num(x), num(y), escape(s))
+}
+
+// rightText writes text ending at x, in the monospaced font
+func (p *pdf) rightText(x float64, size float64, s string) {
+	p.text(x-float64(len([]rune(s)))*monoWidth*size, p.y, fontMono, size, s)
+}
+
+// row writes a table row: a description and four right aligned columns
+func (p *pdf) row(font, description, rate, net, vat, total string) {
+	if runes := []rune(description); len(runes) > maxDescription {
+		description = string(runes[:maxDescription-1]) + "…"
+	}
+	p.text(marginLeft, p.y, font, 10, description)
+	if font == fontBold {
+		p.text(330, p.y, font, 10, rate)
+		p.text(385, p.y, font, 10, net)
+		p.text(450, p.y, font, 10, vat)
+		p.text(495, p.y, font, 10, total)
+	} else {
+		p.rightText(370, 10, rate)
+		p.rightText(430, 10, net)
+		p.rightText(485, 10, vat)
+		p.rightText(marginRight, 10, total)
+	}
+	p.down(15)
+}
+
+// rule draws a horizontal line above the cursor
+func (p *pdf) rule() {
+	y := p.y + 11
+	fmt.Fprintf(p.pages[len(p.pages)-1], "0.5 w %s %s m %s %s l S\n", num(marginLeft), num(y), num(marginRight), num(y))
+	p.down(4)
+}
+
+// bytes assembles the document: catalog, page tree, fonts, then a page and
+// its content stream for every page
+func (p *pdf) bytes() []byte {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	const firstPage = 6
+	kids := make([]string, len(p.pages))
+	for i := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+
+	for i, content := range p.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R /F3 5 0 R >> >> /Contents %d 0 R >>",
+			num(pageWidth), num(pageHeight), firstPage+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes()
+}
+
+// escape encodes text as a PDF string in WinAnsiEncoding
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(byte(r))
+		case r >= 0x20 && r < 0x7F, r >= 0xA0 && r <= 0xFF:
+			b.WriteByte(byte(r))
+		case winAnsi[r] != 0:
+			b.WriteByte(winAnsi[r])
+		case fallback[r] != 0:
+			b.WriteByte(fallback[r])
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// num formats a coordinate or size
+func num(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// formatAmount formats an amount with two decimals
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+// prefixed prefixes a value, or returns it empty
+func prefixed(prefix, value string) string {
+	if value == "" {
+		return ""
+	}
+	return prefix + value
+}
+
+// nonEmpty returns the values that are not empty
+func nonEmpty(values ...string) []string {
+	var result []string
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,60 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testDocument(lines int) *Document {
+	doc := &Document{
+		Kind:        KindInvoice,
+		Number:      "INV-2026-000017",
+		IssueDate:   "2026-07-01",
+		Issuer:      Issuer{Name: "Apartmaji Bled d.o.o.", Address: "Cesta svobode 1, 4260 Bled", TaxID: "SI12345678"},
+		Buyer:       Buyer{CustomerID: 100, Name: "Žan Čuk (VIP)"},
+		Reservation: ReservationInfo{ID: 42, PropertyID: 10, CheckInDate: "2026-07-10", CheckOutDate: "2026-07-14", Nights: 4, NoOfGuests: 2},
+		Currency:    "EUR",
+		Payment:     &Payment{Status: PaymentPaid, Paid: 500},
+	}
+	for i := 0; i < lines; i++ {
+		line := Line{Code: "extra", Description: fmt.Sprintf("Extra %d", i), VatRate: 9.5, Total: 10}
+		line.split()
+		doc.Lines = append(doc.Lines, line)
+	}
+	doc.Totals()
+	return doc
+}
+
+func TestRenderPDF(t *testing.T) {
+	pdf := RenderPDF(testDocument(3))
+
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4")))
+	assert.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+	assert.Contains(t, string(pdf), "(INVOICE INV-2026-000017) Tj")
+	// WinAnsi encoded, with a fallback for characters it lacks, and escaped
+	assert.Contains(t, string(pdf), "(\x8ean Cuk \\(VIP\\)) Tj")
+	assert.Equal(t, pdf, RenderPDF(testDocument(3)))
+
+	// The cross-reference table points at every object
+	match := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+	require.NotNil(t, match)
+	xref, err := strconv.Atoi(string(match[1]))
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(pdf[xref:], []byte("xref\n")))
+	for _, offset := range regexp.MustCompile(`(\d{10}) 00000 n`).FindAllSubmatch(pdf, -1) {
+		at, err := strconv.Atoi(string(offset[1]))
+		require.NoError(t, err)
+		assert.Regexp(t, `^\d+ 0 obj\n`, string(pdf[at:at+12]))
+	}
+}
+
+func TestRenderPDF_Pages(t *testing.T) {
+	pdf := RenderPDF(testDocument(80))
+	assert.Contains(t, string(pdf), "/Count 2")
+}
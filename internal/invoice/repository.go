@@ -0,0 +1,290 @@
+package invoice
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// dbtx is satisfied by both the pool and a transaction
+type dbtx interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// Repository persists invoices, their numbering and the invoice settings of
+// organizations, and reads reservations for invoicing
+type Repository struct {
+	db dbtx
+}
+
+// NewRepository returns a Repository
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+// InTx runs fn with a repository bound to a new transaction, which is
+// committed if fn returns nil and rolled back otherwise
+func (r *Repository) InTx(fn func(tx *Repository) error) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(&Repository{db: tx}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ======== SETTINGS ========
+
+// GetSettings returns the invoice settings of an organization, or nil if
+// they are not configured
+func (r *Repository) GetSettings(organizationID int64) (*Settings, error) {
+	rows, err := r.db.Query(context.Background(), `SELECT `+settingsColumns+`
+        FROM invoice_settings
+        WHERE organization_id = $1
+    `, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[Settings])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// SaveSettings creates or replaces the invoice settings of an organization
+func (r *Repository) SaveSettings(settings *Settings) (*Settings, error) {
+	rows, err := r.db.Query(context.Background(), `
+        INSERT INTO invoice_settings (
+            organization_id, issuer_name, issuer_address, issuer_tax_id,
+            issuer_email, vat_rate, currency, footer
+        )
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        ON CONFLICT (organization_id)
+        DO UPDATE SET issuer_name = EXCLUDED.issuer_name,
+                      issuer_address = EXCLUDED.issuer_address,
+                      issuer_tax_id = EXCLUDED.issuer_tax_id,
+                      issuer_email = EXCLUDED.issuer_email,
+                      vat_rate = EXCLUDED.vat_rate,
+                      currency = EXCLUDED.currency,
+                      footer = EXCLUDED.footer,
+                      updated_at = NOW()
+        RETURNING `+settingsColumns,
+		settings.OrganizationID,
+		settings.IssuerName,
+		settings.IssuerAddress,
+		settings.IssuerTaxID,
+		settings.IssuerEmail,
+		settings.VatRate,
+		settings.Currency,
+		settings.Footer,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	saved, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[Settings])
+	if err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// ======== INVOICES ========
+
+// GetReservation returns a reservation of the organization that is not
+// deleted, locked for the rest of the transaction so that it is invoiced
+// once at a time
+func (r *Repository) GetReservation(id, organizationID int64) (*Reservation, error) {
+	rows, err := r.db.Query(context.Background(), `SELECT `+reservationColumns+`
+        FROM reservation
+        WHERE id = $1
+          AND organization_id = $2
+          AND deleted_at IS NULL
+        FOR UPDATE
+    `, id, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[Reservation])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// GetInvoices returns the invoices and credit notes of a reservation in the
+// order they were issued
+func (r *Repository) GetInvoices(reservationID, organizationID int64) ([]Invoice, error) {
+	rows, err := r.db.Query(context.Background(), `SELECT `+invoiceColumns+`
+        FROM invoice
+        WHERE reservation_id = $1
+          AND organization_id = $2
+        ORDER BY issued_at, id
+    `, reservationID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[Invoice])
+}
+
+// GetInvoice returns an invoice of a reservation, or nil if it doesn't
+// exist. With forUpdate, the invoice is locked for the rest of the
+// transaction so that it is credited once at a time.
+func (r *Repository) GetInvoice(id, reservationID, organizationID int64, forUpdate bool) (*Invoice, error) {
+	query := `SELECT ` + invoiceColumns + `
+        FROM invoice
+        WHERE id = $1
+          AND reservation_id = $2
+          AND organization_id = $3
+    `
+	if forUpdate {
+		query += ` FOR UPDATE`
+	}
+
+	rows, err := r.db.Query(context.Background(), query, id, reservationID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[Invoice])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+// GetPDF returns the number and PDF of an invoice of a reservation. The PDF
+// is nil if the invoice doesn't exist.
+func (r *Repository) GetPDF(id, reservationID, organizationID int64) (string, []byte, error) {
+	var number string
+	var pdf []byte
+	err := r.db.QueryRow(context.Background(), `
+        SELECT number, pdf
+        FROM invoice
+        WHERE id = $1
+          AND reservation_id = $2
+          AND organization_id = $3
+    `, id, reservationID, organizationID).Scan(&number, &pdf)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil, nil
+		}
+		return "", nil, err
+	}
+	return number, pdf, nil
+}
+
+// HasOpenInvoice reports whether a reservation has an invoice that is not
+// fully credited
+func (r *Repository) HasOpenInvoice(reservationID, organizationID int64) (bool, error) {
+	var open bool
+	err := r.db.QueryRow(context.Background(), `
+        SELECT EXISTS (
+            SELECT 1
+            FROM invoice i
+            WHERE i.reservation_id = $1
+              AND i.organization_id = $2
+              AND i.kind = 'INVOICE'
+              AND i.total + COALESCE((
+                  SELECT SUM(c.total)
+                  FROM invoice c
+                  WHERE c.credited_invoice_id = i.id
+              ), 0) <> 0
+        )
+    `, reservationID, organizationID).Scan(&open)
+	return open, err
+}
+
+// CreditedTotal returns the sum of the credit notes of an invoice, which is
+// negative or zero
+func (r *Repository) CreditedTotal(invoiceID int64) (float64, error) {
+	var total float64
+	err := r.db.QueryRow(context.Background(), `
+        SELECT COALESCE(SUM(total), 0)::float8
+        FROM invoice
+        WHERE credited_invoice_id = $1
+    `, invoiceID).Scan(&total)
+	return total, err
+}
+
+// NextNumber takes the next number of a series in a fiscal year. The
+// sequence row stays locked until the transaction ends, and the number is
+// only used if it commits.
+func (r *Repository) NextNumber(organizationID int64, fiscalYear int, series string) (int64, error) {
+	var number int64
+	err := r.db.QueryRow(context.Background(), `
+        INSERT INTO invoice_sequence (organization_id, fiscal_year, series, last_number)
+        VALUES ($1, $2, $3, 1)
+        ON CONFLICT (organization_id, fiscal_year, series)
+        DO UPDATE SET last_number = invoice_sequence.last_number + 1
+        RETURNING last_number
+    `, organizationID, fiscalYear, series).Scan(&number)
+	return number, err
+}
+
+// CreateInvoice stores an issued invoice and its PDF
+func (r *Repository) CreateInvoice(invoice *Invoice, pdf []byte) (*Invoice, error) {
+	rows, err := r.db.Query(context.Background(), `
+        INSERT INTO invoice (
+            organization_id, reservation_id, kind, series, fiscal_year,
+            sequence_number, number, credited_invoice_id, currency, net_total,
+            vat_total, total, document, pdf, issued_by, issued_at
+        )
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+        RETURNING `+invoiceColumns,
+		invoice.OrganizationID,
+		invoice.ReservationID,
+		invoice.Kind,
+		invoice.Series,
+		invoice.FiscalYear,
+		invoice.SequenceNumber,
+		invoice.Number,
+		invoice.CreditedInvoiceID,
+		invoice.Currency,
+		invoice.NetTotal,
+		invoice.VatTotal,
+		invoice.Total,
+		invoice.Document,
+		pdf,
+		invoice.IssuedBy,
+		invoice.IssuedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[Invoice])
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
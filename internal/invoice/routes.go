@@ -0,0 +1,55 @@
+package invoice
+
+import (
+	"hostflow/booking-service/internal/middlewares"
+	"hostflow/booking-service/pkg/lib"
+)
+
+// Routes struct
+type Routes struct {
+	logger              lib.Logger
+	router              *lib.Router
+	controller          *Controller
+	authMiddleware      middlewares.AuthMiddleware
+	rateLimitMiddleware middlewares.RateLimitMiddleware
+}
+
+// SetRoutes returns a Routes struct
+func SetRoutes(
+	logger lib.Logger,
+	router *lib.Router,
+	controller *Controller,
+	authMiddleware middlewares.AuthMiddleware,
+	rateLimitMiddleware middlewares.RateLimitMiddleware,
+) Routes {
+	return Routes{
+		logger:              logger,
+		router:              router,
+		controller:          controller,
+		authMiddleware:      authMiddleware,
+		rateLimitMiddleware: rateLimitMiddleware,
+	}
+}
+
+// Setup registers the invoice routes: the invoices of a reservation and the
+// invoice settings of the organization
+func (route Routes) Setup() {
+	route.logger.Info("Setting up [INVOICE] routes.")
+
+	invoices := route.router.Group("/reservations")
+	invoices.Use(route.authMiddleware.Handler())
+	{
+		invoices.GET("/:id/invoices", route.rateLimitMiddleware.Limit(middlewares.BudgetDefault), middlewares.RequirePermission(middlewares.InvoicesRead), route.controller.GetInvoicesHandler)
+		invoices.POST("/:id/invoices", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), middlewares.RequirePermission(middlewares.InvoicesIssue), route.controller.IssueInvoiceHandler)
+		invoices.GET("/:id/invoices/:invoiceId", route.rateLimitMiddleware.Limit(middlewares.BudgetDefault), middlewares.RequirePermission(middlewares.InvoicesRead), route.controller.GetInvoiceHandler)
+		invoices.GET("/:id/invoices/:invoiceId/pdf", route.rateLimitMiddleware.Limit(middlewares.BudgetDefault), middlewares.RequirePermission(middlewares.InvoicesRead), route.controller.GetPDFHandler)
+		invoices.POST("/:id/invoices/:invoiceId/credit-notes", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), middlewares.RequirePermission(middlewares.InvoicesIssue), route.controller.CreditNoteHandler)
+	}
+
+	settings := route.router.Group("/invoices")
+	settings.Use(route.authMiddleware.Handler(), middlewares.RequirePermission(middlewares.InvoicesManage))
+	{
+		settings.GET("/settings", route.rateLimitMiddleware.Limit(middlewares.BudgetDefault), route.controller.GetSettingsHandler)
+		settings.PUT("/settings", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), route.controller.SaveSettingsHandler)
+	}
+}
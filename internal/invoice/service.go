@@ -0,0 +1,341 @@
+package invoice
+
+import (
+	"fmt"
+	"hostflow/booking-service/internal/audit"
+	"hostflow/booking-service/internal/touristtax"
+	"hostflow/booking-service/pkg/lib"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultCurrency is the currency of organizations that don't set one
+const defaultCurrency = "EUR"
+
+// Service issues invoices and credit notes for reservations
+type Service struct {
+	repo   *Repository
+	audit  *audit.Repository
+	logger lib.Logger
+	now    func() time.Time
+}
+
+// NewService returns a Service
+func NewService(repo *Repository, auditLog *audit.Repository, logger lib.Logger) *Service {
+	return &Service{
+		repo:   repo,
+		audit:  auditLog,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// ======== SETTINGS ========
+
+// GetSettings returns the invoice settings of an organization
+func (s *Service) GetSettings(organizationID int64) (*Settings, error) {
+	settings, err := s.repo.GetSettings(organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		return nil, ErrNotConfigured
+	}
+	return settings, nil
+}
+
+// SaveSettings creates or replaces the invoice settings of an organization.
+// Invoices already issued keep the details they were issued with.
+func (s *Service) SaveSettings(organizationID int64, req SettingsRequest) (*Settings, error) {
+	settings := &Settings{
+		OrganizationID: organizationID,
+		IssuerName:     strings.TrimSpace(req.IssuerName),
+		IssuerAddress:  strings.TrimSpace(req.IssuerAddress),
+		IssuerTaxID:    strings.TrimSpace(req.IssuerTaxID),
+		IssuerEmail:    req.IssuerEmail,
+		VatRate:        req.VatRate,
+		Currency:       strings.ToUpper(req.Currency),
+		Footer:         req.Footer,
+	}
+	if settings.Currency == "" {
+		settings.Currency = defaultCurrency
+	}
+	return s.repo.SaveSettings(settings)
+}
+
+// ======== INVOICES ========
+
+// GetInvoices returns the invoices and credit notes of a reservation
+func (s *Service) GetInvoices(reservationID, organizationID int64) ([]Invoice, error) {
+	return s.repo.GetInvoices(reservationID, organizationID)
+}
+
+// GetInvoice returns an invoice or credit note of a reservation
+func (s *Service) GetInvoice(id, reservationID, organizationID int64) (*Invoice, error) {
+	invoice, err := s.repo.GetInvoice(id, reservationID, organizationID, false)
+	if err != nil {
+		return nil, err
+	}
+	if invoice == nil {
+		return nil, ErrInvoiceNotFound
+	}
+	return invoice, nil
+}
+
+// GetPDF returns the number and PDF of an invoice or credit note
+func (s *Service) GetPDF(id, reservationID, organizationID int64) (string, []byte, error) {
+	number, pdf, err := s.repo.GetPDF(id, reservationID, organizationID)
+	if err != nil {
+		return "", nil, err
+	}
+	if pdf == nil {
+		return "", nil, ErrInvoiceNotFound
+	}
+	return number, pdf, nil
+}
+
+// Issue issues the invoice of a reservation from its price elements. A
+// reservation has at most one invoice that is not fully credited.
+func (s *Service) Issue(reservationID, organizationID int64, req IssueRequest, actor audit.Actor) (*Invoice, error) {
+	var issued *Invoice
+	err := s.repo.InTx(func(tx *Repository) error {
+		settings, err := tx.GetSettings(organizationID)
+		if err != nil {
+			return err
+		}
+		if settings == nil {
+			return ErrNotConfigured
+		}
+
+		reservation, err := tx.GetReservation(reservationID, organizationID)
+		if err != nil {
+			return err
+		}
+		if reservation == nil {
+			return ErrReservationNotFound
+		}
+		if reservation.Status == statusCancelled || reservation.Status == statusRejected {
+			return ErrNotInvoiceable
+		}
+		if reservation.Kind == kindHold {
+			return ErrHoldNotInvoiceable
+		}
+
+		open, err := tx.HasOpenInvoice(reservationID, organizationID)
+		if err != nil {
+			return err
+		}
+		if open {
+			return ErrAlreadyInvoiced
+		}
+
+		lines, err := BuildLines(reservation.PriceElements, reservation.TotalPrice, settings.VatRate)
+		if err != nil {
+			return err
+		}
+
+		now := s.now().UTC()
+		doc := &Document{
+			Kind:        KindInvoice,
+			IssueDate:   now.Format(DateLayout),
+			Issuer:      issuer(settings),
+			Buyer:       Buyer{CustomerID: reservation.CustomerID},
+			Reservation: reservationInfo(reservation),
+			Currency:    settings.Currency,
+			Lines:       lines,
+			Notes:       strings.TrimSpace(req.Notes),
+		}
+		if req.Buyer != nil {
+			doc.Buyer = *req.Buyer
+			doc.Buyer.CustomerID = reservation.CustomerID
+		}
+		if settings.Footer != nil {
+			doc.Footer = *settings.Footer
+		}
+		doc.Totals()
+		doc.Payment = payment(reservation, doc.Total, now, req.DueDate)
+
+		issued, err = s.store(tx, doc, reservation.ID, organizationID, nil, now, actor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return issued, nil
+}
+
+// CreditNote issues a credit note for an invoice, for a cancellation or a
+// refund. Without an amount, everything not yet credited is credited.
+func (s *Service) CreditNote(invoiceID, reservationID, organizationID int64, req CreditNoteRequest, actor audit.Actor) (*Invoice, error) {
+	var issued *Invoice
+	err := s.repo.InTx(func(tx *Repository) error {
+		settings, err := tx.GetSettings(organizationID)
+		if err != nil {
+			return err
+		}
+		if settings == nil {
+			return ErrNotConfigured
+		}
+
+		invoice, err := tx.GetInvoice(invoiceID, reservationID, organizationID, true)
+		if err != nil {
+			return err
+		}
+		if invoice == nil {
+			return ErrInvoiceNotFound
+		}
+		if invoice.Kind != KindInvoice {
+			return ErrNotCreditable
+		}
+
+		credited, err := tx.CreditedTotal(invoice.ID)
+		if err != nil {
+			return err
+		}
+		remaining := round(invoice.Total + credited)
+		if remaining <= 0 {
+			return ErrFullyCredited
+		}
+		amount := remaining
+		if req.Amount != nil {
+			amount = round(*req.Amount)
+			if amount <= 0 || amount > remaining {
+				return ErrInvalidAmount
+			}
+		}
+
+		now := s.now().UTC()
+		original := invoice.Document
+		doc := &Document{
+			Kind:        KindCreditNote,
+			IssueDate:   now.Format(DateLayout),
+			Issuer:      issuer(settings),
+			Buyer:       original.Buyer,
+			Reservation: original.Reservation,
+			Currency:    original.Currency,
+			Lines:       CreditLines(&original, amount, req.Reason),
+			CreditedInvoice: &CreditedRef{
+				ID:        invoice.ID,
+				Number:    invoice.Number,
+				IssueDate: original.IssueDate,
+			},
+			Reason: req.Reason,
+			Notes:  strings.TrimSpace(req.Notes),
+		}
+		if settings.Footer != nil {
+			doc.Footer = *settings.Footer
+		}
+		doc.Totals()
+
+		issued, err = s.store(tx, doc, reservationID, organizationID, &invoice.ID, now, actor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return issued, nil
+}
+
+// ======== PRIVATE METHODS ========
+
+// store numbers a document, renders it and stores it with an audit entry on
+// the reservation
+func (s *Service) store(tx *Repository, doc *Document, reservationID, organizationID int64, creditedInvoiceID *int64, now time.Time, actor audit.Actor) (*Invoice, error) {
+	series, action := seriesInvoice, actionInvoiceIssued
+	if doc.Kind == KindCreditNote {
+		series, action = seriesCreditNote, actionCreditNoteIssued
+	}
+
+	fiscalYear := now.Year()
+	sequence, err := tx.NextNumber(organizationID, fiscalYear, series)
+	if err != nil {
+		return nil, err
+	}
+	doc.Number = formatNumber(series, fiscalYear, sequence)
+
+	invoice, err := tx.CreateInvoice(&Invoice{
+		OrganizationID:    organizationID,
+		ReservationID:     reservationID,
+		Kind:              doc.Kind,
+		Series:            series,
+		FiscalYear:        fiscalYear,
+		SequenceNumber:    sequence,
+		Number:            doc.Number,
+		CreditedInvoiceID: creditedInvoiceID,
+		Currency:          doc.Currency,
+		NetTotal:          doc.NetTotal,
+		VatTotal:          doc.VatTotal,
+		Total:             doc.Total,
+		Document:          *doc,
+		IssuedBy:          actor.Type + ":" + actor.ID,
+		IssuedAt:          now,
+	}, RenderPDF(doc))
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := audit.NewEntry(actor, organizationID, action, audit.EntityReservation,
+		strconv.FormatInt(reservationID, 10), nil, map[string]interface{}{
+			"invoice": map[string]interface{}{
+				"id":     invoice.ID,
+				"kind":   invoice.Kind,
+				"number": invoice.Number,
+				"total":  invoice.Total,
+			},
+		})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.audit.Record(tx.db, entry); err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// issuer converts the settings of an organization to the issuer of an
+// invoice
+func issuer(settings *Settings) Issuer {
+	i := Issuer{
+		Name:    settings.IssuerName,
+		Address: settings.IssuerAddress,
+		TaxID:   settings.IssuerTaxID,
+	}
+	if settings.IssuerEmail != nil {
+		i.Email = *settings.IssuerEmail
+	}
+	return i
+}
+
+// reservationInfo returns the stay of a reservation as printed on an invoice
+func reservationInfo(reservation *Reservation) ReservationInfo {
+	return ReservationInfo{
+		ID:           reservation.ID,
+		PropertyID:   reservation.PropertyID,
+		CheckInDate:  reservation.CheckInDate.Format(DateLayout),
+		CheckOutDate: reservation.CheckOutDate.Format(DateLayout),
+		Nights:       touristtax.Nights(reservation.CheckInDate, reservation.CheckOutDate),
+		NoOfGuests:   reservation.NoOfGuests,
+	}
+}
+
+// payment returns the payment state of a reservation: confirmed and
+// completed reservations are paid, others are due on the given date or on
+// the day of issue
+func payment(reservation *Reservation, total float64, now time.Time, dueDate string) *Payment {
+	if reservation.Status == statusConfirmed || reservation.Status == statusCompleted {
+		return &Payment{Status: PaymentPaid, Paid: total}
+	}
+	if dueDate == "" {
+		dueDate = now.Format(DateLayout)
+	}
+	return &Payment{Status: PaymentDue, AmountDue: total, DueDate: dueDate}
+}
+
+// formatNumber formats an invoice number, e.g. INV-2026-000017
+func formatNumber(series string, fiscalYear int, sequence int64) string {
+	return fmt.Sprintf("%s-%d-%06d", series, fiscalYear, sequence)
+}
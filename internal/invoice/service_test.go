@@ -0,0 +1,147 @@
+package invoice
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"hostflow/booking-service/internal/audit"
+	"hostflow/booking-service/internal/dbtest"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Info(args ...interface{})  {}
+func (nopLogger) Fatal(args ...interface{}) {}
+func (nopLogger) Error(args ...interface{}) {}
+
+// newDBService returns a Service backed by the test database, with the
+// invoice settings of organization 100 configured
+func newDBService(t *testing.T, db *pgxpool.Pool) *Service {
+	t.Helper()
+
+	_, err := db.Exec(context.Background(), `
+        INSERT INTO invoice_settings (organization_id, issuer_name, issuer_address, issuer_tax_id, vat_rate)
+        VALUES (100, 'Apartmaji Novak d.o.o.', 'Slovenska cesta 1, Ljubljana', 'SI12345678', 9.5)
+    `)
+	require.NoError(t, err)
+
+	return NewService(NewRepository(db), audit.NewRepository(db), nopLogger{})
+}
+
+// seedReservation stores a reservation of organization 100 directly in the
+// test database
+func seedReservation(t *testing.T, db *pgxpool.Pool, id int64, status, kind string) {
+	t.Helper()
+
+	_, err := db.Exec(context.Background(), `
+        INSERT INTO reservation (id, organization_id, property_id, customer_id, check_in_date, check_out_date, status, kind, total_price, price_elements)
+        VALUES ($1, 100, 10, 7, '2030-06-01T14:00:00Z', '2030-06-04T10:00:00Z', $2, $3, 300, '{}')
+    `, id, status, kind)
+	require.NoError(t, err)
+}
+
+func TestIssue_RejectsHolds(t *testing.T) {
+	db := dbtest.Open(t)
+	service := newDBService(t, db)
+	actor := audit.SystemActor("test")
+
+	seedReservation(t, db, 1, "CREATED", kindHold)
+	_, err := service.Issue(1, 100, IssueRequest{}, actor)
+	assert.ErrorIs(t, err, ErrHoldNotInvoiceable)
+
+	// The hold didn't use up a number
+	seedReservation(t, db, 2, "CONFIRMED", "BOOKING")
+	issued, err := service.Issue(2, 100, IssueRequest{}, actor)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), issued.SequenceNumber)
+}
+
+func TestIssue_ConcurrentIssuesGetConsecutiveNumbers(t *testing.T) {
+	db := dbtest.Open(t)
+	service := newDBService(t, db)
+	service.now = func() time.Time { return time.Date(2030, 3, 1, 12, 0, 0, 0, time.UTC) }
+
+	const count = 10
+	for id := int64(1); id <= count; id++ {
+		seedReservation(t, db, id, "CONFIRMED", "BOOKING")
+	}
+
+	var wg sync.WaitGroup
+	sequences := make(chan int64, count)
+	for id := int64(1); id <= count; id++ {
+		wg.Add(1)
+		go func(id int64) {
+			defer wg.Done()
+			issued, err := service.Issue(id, 100, IssueRequest{}, audit.SystemActor("test"))
+			if assert.NoError(t, err) {
+				sequences <- issued.SequenceNumber
+			}
+		}(id)
+	}
+	wg.Wait()
+	close(sequences)
+
+	var got []int64
+	for sequence := range sequences {
+		got = append(got, sequence)
+	}
+	sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+	assert.Equal(t, []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, got)
+}
+
+func TestIssue_RestartsNumbersInNewFiscalYear(t *testing.T) {
+	db := dbtest.Open(t)
+	service := newDBService(t, db)
+	actor := audit.SystemActor("test")
+	for id := int64(1); id <= 3; id++ {
+		seedReservation(t, db, id, "CONFIRMED", "BOOKING")
+	}
+
+	service.now = func() time.Time { return time.Date(2030, 12, 31, 23, 30, 0, 0, time.UTC) }
+	first, err := service.Issue(1, 100, IssueRequest{}, actor)
+	require.NoError(t, err)
+	assert.Equal(t, "INV-2030-000001", first.Number)
+	second, err := service.Issue(2, 100, IssueRequest{}, actor)
+	require.NoError(t, err)
+	assert.Equal(t, "INV-2030-000002", second.Number)
+
+	service.now = func() time.Time { return time.Date(2031, 1, 1, 0, 30, 0, 0, time.UTC) }
+	third, err := service.Issue(3, 100, IssueRequest{}, actor)
+	require.NoError(t, err)
+	assert.Equal(t, 2031, third.FiscalYear)
+	assert.Equal(t, "INV-2031-000001", third.Number)
+
+	// Credit notes are numbered in their own series of the year they are
+	// issued in, whatever the year of the credited invoice
+	credit, err := service.CreditNote(first.ID, 1, 100, CreditNoteRequest{Reason: "cancellation"}, actor)
+	require.NoError(t, err)
+	assert.Equal(t, "CN-2031-000001", credit.Number)
+}
+
+func TestInvoice_IsImmutable(t *testing.T) {
+	db := dbtest.Open(t)
+	service := newDBService(t, db)
+
+	seedReservation(t, db, 1, "CONFIRMED", "BOOKING")
+	issued, err := service.Issue(1, 100, IssueRequest{}, audit.SystemActor("test"))
+	require.NoError(t, err)
+
+	_, err = db.Exec(context.Background(), `UPDATE invoice SET total = 0 WHERE id = $1`, issued.ID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "immutable")
+
+	_, err = db.Exec(context.Background(), `DELETE FROM invoice WHERE id = $1`, issued.ID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "immutable")
+
+	var total float64
+	require.NoError(t, db.QueryRow(context.Background(), `SELECT total FROM invoice WHERE id = $1`, issued.ID).Scan(&total))
+	assert.Equal(t, issued.Total, total)
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"hostflow/booking-service/internal/booking"
+	"hostflow/booking-service/pkg/interfaces"
 	"os"
 	"time"
 
@@ -80,5 +81,13 @@ func RegisterKafkaHooks(lifecycle fx.Lifecycle, reader *kafka.Reader, reservatio
 
 var Module = fx.Module("kafka",
 	fx.Provide(NewKafkaReader),
+	fx.Provide(NewEventPublisher),
+	fx.Provide(
+		fx.Annotate(
+			func(publisher *EventPublisher) *EventPublisher { return publisher },
+			fx.As(new(interfaces.EventPublisher)),
+		),
+	),
 	fx.Invoke(RegisterKafkaHooks),
+	fx.Invoke(RegisterPublisherHooks),
 )
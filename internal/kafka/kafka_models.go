@@ -17,3 +17,13 @@ type MessageEnvelope struct {
 	Payload       PaymentAction `json:"payload"`
 	SchemaVersion int           `json:"schemaVersion"`
 }
+
+// EventEnvelope wraps the events the service publishes, like MessageEnvelope
+// wraps those it consumes
+type EventEnvelope struct {
+	MessageId     string `json:"messageId"`
+	MessageType   string `json:"messageType"`
+	OccurredAt    string `json:"occurredAt"`
+	Payload       any    `json:"payload"`
+	SchemaVersion int    `json:"schemaVersion"`
+}
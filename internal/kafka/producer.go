@@ -0,0 +1,94 @@
+package kafka
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"time"
+
+	"hostflow/booking-service/pkg/lib"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"go.uber.org/fx"
+)
+
+// eventSchemaVersion is the version of the envelope of published events
+const eventSchemaVersion = 1
+
+// EventPublisher publishes domain events to KAFKA_EVENTS_TOPIC, in the
+// envelope of the messages the service consumes. It implements
+// interfaces.EventPublisher; when the topic is not set, events are dropped.
+type EventPublisher struct {
+	writer *kafka.Writer
+}
+
+// NewEventPublisher returns an EventPublisher writing to KAFKA_EVENTS_TOPIC
+func NewEventPublisher(logger lib.Logger) *EventPublisher {
+	topic := os.Getenv("KAFKA_EVENTS_TOPIC")
+	if topic == "" {
+		logger.Info("KAFKA_EVENTS_TOPIC is not set, events are not published")
+		return &EventPublisher{}
+	}
+
+	return &EventPublisher{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(os.Getenv("KAFKA_BROKERS")),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			WriteTimeout: 10 * time.Second,
+			Transport: &kafka.Transport{
+				SASL: plain.Mechanism{
+					Username: os.Getenv("KAFKA_USER"),
+					Password: os.Getenv("KAFKA_PASSWORD"),
+				},
+				TLS: &tls.Config{},
+			},
+		},
+	}
+}
+
+// Publish writes an event of the type with its payload, keyed so that the
+// events of a key land on the same partition
+func (p *EventPublisher) Publish(eventType, key string, payload any) error {
+	if p.writer == nil {
+		return nil
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(EventEnvelope{
+		MessageId:     hex.EncodeToString(id),
+		MessageType:   eventType,
+		OccurredAt:    time.Now().UTC().Format(time.RFC3339),
+		Payload:       payload,
+		SchemaVersion: eventSchemaVersion,
+	})
+	if err != nil {
+		return err
+	}
+
+	return p.writer.WriteMessages(context.Background(), kafka.Message{
+		Key:   []byte(key),
+		Value: value,
+	})
+}
+
+// RegisterPublisherHooks flushes and closes the publisher with the application
+func RegisterPublisherHooks(lifecycle fx.Lifecycle, publisher *EventPublisher) {
+	lifecycle.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			if publisher.writer == nil {
+				return nil
+			}
+			return publisher.writer.Close()
+		},
+	})
+}
@@ -1,62 +1,354 @@
 package middlewares
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/MicahParks/keyfunc/v3"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"hostflow/booking-service/pkg/interfaces"
+	"hostflow/booking-service/pkg/lib"
 )
 
+// ======== TYPES ========
+
+// defaultIssuer is the Supabase project used when AUTH_JWT_ISSUERS is not set
+const defaultIssuer = "https://frauwrkbphmjngymcdyk.supabase.co/auth/v1"
+
+// Defaults for the remaining settings
+const (
+	defaultAudience  = "authenticated"
+	defaultAlgorithm = "ES256"
+	defaultLeeway    = 30 * time.Second
+)
+
+// Reasons returned in the body of a 401 response
+const (
+	ReasonMissingToken  = "missing_token"
+	ReasonInvalidToken  = "invalid_token"
+	ReasonTokenExpired  = "token_expired"
+	ReasonInvalidClaims = "invalid_claims"
+)
+
+// UnauthorizedResponse is the body of a 401 response
+type UnauthorizedResponse struct {
+	Error  string `json:"error"`
+	Reason string `json:"reason"`
+}
+
+// IssuerConfig is a trusted token issuer and the keys its tokens are signed with
+type IssuerConfig struct {
+	Issuer    string
+	JWKSURL   string
+	Audiences []string
+}
+
+// AuthConfig configures how JWTs are verified
+type AuthConfig struct {
+	Issuers    []IssuerConfig
+	Algorithms []string
+	Leeway     time.Duration
+}
+
+// issuerVerifier verifies the tokens of a single issuer
+type issuerVerifier struct {
+	config  IssuerConfig
+	keyfunc keyfunc.Keyfunc
+}
+
+// AuthMiddleware verifies the bearer token or API key of a request and
+// stores the resulting Principal in the gin context
 type AuthMiddleware struct {
-	jwksURL string
+	logger     lib.Logger
+	apiKeys    interfaces.APIKeyAuthenticator
+	verifiers  map[string]*issuerVerifier
+	algorithms []string
+	leeway     time.Duration
 }
 
-func NewAuthMiddleware() AuthMiddleware {
-	return AuthMiddleware{
-		jwksURL: "https://frauwrkbphmjngymcdyk.supabase.co/auth/v1/.well-known/jwks.json",
+// ======== PUBLIC METHODS ========
+
+// GetAuthConfig reads the JWT settings from the environment
+func GetAuthConfig() (AuthConfig, error) {
+	issuers := splitList(os.Getenv("AUTH_JWT_ISSUERS"))
+	if len(issuers) == 0 {
+		issuers = []string{defaultIssuer}
+	}
+
+	jwksURLs := splitList(os.Getenv("AUTH_JWT_JWKS_URLS"))
+	if len(jwksURLs) > 0 && len(jwksURLs) != len(issuers) {
+		return AuthConfig{}, fmt.Errorf("AUTH_JWT_JWKS_URLS has %d entries, expected one per issuer (%d)", len(jwksURLs), len(issuers))
 	}
+
+	audiences := splitList(os.Getenv("AUTH_JWT_AUDIENCES"))
+	if len(audiences) == 0 {
+		audiences = []string{defaultAudience}
+	}
+
+	algorithms := splitList(os.Getenv("AUTH_JWT_ALGORITHMS"))
+	if len(algorithms) == 0 {
+		algorithms = []string{defaultAlgorithm}
+	}
+
+	leeway := defaultLeeway
+	if value := os.Getenv("AUTH_JWT_LEEWAY"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			return AuthConfig{}, fmt.Errorf("invalid AUTH_JWT_LEEWAY %q", value)
+		}
+		leeway = parsed
+	}
+
+	config := AuthConfig{Algorithms: algorithms, Leeway: leeway}
+	for i, issuer := range issuers {
+		jwksURL := strings.TrimRight(issuer, "/") + "/.well-known/jwks.json"
+		if len(jwksURLs) > 0 {
+			jwksURL = jwksURLs[i]
+		}
+		config.Issuers = append(config.Issuers, IssuerConfig{
+			Issuer:    issuer,
+			JWKSURL:   jwksURL,
+			Audiences: audiences,
+		})
+	}
+	return config, nil
 }
 
-func (m AuthMiddleware) Handler() gin.HandlerFunc {
-	// Initialize the key function (it handles caching the public key for you)
-	k, err := keyfunc.NewDefault([]string{m.jwksURL})
+// NewAuthMiddleware creates the auth middleware from the environment
+func NewAuthMiddleware(logger lib.Logger, apiKeys interfaces.APIKeyAuthenticator) (AuthMiddleware, error) {
+	config, err := GetAuthConfig()
 	if err != nil {
-		panic(fmt.Sprintf("Failed to fetch JWKS: %v", err))
+		return AuthMiddleware{}, err
 	}
+	return NewAuthMiddlewareWithConfig(config, logger, apiKeys)
+}
 
-	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+// NewAuthMiddlewareWithConfig creates the auth middleware for the given
+// issuers. The JWKS of each issuer is fetched in the background and
+// refreshed periodically, so an unreachable issuer does not prevent startup.
+// API keys are only accepted when apiKeys is not nil.
+func NewAuthMiddlewareWithConfig(config AuthConfig, logger lib.Logger, apiKeys interfaces.APIKeyAuthenticator) (AuthMiddleware, error) {
+	if len(config.Issuers) == 0 {
+		return AuthMiddleware{}, errors.New("at least one JWT issuer must be configured")
+	}
+	if len(config.Algorithms) == 0 {
+		config.Algorithms = []string{defaultAlgorithm}
+	}
 
-		token, err := jwt.Parse(tokenString, k.Keyfunc,
-			jwt.WithValidMethods([]string{"ES256"}),
-			jwt.WithLeeway(5*time.Minute),
-		)
+	verifiers := make(map[string]*issuerVerifier, len(config.Issuers))
+	for _, issuer := range config.Issuers {
+		if issuer.Issuer == "" || issuer.JWKSURL == "" {
+			return AuthMiddleware{}, errors.New("every JWT issuer needs an issuer and a JWKS URL")
+		}
 
+		url := issuer.JWKSURL
+		k, err := keyfunc.NewDefaultOverrideCtx(context.Background(), []string{url}, keyfunc.Override{
+			RateLimitWaitMax: 5 * time.Second,
+			RefreshErrorHandlerFunc: func(u string) func(ctx context.Context, err error) {
+				return func(ctx context.Context, err error) {
+					logger.Error("Failed to refresh JWKS from ", u, ": ", err)
+				}
+			},
+		})
 		if err != nil {
-			fmt.Println("--- JWT DEBUG START ---")
-			fmt.Printf("Error: %v\n", err)
-			fmt.Printf("Token String: %s\n", tokenString)
-			fmt.Println("--- JWT DEBUG END ---")
-
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error":   "Invalid Token Signature",
-				"details": err.Error(), // Send the real error back to Postman for a moment
-			})
+			return AuthMiddleware{}, fmt.Errorf("creating JWKS client for %s: %w", url, err)
+		}
+
+		verifiers[issuer.Issuer] = &issuerVerifier{config: issuer, keyfunc: k}
+	}
+
+	return AuthMiddleware{
+		logger:     logger,
+		apiKeys:    apiKeys,
+		verifiers:  verifiers,
+		algorithms: config.Algorithms,
+		leeway:     config.Leeway,
+	}, nil
+}
+
+// Handler returns the gin handler that authenticates the request
+func (m AuthMiddleware) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authorization := c.GetHeader("Authorization")
+		if key, isAPIKey := strings.CutPrefix(authorization, "ApiKey "); isAPIKey && m.apiKeys != nil {
+			m.authenticateAPIKey(c, strings.TrimSpace(key))
 			return
 		}
 
-		claims := token.Claims.(jwt.MapClaims)
-		userMeta := claims["user_metadata"].(map[string]interface{})
+		tokenString, found := strings.CutPrefix(authorization, "Bearer ")
+		tokenString = strings.TrimSpace(tokenString)
+		if !found || tokenString == "" {
+			abortUnauthorized(c, ReasonMissingToken)
+			return
+		}
 
-		// Pass the data to your controller
-		c.Set("organization_id", int64(userMeta["organization_id"].(float64)))
-		c.Set("role", userMeta["role"].(string))
+		principal, err := m.Authenticate(tokenString)
+		if err != nil {
+			reason := ReasonInvalidToken
+			switch {
+			case errors.Is(err, jwt.ErrTokenExpired):
+				reason = ReasonTokenExpired
+			case errors.Is(err, errInvalidClaims):
+				reason = ReasonInvalidClaims
+			}
+			m.logger.Info("Rejected bearer token: ", err)
+			abortUnauthorized(c, reason)
+			return
+		}
 
+		SetPrincipal(c, principal)
 		c.Next()
 	}
 }
+
+// Authenticate verifies a JWT and extracts the principal from its claims
+func (m AuthMiddleware) Authenticate(tokenString string) (Principal, error) {
+	// The issuer decides which keys and audiences apply, so it is read
+	// before the signature is verified. It is verified again below.
+	unverified, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
+	if err != nil {
+		return Principal{}, err
+	}
+	issuer, err := unverified.Claims.GetIssuer()
+	if err != nil {
+		return Principal{}, err
+	}
+	verifier, trusted := m.verifiers[issuer]
+	if !trusted {
+		return Principal{}, fmt.Errorf("%w: untrusted issuer %q", jwt.ErrTokenInvalidIssuer, issuer)
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(m.algorithms),
+		jwt.WithLeeway(m.leeway),
+		jwt.WithIssuer(issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if len(verifier.config.Audiences) > 0 {
+		options = append(options, jwt.WithAudience(verifier.config.Audiences...))
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(tokenString, claims, verifier.keyfunc.Keyfunc, options...); err != nil {
+		return Principal{}, err
+	}
+
+	return principalFromClaims(issuer, claims)
+}
+
+// ======== PRIVATE METHODS ========
+
+// authenticateAPIKey authenticates a request made with an API key
+func (m AuthMiddleware) authenticateAPIKey(c *gin.Context, key string) {
+	if key == "" {
+		abortUnauthorized(c, ReasonMissingToken)
+		return
+	}
+
+	identity, err := m.apiKeys.AuthenticateAPIKey(key, c.ClientIP())
+	if err != nil {
+		if !errors.Is(err, interfaces.InvalidAPIKeyException) {
+			m.logger.Error("Failed to authenticate API key: ", err)
+		}
+		abortUnauthorized(c, ReasonInvalidToken)
+		return
+	}
+
+	scopes := make([]Permission, len(identity.Scopes))
+	for i, scope := range identity.Scopes {
+		scopes[i] = Permission(scope)
+	}
+
+	SetPrincipal(c, Principal{
+		OrganizationID: identity.OrganizationID,
+		APIKeyID:       identity.ID,
+		Scopes:         scopes,
+	})
+	c.Next()
+}
+
+// errInvalidClaims is returned when a verified token lacks the claims we need
+var errInvalidClaims = errors.New("token is missing required claims")
+
+// principalFromClaims builds the principal from verified claims. The
+// organization and role are read only from app_metadata, which only the
+// server can set; user_metadata is editable by the user and is ignored.
+func principalFromClaims(issuer string, claims jwt.MapClaims) (Principal, error) {
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return Principal{}, fmt.Errorf("%w: sub", errInvalidClaims)
+	}
+
+	appMeta, _ := claims["app_metadata"].(map[string]interface{})
+
+	organizationID, found := int64Claim(appMeta["organization_id"])
+	if !found || organizationID <= 0 {
+		return Principal{}, fmt.Errorf("%w: app_metadata.organization_id", errInvalidClaims)
+	}
+
+	role, _ := appMeta["role"].(string)
+	if role == "" {
+		return Principal{}, fmt.Errorf("%w: app_metadata.role", errInvalidClaims)
+	}
+	email, _ := claims["email"].(string)
+
+	return Principal{
+		UserID:         subject,
+		Email:          email,
+		OrganizationID: organizationID,
+		Role:           ParseRole(role),
+		Issuer:         issuer,
+	}, nil
+}
+
+// int64Claim converts a numeric claim, which may be encoded as a number or a string
+func int64Claim(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case float64:
+		if v != float64(int64(v)) {
+			return 0, false
+		}
+		return int64(v), true
+	case json.Number:
+		n, err := v.Int64()
+		return n, err == nil
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// abortUnauthorized aborts the request with a 401 that carries no token details
+func abortUnauthorized(c *gin.Context, reason string) {
+	challenge := "Bearer"
+	if reason != ReasonMissingToken {
+		challenge = `Bearer error="invalid_token"`
+	}
+	c.Header("WWW-Authenticate", challenge)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, UnauthorizedResponse{
+		Error:  "Unauthorized",
+		Reason: reason,
+	})
+}
+
+// splitList splits a comma separated environment variable
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,327 @@
+package middlewares
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"hostflow/booking-service/pkg/interfaces"
+)
+
+const (
+	testIssuer   = "https://auth.example.test/auth/v1"
+	testAudience = "authenticated"
+	testKeyID    = "test-key"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Info(args ...interface{})  {}
+func (nopLogger) Fatal(args ...interface{}) {}
+func (nopLogger) Error(args ...interface{}) {}
+
+// newJWKSServer serves the public part of key as a JWK set
+func newJWKSServer(t *testing.T, key *ecdsa.PrivateKey) *httptest.Server {
+	t.Helper()
+
+	ecdhKey, err := key.PublicKey.ECDH()
+	require.NoError(t, err)
+	point := ecdhKey.Bytes() // 0x04 || X || Y
+	encode := base64.RawURLEncoding.EncodeToString
+
+	body, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "EC",
+			"crv": "P-256",
+			"alg": "ES256",
+			"use": "sig",
+			"kid": testKeyID,
+			"x":   encode(point[1:33]),
+			"y":   encode(point[33:]),
+		}},
+	})
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestAuth(t *testing.T, key *ecdsa.PrivateKey) AuthMiddleware {
+	t.Helper()
+
+	server := newJWKSServer(t, key)
+	auth, err := NewAuthMiddlewareWithConfig(AuthConfig{
+		Issuers: []IssuerConfig{{
+			Issuer:    testIssuer,
+			JWKSURL:   server.URL,
+			Audiences: []string{testAudience},
+		}},
+		Algorithms: []string{"ES256"},
+		Leeway:     30 * time.Second,
+	}, nopLogger{}, nil)
+	require.NoError(t, err)
+	return auth
+}
+
+func validClaims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":   testIssuer,
+		"aud":   testAudience,
+		"sub":   "7d1c3f5e-user",
+		"email": "host@example.test",
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+		"app_metadata": map[string]interface{}{
+			"organization_id": 100,
+			"role":            "manager",
+		},
+	}
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = testKeyID
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func newAuthRouter(auth AuthMiddleware) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/me", auth.Handler(), func(c *gin.Context) {
+		principal, _ := GetPrincipal(c)
+		c.JSON(http.StatusOK, principal)
+	})
+	return r
+}
+
+func doAuthRequest(r *gin.Engine, authorization string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/me", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func generateKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func TestAuthMiddleware_ValidToken(t *testing.T) {
+	key := generateKey(t)
+	r := newAuthRouter(newTestAuth(t, key))
+
+	w := doAuthRequest(r, "Bearer "+sign(t, key, validClaims()))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var principal Principal
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &principal))
+	assert.Equal(t, Principal{
+		UserID:         "7d1c3f5e-user",
+		Email:          "host@example.test",
+		OrganizationID: 100,
+		Role:           RoleManager,
+		Issuer:         testIssuer,
+	}, principal)
+}
+
+func TestAuthMiddleware_IgnoresUserMetadata(t *testing.T) {
+	key := generateKey(t)
+	auth := newTestAuth(t, key)
+
+	// Users can edit their own user_metadata, so it can't grant a role or
+	// switch the organization
+	claims := validClaims()
+	claims["user_metadata"] = map[string]interface{}{"organization_id": "200", "role": "owner"}
+
+	principal, err := auth.Authenticate(sign(t, key, claims))
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), principal.OrganizationID)
+	assert.Equal(t, RoleManager, principal.Role)
+}
+
+func TestAuthMiddleware_Rejections(t *testing.T) {
+	key := generateKey(t)
+	otherKey := generateKey(t)
+	r := newAuthRouter(newTestAuth(t, key))
+
+	with := func(change func(jwt.MapClaims)) jwt.MapClaims {
+		claims := validClaims()
+		change(claims)
+		return claims
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		reason        string
+	}{
+		{"missing header", "", ReasonMissingToken},
+		{"not a bearer token", "Basic dXNlcjpwYXNz", ReasonMissingToken},
+		{"malformed token", "Bearer not-a-jwt", ReasonInvalidToken},
+		{"wrong signing key", "Bearer " + sign(t, otherKey, validClaims()), ReasonInvalidToken},
+		{"untrusted issuer", "Bearer " + sign(t, key, with(func(c jwt.MapClaims) { c["iss"] = "https://evil.example.test" })), ReasonInvalidToken},
+		{"wrong audience", "Bearer " + sign(t, key, with(func(c jwt.MapClaims) { c["aud"] = "service_role" })), ReasonInvalidToken},
+		{"expired", "Bearer " + sign(t, key, with(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() })), ReasonTokenExpired},
+		{"expired beyond leeway", "Bearer " + sign(t, key, with(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-2 * time.Minute).Unix() })), ReasonTokenExpired},
+		{"missing exp", "Bearer " + sign(t, key, with(func(c jwt.MapClaims) { delete(c, "exp") })), ReasonInvalidToken},
+		{"missing app_metadata", "Bearer " + sign(t, key, with(func(c jwt.MapClaims) { delete(c, "app_metadata") })), ReasonInvalidClaims},
+		{"organization_id of wrong type", "Bearer " + sign(t, key, with(func(c jwt.MapClaims) {
+			c["app_metadata"] = map[string]interface{}{"organization_id": true, "role": "owner"}
+		})), ReasonInvalidClaims},
+		{"missing role", "Bearer " + sign(t, key, with(func(c jwt.MapClaims) {
+			c["app_metadata"] = map[string]interface{}{"organization_id": 100}
+		})), ReasonInvalidClaims},
+		{"organization and role only in user_metadata", "Bearer " + sign(t, key, with(func(c jwt.MapClaims) {
+			delete(c, "app_metadata")
+			c["user_metadata"] = map[string]interface{}{"organization_id": 100, "role": "owner"}
+		})), ReasonInvalidClaims},
+		{"role only in user_metadata", "Bearer " + sign(t, key, with(func(c jwt.MapClaims) {
+			c["app_metadata"] = map[string]interface{}{"organization_id": 100}
+			c["user_metadata"] = map[string]interface{}{"role": "owner"}
+		})), ReasonInvalidClaims},
+		{"missing subject", "Bearer " + sign(t, key, with(func(c jwt.MapClaims) { delete(c, "sub") })), ReasonInvalidClaims},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doAuthRequest(r, tt.authorization)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.JSONEq(t, `{"error":"Unauthorized","reason":"`+tt.reason+`"}`, w.Body.String())
+			assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+		})
+	}
+}
+
+func TestAuthMiddleware_WithinLeeway(t *testing.T) {
+	key := generateKey(t)
+	r := newAuthRouter(newTestAuth(t, key))
+
+	claims := validClaims()
+	claims["exp"] = time.Now().Add(-10 * time.Second).Unix()
+
+	w := doAuthRequest(r, "Bearer "+sign(t, key, claims))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuthMiddleware_MultipleIssuers(t *testing.T) {
+	key := generateKey(t)
+	secondKey := generateKey(t)
+	first := newJWKSServer(t, key)
+	second := newJWKSServer(t, secondKey)
+
+	auth, err := NewAuthMiddlewareWithConfig(AuthConfig{
+		Issuers: []IssuerConfig{
+			{Issuer: testIssuer, JWKSURL: first.URL, Audiences: []string{testAudience}},
+			{Issuer: "https://second.example.test", JWKSURL: second.URL, Audiences: []string{"partners"}},
+		},
+		Leeway: 30 * time.Second,
+	}, nopLogger{}, nil)
+	require.NoError(t, err)
+
+	claims := validClaims()
+	claims["iss"] = "https://second.example.test"
+	claims["aud"] = "partners"
+
+	principal, err := auth.Authenticate(sign(t, secondKey, claims))
+	require.NoError(t, err)
+	assert.Equal(t, "https://second.example.test", principal.Issuer)
+
+	// A token of the second issuer signed with the first issuer's key is rejected
+	_, err = auth.Authenticate(sign(t, key, claims))
+	assert.Error(t, err)
+}
+
+func TestAuthMiddleware_UnreachableJWKSDoesNotPanic(t *testing.T) {
+	auth, err := NewAuthMiddlewareWithConfig(AuthConfig{
+		Issuers: []IssuerConfig{{Issuer: testIssuer, JWKSURL: "http://127.0.0.1:1/jwks.json"}},
+	}, nopLogger{}, nil)
+	require.NoError(t, err)
+
+	key := generateKey(t)
+	w := doAuthRequest(newAuthRouter(auth), "Bearer "+sign(t, key, validClaims()))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestGetAuthConfig(t *testing.T) {
+	t.Setenv("AUTH_JWT_ISSUERS", "https://a.example.test/auth/v1, https://b.example.test")
+	t.Setenv("AUTH_JWT_JWKS_URLS", "")
+	t.Setenv("AUTH_JWT_AUDIENCES", "authenticated,partners")
+	t.Setenv("AUTH_JWT_LEEWAY", "10s")
+
+	config, err := GetAuthConfig()
+
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Second, config.Leeway)
+	require.Len(t, config.Issuers, 2)
+	assert.Equal(t, "https://a.example.test/auth/v1/.well-known/jwks.json", config.Issuers[0].JWKSURL)
+	assert.Equal(t, []string{"authenticated", "partners"}, config.Issuers[1].Audiences)
+
+	t.Setenv("AUTH_JWT_JWKS_URLS", "https://only-one.example.test")
+	_, err = GetAuthConfig()
+	assert.Error(t, err)
+}
+
+type fakeAPIKeys map[string]*interfaces.APIKeyIdentity
+
+func (f fakeAPIKeys) AuthenticateAPIKey(key, clientIP string) (*interfaces.APIKeyIdentity, error) {
+	if identity, ok := f[key]; ok {
+		return identity, nil
+	}
+	return nil, interfaces.InvalidAPIKeyException
+}
+
+func TestAuthMiddleware_APIKey(t *testing.T) {
+	key := generateKey(t)
+	server := newJWKSServer(t, key)
+	auth, err := NewAuthMiddlewareWithConfig(AuthConfig{
+		Issuers: []IssuerConfig{{Issuer: testIssuer, JWKSURL: server.URL}},
+	}, nopLogger{}, fakeAPIKeys{
+		"hfk_abcd1234_secret": {ID: 7, OrganizationID: 100, Name: "channel manager", Scopes: []string{"reservations:read"}},
+	})
+	require.NoError(t, err)
+	r := newAuthRouter(auth)
+
+	w := doAuthRequest(r, "ApiKey hfk_abcd1234_secret")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var principal Principal
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &principal))
+	assert.Equal(t, Principal{
+		OrganizationID: 100,
+		APIKeyID:       7,
+		Scopes:         []Permission{ReservationsRead},
+	}, principal)
+
+	w = doAuthRequest(r, "ApiKey hfk_abcd1234_wrong")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.JSONEq(t, `{"error":"Unauthorized","reason":"invalid_token"}`, w.Body.String())
+}
@@ -66,6 +66,7 @@ func (middleware CorsMiddleware) Setup() {
 		AllowOriginFunc:  func(origin string) bool { return true },
 		AllowedHeaders:   []string{"*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "HEAD", "OPTIONS"},
+		ExposedHeaders:   []string{"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After", "X-Request-ID"},
 		Debug:            debug,
 	}))
 }
@@ -49,12 +49,16 @@ type Middlewares []Middleware
 
 // GetMiddlewares creates new middlewares
 func GetMiddlewares(
+	requestIDMiddleware RequestIDMiddleware,
 	corsMiddleware CorsMiddleware,
 	errorsMiddleware ErrorsMiddleware,
+	rateLimitMiddleware RateLimitMiddleware,
 ) Middlewares {
 	return Middlewares{
+		requestIDMiddleware,
 		corsMiddleware,
 		errorsMiddleware,
+		rateLimitMiddleware,
 	}
 }
 
@@ -69,8 +73,12 @@ func (m Middlewares) Setup() {
 
 // Module Middleware exported
 var Module = fx.Options(
+	fx.Provide(GetRequestIDMiddleware),
 	fx.Provide(GetCorsMiddleware),
 	fx.Provide(GetErrorsMiddleware),
 	fx.Provide(GetMiddlewares),
 	fx.Provide(NewAuthMiddleware),
+	fx.Provide(GetRateLimitStore),
+	fx.Provide(GetRateLimitMiddleware),
+	fx.Invoke(RegisterRateLimitPurgeHooks),
 )
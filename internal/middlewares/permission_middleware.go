@@ -0,0 +1,254 @@
+package middlewares
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ======== TYPES ========
+
+// Role is the role of a user inside an organization, taken from the
+// role claim in the user's JWT metadata
+type Role string
+
+// Permission is an action on a resource, in the form "resource:action"
+type Permission string
+
+// Roles
+const (
+	RoleOwner     Role = "owner"
+	RoleManager   Role = "manager"
+	RoleFrontDesk Role = "front-desk"
+	RoleCleaner   Role = "cleaner"
+	RoleReadOnly  Role = "read-only"
+)
+
+// Permissions
+const (
+	ReservationsRead   Permission = "reservations:read"
+	ReservationsCreate Permission = "reservations:create"
+	ReservationsUpdate Permission = "reservations:update"
+	ReservationsDelete Permission = "reservations:delete"
+	ReservationsImport Permission = "reservations:import"
+	ReservationsExport Permission = "reservations:export"
+
+	// GuestDataDecrypt allows reading encrypted guest data in plaintext;
+	// without it, encrypted values are redacted
+	GuestDataDecrypt Permission = "guestdata:decrypt"
+
+	CustomersRead   Permission = "customers:read"
+	CustomersCreate Permission = "customers:create"
+	CustomersUpdate Permission = "customers:update"
+	CustomersDelete Permission = "customers:delete"
+	CustomersMerge  Permission = "customers:merge"
+
+	CommunicationRead   Permission = "communication:read"
+	CommunicationSend   Permission = "communication:send"
+	CommunicationManage Permission = "communication:manage"
+
+	APIKeysManage Permission = "apikeys:manage"
+
+	AuditRead Permission = "audit:read"
+
+	PrivacyManage Permission = "privacy:manage"
+
+	TouristTaxManage Permission = "touristtax:manage"
+
+	InvoicesRead   Permission = "invoices:read"
+	InvoicesIssue  Permission = "invoices:issue"
+	InvoicesManage Permission = "invoices:manage"
+
+	ReportsRead Permission = "reports:read"
+
+	RoomsRead   Permission = "rooms:read"
+	RoomsManage Permission = "rooms:manage"
+
+	WaitlistRead   Permission = "waitlist:read"
+	WaitlistManage Permission = "waitlist:manage"
+)
+
+// Reasons returned in the body of a 403 response
+const (
+	ReasonMissingRole            = "missing_role"
+	ReasonUnknownRole            = "unknown_role"
+	ReasonInsufficientPermission = "insufficient_permission"
+	ReasonInsufficientScope      = "insufficient_scope"
+)
+
+// ForbiddenResponse is the body of a 403 response
+type ForbiddenResponse struct {
+	Error    string `json:"error" example:"Forbidden"`
+	Reason   string `json:"reason" example:"insufficient_permission"`
+	Required string `json:"required,omitempty" example:"reservations:delete"`
+	Role     string `json:"role,omitempty" example:"cleaner"`
+}
+
+// ======== POLICY ========
+
+// readPermissions can be granted to every role that may see the data.
+var readPermissions = []Permission{
+	ReservationsRead,
+	CustomersRead,
+	CommunicationRead,
+	InvoicesRead,
+	RoomsRead,
+	WaitlistRead,
+}
+
+// policy maps every role to the permissions it is granted.
+var policy = map[Role]map[Permission]bool{
+	RoleOwner: grant(
+		readPermissions,
+		ReservationsCreate, ReservationsUpdate, ReservationsDelete, ReservationsImport, ReservationsExport, GuestDataDecrypt,
+		CustomersCreate, CustomersUpdate, CustomersDelete, CustomersMerge,
+		CommunicationSend, CommunicationManage,
+		APIKeysManage, AuditRead, PrivacyManage, TouristTaxManage,
+		InvoicesIssue, InvoicesManage, ReportsRead, RoomsManage, WaitlistManage,
+	),
+	RoleManager: grant(
+		readPermissions,
+		ReservationsCreate, ReservationsUpdate, ReservationsDelete, ReservationsImport, ReservationsExport, GuestDataDecrypt,
+		CustomersCreate, CustomersUpdate, CustomersDelete, CustomersMerge,
+		CommunicationSend, CommunicationManage,
+		AuditRead, PrivacyManage, TouristTaxManage,
+		InvoicesIssue, InvoicesManage, ReportsRead, RoomsManage, WaitlistManage,
+	),
+	RoleFrontDesk: grant(
+		readPermissions,
+		ReservationsCreate, ReservationsUpdate, GuestDataDecrypt,
+		CustomersCreate, CustomersUpdate,
+		CommunicationSend,
+		InvoicesIssue, WaitlistManage,
+	),
+	// Cleaners only need to know when guests arrive and leave.
+	RoleCleaner: grant(
+		nil,
+		ReservationsRead,
+	),
+	RoleReadOnly: grant(
+		readPermissions,
+	),
+}
+
+// grant builds a permission set.
+func grant(base []Permission, extra ...Permission) map[Permission]bool {
+	set := make(map[Permission]bool, len(base)+len(extra))
+	for _, p := range base {
+		set[p] = true
+	}
+	for _, p := range extra {
+		set[p] = true
+	}
+	return set
+}
+
+// ======== PUBLIC METHODS ========
+
+// ParseRole normalizes the role claim. Unknown roles are returned as-is
+// and are granted nothing.
+func ParseRole(value string) Role {
+	role := Role(strings.ToLower(strings.TrimSpace(value)))
+	if role == "front_desk" || role == "frontdesk" {
+		return RoleFrontDesk
+	}
+	if role == "read_only" || role == "readonly" {
+		return RoleReadOnly
+	}
+	return role
+}
+
+// HasPermission reports whether the role is granted the permission.
+func HasPermission(role Role, permission Permission) bool {
+	return policy[role][permission]
+}
+
+// ScopePermissions are the permissions an API key can be granted. Managing
+// API keys is reserved to users so a leaked key cannot mint new ones.
+var ScopePermissions = []Permission{
+	ReservationsRead, ReservationsCreate, ReservationsUpdate, ReservationsDelete, ReservationsImport, ReservationsExport, GuestDataDecrypt,
+	CustomersRead, CustomersCreate, CustomersUpdate, CustomersDelete, CustomersMerge,
+	CommunicationRead, CommunicationSend, CommunicationManage,
+	AuditRead, PrivacyManage, TouristTaxManage,
+	InvoicesRead, InvoicesIssue, InvoicesManage, ReportsRead,
+	RoomsRead, RoomsManage,
+	WaitlistRead, WaitlistManage,
+}
+
+// IsScopePermission reports whether the permission can be granted to an API key.
+func IsScopePermission(permission Permission) bool {
+	for _, p := range ScopePermissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPermitted reports whether the authenticated caller of the request is
+// granted the permission, by its role or, for API keys, by its scopes. It is
+// used where a permission changes what a route returns rather than whether
+// it may be called.
+func IsPermitted(ctx *gin.Context, permission Permission) bool {
+	if principal, ok := GetPrincipal(ctx); ok && principal.APIKeyID != 0 {
+		return principal.HasScope(permission)
+	}
+	return HasPermission(ParseRole(ctx.GetString("role")), permission)
+}
+
+// RequirePermission returns a route-level middleware that aborts with 403
+// unless the authenticated role is granted every given permission. Requests
+// authenticated with an API key are checked against the key's scopes
+// instead. It must run after the auth middleware.
+func RequirePermission(permissions ...Permission) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if principal, ok := GetPrincipal(ctx); ok && principal.APIKeyID != 0 {
+			for _, permission := range permissions {
+				if !principal.HasScope(permission) {
+					ctx.AbortWithStatusJSON(http.StatusForbidden, ForbiddenResponse{
+						Error:    "Forbidden",
+						Reason:   ReasonInsufficientScope,
+						Required: string(permission),
+					})
+					return
+				}
+			}
+			ctx.Next()
+			return
+		}
+
+		value := ctx.GetString("role")
+		if value == "" {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, ForbiddenResponse{
+				Error:  "Forbidden",
+				Reason: ReasonMissingRole,
+			})
+			return
+		}
+
+		role := ParseRole(value)
+		if _, known := policy[role]; !known {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, ForbiddenResponse{
+				Error:  "Forbidden",
+				Reason: ReasonUnknownRole,
+				Role:   value,
+			})
+			return
+		}
+
+		for _, permission := range permissions {
+			if !HasPermission(role, permission) {
+				ctx.AbortWithStatusJSON(http.StatusForbidden, ForbiddenResponse{
+					Error:    "Forbidden",
+					Reason:   ReasonInsufficientPermission,
+					Required: string(permission),
+					Role:     string(role),
+				})
+				return
+			}
+		}
+
+		ctx.Next()
+	}
+}
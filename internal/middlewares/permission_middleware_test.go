@@ -0,0 +1,116 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPermissionRouter(role string, permissions ...Permission) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.DELETE("/reservations/:id",
+		func(c *gin.Context) {
+			if role != "" {
+				c.Set("role", role)
+			}
+		},
+		RequirePermission(permissions...),
+		func(c *gin.Context) { c.Status(http.StatusNoContent) },
+	)
+	return r
+}
+
+func TestRequirePermission_Allowed(t *testing.T) {
+	r := newPermissionRouter("manager", ReservationsDelete)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/reservations/1", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestRequirePermission_InsufficientPermission(t *testing.T) {
+	r := newPermissionRouter("cleaner", ReservationsDelete)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/reservations/1", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.JSONEq(t, `{"error":"Forbidden","reason":"insufficient_permission","required":"reservations:delete","role":"cleaner"}`, w.Body.String())
+}
+
+func TestRequirePermission_UnknownRole(t *testing.T) {
+	r := newPermissionRouter("superuser", ReservationsRead)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/reservations/1", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), `"reason":"unknown_role"`)
+}
+
+func TestRequirePermission_MissingRole(t *testing.T) {
+	r := newPermissionRouter("", ReservationsRead)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/reservations/1", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), `"reason":"missing_role"`)
+}
+
+func TestPolicy(t *testing.T) {
+	assert.True(t, HasPermission(RoleOwner, CustomersMerge))
+	assert.True(t, HasPermission(RoleFrontDesk, ReservationsCreate))
+	assert.False(t, HasPermission(RoleFrontDesk, ReservationsDelete))
+	assert.True(t, HasPermission(RoleCleaner, ReservationsRead))
+	assert.False(t, HasPermission(RoleCleaner, CustomersRead))
+	assert.True(t, HasPermission(RoleReadOnly, CustomersRead))
+	assert.False(t, HasPermission(RoleReadOnly, ReservationsUpdate))
+	assert.Equal(t, RoleFrontDesk, ParseRole(" Front_Desk "))
+}
+
+func TestRequirePermission_APIKeyScopes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		SetPrincipal(c, Principal{OrganizationID: 100, APIKeyID: 7, Scopes: []Permission{ReservationsRead}})
+	})
+	r.GET("/reservations", RequirePermission(ReservationsRead), func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.DELETE("/reservations/:id", RequirePermission(ReservationsDelete), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/reservations", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", "/reservations/1", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.JSONEq(t, `{"error":"Forbidden","reason":"insufficient_scope","required":"reservations:delete"}`, w.Body.String())
+}
+
+func TestIsPermitted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	SetPrincipal(c, Principal{OrganizationID: 100, Role: RoleFrontDesk})
+	assert.True(t, IsPermitted(c, GuestDataDecrypt))
+
+	c, _ = gin.CreateTestContext(httptest.NewRecorder())
+	SetPrincipal(c, Principal{OrganizationID: 100, Role: RoleCleaner})
+	assert.False(t, IsPermitted(c, GuestDataDecrypt))
+
+	c, _ = gin.CreateTestContext(httptest.NewRecorder())
+	SetPrincipal(c, Principal{OrganizationID: 100, APIKeyID: 7, Scopes: []Permission{ReservationsRead}})
+	assert.False(t, IsPermitted(c, GuestDataDecrypt))
+}
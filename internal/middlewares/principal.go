@@ -0,0 +1,59 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// ======== TYPES ========
+
+// principalKey is the gin context key the authenticated principal is stored under
+const principalKey = "principal"
+
+// Principal is the authenticated caller of a request. It is either a user
+// authenticated with a JWT, or an integration authenticated with an API key,
+// in which case APIKeyID is set and Scopes replace the role.
+type Principal struct {
+	UserID         string       `json:"user_id,omitempty"`
+	Email          string       `json:"email,omitempty"`
+	OrganizationID int64        `json:"organization_id"`
+	Role           Role         `json:"role,omitempty"`
+	Issuer         string       `json:"issuer,omitempty"`
+	APIKeyID       int64        `json:"api_key_id,omitempty"`
+	Scopes         []Permission `json:"scopes,omitempty"`
+}
+
+// HasScope reports whether an API key principal was granted the permission
+func (p Principal) HasScope(permission Permission) bool {
+	for _, scope := range p.Scopes {
+		if scope == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// ======== PUBLIC METHODS ========
+
+// SetPrincipal stores the principal in the gin context. The organization
+// id and role are also stored under their own keys, which is what the
+// controllers and RequirePermission read.
+func SetPrincipal(ctx *gin.Context, principal Principal) {
+	ctx.Set(principalKey, principal)
+	ctx.Set("organization_id", principal.OrganizationID)
+	ctx.Set("role", string(principal.Role))
+	ctx.Set("user_id", principal.UserID)
+	if principal.APIKeyID != 0 {
+		ctx.Set("api_key_id", principal.APIKeyID)
+	}
+}
+
+// GetPrincipal returns the principal of the request, if it is authenticated
+func GetPrincipal(ctx *gin.Context) (Principal, bool) {
+	value, exists := ctx.Get(principalKey)
+	if !exists {
+		return Principal{}, false
+	}
+
+	principal, ok := value.(Principal)
+	return principal, ok
+}
@@ -0,0 +1,196 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"hostflow/booking-service/pkg/lib"
+)
+
+// ======== TYPES ========
+
+// Budget is the name of a rate limit applied to a group of routes
+type Budget string
+
+// Budgets
+const (
+	// BudgetGlobal applies per client IP to every request, before it is
+	// authenticated
+	BudgetGlobal Budget = "global"
+	// BudgetDefault applies to authenticated routes without a budget of their own
+	BudgetDefault Budget = "default"
+	// BudgetSearch applies to listing, search and availability reads, which
+	// are the hottest routes
+	BudgetSearch Budget = "search"
+	// BudgetWrite applies to updates and deletes
+	BudgetWrite Budget = "write"
+	// BudgetCreate applies to creating reservations and customers, which
+	// triggers availability checks, payments and emails
+	BudgetCreate Budget = "create"
+	// BudgetPortal applies per client IP to the unauthenticated guest portal,
+	// which also slows down guessing link PINs
+	BudgetPortal Budget = "portal"
+)
+
+// defaultBudgets are used for budgets RATE_LIMIT_BUDGETS does not override
+var defaultBudgets = map[Budget]Limit{
+	BudgetGlobal:  {Requests: 1200, Period: time.Minute},
+	BudgetDefault: {Requests: 300, Period: time.Minute},
+	BudgetSearch:  {Requests: 600, Period: time.Minute},
+	BudgetWrite:   {Requests: 120, Period: time.Minute},
+	BudgetCreate:  {Requests: 30, Period: time.Minute},
+	BudgetPortal:  {Requests: 60, Period: time.Minute},
+}
+
+// rateLimitTimeout bounds a single call to the store
+const rateLimitTimeout = 2 * time.Second
+
+// ReasonRateLimited is returned in the body of a 429 response
+const ReasonRateLimited = "rate_limited"
+
+// RateLimitMiddleware throttles requests with token buckets. Authenticated
+// requests are limited per API key or organization, others per client IP.
+type RateLimitMiddleware struct {
+	router  *lib.Router
+	logger  lib.Logger
+	store   RateLimitStore
+	budgets map[Budget]Limit
+}
+
+// ======== PUBLIC METHODS ========
+
+// GetRateLimitMiddleware returns the rate limit middleware. Budgets can be
+// overridden with RATE_LIMIT_BUDGETS, e.g. "search=1000/m,create=10/m".
+func GetRateLimitMiddleware(router *lib.Router, logger lib.Logger, store RateLimitStore) (RateLimitMiddleware, error) {
+	budgets, err := ParseBudgets(os.Getenv("RATE_LIMIT_BUDGETS"))
+	if err != nil {
+		return RateLimitMiddleware{}, err
+	}
+
+	return RateLimitMiddleware{
+		router:  router,
+		logger:  logger,
+		store:   store,
+		budgets: budgets,
+	}, nil
+}
+
+// Setup applies the global per-IP budget to every request except health
+// checks and metrics
+func (m RateLimitMiddleware) Setup() {
+	m.logger.Info("Setting up [RATE LIMIT] middleware")
+
+	limiter := m.Limit(BudgetGlobal)
+	m.router.Use(func(c *gin.Context) {
+		path := c.Request.URL.Path
+		if strings.HasPrefix(path, "/health") || strings.HasPrefix(path, "/metrics") {
+			c.Next()
+			return
+		}
+		limiter(c)
+	})
+}
+
+// Limit returns a route-level middleware enforcing the budget. It must run
+// after the auth middleware for the limit to apply per organization or API
+// key rather than per IP.
+func (m RateLimitMiddleware) Limit(budget Budget) gin.HandlerFunc {
+	limit, ok := m.budgets[budget]
+	if !ok {
+		limit = m.budgets[BudgetDefault]
+	}
+
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), rateLimitTimeout)
+		defer cancel()
+
+		key := string(budget) + ":" + rateLimitIdentity(c, budget)
+		result, err := m.store.Take(ctx, key, limit)
+		if err != nil {
+			// Failing open: an unavailable store must not take the API down
+			m.logger.Error("Rate limit store failed: ", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(limit.Requests))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+		c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Requests, int(limit.Period.Seconds())))
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":  "Too Many Requests",
+				"reason": ReasonRateLimited,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ParseBudgets parses budget overrides of the form
+// "name=requests/period,...", where period is s, m or h, on top of the
+// default budgets
+func ParseBudgets(value string) (map[Budget]Limit, error) {
+	budgets := make(map[Budget]Limit, len(defaultBudgets))
+	for budget, limit := range defaultBudgets {
+		budgets[budget] = limit
+	}
+
+	for _, item := range splitList(value) {
+		name, spec, found := strings.Cut(item, "=")
+		requestsValue, periodValue, hasPeriod := strings.Cut(spec, "/")
+		if !found || !hasPeriod {
+			return nil, fmt.Errorf("invalid rate limit budget %q, expected name=requests/period", item)
+		}
+
+		requests, err := strconv.Atoi(strings.TrimSpace(requestsValue))
+		if err != nil || requests <= 0 {
+			return nil, fmt.Errorf("invalid number of requests in rate limit budget %q", item)
+		}
+
+		var period time.Duration
+		switch strings.TrimSpace(periodValue) {
+		case "s":
+			period = time.Second
+		case "m":
+			period = time.Minute
+		case "h":
+			period = time.Hour
+		default:
+			return nil, fmt.Errorf("invalid period in rate limit budget %q, expected s, m or h", item)
+		}
+
+		budgets[Budget(strings.TrimSpace(name))] = Limit{Requests: requests, Period: period}
+	}
+
+	return budgets, nil
+}
+
+// ======== PRIVATE METHODS ========
+
+// rateLimitIdentity returns who a request is counted against
+func rateLimitIdentity(c *gin.Context, budget Budget) string {
+	if principal, ok := GetPrincipal(c); ok && budget != BudgetGlobal {
+		if principal.APIKeyID != 0 {
+			return "key:" + strconv.FormatInt(principal.APIKeyID, 10)
+		}
+		return "org:" + strconv.FormatInt(principal.OrganizationID, 10)
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// ceilSeconds rounds a duration up to whole seconds
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,158 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"hostflow/booking-service/internal/dbtest"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTake_RefillsOverTime(t *testing.T) {
+	limit := Limit{Requests: 2, Period: 2 * time.Second}
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	b, result := take(bucket{}, limit, start)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+
+	b, result = take(b, limit, start)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.Equal(t, 2*time.Second, result.Reset)
+
+	b, result = take(b, limit, start.Add(500*time.Millisecond))
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+
+	_, result = take(b, limit, start.Add(time.Second))
+	assert.True(t, result.Allowed)
+}
+
+func TestMemoryRateLimitStore_SeparateKeys(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	limit := Limit{Requests: 1, Period: time.Minute}
+
+	first, _ := store.Take(context.Background(), "create:org:1", limit)
+	second, _ := store.Take(context.Background(), "create:org:1", limit)
+	other, _ := store.Take(context.Background(), "create:org:2", limit)
+
+	assert.True(t, first.Allowed)
+	assert.False(t, second.Allowed)
+	assert.True(t, other.Allowed)
+}
+
+func TestPostgresRateLimitStore_PurgesIdleBuckets(t *testing.T) {
+	db := dbtest.Open(t)
+	store := NewPostgresRateLimitStore(db)
+	ctx := context.Background()
+
+	// The idle bucket is full again after half a second and then kept for
+	// one more refill window; the busy one is empty for an hour
+	idle := Limit{Requests: 2, Period: time.Second}
+	busy := Limit{Requests: 1, Period: time.Hour}
+	_, err := store.Take(ctx, "idle", idle)
+	require.NoError(t, err)
+	_, err = store.Take(ctx, "busy", busy)
+	require.NoError(t, err)
+
+	var kept float64
+	require.NoError(t, db.QueryRow(ctx, `
+        SELECT EXTRACT(EPOCH FROM expires_at - updated_at) FROM rate_limit_bucket WHERE key = 'idle'
+    `).Scan(&kept))
+	assert.InDelta(t, 1.5, kept, 0.001)
+
+	_, err = db.Exec(ctx, `
+        UPDATE rate_limit_bucket
+        SET updated_at = updated_at - INTERVAL '2 seconds',
+            expires_at = expires_at - INTERVAL '2 seconds'
+    `)
+	require.NoError(t, err)
+
+	purged, err := store.PurgeIdle(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	rows, err := db.Query(ctx, `SELECT key FROM rate_limit_bucket`)
+	require.NoError(t, err)
+	left, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	require.NoError(t, err)
+	assert.Equal(t, []string{"busy"}, left)
+
+	// A purged bucket starts over full
+	result, err := store.Take(ctx, "idle", idle)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Remaining)
+	result, err = store.Take(ctx, "busy", busy)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+}
+
+func newRateLimitRouter(t *testing.T, budgets string) *gin.Engine {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	t.Setenv("RATE_LIMIT_BUDGETS", budgets)
+	m, err := GetRateLimitMiddleware(r, nopLogger{}, NewMemoryRateLimitStore())
+	require.NoError(t, err)
+
+	r.POST("/reservations/",
+		func(c *gin.Context) {
+			SetPrincipal(c, Principal{OrganizationID: int64(len(c.GetHeader("X-Org"))), Role: RoleOwner})
+		},
+		m.Limit(BudgetCreate),
+		func(c *gin.Context) { c.Status(http.StatusCreated) },
+	)
+	return r
+}
+
+func postReservation(r *gin.Engine, org string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/reservations/", nil)
+	req.Header.Set("X-Org", org)
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitMiddleware_LimitsPerOrganization(t *testing.T) {
+	r := newRateLimitRouter(t, "create=2/m")
+
+	w := postReservation(r, "a")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "2;w=60", w.Header().Get("RateLimit-Policy"))
+
+	assert.Equal(t, http.StatusCreated, postReservation(r, "a").Code)
+
+	w = postReservation(r, "a")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	assert.JSONEq(t, `{"error":"Too Many Requests","reason":"rate_limited"}`, w.Body.String())
+
+	// Another organization has its own bucket
+	assert.Equal(t, http.StatusCreated, postReservation(r, "bb").Code)
+}
+
+func TestParseBudgets(t *testing.T) {
+	budgets, err := ParseBudgets("search=1000/m, create=10/h")
+
+	require.NoError(t, err)
+	assert.Equal(t, Limit{Requests: 1000, Period: time.Minute}, budgets[BudgetSearch])
+	assert.Equal(t, Limit{Requests: 10, Period: time.Hour}, budgets[BudgetCreate])
+	assert.Equal(t, defaultBudgets[BudgetWrite], budgets[BudgetWrite])
+
+	for _, invalid := range []string{"search", "search=10", "search=0/m", "search=10/d"} {
+		_, err := ParseBudgets(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,253 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/fx"
+	"hostflow/booking-service/pkg/lib"
+)
+
+// ======== TYPES ========
+
+// Limit is a token bucket that holds Requests tokens and refills completely
+// over Period
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// RateLimitResult is the outcome of taking a token from a bucket
+type RateLimitResult struct {
+	Allowed   bool
+	Remaining int
+	// Reset is the time until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is the time until the next token is available; it is only
+	// set when the request is not allowed
+	RetryAfter time.Duration
+}
+
+// RateLimitStore holds the token buckets. The in-memory store only limits
+// a single replica; a shared store makes every replica enforce the same
+// limits.
+type RateLimitStore interface {
+	// Take removes a token from the bucket identified by key
+	Take(ctx context.Context, key string, limit Limit) (RateLimitResult, error)
+}
+
+// bucket is the state of a token bucket
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// ======== TOKEN BUCKET ========
+
+// rate returns the tokens added per second
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// take refills the bucket up to now and removes a token if one is available.
+// A missing bucket (zero updated time) starts full.
+func take(b bucket, limit Limit, now time.Time) (bucket, RateLimitResult) {
+	capacity := float64(limit.Requests)
+
+	tokens := capacity
+	if !b.updated.IsZero() {
+		elapsed := now.Sub(b.updated).Seconds()
+		if elapsed < 0 {
+			elapsed = 0
+		}
+		tokens = math.Min(capacity, b.tokens+elapsed*limit.rate())
+	}
+
+	result := RateLimitResult{}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - tokens) / limit.rate())
+	}
+
+	result.Remaining = int(math.Floor(tokens))
+	result.Reset = seconds((capacity - tokens) / limit.rate())
+
+	return bucket{tokens: tokens, updated: now}, result
+}
+
+// seconds converts a number of seconds to a duration
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// ======== MEMORY STORE ========
+
+// MemoryRateLimitStore keeps the buckets in process memory
+type MemoryRateLimitStore struct {
+	mu      sync.Mutex
+	buckets map[string]bucket
+	now     func() time.Time
+	takes   int
+}
+
+// NewMemoryRateLimitStore returns an empty MemoryRateLimitStore
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets: make(map[string]bucket),
+		now:     time.Now,
+	}
+}
+
+// memoryCleanupEvery is how many takes happen between removals of idle buckets
+const memoryCleanupEvery = 10000
+
+// memoryIdleAfter is how long a bucket must be unused to be removed. Every
+// budget refills within this time, so a removed bucket was full anyway.
+const memoryIdleAfter = time.Hour
+
+// Take implements RateLimitStore
+func (s *MemoryRateLimitStore) Take(_ context.Context, key string, limit Limit) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	updated, result := take(s.buckets[key], limit, now)
+	s.buckets[key] = updated
+
+	s.takes++
+	if s.takes >= memoryCleanupEvery {
+		s.takes = 0
+		for k, b := range s.buckets {
+			if now.Sub(b.updated) > memoryIdleAfter {
+				delete(s.buckets, k)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// ======== POSTGRES STORE ========
+
+// rateLimitPurgeInterval is how often idle buckets are removed from the
+// rate_limit_bucket table
+const rateLimitPurgeInterval = 10 * time.Minute
+
+// PostgresRateLimitStore keeps the buckets in the rate_limit_bucket table so
+// that every replica shares them
+type PostgresRateLimitStore struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresRateLimitStore returns a PostgresRateLimitStore
+func NewPostgresRateLimitStore(db *pgxpool.Pool) *PostgresRateLimitStore {
+	return &PostgresRateLimitStore{
+		db: db,
+	}
+}
+
+// Take implements RateLimitStore. The bucket row is locked for the duration
+// of the transaction, so concurrent requests on several replicas are
+// serialized per key.
+func (s *PostgresRateLimitStore) Take(ctx context.Context, key string, limit Limit) (RateLimitResult, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	// A new bucket starts full. An existing one is locked right away, so
+	// that the purge can't remove it before it is read.
+	_, err = tx.Exec(ctx, `
+        INSERT INTO rate_limit_bucket (key, tokens, updated_at)
+        VALUES ($1, $2, clock_timestamp())
+        ON CONFLICT (key) DO UPDATE SET tokens = rate_limit_bucket.tokens
+    `, key, float64(limit.Requests))
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	var current bucket
+	var now time.Time
+	err = tx.QueryRow(ctx, `
+        SELECT tokens, updated_at, clock_timestamp()
+        FROM rate_limit_bucket
+        WHERE key = $1
+        FOR UPDATE
+    `, key).Scan(&current.tokens, &current.updated, &now)
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	updated, result := take(current, limit, now)
+
+	_, err = tx.Exec(ctx, `
+        UPDATE rate_limit_bucket
+        SET tokens = $2,
+            updated_at = $3,
+            expires_at = $4
+        WHERE key = $1
+    `, key, updated.tokens, updated.updated, updated.updated.Add(result.Reset+limit.Period))
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return RateLimitResult{}, err
+	}
+	return result, nil
+}
+
+// PurgeIdle removes the buckets that have been full for longer than their
+// refill window and returns how many were removed
+func (s *PostgresRateLimitStore) PurgeIdle(ctx context.Context) (int64, error) {
+	tag, err := s.db.Exec(ctx, `DELETE FROM rate_limit_bucket WHERE expires_at < clock_timestamp()`)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// ======== PUBLIC METHODS ========
+
+// GetRateLimitStore returns the store selected by RATE_LIMIT_STORE
+// ("memory", the default, or "postgres")
+func GetRateLimitStore(db *lib.Database, logger lib.Logger) RateLimitStore {
+	switch os.Getenv("RATE_LIMIT_STORE") {
+	case "postgres":
+		logger.Info("Using the shared Postgres rate limit store")
+		return NewPostgresRateLimitStore(db)
+	case "", "memory":
+		return NewMemoryRateLimitStore()
+	default:
+		logger.Error("Unknown RATE_LIMIT_STORE ", os.Getenv("RATE_LIMIT_STORE"), ", using the in-memory store")
+		return NewMemoryRateLimitStore()
+	}
+}
+
+// RegisterRateLimitPurgeHooks removes the idle buckets of the shared store
+// periodically; the in-memory store removes its own
+func RegisterRateLimitPurgeHooks(lifecycle fx.Lifecycle, store RateLimitStore, logger lib.Logger) {
+	postgres, ok := store.(*PostgresRateLimitStore)
+	if !ok {
+		return
+	}
+
+	lib.NewWorker(rateLimitPurgeInterval, func(ctx context.Context) {
+		purged, err := postgres.PurgeIdle(ctx)
+		if err != nil {
+			logger.Error("Failed to purge idle rate limit buckets:", err)
+			return
+		}
+		if purged > 0 {
+			logger.Info(fmt.Sprintf("Purged %d idle rate limit buckets", purged))
+		}
+	}).Start(lifecycle)
+}
@@ -0,0 +1,75 @@
+package middlewares
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+	"hostflow/booking-service/pkg/lib"
+)
+
+// ======== TYPES ========
+
+// RequestIDKey is the gin context key the request id is stored under
+const RequestIDKey = "request_id"
+
+// RequestIDHeader is the header a request id is read from and returned in
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds request ids supplied by clients
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware assigns every request an id, so that logs and audit
+// entries can be correlated with it
+type RequestIDMiddleware struct {
+	router *lib.Router
+	logger lib.Logger
+}
+
+// ======== PUBLIC METHODS ========
+
+// GetRequestIDMiddleware returns the request id middleware
+func GetRequestIDMiddleware(router *lib.Router, logger lib.Logger) RequestIDMiddleware {
+	return RequestIDMiddleware{
+		router: router,
+		logger: logger,
+	}
+}
+
+// Setup sets up the request id middleware. A well-formed X-Request-ID sent
+// by a proxy or client is kept, otherwise a new id is generated.
+func (m RequestIDMiddleware) Setup() {
+	m.logger.Info("Setting up [REQUEST ID] middleware")
+	m.router.Use(func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+
+		c.Set(RequestIDKey, id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	})
+}
+
+// ======== PRIVATE METHODS ========
+
+// validRequestID reports whether a client-supplied id is safe to log and store
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns a random 128-bit id
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
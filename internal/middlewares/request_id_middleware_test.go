@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func doRequestIDRequest(header string) (*httptest.ResponseRecorder, string) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	GetRequestIDMiddleware(r, nopLogger{}).Setup()
+
+	var seen string
+	r.GET("/", func(c *gin.Context) {
+		seen = c.GetString(RequestIDKey)
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if header != "" {
+		req.Header.Set(RequestIDHeader, header)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w, seen
+}
+
+func TestRequestIDMiddleware_KeepsClientID(t *testing.T) {
+	w, seen := doRequestIDRequest("req-123")
+
+	assert.Equal(t, "req-123", seen)
+	assert.Equal(t, "req-123", w.Header().Get(RequestIDHeader))
+}
+
+func TestRequestIDMiddleware_GeneratesID(t *testing.T) {
+	for _, header := range []string{"", "has spaces", strings.Repeat("a", maxRequestIDLength+1)} {
+		w, seen := doRequestIDRequest(header)
+
+		assert.Len(t, seen, 32)
+		assert.Equal(t, seen, w.Header().Get(RequestIDHeader))
+	}
+}
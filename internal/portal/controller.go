@@ -0,0 +1,327 @@
+package portal
+
+import (
+	"errors"
+	"hostflow/booking-service/internal/audit"
+	"hostflow/booking-service/internal/guest"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sessionKey is the gin context key the portal session is stored under
+const sessionKey = "portal_session"
+
+// PinHeader carries the PIN of a PIN-protected link
+const PinHeader = "X-Guest-Pin"
+
+// Controller handles HTTP requests for portal links and the guest portal
+type Controller struct {
+	service *Service
+}
+
+// NewController returns a Controller
+func NewController(service *Service) *Controller {
+	return &Controller{
+		service: service,
+	}
+}
+
+func (c *Controller) getOrgID(ctx *gin.Context) (int64, bool) {
+	val, exists := ctx.Get("organization_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return 0, false
+	}
+	return val.(int64), true
+}
+
+func (c *Controller) getID(ctx *gin.Context, param string) (int64, bool) {
+	id, err := strconv.ParseInt(ctx.Param(param), 10, 64)
+	if err != nil || id <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return 0, false
+	}
+	return id, true
+}
+
+// ======== STAFF ========
+
+// CreateLinkHandler godoc
+// @Summary Create a guest portal link
+// @Description Issues a signed magic link to the guest portal of the reservation, valid until the day after check-out unless expires_at is given (at most 90 days). With a PIN, guests must also send it in the X-Guest-Pin header; the PIN is stored hashed. The token and URL are only returned once.
+// @Tags guest-portal
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Reservation ID"
+// @Param request body LinkRequest false "Link options"
+// @Success 201 {object} IssuedLink
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /reservations/{id}/portal-links [post]
+func (c *Controller) CreateLinkHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+	reservationID, ok := c.getID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var req LinkRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	link, err := c.service.CreateLink(reservationID, orgID, req, audit.ActorFromContext(ctx))
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, link)
+}
+
+// GetLinksHandler godoc
+// @Summary Get the guest portal links of a reservation
+// @Description Returns the links issued for the reservation, newest first, without their tokens
+// @Tags guest-portal
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Reservation ID"
+// @Success 200 {array} Link
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /reservations/{id}/portal-links [get]
+func (c *Controller) GetLinksHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+	reservationID, ok := c.getID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	links, err := c.service.GetLinks(reservationID, orgID)
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, links)
+}
+
+// RevokeLinkHandler godoc
+// @Summary Revoke a guest portal link
+// @Tags guest-portal
+// @Security ApiKeyAuth
+// @Param id path int true "Reservation ID"
+// @Param linkId path int true "Link ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} ErrorResponse
+// @Router /reservations/{id}/portal-links/{linkId} [delete]
+func (c *Controller) RevokeLinkHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+	reservationID, ok := c.getID(ctx, "id")
+	if !ok {
+		return
+	}
+	linkID, ok := c.getID(ctx, "linkId")
+	if !ok {
+		return
+	}
+
+	if err := c.service.RevokeLink(linkID, reservationID, orgID, audit.ActorFromContext(ctx)); err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ======== GUESTS ========
+
+// RequireLink authenticates guest portal requests with the link token in the
+// Authorization header ("Bearer <token>") and, for PIN-protected links, the
+// PIN in the X-Guest-Pin header
+func (c *Controller) RequireLink() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, found := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+		if !found || token == "" {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Error: ErrInvalidToken.Error()})
+			return
+		}
+
+		session, err := c.service.Authenticate(strings.TrimSpace(token), ctx.GetHeader(PinHeader))
+		if err != nil {
+			c.fail(ctx, err)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Set(sessionKey, session)
+		ctx.Next()
+	}
+}
+
+// GetReservationHandler godoc
+// @Summary View the reservation
+// @Description Returns the reservation of the portal link. Encrypted guest data is redacted.
+// @Tags guest-portal
+// @Produce json
+// @Security GuestPortalToken
+// @Param X-Guest-Pin header string false "PIN of a PIN-protected link"
+// @Success 200 {object} View
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /portal/reservation [get]
+func (c *Controller) GetReservationHandler(ctx *gin.Context) {
+	session := c.session(ctx)
+
+	view, err := c.service.View(session, audit.GuestActor(ctx, session.LinkID))
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, view)
+}
+
+// RegisterGuestsHandler godoc
+// @Summary Register the guests
+// @Description Replaces the registered guests of the reservation. The guests are validated against the required fields of their nationality and their number must equal no_of_guests. Fields returned redacted may be sent back as they are to keep them.
+// @Tags guest-portal
+// @Accept json
+// @Produce json
+// @Security GuestPortalToken
+// @Param X-Guest-Pin header string false "PIN of a PIN-protected link"
+// @Param request body GuestsRequest true "Guests"
+// @Success 200 {object} View
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /portal/reservation/guests [put]
+func (c *Controller) RegisterGuestsHandler(ctx *gin.Context) {
+	session := c.session(ctx)
+
+	var req GuestsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	view, err := c.service.RegisterGuests(session, req.Guests, audit.GuestActor(ctx, session.LinkID))
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, view)
+}
+
+// UpdateArrivalHandler godoc
+// @Summary Set the arrival time and special requests
+// @Description Stores the expected arrival time (HH:MM) and special requests in the additional requests of the reservation. Empty values clear them.
+// @Tags guest-portal
+// @Accept json
+// @Produce json
+// @Security GuestPortalToken
+// @Param X-Guest-Pin header string false "PIN of a PIN-protected link"
+// @Param request body ArrivalRequest true "Arrival details"
+// @Success 200 {object} View
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /portal/reservation/arrival [put]
+func (c *Controller) UpdateArrivalHandler(ctx *gin.Context) {
+	session := c.session(ctx)
+
+	var req ArrivalRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	view, err := c.service.UpdateArrival(session, req, audit.GuestActor(ctx, session.LinkID))
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, view)
+}
+
+// AcceptHouseRulesHandler godoc
+// @Summary Accept the house rules
+// @Description Records when the guest accepted the house rules, and optionally their version, in the additional requests of the reservation
+// @Tags guest-portal
+// @Accept json
+// @Produce json
+// @Security GuestPortalToken
+// @Param X-Guest-Pin header string false "PIN of a PIN-protected link"
+// @Param request body HouseRulesRequest true "Acceptance"
+// @Success 200 {object} View
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /portal/reservation/house-rules [post]
+func (c *Controller) AcceptHouseRulesHandler(ctx *gin.Context) {
+	session := c.session(ctx)
+
+	var req HouseRulesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	view, err := c.service.AcceptHouseRules(session, req, audit.GuestActor(ctx, session.LinkID))
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, view)
+}
+
+// ======== PRIVATE METHODS ========
+
+// session returns the session set by RequireLink
+func (c *Controller) session(ctx *gin.Context) *Session {
+	return ctx.MustGet(sessionKey).(*Session)
+}
+
+// fail responds with the status of a service error
+func (c *Controller) fail(ctx *gin.Context, err error) {
+	var invalid *guest.ValidationError
+	switch {
+	case errors.As(err, &invalid):
+		ctx.JSON(http.StatusBadRequest, gin.H{"errors": invalid.Errors})
+	case errors.Is(err, ErrInvalidToken), errors.Is(err, ErrPinRequired), errors.Is(err, ErrInvalidPin):
+		ctx.JSON(http.StatusUnauthorized, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, ErrLinkLocked):
+		ctx.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, ErrReservationNotFound), errors.Is(err, ErrLinkNotFound):
+		ctx.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, ErrReservationClosed):
+		ctx.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, ErrInvalidExpiry):
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, ErrNotConfigured):
+		ctx.JSON(http.StatusServiceUnavailable, ErrorResponse{Error: err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
@@ -0,0 +1,196 @@
+package portal
+
+import (
+	"errors"
+	"hostflow/booking-service/internal/guest"
+	"time"
+)
+
+// Reservation statuses the portal cares about
+const (
+	statusPaymentRequired = "PAYMENT_REQUIRED"
+	statusCancelled       = "CANCELLED"
+	statusRejected        = "REJECTED"
+	statusCompleted       = "COMPLETED"
+	statusNoShow          = "NO_SHOW"
+)
+
+// Audit log actions of the guest portal, recorded on the reservation
+const (
+	actionLinkCreated        = "portal_link_created"
+	actionLinkRevoked        = "portal_link_revoked"
+	actionViewed             = "portal_viewed"
+	actionGuestsRegistered   = "guests_registered"
+	actionArrivalUpdated     = "arrival_updated"
+	actionHouseRulesAccepted = "house_rules_accepted"
+)
+
+// Keys of additional_requests written by the portal
+const (
+	keyArrivalTime          = "arrival_time"
+	keySpecialRequests      = "special_requests"
+	keyHouseRulesAcceptedAt = "house_rules_accepted_at"
+	keyHouseRulesVersion    = "house_rules_version"
+)
+
+// maxPinAttempts is how many wrong PINs lock a link
+const maxPinAttempts = 5
+
+// maxLinkLifetime bounds how long a link can be valid
+const maxLinkLifetime = 90 * 24 * time.Hour
+
+// linkColumns are the columns scanned into Link
+const linkColumns = `
+    id, organization_id, reservation_id, pin_hash, failed_pin_attempts,
+    expires_at, revoked_at, last_used_at, created_by, created_at
+`
+
+// reservationColumns are the columns scanned into Reservation
+const reservationColumns = `
+    id, organization_id, property_id, check_in_date, check_out_date, status,
+    total_price, payment_url, price_elements, no_of_guests, guest_data,
+    additional_requests
+`
+
+// Errors returned by the portal service
+var (
+	ErrNotConfigured       = errors.New("the guest portal is not configured")
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrLinkNotFound        = errors.New("portal link not found")
+	ErrInvalidToken        = errors.New("invalid or expired portal link")
+	ErrPinRequired         = errors.New("a PIN is required for this portal link")
+	ErrInvalidPin          = errors.New("invalid PIN")
+	ErrLinkLocked          = errors.New("the portal link is locked after too many wrong PINs")
+	ErrReservationClosed   = errors.New("the reservation can no longer be changed")
+	ErrInvalidExpiry       = errors.New("expires_at must be in the future and within 90 days")
+)
+
+// Link is a magic link giving a guest access to the portal of a reservation
+type Link struct {
+	ID                int64      `json:"id" db:"id"`
+	OrganizationID    int64      `json:"organization_id" db:"organization_id"`
+	ReservationID     int64      `json:"reservation_id" db:"reservation_id"`
+	PinHash           *string    `json:"-" db:"pin_hash"`
+	FailedPinAttempts int        `json:"failed_pin_attempts" db:"failed_pin_attempts"`
+	ExpiresAt         time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt         *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	LastUsedAt        *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	CreatedBy         string     `json:"created_by" db:"created_by" example:"user:7d1c3f5e-5b8e-4a43-9d0b-2f6b1f0c9a11"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	PinProtected      bool       `json:"pin_protected" db:"-"`
+}
+
+// usable reports whether the link may still be used
+func (l *Link) usable(now time.Time) bool {
+	return l.RevokedAt == nil && now.Before(l.ExpiresAt)
+}
+
+// LinkRequest is the body of a request for a new portal link
+type LinkRequest struct {
+	// ExpiresAt defaults to the day after check-out
+	ExpiresAt *time.Time `json:"expires_at" example:"2026-08-15T12:00:00Z"`
+	// Pin optionally protects the link with 4 to 8 digits, which must be
+	// shared with the guest separately
+	Pin string `json:"pin" binding:"omitempty,numeric,min=4,max=8" example:"482913"`
+}
+
+// IssuedLink is a new portal link. The token and URL are only returned once.
+type IssuedLink struct {
+	Link
+	Token string `json:"token" example:"42.1786788000.kq3V0dG9r..."`
+	URL   string `json:"url,omitempty" example:"https://guest.hostflow.app/check-in?token=42.1786788000.kq3V0dG9r..."`
+}
+
+// Reservation is the part of a reservation the portal reads and writes
+type Reservation struct {
+	ID                 int64                  `db:"id"`
+	OrganizationID     int64                  `db:"organization_id"`
+	PropertyID         int64                  `db:"property_id"`
+	CheckInDate        time.Time              `db:"check_in_date"`
+	CheckOutDate       time.Time              `db:"check_out_date"`
+	Status             string                 `db:"status"`
+	TotalPrice         float64                `db:"total_price"`
+	PaymentURL         string                 `db:"payment_url"`
+	PriceElements      map[string]interface{} `db:"price_elements"`
+	NoOfGuests         int                    `db:"no_of_guests"`
+	GuestData          map[string]interface{} `db:"guest_data"`
+	AdditionalRequests map[string]interface{} `db:"additional_requests"`
+}
+
+// details are the fields of a reservation guests can change, directly or
+// through the tourist tax of the guests they register, as compared in the
+// audit log
+type details struct {
+	GuestData          map[string]interface{} `json:"guest_data"`
+	AdditionalRequests map[string]interface{} `json:"additional_requests"`
+	PriceElements      map[string]interface{} `json:"price_elements"`
+	TotalPrice         float64                `json:"total_price"`
+}
+
+// details returns the fields of the reservation guests can change
+func (r *Reservation) details() details {
+	return details{
+		GuestData:          r.GuestData,
+		AdditionalRequests: r.AdditionalRequests,
+		PriceElements:      r.PriceElements,
+		TotalPrice:         r.TotalPrice,
+	}
+}
+
+// closed reports whether guests can no longer change the reservation
+func (r *Reservation) closed() bool {
+	switch r.Status {
+	case statusCancelled, statusRejected, statusCompleted, statusNoShow:
+		return true
+	}
+	return false
+}
+
+// Session is an authenticated use of a portal link
+type Session struct {
+	LinkID         int64
+	OrganizationID int64
+	ReservationID  int64
+}
+
+// View is the reservation as shown to the guest. Encrypted guest data is
+// redacted; redacted values sent back are kept as stored.
+type View struct {
+	ID                   int64         `json:"id" example:"42"`
+	PropertyID           int64         `json:"property_id" example:"10"`
+	CheckInDate          time.Time     `json:"check_in_date" example:"2026-08-10T15:00:00Z"`
+	CheckOutDate         time.Time     `json:"check_out_date" example:"2026-08-14T10:00:00Z"`
+	Status               string        `json:"status" example:"CONFIRMED"`
+	TotalPrice           float64       `json:"total_price" example:"480.00"`
+	PaymentURL           string        `json:"payment_url,omitempty"`
+	NoOfGuests           int           `json:"no_of_guests" example:"2"`
+	Guests               []guest.Guest `json:"guests"`
+	ArrivalTime          string        `json:"arrival_time,omitempty" example:"16:30"`
+	SpecialRequests      string        `json:"special_requests,omitempty" example:"Baby cot, please"`
+	HouseRulesAcceptedAt *time.Time    `json:"house_rules_accepted_at,omitempty"`
+	CanEdit              bool          `json:"can_edit" example:"true"`
+}
+
+// GuestsRequest is the body of a guest registration. It replaces the
+// registered guests.
+type GuestsRequest struct {
+	Guests []guest.Guest `json:"guests" binding:"required,min=1"`
+}
+
+// ArrivalRequest is the body of an arrival update
+type ArrivalRequest struct {
+	ArrivalTime     string `json:"arrival_time" binding:"omitempty,datetime=15:04" example:"16:30"`
+	SpecialRequests string `json:"special_requests" binding:"max=2000" example:"Baby cot, please"`
+}
+
+// HouseRulesRequest is the body of a house rules acceptance
+type HouseRulesRequest struct {
+	Accepted bool `json:"accepted" binding:"required" example:"true"`
+	// Version optionally identifies the accepted house rules
+	Version string `json:"version" binding:"max=100" example:"2026-01"`
+}
+
+// ErrorResponse is the body of an error response
+type ErrorResponse struct {
+	Error string `json:"error" example:"invalid or expired portal link"`
+}
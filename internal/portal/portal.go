@@ -0,0 +1,12 @@
+package portal
+
+import (
+	"go.uber.org/fx"
+)
+
+// ======== EXPORTS ========
+
+// Module exports the guest portal
+var Module = fx.Options(
+	fx.Provide(NewRepository, NewService, NewController, SetRoutes),
+)
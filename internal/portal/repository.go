@@ -0,0 +1,215 @@
+package portal
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// dbtx is satisfied by both the pool and a transaction
+type dbtx interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// Repository persists portal links and reads and updates the guest facing
+// fields of reservations
+type Repository struct {
+	db dbtx
+}
+
+// NewRepository returns a Repository
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+// InTx runs fn with a repository bound to a new transaction, which is
+// committed if fn returns nil and rolled back otherwise
+func (r *Repository) InTx(fn func(tx *Repository) error) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(&Repository{db: tx}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// CreateLink stores a new link
+func (r *Repository) CreateLink(link *Link) (*Link, error) {
+	query := `
+        INSERT INTO guest_portal_link (organization_id, reservation_id, pin_hash, expires_at, created_by)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING ` + linkColumns
+
+	rows, err := r.db.Query(context.Background(), query,
+		link.OrganizationID,
+		link.ReservationID,
+		link.PinHash,
+		link.ExpiresAt,
+		link.CreatedBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectLink(rows)
+}
+
+// GetLink returns a link by id, locked for the rest of the transaction so
+// that concurrent wrong PINs are all counted
+func (r *Repository) GetLink(id int64) (*Link, error) {
+	query := `SELECT ` + linkColumns + `
+        FROM guest_portal_link
+        WHERE id = $1
+        FOR UPDATE
+    `
+
+	rows, err := r.db.Query(context.Background(), query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectLink(rows)
+}
+
+// GetLinks returns the links of a reservation, newest first
+func (r *Repository) GetLinks(reservationID, organizationID int64) ([]Link, error) {
+	query := `SELECT ` + linkColumns + `
+        FROM guest_portal_link
+        WHERE reservation_id = $1
+          AND organization_id = $2
+        ORDER BY created_at DESC
+    `
+
+	rows, err := r.db.Query(context.Background(), query, reservationID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links, err := pgx.CollectRows(rows, pgx.RowToStructByName[Link])
+	if err != nil {
+		return nil, err
+	}
+	for i := range links {
+		links[i].PinProtected = links[i].PinHash != nil
+	}
+	return links, nil
+}
+
+// RevokeLink revokes a link of a reservation. It returns nil if the link
+// doesn't exist or was already revoked.
+func (r *Repository) RevokeLink(id, reservationID, organizationID int64) (*Link, error) {
+	query := `
+        UPDATE guest_portal_link
+        SET revoked_at = NOW()
+        WHERE id = $1
+          AND reservation_id = $2
+          AND organization_id = $3
+          AND revoked_at IS NULL
+        RETURNING ` + linkColumns
+
+	rows, err := r.db.Query(context.Background(), query, id, reservationID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectLink(rows)
+}
+
+// RecordPinFailure counts a wrong PIN
+func (r *Repository) RecordPinFailure(id int64) error {
+	_, err := r.db.Exec(context.Background(), `
+        UPDATE guest_portal_link
+        SET failed_pin_attempts = failed_pin_attempts + 1
+        WHERE id = $1
+    `, id)
+	return err
+}
+
+// TouchLink records a successful use of a link
+func (r *Repository) TouchLink(id int64) error {
+	_, err := r.db.Exec(context.Background(), `
+        UPDATE guest_portal_link
+        SET last_used_at = NOW(),
+            failed_pin_attempts = 0
+        WHERE id = $1
+    `, id)
+	return err
+}
+
+// GetReservation returns a reservation of the organization that is not
+// deleted. With forUpdate, the reservation is locked for the rest of the
+// transaction.
+func (r *Repository) GetReservation(id, organizationID int64, forUpdate bool) (*Reservation, error) {
+	query := `SELECT ` + reservationColumns + `
+        FROM reservation
+        WHERE id = $1
+          AND organization_id = $2
+          AND deleted_at IS NULL
+    `
+	if forUpdate {
+		query += ` FOR UPDATE`
+	}
+
+	rows, err := r.db.Query(context.Background(), query, id, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[Reservation])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &reservation, nil
+}
+
+// UpdateDetails stores the guest data, additional requests and price of a
+// reservation
+func (r *Repository) UpdateDetails(reservation *Reservation) error {
+	_, err := r.db.Exec(context.Background(), `
+        UPDATE reservation
+        SET guest_data = $2,
+            additional_requests = $3,
+            price_elements = $4,
+            total_price = $5,
+            update_at = $6
+        WHERE id = $1
+          AND deleted_at IS NULL
+    `, reservation.ID, reservation.GuestData, reservation.AdditionalRequests,
+		reservation.PriceElements, reservation.TotalPrice, time.Now())
+	return err
+}
+
+// collectLink scans a single link, or returns nil if there is none
+func collectLink(rows pgx.Rows) (*Link, error) {
+	link, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[Link])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	link.PinProtected = link.PinHash != nil
+	return &link, nil
+}
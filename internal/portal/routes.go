@@ -0,0 +1,56 @@
+package portal
+
+import (
+	"hostflow/booking-service/internal/middlewares"
+	"hostflow/booking-service/pkg/lib"
+)
+
+// Routes struct
+type Routes struct {
+	logger              lib.Logger
+	router              *lib.Router
+	controller          *Controller
+	authMiddleware      middlewares.AuthMiddleware
+	rateLimitMiddleware middlewares.RateLimitMiddleware
+}
+
+// SetRoutes returns a Routes struct
+func SetRoutes(
+	logger lib.Logger,
+	router *lib.Router,
+	controller *Controller,
+	authMiddleware middlewares.AuthMiddleware,
+	rateLimitMiddleware middlewares.RateLimitMiddleware,
+) Routes {
+	return Routes{
+		logger:              logger,
+		router:              router,
+		controller:          controller,
+		authMiddleware:      authMiddleware,
+		rateLimitMiddleware: rateLimitMiddleware,
+	}
+}
+
+// Setup registers the portal link routes for staff, and the guest portal
+// routes, which are authenticated with a link token instead of a JWT or API
+// key and limited per client IP
+func (route Routes) Setup() {
+	route.logger.Info("Setting up [GUEST PORTAL] routes.")
+
+	links := route.router.Group("/reservations")
+	links.Use(route.authMiddleware.Handler())
+	{
+		links.POST("/:id/portal-links", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), middlewares.RequirePermission(middlewares.ReservationsUpdate), route.controller.CreateLinkHandler)
+		links.GET("/:id/portal-links", route.rateLimitMiddleware.Limit(middlewares.BudgetDefault), middlewares.RequirePermission(middlewares.ReservationsRead), route.controller.GetLinksHandler)
+		links.DELETE("/:id/portal-links/:linkId", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), middlewares.RequirePermission(middlewares.ReservationsUpdate), route.controller.RevokeLinkHandler)
+	}
+
+	portal := route.router.Group("/portal")
+	portal.Use(route.rateLimitMiddleware.Limit(middlewares.BudgetPortal), route.controller.RequireLink())
+	{
+		portal.GET("/reservation", route.controller.GetReservationHandler)
+		portal.PUT("/reservation/guests", route.controller.RegisterGuestsHandler)
+		portal.PUT("/reservation/arrival", route.controller.UpdateArrivalHandler)
+		portal.POST("/reservation/house-rules", route.controller.AcceptHouseRulesHandler)
+	}
+}
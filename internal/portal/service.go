@@ -0,0 +1,412 @@
+package portal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hostflow/booking-service/internal/audit"
+	"hostflow/booking-service/internal/encryption"
+	"hostflow/booking-service/internal/guest"
+	"hostflow/booking-service/internal/touristtax"
+	"hostflow/booking-service/pkg/common"
+	"hostflow/booking-service/pkg/lib"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// minSecretLength is the minimum length of GUEST_PORTAL_SECRET
+const minSecretLength = 32
+
+// Service issues portal links and handles what guests do through them
+type Service struct {
+	repo       *Repository
+	audit      *audit.Repository
+	guestData  *encryption.FieldEncryptor
+	rules      *guest.Rules
+	touristTax *touristtax.Service
+	signer     *Signer
+	baseURL    string
+	logger     lib.Logger
+	now        func() time.Time
+}
+
+// NewService returns a Service. Tokens are signed with GUEST_PORTAL_SECRET;
+// without it, the portal is disabled. Links point to GUEST_PORTAL_URL.
+func NewService(
+	repo *Repository,
+	auditLog *audit.Repository,
+	guestData *encryption.FieldEncryptor,
+	rules *guest.Rules,
+	touristTax *touristtax.Service,
+	logger lib.Logger,
+) (*Service, error) {
+	s := &Service{
+		repo:       repo,
+		audit:      auditLog,
+		guestData:  guestData,
+		rules:      rules,
+		touristTax: touristTax,
+		baseURL:    os.Getenv("GUEST_PORTAL_URL"),
+		logger:     logger,
+		now:        time.Now,
+	}
+
+	secret := os.Getenv("GUEST_PORTAL_SECRET")
+	switch {
+	case secret == "":
+		logger.Info("GUEST_PORTAL_SECRET is not set, the guest portal is disabled")
+	case len(secret) < minSecretLength:
+		return nil, errors.New("GUEST_PORTAL_SECRET must be at least 32 characters long")
+	default:
+		s.signer = NewSigner([]byte(secret))
+	}
+
+	return s, nil
+}
+
+// ======== LINKS ========
+
+// CreateLink issues a new link to the portal of a reservation, optionally
+// protected with a PIN
+func (s *Service) CreateLink(reservationID, organizationID int64, req LinkRequest, actor audit.Actor) (*IssuedLink, error) {
+	if s.signer == nil {
+		return nil, ErrNotConfigured
+	}
+
+	reservation, err := s.repo.GetReservation(reservationID, organizationID, false)
+	if err != nil {
+		return nil, err
+	}
+	if reservation == nil {
+		return nil, ErrReservationNotFound
+	}
+
+	now := s.now()
+	expiresAt := reservation.CheckOutDate.Add(24 * time.Hour)
+	if req.ExpiresAt != nil {
+		expiresAt = *req.ExpiresAt
+	}
+	if !expiresAt.After(now) || expiresAt.Sub(now) > maxLinkLifetime {
+		return nil, ErrInvalidExpiry
+	}
+
+	link := &Link{
+		OrganizationID: organizationID,
+		ReservationID:  reservationID,
+		ExpiresAt:      expiresAt.UTC().Truncate(time.Second),
+		CreatedBy:      actor.Type + ":" + actor.ID,
+	}
+	if req.Pin != "" {
+		hash, err := common.Hasher.Hash(req.Pin)
+		if err != nil {
+			return nil, err
+		}
+		link.PinHash = &hash
+	}
+
+	var created *Link
+	err = s.repo.InTx(func(tx *Repository) error {
+		var err error
+		created, err = tx.CreateLink(link)
+		if err != nil {
+			return err
+		}
+		return s.record(tx, actor, actionLinkCreated, reservation, nil, map[string]interface{}{"portal_link": created})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	issued := &IssuedLink{
+		Link:  *created,
+		Token: s.signer.Sign(created.ID, created.ExpiresAt),
+	}
+	if s.baseURL != "" {
+		issued.URL = s.baseURL + "?token=" + url.QueryEscape(issued.Token)
+	}
+	return issued, nil
+}
+
+// GetLinks returns the links of a reservation, newest first
+func (s *Service) GetLinks(reservationID, organizationID int64) ([]Link, error) {
+	return s.repo.GetLinks(reservationID, organizationID)
+}
+
+// RevokeLink revokes a link before it expires
+func (s *Service) RevokeLink(linkID, reservationID, organizationID int64, actor audit.Actor) error {
+	return s.repo.InTx(func(tx *Repository) error {
+		revoked, err := tx.RevokeLink(linkID, reservationID, organizationID)
+		if err != nil {
+			return err
+		}
+		if revoked == nil {
+			return ErrLinkNotFound
+		}
+
+		reservation := &Reservation{ID: reservationID, OrganizationID: organizationID}
+		return s.record(tx, actor, actionLinkRevoked, reservation, nil, map[string]interface{}{"portal_link": revoked})
+	})
+}
+
+// ======== GUESTS ========
+
+// Authenticate checks a token, and the PIN if the link has one. Wrong PINs
+// are counted, and lock the link after maxPinAttempts.
+func (s *Service) Authenticate(token, pin string) (*Session, error) {
+	if s.signer == nil {
+		return nil, ErrNotConfigured
+	}
+
+	now := s.now()
+	linkID, err := s.signer.Verify(token, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var session *Session
+	var denied error
+	err = s.repo.InTx(func(tx *Repository) error {
+		link, err := tx.GetLink(linkID)
+		if err != nil {
+			return err
+		}
+		if link == nil || !link.usable(now) {
+			denied = ErrInvalidToken
+			return nil
+		}
+
+		if link.PinHash != nil {
+			if link.FailedPinAttempts >= maxPinAttempts {
+				denied = ErrLinkLocked
+				return nil
+			}
+			if pin == "" {
+				denied = ErrPinRequired
+				return nil
+			}
+			matches, err := common.Hasher.Compare(pin, *link.PinHash)
+			if err != nil {
+				return err
+			}
+			if !matches {
+				// The failure is committed, unlike an error
+				denied = ErrInvalidPin
+				return tx.RecordPinFailure(link.ID)
+			}
+		}
+
+		session = &Session{
+			LinkID:         link.ID,
+			OrganizationID: link.OrganizationID,
+			ReservationID:  link.ReservationID,
+		}
+		return tx.TouchLink(link.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if denied != nil {
+		return nil, denied
+	}
+
+	return session, nil
+}
+
+// View returns the reservation of the session as shown to the guest
+func (s *Service) View(session *Session, actor audit.Actor) (*View, error) {
+	reservation, err := s.repo.GetReservation(session.ReservationID, session.OrganizationID, false)
+	if err != nil {
+		return nil, err
+	}
+	if reservation == nil {
+		return nil, ErrReservationNotFound
+	}
+
+	if err := s.record(s.repo, actor, actionViewed, reservation, nil, nil); err != nil {
+		return nil, err
+	}
+
+	return s.view(reservation), nil
+}
+
+// RegisterGuests replaces the registered guests of the reservation. Values
+// sent back redacted keep their stored value. The tourist tax is
+// recalculated from the ages of the guests.
+func (s *Service) RegisterGuests(session *Session, guests []guest.Guest, actor audit.Actor) (*View, error) {
+	return s.update(session, actor, actionGuestsRegistered, func(reservation *Reservation, before details) error {
+		if reservation.GuestData == nil {
+			reservation.GuestData = make(map[string]interface{})
+		}
+		if err := guest.SetGuests(reservation.GuestData, guests); err != nil {
+			return err
+		}
+		if err := s.rules.Validate(reservation.GuestData, reservation.NoOfGuests); err != nil {
+			return err
+		}
+		if err := s.guestData.Encrypt(context.Background(), reservation.GuestData, before.GuestData); err != nil {
+			return err
+		}
+
+		if reservation.PriceElements == nil {
+			reservation.PriceElements = make(map[string]interface{})
+		}
+		stay := touristtax.Stay{
+			OrganizationID: reservation.OrganizationID,
+			PropertyID:     reservation.PropertyID,
+			CheckIn:        reservation.CheckInDate,
+			CheckOut:       reservation.CheckOutDate,
+			NoOfGuests:     reservation.NoOfGuests,
+			GuestData:      reservation.GuestData,
+		}
+		total, err := s.touristTax.Apply(stay, reservation.PriceElements, reservation.TotalPrice)
+		if err != nil {
+			return err
+		}
+		reservation.TotalPrice = total
+		return nil
+	})
+}
+
+// UpdateArrival sets the expected arrival time and the special requests of
+// the guest. Empty values clear them.
+func (s *Service) UpdateArrival(session *Session, req ArrivalRequest, actor audit.Actor) (*View, error) {
+	return s.update(session, actor, actionArrivalUpdated, func(reservation *Reservation, _ details) error {
+		setOrDelete(reservation.AdditionalRequests, keyArrivalTime, req.ArrivalTime)
+		setOrDelete(reservation.AdditionalRequests, keySpecialRequests, req.SpecialRequests)
+		return nil
+	})
+}
+
+// AcceptHouseRules records that the guest accepted the house rules
+func (s *Service) AcceptHouseRules(session *Session, req HouseRulesRequest, actor audit.Actor) (*View, error) {
+	return s.update(session, actor, actionHouseRulesAccepted, func(reservation *Reservation, _ details) error {
+		reservation.AdditionalRequests[keyHouseRulesAcceptedAt] = s.now().UTC().Format(time.RFC3339)
+		setOrDelete(reservation.AdditionalRequests, keyHouseRulesVersion, req.Version)
+		return nil
+	})
+}
+
+// ======== PRIVATE METHODS ========
+
+// update changes the reservation of the session with fn and records the
+// change, in a single transaction
+func (s *Service) update(session *Session, actor audit.Actor, action string, fn func(reservation *Reservation, before details) error) (*View, error) {
+	var updated *Reservation
+	err := s.repo.InTx(func(tx *Repository) error {
+		reservation, err := tx.GetReservation(session.ReservationID, session.OrganizationID, true)
+		if err != nil {
+			return err
+		}
+		if reservation == nil {
+			return ErrReservationNotFound
+		}
+		if reservation.closed() {
+			return ErrReservationClosed
+		}
+
+		before, err := clone(reservation.details())
+		if err != nil {
+			return err
+		}
+		if reservation.AdditionalRequests == nil {
+			reservation.AdditionalRequests = make(map[string]interface{})
+		}
+
+		if err := fn(reservation, before); err != nil {
+			return err
+		}
+		if err := tx.UpdateDetails(reservation); err != nil {
+			return err
+		}
+
+		updated = reservation
+		return s.record(tx, actor, action, reservation, before, reservation.details())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.view(updated), nil
+}
+
+// record writes an audit entry on the reservation
+func (s *Service) record(repo *Repository, actor audit.Actor, action string, reservation *Reservation, before, after interface{}) error {
+	entry, err := audit.NewEntry(actor, reservation.OrganizationID, action, audit.EntityReservation,
+		strconv.FormatInt(reservation.ID, 10), before, after)
+	if err != nil {
+		return err
+	}
+	return s.audit.Record(repo.db, entry)
+}
+
+// view converts a reservation for the guest. Encrypted guest data is
+// redacted: a link may be forwarded, so it never reveals documents.
+func (s *Service) view(reservation *Reservation) *View {
+	v := &View{
+		ID:           reservation.ID,
+		PropertyID:   reservation.PropertyID,
+		CheckInDate:  reservation.CheckInDate,
+		CheckOutDate: reservation.CheckOutDate,
+		Status:       reservation.Status,
+		TotalPrice:   reservation.TotalPrice,
+		NoOfGuests:   reservation.NoOfGuests,
+		Guests:       []guest.Guest{},
+		CanEdit:      !reservation.closed(),
+	}
+	if reservation.Status == statusPaymentRequired {
+		v.PaymentURL = reservation.PaymentURL
+	}
+
+	if reservation.GuestData != nil {
+		guests, err := s.redactedGuests(reservation.GuestData)
+		if err != nil {
+			s.logger.Error(fmt.Sprintf("Failed to read the guests of reservation %d:", reservation.ID), err)
+		} else if guests != nil {
+			v.Guests = guests
+		}
+	}
+
+	v.ArrivalTime, _ = reservation.AdditionalRequests[keyArrivalTime].(string)
+	v.SpecialRequests, _ = reservation.AdditionalRequests[keySpecialRequests].(string)
+	if accepted, ok := reservation.AdditionalRequests[keyHouseRulesAcceptedAt].(string); ok {
+		if at, err := time.Parse(time.RFC3339, accepted); err == nil {
+			v.HouseRulesAcceptedAt = &at
+		}
+	}
+
+	return v
+}
+
+// redactedGuests returns the registered guests of guest data with its
+// encrypted values redacted
+func (s *Service) redactedGuests(guestData map[string]interface{}) ([]guest.Guest, error) {
+	data, err := clone(guestData)
+	if err != nil {
+		return nil, err
+	}
+	s.guestData.Redact(data)
+	return guest.Guests(data)
+}
+
+// clone returns a deep copy of a JSON value
+func clone[T any](value T) (T, error) {
+	var copied T
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return copied, err
+	}
+	err = json.Unmarshal(raw, &copied)
+	return copied, err
+}
+
+// setOrDelete sets a key of a JSON object, or removes it for an empty value
+func setOrDelete(object map[string]interface{}, key, value string) {
+	if value == "" {
+		delete(object, key)
+		return
+	}
+	object[key] = value
+}
@@ -0,0 +1,112 @@
+package portal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hostflow/booking-service/internal/encryption"
+	"hostflow/booking-service/internal/guest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestViewRedactsEncryptedGuestData(t *testing.T) {
+	provider, err := encryption.NewStaticKeyProvider("v1", map[string][]byte{"v1": bytes.Repeat([]byte{1}, 32)})
+	require.NoError(t, err)
+	guestData, err := encryption.NewFieldEncryptor(provider, []string{"guests.*.document_number"})
+	require.NoError(t, err)
+
+	data := map[string]interface{}{}
+	require.NoError(t, guest.SetGuests(data, []guest.Guest{{FirstName: "Ana", DocumentNumber: "PB1234567"}}))
+	require.NoError(t, guestData.Encrypt(context.Background(), data, nil))
+
+	s := &Service{guestData: guestData}
+	view := s.view(&Reservation{
+		ID:         42,
+		Status:     "PAYMENT_REQUIRED",
+		PaymentURL: "https://pay.example/42",
+		GuestData:  data,
+		AdditionalRequests: map[string]interface{}{
+			keyArrivalTime:          "16:30",
+			keyHouseRulesAcceptedAt: "2026-08-01T10:00:00Z",
+		},
+	})
+
+	assert.Equal(t, []guest.Guest{{FirstName: "Ana", DocumentNumber: encryption.Redacted}}, view.Guests)
+	assert.True(t, encryption.IsEncrypted(data["guests"].([]interface{})[0].(map[string]interface{})["document_number"]))
+	assert.Equal(t, "https://pay.example/42", view.PaymentURL)
+	assert.Equal(t, "16:30", view.ArrivalTime)
+	assert.Equal(t, time.Date(2026, 8, 1, 10, 0, 0, 0, time.UTC), view.HouseRulesAcceptedAt.UTC())
+	assert.True(t, view.CanEdit)
+}
+
+func TestViewOfClosedReservation(t *testing.T) {
+	s := &Service{}
+	view := s.view(&Reservation{ID: 42, Status: "CANCELLED", PaymentURL: "https://pay.example/42"})
+
+	assert.False(t, view.CanEdit)
+	assert.Empty(t, view.PaymentURL)
+	assert.Equal(t, []guest.Guest{}, view.Guests)
+}
+
+// errorLogger records the logged errors
+type errorLogger struct {
+	errors []string
+}
+
+func (l *errorLogger) Info(args ...interface{})  {}
+func (l *errorLogger) Fatal(args ...interface{}) {}
+func (l *errorLogger) Error(args ...interface{}) { l.errors = append(l.errors, fmt.Sprint(args...)) }
+
+func TestViewAndRegisterGuests_KeepEncryptedAddress(t *testing.T) {
+	provider, err := encryption.NewStaticKeyProvider("v1", map[string][]byte{"v1": bytes.Repeat([]byte{1}, 32)})
+	require.NoError(t, err)
+	guestData, err := encryption.NewFieldEncryptor(provider, []string{"guests.*.address"})
+	require.NoError(t, err)
+	rules, err := guest.ParseRules("default=first_name,address")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	address := guest.Address{Street: "Slovenska cesta 1", City: "Ljubljana", Country: "SI"}
+	stored := map[string]interface{}{}
+	require.NoError(t, guest.SetGuests(stored, []guest.Guest{{FirstName: "Ana", Address: &address}}))
+	require.NoError(t, guestData.Encrypt(ctx, stored, nil))
+
+	logger := &errorLogger{}
+	s := &Service{guestData: guestData, logger: logger}
+	view := s.view(&Reservation{ID: 42, Status: "CONFIRMED", GuestData: stored})
+
+	require.Empty(t, logger.errors)
+	require.Len(t, view.Guests, 1)
+	assert.Equal(t, "Ana", view.Guests[0].FirstName)
+	assert.Equal(t, guest.RedactedAddress, *view.Guests[0].Address)
+
+	// The guests sent back as shown keep the stored address, as
+	// RegisterGuests stores them
+	resubmitted, err := clone(stored)
+	require.NoError(t, err)
+	require.NoError(t, guest.SetGuests(resubmitted, view.Guests))
+	require.NoError(t, rules.Validate(resubmitted, 1))
+	require.NoError(t, guestData.Encrypt(ctx, resubmitted, stored))
+
+	require.NoError(t, guestData.Decrypt(ctx, resubmitted))
+	guests, err := guest.Guests(resubmitted)
+	require.NoError(t, err)
+	assert.Equal(t, []guest.Guest{{FirstName: "Ana", Address: &address}}, guests)
+}
+
+func TestViewLogsInvalidGuestData(t *testing.T) {
+	logger := &errorLogger{}
+	s := &Service{logger: logger}
+	view := s.view(&Reservation{
+		ID:        42,
+		Status:    "CONFIRMED",
+		GuestData: map[string]interface{}{"guests": "Ana"},
+	})
+
+	assert.Equal(t, []guest.Guest{}, view.Guests)
+	assert.Len(t, logger.errors, 1)
+}
@@ -0,0 +1,40 @@
+package portal
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignerVerify(t *testing.T) {
+	signer := NewSigner([]byte("0123456789abcdef0123456789abcdef"))
+	now := time.Date(2026, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	token := signer.Sign(42, now.Add(time.Hour))
+	linkID, err := signer.Verify(token, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), linkID)
+
+	// Expired
+	_, err = signer.Verify(token, now.Add(2*time.Hour))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	// Another link id or expiry with the same signature
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 3)
+	_, err = signer.Verify("43."+parts[1]+"."+parts[2], now)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = signer.Verify(parts[0]+".9999999999."+parts[2], now)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	// Signed with another secret
+	other := NewSigner([]byte("fedcba9876543210fedcba9876543210"))
+	_, err = other.Verify(token, now)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = signer.Verify("not-a-token", now)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
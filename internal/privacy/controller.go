@@ -0,0 +1,187 @@
+package privacy
+
+import (
+	"errors"
+	"hostflow/booking-service/internal/audit"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultPageSize and maxPageSize bound the requests returned per page
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// Controller handles HTTP requests for GDPR tooling
+type Controller struct {
+	service *Service
+}
+
+// NewController returns a Controller
+func NewController(service *Service) *Controller {
+	return &Controller{
+		service: service,
+	}
+}
+
+func (c *Controller) getOrgID(ctx *gin.Context) (int64, bool) {
+	val, exists := ctx.Get("organization_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return 0, false
+	}
+	return val.(int64), true
+}
+
+func (c *Controller) getID(ctx *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return 0, false
+	}
+	return id, true
+}
+
+// ExportCustomerHandler godoc
+// @Summary Export a customer's data
+// @Description Returns every reservation of the customer, including deleted ones, and the emails sent about them as a JSON bundle. The request is tracked as an EXPORT privacy request; the bundle itself is not stored.
+// @Tags privacy
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Customer ID"
+// @Success 200 {object} ExportBundle
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /privacy/customers/{id}/export [post]
+func (c *Controller) ExportCustomerHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+	customerID, ok := c.getID(ctx)
+	if !ok {
+		return
+	}
+
+	bundle, err := c.service.Export(orgID, customerID, audit.ActorFromContext(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Header("Content-Disposition", "attachment; filename=customer-"+strconv.FormatInt(customerID, 10)+"-export.json")
+	ctx.JSON(http.StatusOK, bundle)
+}
+
+// EraseCustomerHandler godoc
+// @Summary Erase a customer's personal data
+// @Description Anonymizes every reservation of the customer: guest data, additional requests and payment links are cleared, also from earlier audit entries, while dates, guest counts and financial totals are kept. The request is tracked as an ERASURE privacy request.
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Customer ID"
+// @Param request body ErasureRequest false "Erasure details"
+// @Success 200 {object} Request
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /privacy/customers/{id}/erasure [post]
+func (c *Controller) EraseCustomerHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+	customerID, ok := c.getID(ctx)
+	if !ok {
+		return
+	}
+
+	var req ErasureRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	result, err := c.service.Erase(orgID, customerID, req.Reason, audit.ActorFromContext(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// GetRequestsHandler godoc
+// @Summary Get privacy requests
+// @Description Returns the export and erasure requests of the organization, newest first
+// @Tags privacy
+// @Produce json
+// @Security ApiKeyAuth
+// @Param limit query int false "Page size (max 200)" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} RequestPage
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /privacy/requests [get]
+func (c *Controller) GetRequestsHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", strconv.Itoa(defaultPageSize)))
+	if err != nil || limit < 1 || limit > maxPageSize {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+	offset, err := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+		return
+	}
+
+	page, err := c.service.GetRequests(orgID, limit, offset)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, page)
+}
+
+// GetRequestHandler godoc
+// @Summary Get a privacy request
+// @Tags privacy
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Request ID"
+// @Success 200 {object} Request
+// @Failure 404 {object} map[string]string
+// @Router /privacy/requests/{id} [get]
+func (c *Controller) GetRequestHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+	id, ok := c.getID(ctx)
+	if !ok {
+		return
+	}
+
+	req, err := c.service.GetRequest(id, orgID)
+	if errors.Is(err, ErrRequestNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, req)
+}
@@ -0,0 +1,106 @@
+package privacy
+
+import (
+	"time"
+)
+
+// Request types
+const (
+	RequestExport  = "EXPORT"
+	RequestErasure = "ERASURE"
+)
+
+// Request statuses
+const (
+	StatusPending   = "PENDING"
+	StatusCompleted = "COMPLETED"
+	StatusFailed    = "FAILED"
+)
+
+// Audit log actions of privacy requests and anonymized reservations
+const (
+	actionRequested  = "requested"
+	actionCompleted  = "completed"
+	actionFailed     = "failed"
+	actionAnonymized = "anonymize"
+)
+
+// personalFields are the reservation fields cleared by an erasure. They are
+// free-form, so they are cleared as a whole; the dates, guest count and
+// financial fields are kept for accounting.
+var personalFields = []string{"guest_data", "additional_requests", "payment_url"}
+
+// Request is a GDPR export or erasure request of a customer. It records who
+// asked for what and how it ended, but never the personal data itself.
+type Request struct {
+	ID             int64                  `json:"id" db:"id"`
+	OrganizationID int64                  `json:"organization_id" db:"organization_id"`
+	CustomerID     int64                  `json:"customer_id" db:"customer_id"`
+	Type           string                 `json:"type" db:"type" example:"ERASURE" enums:"EXPORT,ERASURE"`
+	Status         string                 `json:"status" db:"status" example:"COMPLETED" enums:"PENDING,COMPLETED,FAILED"`
+	Reason         *string                `json:"reason,omitempty" db:"reason" example:"Request by e-mail of 2026-03-01"`
+	RequestedBy    string                 `json:"requested_by" db:"requested_by" example:"user:7d1c3f5e-5b8e-4a43-9d0b-2f6b1f0c9a11"`
+	Result         map[string]interface{} `json:"result" db:"result"`
+	FailureReason  *string                `json:"failure_reason,omitempty" db:"failure_reason"`
+	CreatedAt      time.Time              `json:"created_at" db:"created_at"`
+	CompletedAt    *time.Time             `json:"completed_at,omitempty" db:"completed_at"`
+}
+
+// ErasureRequest is the body of an erasure request
+type ErasureRequest struct {
+	Reason string `json:"reason" binding:"max=500" example:"Request by e-mail of 2026-03-01"`
+}
+
+// ReservationRecord is a reservation as included in an export, deleted and
+// anonymized reservations included
+type ReservationRecord struct {
+	ID                 int64                  `json:"id" db:"id"`
+	PropertyID         int64                  `json:"property_id" db:"property_id"`
+	CheckInDate        time.Time              `json:"check_in_date" db:"check_in_date"`
+	CheckOutDate       time.Time              `json:"check_out_date" db:"check_out_date"`
+	Status             string                 `json:"status" db:"status"`
+	TotalPrice         float64                `json:"total_price" db:"total_price"`
+	PriceElements      map[string]interface{} `json:"price_elements" db:"price_elements"`
+	NoOfGuests         int                    `json:"no_of_guests" db:"no_of_guests"`
+	GuestData          map[string]interface{} `json:"guest_data" db:"guest_data"`
+	AdditionalRequests map[string]interface{} `json:"additional_requests" db:"additional_requests"`
+	CreatedAt          time.Time              `json:"created_at" db:"created_at"`
+	DeletedAt          *time.Time             `json:"deleted_at,omitempty" db:"deleted_at"`
+	AnonymizedAt       *time.Time             `json:"anonymized_at,omitempty" db:"anonymized_at"`
+}
+
+// EmailRecord is an email sent about one of the customer's reservations
+type EmailRecord struct {
+	ReservationID int64      `json:"reservation_id" db:"reservation_id"`
+	EmailType     string     `json:"email_type" db:"email_type" example:"CONFIRMATION"`
+	Status        string     `json:"status" db:"status" example:"SENT"`
+	SentAt        *time.Time `json:"sent_at,omitempty" db:"sent_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+}
+
+// ExportBundle holds all reservation data of a customer
+type ExportBundle struct {
+	RequestID      int64               `json:"request_id" example:"12"`
+	OrganizationID int64               `json:"organization_id" example:"1"`
+	CustomerID     int64               `json:"customer_id" example:"100"`
+	GeneratedAt    time.Time           `json:"generated_at"`
+	Reservations   []ReservationRecord `json:"reservations"`
+	Emails         []EmailRecord       `json:"emails"`
+}
+
+// RequestPage is a page of privacy requests
+type RequestPage struct {
+	Items  []Request `json:"items"`
+	Total  int64     `json:"total"`
+	Limit  int       `json:"limit"`
+	Offset int       `json:"offset"`
+}
+
+// anonymized is the personal data of a reservation before it was erased
+type anonymized struct {
+	ID                 int64                  `db:"id"`
+	OrganizationID     int64                  `db:"organization_id"`
+	GuestData          map[string]interface{} `db:"guest_data"`
+	AdditionalRequests map[string]interface{} `db:"additional_requests"`
+	PaymentURL         string                 `db:"payment_url"`
+}
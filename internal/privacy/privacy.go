@@ -0,0 +1,13 @@
+package privacy
+
+import (
+	"go.uber.org/fx"
+)
+
+// ======== EXPORTS ========
+
+// Module exports the GDPR tooling
+var Module = fx.Options(
+	fx.Provide(NewRepository, NewService, NewController, SetRoutes, NewRetentionJob),
+	fx.Invoke(RegisterRetentionHooks),
+)
@@ -0,0 +1,287 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// requestColumns are the columns scanned into Request
+const requestColumns = `
+    id, organization_id, customer_id, type, status, reason, requested_by,
+    result, failure_reason, created_at, completed_at
+`
+
+// anonymizeReturning are the columns scanned into anonymized. The values are
+// taken from the locked rows, before they are cleared.
+const anonymizeReturning = `
+    t.id, t.organization_id, t.guest_data, t.additional_requests, t.payment_url
+`
+
+// dbtx is satisfied by both the pool and a transaction
+type dbtx interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// Repository persists privacy requests and reads and erases the personal
+// data of reservations
+type Repository struct {
+	db dbtx
+}
+
+// NewRepository returns a Repository
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+// InTx runs fn with a repository bound to a new transaction, which is
+// committed if fn returns nil and rolled back otherwise
+func (r *Repository) InTx(fn func(tx *Repository) error) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(&Repository{db: tx}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// CreateRequest stores a new pending request
+func (r *Repository) CreateRequest(req *Request) (*Request, error) {
+	query := `
+        INSERT INTO privacy_request (organization_id, customer_id, type, reason, requested_by)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING ` + requestColumns
+
+	rows, err := r.db.Query(context.Background(), query,
+		req.OrganizationID,
+		req.CustomerID,
+		req.Type,
+		req.Reason,
+		req.RequestedBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[Request])
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// FinishRequest sets the final status of a pending request
+func (r *Repository) FinishRequest(id int64, status string, result map[string]interface{}, failureReason *string) (*Request, error) {
+	if result == nil {
+		result = map[string]interface{}{}
+	}
+
+	query := `
+        UPDATE privacy_request
+        SET status = $2,
+            result = $3,
+            failure_reason = $4,
+            completed_at = NOW()
+        WHERE id = $1
+          AND status = 'PENDING'
+        RETURNING ` + requestColumns
+
+	rows, err := r.db.Query(context.Background(), query, id, status, result, failureReason)
+	if err != nil {
+		return nil, err
+	}
+
+	finished, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[Request])
+	if err != nil {
+		return nil, err
+	}
+
+	return &finished, nil
+}
+
+// GetRequest returns a request of the organization
+func (r *Repository) GetRequest(id, organizationID int64) (*Request, error) {
+	query := `SELECT ` + requestColumns + `
+        FROM privacy_request
+        WHERE id = $1
+          AND organization_id = $2
+    `
+
+	rows, err := r.db.Query(context.Background(), query, id, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[Request])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+// GetRequests returns a page of the requests of the organization, newest first
+func (r *Repository) GetRequests(organizationID int64, limit, offset int) (*RequestPage, error) {
+	ctx := context.Background()
+
+	var total int64
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM privacy_request WHERE organization_id = $1`, organizationID).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + requestColumns + `
+        FROM privacy_request
+        WHERE organization_id = $1
+        ORDER BY created_at DESC, id DESC
+        LIMIT $2 OFFSET $3
+    `
+
+	rows, err := r.db.Query(ctx, query, organizationID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	requests, err := pgx.CollectRows(rows, pgx.RowToStructByName[Request])
+	if err != nil {
+		return nil, err
+	}
+
+	return &RequestPage{
+		Items:  requests,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+// GetCustomerReservations returns every reservation of a customer, including
+// deleted and anonymized ones
+func (r *Repository) GetCustomerReservations(organizationID, customerID int64) ([]ReservationRecord, error) {
+	query := `
+        SELECT id, property_id, check_in_date, check_out_date, status, total_price,
+               price_elements, no_of_guests, guest_data, additional_requests,
+               created_at, deleted_at, anonymized_at
+        FROM reservation
+        WHERE organization_id = $1
+          AND customer_id = $2
+        ORDER BY check_in_date
+    `
+
+	rows, err := r.db.Query(context.Background(), query, organizationID, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[ReservationRecord])
+}
+
+// GetCustomerEmails returns the emails sent about the customer's reservations
+func (r *Repository) GetCustomerEmails(organizationID, customerID int64) ([]EmailRecord, error) {
+	query := `
+        SELECT reservation_id, email_type, status, sent_at, created_at
+        FROM email_delivery
+        WHERE organization_id = $1
+          AND customer_id = $2
+        ORDER BY created_at
+    `
+
+	rows, err := r.db.Query(context.Background(), query, organizationID, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[EmailRecord])
+}
+
+// AnonymizeCustomerReservations erases the personal data of every
+// reservation of a customer and returns the data that was erased
+func (r *Repository) AnonymizeCustomerReservations(organizationID, customerID int64) ([]anonymized, error) {
+	query := `
+        WITH t AS (
+            SELECT id, organization_id, guest_data, additional_requests, payment_url
+            FROM reservation
+            WHERE organization_id = $1
+              AND customer_id = $2
+            FOR UPDATE
+        )
+        UPDATE reservation r
+        SET guest_data = '{}',
+            additional_requests = '{}',
+            payment_url = '',
+            anonymized_at = NOW()
+        FROM t
+        WHERE r.id = t.id
+        RETURNING ` + anonymizeReturning
+
+	return r.anonymize(query, organizationID, customerID)
+}
+
+// AnonymizeCheckedOut erases the personal data of up to limit reservations
+// that checked out before the cutoff and were not anonymized yet. Rows locked
+// by another replica are skipped.
+func (r *Repository) AnonymizeCheckedOut(cutoff time.Time, limit int) ([]anonymized, error) {
+	query := `
+        WITH t AS (
+            SELECT id, organization_id, guest_data, additional_requests, payment_url
+            FROM reservation
+            WHERE check_out_date < $1
+              AND anonymized_at IS NULL
+            ORDER BY check_out_date
+            LIMIT $2
+            FOR UPDATE SKIP LOCKED
+        )
+        UPDATE reservation r
+        SET guest_data = '{}',
+            additional_requests = '{}',
+            payment_url = '',
+            anonymized_at = NOW()
+        FROM t
+        WHERE r.id = t.id
+        RETURNING ` + anonymizeReturning
+
+	return r.anonymize(query, cutoff, limit)
+}
+
+// ClearEmailPaymentURLs removes the payment links of the reservations from
+// their email deliveries
+func (r *Repository) ClearEmailPaymentURLs(reservationIDs []int64) error {
+	query := `
+        UPDATE email_delivery
+        SET payment_url = ''
+        WHERE reservation_id = ANY($1)
+          AND payment_url <> ''
+    `
+
+	_, err := r.db.Exec(context.Background(), query, reservationIDs)
+	return err
+}
+
+// anonymize runs an anonymizing statement
+func (r *Repository) anonymize(query string, args ...any) ([]anonymized, error) {
+	rows, err := r.db.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[anonymized])
+}
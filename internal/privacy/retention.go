@@ -0,0 +1,99 @@
+package privacy
+
+import (
+	"context"
+	"fmt"
+	"hostflow/booking-service/pkg/lib"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/fx"
+)
+
+const (
+	// retentionInterval is how often checked-out reservations are anonymized.
+	retentionInterval = time.Hour
+	// retentionBatchSize limits how many reservations are anonymized per
+	// transaction.
+	retentionBatchSize = 100
+)
+
+// RetentionJob anonymizes the guest data of reservations a number of months
+// after checkout
+type RetentionJob struct {
+	service *Service
+	logger  lib.Logger
+	months  int
+	now     func() time.Time
+}
+
+// NewRetentionJob returns a RetentionJob. The retention is read from
+// GUEST_DATA_RETENTION_MONTHS; when it is unset or 0, nothing is anonymized.
+func NewRetentionJob(service *Service, logger lib.Logger) (*RetentionJob, error) {
+	months, err := parseRetentionMonths(os.Getenv("GUEST_DATA_RETENTION_MONTHS"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &RetentionJob{
+		service: service,
+		logger:  logger,
+		months:  months,
+		now:     time.Now,
+	}, nil
+}
+
+// parseRetentionMonths parses a number of months
+func parseRetentionMonths(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	months, err := strconv.Atoi(value)
+	if err != nil || months < 0 {
+		return 0, fmt.Errorf("invalid GUEST_DATA_RETENTION_MONTHS %q, expected a number of months", value)
+	}
+
+	return months, nil
+}
+
+// cutoff returns the checkout time before which guest data is anonymized
+func (j *RetentionJob) cutoff() time.Time {
+	return j.now().AddDate(0, -j.months, 0)
+}
+
+// tick anonymizes every reservation past retention, one batch per transaction.
+func (j *RetentionJob) tick(ctx context.Context) {
+	cutoff := j.cutoff()
+
+	var total int
+	for ctx.Err() == nil {
+		n, err := j.service.AnonymizeCheckedOut(cutoff, retentionBatchSize)
+		if err != nil {
+			j.logger.Error("Failed to anonymize checked-out reservations:", err)
+			break
+		}
+		total += n
+		if n < retentionBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		j.logger.Info(fmt.Sprintf("Anonymized the guest data of %d reservations", total))
+	}
+}
+
+// RegisterRetentionHooks starts the retention job with the application, if
+// a retention period is configured
+func RegisterRetentionHooks(lifecycle fx.Lifecycle, job *RetentionJob) {
+	if job.months == 0 {
+		job.logger.Info("GUEST_DATA_RETENTION_MONTHS is not set, guest data is kept")
+		return
+	}
+
+	lib.NewWorker(retentionInterval, job.tick).Start(lifecycle)
+}
@@ -0,0 +1,30 @@
+package privacy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRetentionMonths(t *testing.T) {
+	months, err := parseRetentionMonths("")
+	assert.NoError(t, err)
+	assert.Zero(t, months)
+
+	months, err = parseRetentionMonths(" 24 ")
+	assert.NoError(t, err)
+	assert.Equal(t, 24, months)
+
+	_, err = parseRetentionMonths("-1")
+	assert.Error(t, err)
+}
+
+func TestRetentionJobCutoff(t *testing.T) {
+	job := &RetentionJob{
+		months: 18,
+		now:    func() time.Time { return time.Date(2026, 10, 18, 3, 0, 0, 0, time.UTC) },
+	}
+
+	assert.Equal(t, time.Date(2025, 4, 18, 3, 0, 0, 0, time.UTC), job.cutoff())
+}
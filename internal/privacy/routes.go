@@ -0,0 +1,47 @@
+package privacy
+
+import (
+	"hostflow/booking-service/internal/middlewares"
+	"hostflow/booking-service/pkg/lib"
+)
+
+// Routes struct
+type Routes struct {
+	logger              lib.Logger
+	router              *lib.Router
+	controller          *Controller
+	authMiddleware      middlewares.AuthMiddleware
+	rateLimitMiddleware middlewares.RateLimitMiddleware
+}
+
+// SetRoutes returns a Routes struct
+func SetRoutes(
+	logger lib.Logger,
+	router *lib.Router,
+	controller *Controller,
+	authMiddleware middlewares.AuthMiddleware,
+	rateLimitMiddleware middlewares.RateLimitMiddleware,
+) Routes {
+	return Routes{
+		logger:              logger,
+		router:              router,
+		controller:          controller,
+		authMiddleware:      authMiddleware,
+		rateLimitMiddleware: rateLimitMiddleware,
+	}
+}
+
+// Setup registers the privacy routes. Every route handles personal data
+// and requires privacy:manage.
+func (route Routes) Setup() {
+	route.logger.Info("Setting up [PRIVACY] routes.")
+
+	privacy := route.router.Group("/privacy")
+	privacy.Use(route.authMiddleware.Handler(), middlewares.RequirePermission(middlewares.PrivacyManage))
+	{
+		privacy.POST("/customers/:id/export", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), route.controller.ExportCustomerHandler)
+		privacy.POST("/customers/:id/erasure", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), route.controller.EraseCustomerHandler)
+		privacy.GET("/requests", route.rateLimitMiddleware.Limit(middlewares.BudgetDefault), route.controller.GetRequestsHandler)
+		privacy.GET("/requests/:id", route.rateLimitMiddleware.Limit(middlewares.BudgetDefault), route.controller.GetRequestHandler)
+	}
+}
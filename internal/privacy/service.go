@@ -0,0 +1,249 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hostflow/booking-service/internal/audit"
+	"hostflow/booking-service/internal/encryption"
+	"hostflow/booking-service/pkg/lib"
+	"strconv"
+	"time"
+)
+
+// ErrRequestNotFound is returned for requests of another organization or
+// that don't exist
+var ErrRequestNotFound = errors.New("privacy request not found")
+
+// Service handles GDPR export and erasure requests
+type Service struct {
+	repo      *Repository
+	audit     *audit.Repository
+	guestData *encryption.FieldEncryptor
+	logger    lib.Logger
+}
+
+// NewService returns a Service
+func NewService(repo *Repository, auditLog *audit.Repository, guestData *encryption.FieldEncryptor, logger lib.Logger) *Service {
+	return &Service{
+		repo:      repo,
+		audit:     auditLog,
+		guestData: guestData,
+		logger:    logger,
+	}
+}
+
+// Export collects every reservation of the customer, with the emails sent
+// about them, into a bundle. The request is tracked, but the bundle is only
+// returned to the caller and never stored.
+func (s *Service) Export(organizationID, customerID int64, actor audit.Actor) (*ExportBundle, error) {
+	req, err := s.open(organizationID, customerID, RequestExport, "", actor)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &ExportBundle{
+		RequestID:      req.ID,
+		OrganizationID: organizationID,
+		CustomerID:     customerID,
+		GeneratedAt:    time.Now().UTC(),
+	}
+
+	bundle.Reservations, err = s.repo.GetCustomerReservations(organizationID, customerID)
+	// The bundle is handed to the guest, so encrypted guest data is decrypted
+	for i := 0; err == nil && i < len(bundle.Reservations); i++ {
+		err = s.guestData.Decrypt(context.Background(), bundle.Reservations[i].GuestData)
+	}
+	if err == nil {
+		bundle.Emails, err = s.repo.GetCustomerEmails(organizationID, customerID)
+	}
+	if err != nil {
+		s.fail(req, actor, err)
+		return nil, err
+	}
+
+	err = s.repo.InTx(func(tx *Repository) error {
+		_, err := s.finish(tx, req, actor, map[string]interface{}{
+			"reservations": len(bundle.Reservations),
+			"emails":       len(bundle.Emails),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+// Erase anonymizes every reservation of the customer. Personal fields are
+// cleared, on the reservations and in their earlier audit entries, while
+// dates, guest counts and financial totals are kept for accounting.
+func (s *Service) Erase(organizationID, customerID int64, reason string, actor audit.Actor) (*Request, error) {
+	req, err := s.open(organizationID, customerID, RequestErasure, reason, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	var finished *Request
+	err = s.repo.InTx(func(tx *Repository) error {
+		erased, err := tx.AnonymizeCustomerReservations(organizationID, customerID)
+		if err != nil {
+			return err
+		}
+		if err := s.recordAnonymized(tx, erased, actor); err != nil {
+			return err
+		}
+
+		finished, err = s.finish(tx, req, actor, map[string]interface{}{
+			"reservations_anonymized": len(erased),
+		})
+		return err
+	})
+	if err != nil {
+		s.fail(req, actor, err)
+		return nil, err
+	}
+
+	return finished, nil
+}
+
+// GetRequest returns a request of the organization
+func (s *Service) GetRequest(id, organizationID int64) (*Request, error) {
+	req, err := s.repo.GetRequest(id, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if req == nil {
+		return nil, ErrRequestNotFound
+	}
+	return req, nil
+}
+
+// GetRequests returns a page of the requests of the organization
+func (s *Service) GetRequests(organizationID int64, limit, offset int) (*RequestPage, error) {
+	return s.repo.GetRequests(organizationID, limit, offset)
+}
+
+// AnonymizeCheckedOut anonymizes up to limit reservations that checked out
+// before the cutoff, and returns how many were anonymized
+func (s *Service) AnonymizeCheckedOut(cutoff time.Time, limit int) (int, error) {
+	var count int
+	err := s.repo.InTx(func(tx *Repository) error {
+		erased, err := tx.AnonymizeCheckedOut(cutoff, limit)
+		if err != nil {
+			return err
+		}
+		count = len(erased)
+		return s.recordAnonymized(tx, erased, audit.SystemActor("retention"))
+	})
+	return count, err
+}
+
+// open creates a pending request and records it in the audit log
+func (s *Service) open(organizationID, customerID int64, requestType, reason string, actor audit.Actor) (*Request, error) {
+	req := &Request{
+		OrganizationID: organizationID,
+		CustomerID:     customerID,
+		Type:           requestType,
+		RequestedBy:    actor.Type + ":" + actor.ID,
+	}
+	if reason != "" {
+		req.Reason = &reason
+	}
+
+	var created *Request
+	err := s.repo.InTx(func(tx *Repository) error {
+		var err error
+		created, err = tx.CreateRequest(req)
+		if err != nil {
+			return err
+		}
+		return s.record(tx, actor, actionRequested, nil, created)
+	})
+	return created, err
+}
+
+// finish completes a request in the transaction of tx
+func (s *Service) finish(tx *Repository, req *Request, actor audit.Actor, result map[string]interface{}) (*Request, error) {
+	finished, err := tx.FinishRequest(req.ID, StatusCompleted, result, nil)
+	if err != nil {
+		return nil, err
+	}
+	return finished, s.record(tx, actor, actionCompleted, req, finished)
+}
+
+// fail marks a request as failed. The request was already tracked, so a
+// failure to do so is only logged.
+func (s *Service) fail(req *Request, actor audit.Actor, cause error) {
+	reason := cause.Error()
+	err := s.repo.InTx(func(tx *Repository) error {
+		failed, err := tx.FinishRequest(req.ID, StatusFailed, nil, &reason)
+		if err != nil {
+			return err
+		}
+		return s.record(tx, actor, actionFailed, req, failed)
+	})
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to mark privacy request %d as failed:", req.ID), err)
+	}
+}
+
+// record writes the audit entry of a change to a request
+func (s *Service) record(tx *Repository, actor audit.Actor, action string, before, after *Request) error {
+	entry, err := audit.NewEntry(actor, after.OrganizationID, action, audit.EntityPrivacyRequest,
+		strconv.FormatInt(after.ID, 10), before, after)
+	if err != nil {
+		return err
+	}
+	return s.audit.Record(tx.db, entry)
+}
+
+// recordAnonymized records the anonymization of reservations in the audit
+// log without the erased values, redacts the personal data from their
+// earlier entries and removes their payment links from sent emails
+func (s *Service) recordAnonymized(tx *Repository, erased []anonymized, actor audit.Actor) error {
+	if len(erased) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(erased))
+	entityIDs := make([]string, 0, len(erased))
+	for _, reservation := range erased {
+		ids = append(ids, reservation.ID)
+		entityIDs = append(entityIDs, strconv.FormatInt(reservation.ID, 10))
+	}
+
+	if err := s.audit.RedactEntries(tx.db, audit.EntityReservation, entityIDs, personalFields); err != nil {
+		return err
+	}
+	if err := tx.ClearEmailPaymentURLs(ids); err != nil {
+		return err
+	}
+
+	for _, reservation := range erased {
+		before := map[string]interface{}{
+			"guest_data":          reservation.GuestData,
+			"additional_requests": reservation.AdditionalRequests,
+			"payment_url":         reservation.PaymentURL,
+		}
+		after := map[string]interface{}{
+			"guest_data":          map[string]interface{}{},
+			"additional_requests": map[string]interface{}{},
+			"payment_url":         "",
+		}
+
+		entry, err := audit.NewEntry(actor, reservation.OrganizationID, actionAnonymized, audit.EntityReservation,
+			strconv.FormatInt(reservation.ID, 10), before, after)
+		if err != nil {
+			return err
+		}
+		audit.Redact(entry.Changes, personalFields...)
+
+		if err := s.audit.Record(tx.db, entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
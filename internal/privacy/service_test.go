@@ -0,0 +1,159 @@
+package privacy
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"hostflow/booking-service/internal/audit"
+	"hostflow/booking-service/internal/dbtest"
+	"hostflow/booking-service/internal/encryption"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Info(args ...interface{})  {}
+func (nopLogger) Fatal(args ...interface{}) {}
+func (nopLogger) Error(args ...interface{}) {}
+
+func newDBService(t *testing.T, db *pgxpool.Pool) *Service {
+	t.Helper()
+
+	guestData, err := encryption.NewFieldEncryptor(nil, nil)
+	require.NoError(t, err)
+	return NewService(NewRepository(db), audit.NewRepository(db), guestData, nopLogger{})
+}
+
+// seedStay stores a reservation of organization 100 with personal data, an
+// email with its payment link and the audit entry of an earlier update
+func seedStay(t *testing.T, db *pgxpool.Pool, id, customerID int64, checkOut time.Time) {
+	t.Helper()
+	ctx := context.Background()
+	paymentURL := "https://pay.example/" + strconv.FormatInt(id, 10)
+
+	_, err := db.Exec(ctx, `
+        INSERT INTO reservation (
+            id, organization_id, property_id, customer_id, check_in_date, check_out_date,
+            status, total_price, payment_url, price_elements, no_of_guests, guest_data, additional_requests
+        )
+        VALUES ($1, 100, 10, $2, $3, $4, 'COMPLETED', 412.5, $5,
+                '{"cleaning_fee": 50}', 2, '{"guests": [{"first_name": "Ana"}]}', '{"arrival_time": "16:30"}')
+    `, id, customerID, checkOut.AddDate(0, 0, -3), checkOut, paymentURL)
+	require.NoError(t, err)
+
+	_, err = db.Exec(ctx, `
+        INSERT INTO email_delivery (organization_id, reservation_id, customer_id, property_id, email_type, payment_url)
+        VALUES (100, $1, $2, 10, 'PAYMENT', $3)
+    `, id, customerID, paymentURL)
+	require.NoError(t, err)
+
+	entry, err := audit.NewEntry(audit.SystemActor("test"), 100, audit.ActionUpdate, audit.EntityReservation, strconv.FormatInt(id, 10),
+		map[string]interface{}{"total_price": 400.0, "guest_data": map[string]interface{}{"phone": "+386 41 000 000"}},
+		map[string]interface{}{"total_price": 412.5, "guest_data": map[string]interface{}{"phone": "+386 41 111 111"}},
+	)
+	require.NoError(t, err)
+	require.NoError(t, audit.NewRepository(db).Record(db, entry))
+}
+
+// personalData returns the personal fields of a reservation and whether it
+// was anonymized
+func personalData(t *testing.T, db *pgxpool.Pool, id int64) (guestData, paymentURL string, anonymized bool) {
+	t.Helper()
+
+	require.NoError(t, db.QueryRow(context.Background(), `
+        SELECT guest_data::text || additional_requests::text, payment_url, anonymized_at IS NOT NULL
+        FROM reservation
+        WHERE id = $1
+    `, id).Scan(&guestData, &paymentURL, &anonymized))
+	return guestData, paymentURL, anonymized
+}
+
+func TestErase_ClearsPersonalDataAndKeepsTotals(t *testing.T) {
+	db := dbtest.Open(t)
+	service := newDBService(t, db)
+	ctx := context.Background()
+	checkOut := time.Date(2025, 6, 4, 10, 0, 0, 0, time.UTC)
+
+	seedStay(t, db, 1, 7, checkOut)
+	seedStay(t, db, 2, 7, checkOut.AddDate(0, 1, 0))
+	seedStay(t, db, 3, 8, checkOut)
+
+	req, err := service.Erase(100, 7, "Customer request", audit.SystemActor("test"))
+	require.NoError(t, err)
+	assert.Equal(t, StatusCompleted, req.Status)
+	assert.EqualValues(t, 2, req.Result["reservations_anonymized"])
+
+	for _, id := range []int64{1, 2} {
+		data, paymentURL, anonymized := personalData(t, db, id)
+		assert.Equal(t, "{}{}", data)
+		assert.Empty(t, paymentURL)
+		assert.True(t, anonymized)
+
+		var total float64
+		var priceElements string
+		var guests int
+		require.NoError(t, db.QueryRow(ctx, `
+            SELECT total_price, price_elements::text, no_of_guests FROM reservation WHERE id = $1
+        `, id).Scan(&total, &priceElements, &guests))
+		assert.Equal(t, 412.5, total)
+		assert.JSONEq(t, `{"cleaning_fee": 50}`, priceElements)
+		assert.Equal(t, 2, guests)
+	}
+
+	// The other customer is untouched
+	data, paymentURL, anonymized := personalData(t, db, 3)
+	assert.Contains(t, data, "Ana")
+	assert.NotEmpty(t, paymentURL)
+	assert.False(t, anonymized)
+
+	var links int
+	require.NoError(t, db.QueryRow(ctx, `SELECT COUNT(*) FROM email_delivery WHERE payment_url <> ''`).Scan(&links))
+	assert.Equal(t, 1, links)
+
+	// Earlier audit entries keep the totals but not the personal data, and
+	// the anonymization itself doesn't record the erased values
+	page, err := audit.NewRepository(db).GetEntries(100, audit.EntityReservation, "1", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	for _, entry := range page.Items {
+		for field, change := range entry.Changes {
+			if field == "total_price" {
+				assert.Equal(t, 412.5, change.After)
+				continue
+			}
+			if change.Before != nil {
+				assert.Equal(t, audit.Redacted, change.Before, "%s %s", entry.Action, field)
+			}
+			if change.After != nil {
+				assert.Equal(t, audit.Redacted, change.After, "%s %s", entry.Action, field)
+			}
+		}
+	}
+}
+
+func TestAnonymizeCheckedOut_StopsAtCutoffAndLimit(t *testing.T) {
+	db := dbtest.Open(t)
+	service := newDBService(t, db)
+	cutoff := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	seedStay(t, db, 1, 7, cutoff.AddDate(0, -2, 0))
+	seedStay(t, db, 2, 8, cutoff.AddDate(0, -1, 0))
+	seedStay(t, db, 3, 9, cutoff.AddDate(0, 0, 1))
+
+	for _, want := range []int{1, 1, 0} {
+		count, err := service.AnonymizeCheckedOut(cutoff, 1)
+		require.NoError(t, err)
+		assert.Equal(t, want, count)
+	}
+
+	for id, want := range map[int64]bool{1: true, 2: true, 3: false} {
+		_, paymentURL, anonymized := personalData(t, db, id)
+		assert.Equal(t, want, anonymized, "reservation %d", id)
+		assert.Equal(t, want, paymentURL == "", "reservation %d", id)
+	}
+}
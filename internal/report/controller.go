@@ -0,0 +1,225 @@
+package report
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Controller handles HTTP requests for reports
+type Controller struct {
+	service *Service
+}
+
+// NewController returns a Controller
+func NewController(service *Service) *Controller {
+	return &Controller{
+		service: service,
+	}
+}
+
+func (c *Controller) getOrgID(ctx *gin.Context) (int64, bool) {
+	val, exists := ctx.Get("organization_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return 0, false
+	}
+	return val.(int64), true
+}
+
+// bind reads the query of a report request
+func (c *Controller) bind(ctx *gin.Context) (int64, Query, bool) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return 0, Query{}, false
+	}
+
+	var q Query
+	if err := ctx.ShouldBindQuery(&q); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return 0, Query{}, false
+	}
+	return orgID, q, true
+}
+
+// PerformanceHandler godoc
+// @Summary Occupancy, ADR and RevPAR
+// @Description Returns the available and booked nights, occupancy (%), revenue, ADR and RevPAR per period. Every property with reservations is available every night, once per active unit if it has room types; nights are booked by confirmed and completed reservations, whose revenue excluding tourist tax is spread evenly over their nights.
+// @Tags reports
+// @Produce json
+// @Produce text/csv
+// @Security ApiKeyAuth
+// @Param from query string false "First day (YYYY-MM-DD), by default the first day of the current month"
+// @Param to query string false "Last day (YYYY-MM-DD), by default a month after from"
+// @Param group_by query string false "Period length" Enums(day, week, month) default(month)
+// @Param by_property query bool false "One row per property and period"
+// @Param property_id query int false "Only this property"
+// @Param format query string false "Output format" Enums(json, csv) default(json)
+// @Success 200 {object} PerformanceReport
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /reports/performance [get]
+func (c *Controller) PerformanceHandler(ctx *gin.Context) {
+	orgID, q, ok := c.bind(ctx)
+	if !ok {
+		return
+	}
+
+	report, err := c.service.Performance(orgID, q)
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+	respond(ctx, q, "performance", report, report.Rows)
+}
+
+// RevenueByStatusHandler godoc
+// @Summary Revenue by status
+// @Description Returns the number and total price of the reservations checking in per period, by status
+// @Tags reports
+// @Produce json
+// @Produce text/csv
+// @Security ApiKeyAuth
+// @Param from query string false "First day (YYYY-MM-DD), by default the first day of the current month"
+// @Param to query string false "Last day (YYYY-MM-DD), by default a month after from"
+// @Param group_by query string false "Period length" Enums(day, week, month) default(month)
+// @Param by_property query bool false "One row per property and period"
+// @Param property_id query int false "Only this property"
+// @Param format query string false "Output format" Enums(json, csv) default(json)
+// @Success 200 {object} StatusReport
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /reports/revenue-by-status [get]
+func (c *Controller) RevenueByStatusHandler(ctx *gin.Context) {
+	orgID, q, ok := c.bind(ctx)
+	if !ok {
+		return
+	}
+
+	report, err := c.service.RevenueByStatus(orgID, q)
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+	respond(ctx, q, "revenue-by-status", report, report.Rows)
+}
+
+// LeadTimeHandler godoc
+// @Summary Lead time
+// @Description Returns the average, median, minimum and maximum days between booking and check-in of the reservations checking in per period, except cancelled and rejected ones
+// @Tags reports
+// @Produce json
+// @Produce text/csv
+// @Security ApiKeyAuth
+// @Param from query string false "First day (YYYY-MM-DD), by default the first day of the current month"
+// @Param to query string false "Last day (YYYY-MM-DD), by default a month after from"
+// @Param group_by query string false "Period length" Enums(day, week, month) default(month)
+// @Param by_property query bool false "One row per property and period"
+// @Param property_id query int false "Only this property"
+// @Param format query string false "Output format" Enums(json, csv) default(json)
+// @Success 200 {object} LeadTimeReport
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /reports/lead-time [get]
+func (c *Controller) LeadTimeHandler(ctx *gin.Context) {
+	orgID, q, ok := c.bind(ctx)
+	if !ok {
+		return
+	}
+
+	report, err := c.service.LeadTime(orgID, q)
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+	respond(ctx, q, "lead-time", report, report.Rows)
+}
+
+// StayLengthsHandler godoc
+// @Summary Length of stay distribution
+// @Description Returns the number of reservations checking in per period by number of nights (1 to 7, 8-13 and 14+), except cancelled and rejected ones
+// @Tags reports
+// @Produce json
+// @Produce text/csv
+// @Security ApiKeyAuth
+// @Param from query string false "First day (YYYY-MM-DD), by default the first day of the current month"
+// @Param to query string false "Last day (YYYY-MM-DD), by default a month after from"
+// @Param group_by query string false "Period length" Enums(day, week, month) default(month)
+// @Param by_property query bool false "One row per property and period"
+// @Param property_id query int false "Only this property"
+// @Param format query string false "Output format" Enums(json, csv) default(json)
+// @Success 200 {object} StayLengthReport
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /reports/length-of-stay [get]
+func (c *Controller) StayLengthsHandler(ctx *gin.Context) {
+	orgID, q, ok := c.bind(ctx)
+	if !ok {
+		return
+	}
+
+	report, err := c.service.StayLengths(orgID, q)
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+	respond(ctx, q, "length-of-stay", report, report.Rows)
+}
+
+// CancellationsHandler godoc
+// @Summary Cancellation rate
+// @Description Returns the share (%) of the reservations checking in per period that were cancelled. Rejected reservations are not counted.
+// @Tags reports
+// @Produce json
+// @Produce text/csv
+// @Security ApiKeyAuth
+// @Param from query string false "First day (YYYY-MM-DD), by default the first day of the current month"
+// @Param to query string false "Last day (YYYY-MM-DD), by default a month after from"
+// @Param group_by query string false "Period length" Enums(day, week, month) default(month)
+// @Param by_property query bool false "One row per property and period"
+// @Param property_id query int false "Only this property"
+// @Param format query string false "Output format" Enums(json, csv) default(json)
+// @Success 200 {object} CancellationReport
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /reports/cancellations [get]
+func (c *Controller) CancellationsHandler(ctx *gin.Context) {
+	orgID, q, ok := c.bind(ctx)
+	if !ok {
+		return
+	}
+
+	report, err := c.service.Cancellations(orgID, q)
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+	respond(ctx, q, "cancellations", report, report.Rows)
+}
+
+// ======== PRIVATE METHODS ========
+
+// respond writes a report as JSON, or its rows as CSV
+func respond[T row](ctx *gin.Context, q Query, name string, report interface{}, rows []T) {
+	if q.Format != FormatCSV {
+		ctx.JSON(http.StatusOK, report)
+		return
+	}
+
+	ctx.Header("Content-Disposition", "attachment; filename="+name+".csv")
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Status(http.StatusOK)
+	if err := WriteCSV(ctx.Writer, rows); err != nil {
+		ctx.Error(err)
+	}
+}
+
+// fail responds with the status of a service error
+func (c *Controller) fail(ctx *gin.Context, err error) {
+	if errors.Is(err, ErrInvalidRange) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
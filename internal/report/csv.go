@@ -0,0 +1,98 @@
+package report
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// row is a report row that can be written as CSV
+type row interface {
+	header() []string
+	record() []string
+}
+
+// WriteCSV writes report rows as CSV, with a header even without rows
+func WriteCSV[T row](w io.Writer, rows []T) error {
+	writer := csv.NewWriter(w)
+
+	var zero T
+	if err := writer.Write(zero.header()); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		if err := writer.Write(r.record()); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func (PerformanceRow) header() []string {
+	return []string{"period", "property_id", "available_nights", "booked_nights", "occupancy", "revenue", "adr", "revpar"}
+}
+
+func (r PerformanceRow) record() []string {
+	return []string{
+		r.Period, formatID(r.PropertyID), formatInt(r.AvailableNights), formatInt(r.BookedNights),
+		formatFloat(r.Occupancy), formatFloat(r.Revenue), formatFloat(r.ADR), formatFloat(r.RevPAR),
+	}
+}
+
+func (StatusRow) header() []string {
+	return []string{"period", "property_id", "status", "reservations", "revenue"}
+}
+
+func (r StatusRow) record() []string {
+	return []string{r.Period, formatID(r.PropertyID), r.Status, formatInt(r.Reservations), formatFloat(r.Revenue)}
+}
+
+func (LeadTimeRow) header() []string {
+	return []string{"period", "property_id", "reservations", "average_days", "median_days", "min_days", "max_days"}
+}
+
+func (r LeadTimeRow) record() []string {
+	return []string{
+		r.Period, formatID(r.PropertyID), formatInt(r.Reservations), formatFloat(r.AverageDays),
+		formatFloat(r.MedianDays), formatInt(r.MinDays), formatInt(r.MaxDays),
+	}
+}
+
+func (StayLengthRow) header() []string {
+	return []string{"period", "property_id", "nights", "reservations"}
+}
+
+func (r StayLengthRow) record() []string {
+	return []string{r.Period, formatID(r.PropertyID), r.Nights, formatInt(r.Reservations)}
+}
+
+func (CancellationRow) header() []string {
+	return []string{"period", "property_id", "reservations", "cancelled", "cancellation_rate"}
+}
+
+func (r CancellationRow) record() []string {
+	return []string{
+		r.Period, formatID(r.PropertyID), formatInt(r.Reservations), formatInt(r.Cancelled),
+		formatFloat(r.CancellationRate),
+	}
+}
+
+// formatID formats an optional property id, empty when rows are not per
+// property
+func formatID(id *int64) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatInt(*id, 10)
+}
+
+func formatInt(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+// formatFloat formats rates and amounts with two decimals
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 2, 64)
+}
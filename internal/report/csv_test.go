@@ -0,0 +1,33 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCSV(t *testing.T) {
+	propertyID := int64(10)
+	rows := []PerformanceRow{
+		{Period: "2026-07-01", AvailableNights: 31, BookedNights: 24, Occupancy: 77.42, Revenue: 2880, ADR: 120, RevPAR: 92.9},
+		{Period: "2026-08-01", PropertyID: &propertyID, AvailableNights: 31},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, rows))
+
+	assert.Equal(t,
+		"period,property_id,available_nights,booked_nights,occupancy,revenue,adr,revpar\n"+
+			"2026-07-01,,31,24,77.42,2880.00,120.00,92.90\n"+
+			"2026-08-01,10,31,0,0.00,0.00,0.00,0.00\n",
+		buf.String())
+}
+
+func TestWriteCSVWithoutRows(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV[StayLengthRow](&buf, nil))
+
+	assert.Equal(t, "period,property_id,nights,reservations\n", buf.String())
+}
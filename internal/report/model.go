@@ -0,0 +1,143 @@
+package report
+
+import (
+	"errors"
+	"time"
+)
+
+// DateLayout is the format of report dates
+const DateLayout = "2006-01-02"
+
+// Period lengths rows can be grouped by
+const (
+	GroupDay   = "day"
+	GroupWeek  = "week"
+	GroupMonth = "month"
+)
+
+// Output formats
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+// maxRange bounds the days a report covers, so that per-night queries stay
+// cheap
+const maxRange = 731
+
+// Errors returned by the report service
+var (
+	ErrInvalidRange = errors.New("to must not be before from, and a report covers at most two years")
+)
+
+// Query selects the reservations a report covers and how its rows are
+// grouped. From and To are inclusive dates; they default to the current
+// month.
+type Query struct {
+	From       string `form:"from" binding:"omitempty,datetime=2006-01-02" example:"2026-07-01"`
+	To         string `form:"to" binding:"omitempty,datetime=2006-01-02" example:"2026-07-31"`
+	GroupBy    string `form:"group_by" binding:"omitempty,oneof=day week month" example:"month"`
+	ByProperty bool   `form:"by_property" example:"true"`
+	PropertyID *int64 `form:"property_id" binding:"omitempty,min=1" example:"10"`
+	Format     string `form:"format" binding:"omitempty,oneof=json csv" example:"json"`
+}
+
+// filter is a validated query
+type filter struct {
+	OrganizationID int64
+	From           time.Time
+	To             time.Time
+	GroupBy        string
+	ByProperty     bool
+	PropertyID     *int64
+}
+
+// Meta describes what a report covers
+type Meta struct {
+	From       string `json:"from" example:"2026-07-01"`
+	To         string `json:"to" example:"2026-07-31"`
+	GroupBy    string `json:"group_by" example:"month"`
+	ByProperty bool   `json:"by_property" example:"true"`
+	PropertyID *int64 `json:"property_id,omitempty" example:"10"`
+}
+
+// PerformanceRow is the occupancy and revenue of a period, and of a property
+// when grouped by property. Revenue excludes tourist tax.
+type PerformanceRow struct {
+	Period          string  `json:"period" db:"period" example:"2026-07-01"`
+	PropertyID      *int64  `json:"property_id,omitempty" db:"property_id" example:"10"`
+	AvailableNights int64   `json:"available_nights" db:"available_nights" example:"31"`
+	BookedNights    int64   `json:"booked_nights" db:"booked_nights" example:"24"`
+	Occupancy       float64 `json:"occupancy" db:"occupancy" example:"77.42"`
+	Revenue         float64 `json:"revenue" db:"revenue" example:"2880.00"`
+	ADR             float64 `json:"adr" db:"adr" example:"120.00"`
+	RevPAR          float64 `json:"revpar" db:"revpar" example:"92.90"`
+}
+
+// StatusRow is the revenue of the reservations with a status
+type StatusRow struct {
+	Period       string  `json:"period" db:"period" example:"2026-07-01"`
+	PropertyID   *int64  `json:"property_id,omitempty" db:"property_id" example:"10"`
+	Status       string  `json:"status" db:"status" example:"CONFIRMED"`
+	Reservations int64   `json:"reservations" db:"reservations" example:"12"`
+	Revenue      float64 `json:"revenue" db:"revenue" example:"2880.00"`
+}
+
+// LeadTimeRow is how many days ahead of check-in reservations were made
+type LeadTimeRow struct {
+	Period       string  `json:"period" db:"period" example:"2026-07-01"`
+	PropertyID   *int64  `json:"property_id,omitempty" db:"property_id" example:"10"`
+	Reservations int64   `json:"reservations" db:"reservations" example:"12"`
+	AverageDays  float64 `json:"average_days" db:"average_days" example:"34.5"`
+	MedianDays   float64 `json:"median_days" db:"median_days" example:"28"`
+	MinDays      int64   `json:"min_days" db:"min_days" example:"0"`
+	MaxDays      int64   `json:"max_days" db:"max_days" example:"180"`
+}
+
+// StayLengthRow is the number of reservations of a length of stay. Stays of
+// 8 to 13 nights and of 14 nights or more are counted together.
+type StayLengthRow struct {
+	Period       string `json:"period" db:"period" example:"2026-07-01"`
+	PropertyID   *int64 `json:"property_id,omitempty" db:"property_id" example:"10"`
+	Nights       string `json:"nights" db:"nights" example:"3"`
+	Reservations int64  `json:"reservations" db:"reservations" example:"5"`
+}
+
+// CancellationRow is the share of reservations that were cancelled
+type CancellationRow struct {
+	Period           string  `json:"period" db:"period" example:"2026-07-01"`
+	PropertyID       *int64  `json:"property_id,omitempty" db:"property_id" example:"10"`
+	Reservations     int64   `json:"reservations" db:"reservations" example:"20"`
+	Cancelled        int64   `json:"cancelled" db:"cancelled" example:"3"`
+	CancellationRate float64 `json:"cancellation_rate" db:"cancellation_rate" example:"15.00"`
+}
+
+// PerformanceReport is the occupancy, ADR and RevPAR report
+type PerformanceReport struct {
+	Meta
+	Rows []PerformanceRow `json:"rows"`
+}
+
+// StatusReport is the revenue by status report
+type StatusReport struct {
+	Meta
+	Rows []StatusRow `json:"rows"`
+}
+
+// LeadTimeReport is the lead time report
+type LeadTimeReport struct {
+	Meta
+	Rows []LeadTimeRow `json:"rows"`
+}
+
+// StayLengthReport is the length of stay distribution
+type StayLengthReport struct {
+	Meta
+	Rows []StayLengthRow `json:"rows"`
+}
+
+// CancellationReport is the cancellation rate report
+type CancellationReport struct {
+	Meta
+	Rows []CancellationRow `json:"rows"`
+}
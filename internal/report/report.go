@@ -0,0 +1,12 @@
+package report
+
+import (
+	"go.uber.org/fx"
+)
+
+// ======== EXPORTS ========
+
+// Module exports the reservation reports
+var Module = fx.Options(
+	fx.Provide(NewRepository, NewService, NewController, SetRoutes),
+)
@@ -0,0 +1,216 @@
+package report
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Every query takes the same parameters:
+// $1 organization, $2 first day, $3 last day (inclusive), $4 period length
+// (day, week or month), $5 whether rows are per property and $6 an optional
+// property.
+
+// scope limits a query to the reservations of the organization that are not
+// deleted, and of the property if one is given. Holds are left out: they are
+// no bookings until they are converted.
+const scope = `
+    r.organization_id = $1
+    AND r.deleted_at IS NULL
+    AND r.kind = 'BOOKING'
+    AND ($6::bigint IS NULL OR r.property_id = $6)
+`
+
+// byCheckIn scopes a query to the reservations checking in between the
+// first and the last day
+const byCheckIn = scope + `
+    AND r.check_in_date::date BETWEEN $2::date AND $3::date
+`
+
+// checkInPeriod and property are the grouping columns of queries over
+// reservations by check-in day
+const (
+	checkInPeriod = `to_char(date_trunc($4, r.check_in_date::date::timestamp), 'YYYY-MM-DD') AS period`
+	property      = `CASE WHEN $5::boolean THEN r.property_id::bigint END AS property_id`
+)
+
+// Repository runs the report queries
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+// NewRepository returns a Repository
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+// Performance returns the available and booked nights and the revenue per
+// period. Every active unit of a property, or the property itself when it
+// has no room types, is available every night of the range, and is booked
+// by confirmed and completed reservations; revenue is spread evenly over
+// the nights of a stay and excludes tourist tax.
+func (r *Repository) Performance(f filter) ([]PerformanceRow, error) {
+	query := `
+        WITH properties AS (
+            SELECT p.property_id,
+                   GREATEST((
+                       SELECT COUNT(*)
+                       FROM room_unit u
+                       WHERE u.organization_id = $1
+                         AND u.property_id = p.property_id
+                         AND u.active
+                   ), 1) AS units
+            FROM (
+                SELECT DISTINCT r.property_id::bigint AS property_id
+                FROM reservation r
+                WHERE ` + scope + `
+            ) p
+        ),
+        supply AS (
+            SELECT date_trunc($4, d) AS period, p.property_id, SUM(p.units) AS available
+            FROM generate_series($2::date::timestamp, $3::date::timestamp, interval '1 day') d
+            CROSS JOIN properties p
+            GROUP BY 1, 2
+        ),
+        booked AS (
+            SELECT date_trunc($4, n) AS period,
+                   r.property_id::bigint AS property_id,
+                   COUNT(*) AS nights,
+                   SUM((r.total_price - COALESCE((r.price_elements->'tourist_tax'->>'amount')::numeric, 0))
+                       / GREATEST(r.check_out_date::date - r.check_in_date::date, 1)) AS revenue
+            FROM reservation r
+            CROSS JOIN LATERAL generate_series(
+                GREATEST(r.check_in_date::date, $2::date)::timestamp,
+                LEAST(r.check_out_date::date - 1, $3::date)::timestamp,
+                interval '1 day'
+            ) n
+            WHERE ` + scope + `
+              AND r.status IN ('CONFIRMED', 'COMPLETED')
+              AND r.check_in_date::date <= $3::date
+              AND r.check_out_date::date > $2::date
+            GROUP BY 1, 2
+        )
+        SELECT to_char(s.period, 'YYYY-MM-DD') AS period,
+               CASE WHEN $5::boolean THEN s.property_id END AS property_id,
+               SUM(s.available)::bigint AS available_nights,
+               COALESCE(SUM(b.nights), 0)::bigint AS booked_nights,
+               COALESCE(ROUND(SUM(b.nights) * 100.0 / NULLIF(SUM(s.available), 0), 2), 0)::float8 AS occupancy,
+               COALESCE(ROUND(SUM(b.revenue), 2), 0)::float8 AS revenue,
+               COALESCE(ROUND(SUM(b.revenue) / NULLIF(SUM(b.nights), 0), 2), 0)::float8 AS adr,
+               COALESCE(ROUND(SUM(b.revenue) / NULLIF(SUM(s.available), 0), 2), 0)::float8 AS revpar
+        FROM supply s
+        LEFT JOIN booked b ON b.period = s.period AND b.property_id = s.property_id
+        GROUP BY 1, 2
+        ORDER BY 1, 2
+    `
+
+	return collect[PerformanceRow](r, query, f)
+}
+
+// RevenueByStatus returns the number and total price of the reservations
+// checking in per period, by status
+func (r *Repository) RevenueByStatus(f filter) ([]StatusRow, error) {
+	query := `
+        SELECT ` + checkInPeriod + `, ` + property + `,
+               r.status,
+               COUNT(*) AS reservations,
+               COALESCE(SUM(r.total_price), 0)::float8 AS revenue
+        FROM reservation r
+        WHERE ` + byCheckIn + `
+        GROUP BY 1, 2, 3
+        ORDER BY 1, 2, 3
+    `
+
+	return collect[StatusRow](r, query, f)
+}
+
+// LeadTime returns the days between booking and check-in of the
+// reservations checking in per period, except cancelled and rejected ones
+func (r *Repository) LeadTime(f filter) ([]LeadTimeRow, error) {
+	query := `
+        WITH stays AS (
+            SELECT ` + checkInPeriod + `, ` + property + `,
+                   GREATEST(r.check_in_date::date - r.created_at::date, 0) AS lead_days
+            FROM reservation r
+            WHERE ` + byCheckIn + `
+              AND r.status NOT IN ('CANCELLED', 'REJECTED')
+        )
+        SELECT period, property_id,
+               COUNT(*) AS reservations,
+               ROUND(AVG(lead_days), 1)::float8 AS average_days,
+               percentile_cont(0.5) WITHIN GROUP (ORDER BY lead_days)::float8 AS median_days,
+               MIN(lead_days)::bigint AS min_days,
+               MAX(lead_days)::bigint AS max_days
+        FROM stays
+        GROUP BY 1, 2
+        ORDER BY 1, 2
+    `
+
+	return collect[LeadTimeRow](r, query, f)
+}
+
+// StayLengths returns the number of reservations checking in per period by
+// length of stay, except cancelled and rejected ones
+func (r *Repository) StayLengths(f filter) ([]StayLengthRow, error) {
+	query := `
+        WITH stays AS (
+            SELECT ` + checkInPeriod + `, ` + property + `,
+                   r.check_out_date::date - r.check_in_date::date AS nights
+            FROM reservation r
+            WHERE ` + byCheckIn + `
+              AND r.status NOT IN ('CANCELLED', 'REJECTED')
+        )
+        SELECT period, property_id,
+               CASE
+                   WHEN nights >= 14 THEN '14+'
+                   WHEN nights >= 8 THEN '8-13'
+                   ELSE nights::text
+               END AS nights,
+               COUNT(*) AS reservations
+        FROM stays
+        GROUP BY 1, 2, 3
+        ORDER BY 1, 2, MIN(nights)
+    `
+
+	return collect[StayLengthRow](r, query, f)
+}
+
+// Cancellations returns the share of the reservations checking in per
+// period that were cancelled. Rejected reservations never were bookings and
+// are not counted.
+func (r *Repository) Cancellations(f filter) ([]CancellationRow, error) {
+	query := `
+        SELECT ` + checkInPeriod + `, ` + property + `,
+               COUNT(*) AS reservations,
+               COUNT(*) FILTER (WHERE r.status = 'CANCELLED') AS cancelled,
+               ROUND(COUNT(*) FILTER (WHERE r.status = 'CANCELLED') * 100.0 / COUNT(*), 2)::float8 AS cancellation_rate
+        FROM reservation r
+        WHERE ` + byCheckIn + `
+          AND r.status <> 'REJECTED'
+        GROUP BY 1, 2
+        ORDER BY 1, 2
+    `
+
+	return collect[CancellationRow](r, query, f)
+}
+
+// collect runs a report query with the parameters of a filter
+func collect[T any](r *Repository, query string, f filter) ([]T, error) {
+	rows, err := r.db.Query(context.Background(), query,
+		f.OrganizationID,
+		f.From,
+		f.To,
+		f.GroupBy,
+		f.ByProperty,
+		f.PropertyID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[T])
+}
@@ -0,0 +1,52 @@
+package report
+
+import (
+	"hostflow/booking-service/internal/middlewares"
+	"hostflow/booking-service/pkg/lib"
+)
+
+// Routes struct
+type Routes struct {
+	logger              lib.Logger
+	router              *lib.Router
+	controller          *Controller
+	authMiddleware      middlewares.AuthMiddleware
+	rateLimitMiddleware middlewares.RateLimitMiddleware
+}
+
+// SetRoutes returns a Routes struct
+func SetRoutes(
+	logger lib.Logger,
+	router *lib.Router,
+	controller *Controller,
+	authMiddleware middlewares.AuthMiddleware,
+	rateLimitMiddleware middlewares.RateLimitMiddleware,
+) Routes {
+	return Routes{
+		logger:              logger,
+		router:              router,
+		controller:          controller,
+		authMiddleware:      authMiddleware,
+		rateLimitMiddleware: rateLimitMiddleware,
+	}
+}
+
+// Setup registers the report routes. Reports aggregate over many
+// reservations and share the search budget.
+func (route Routes) Setup() {
+	route.logger.Info("Setting up [REPORT] routes.")
+
+	reports := route.router.Group("/reports")
+	reports.Use(
+		route.authMiddleware.Handler(),
+		middlewares.RequirePermission(middlewares.ReportsRead),
+		route.rateLimitMiddleware.Limit(middlewares.BudgetSearch),
+	)
+	{
+		reports.GET("/performance", route.controller.PerformanceHandler)
+		reports.GET("/revenue-by-status", route.controller.RevenueByStatusHandler)
+		reports.GET("/lead-time", route.controller.LeadTimeHandler)
+		reports.GET("/length-of-stay", route.controller.StayLengthsHandler)
+		reports.GET("/cancellations", route.controller.CancellationsHandler)
+	}
+}
@@ -0,0 +1,145 @@
+package report
+
+import (
+	"hostflow/booking-service/pkg/lib"
+	"time"
+)
+
+// Service computes reservation reports
+type Service struct {
+	repo   *Repository
+	logger lib.Logger
+	now    func() time.Time
+}
+
+// NewService returns a Service
+func NewService(repo *Repository, logger lib.Logger) *Service {
+	return &Service{
+		repo:   repo,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Performance returns the occupancy, ADR and RevPAR report
+func (s *Service) Performance(organizationID int64, q Query) (*PerformanceReport, error) {
+	f, meta, err := s.filter(organizationID, q)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.Performance(f)
+	if err != nil {
+		return nil, err
+	}
+	return &PerformanceReport{Meta: meta, Rows: orEmpty(rows)}, nil
+}
+
+// RevenueByStatus returns the revenue by status report
+func (s *Service) RevenueByStatus(organizationID int64, q Query) (*StatusReport, error) {
+	f, meta, err := s.filter(organizationID, q)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.RevenueByStatus(f)
+	if err != nil {
+		return nil, err
+	}
+	return &StatusReport{Meta: meta, Rows: orEmpty(rows)}, nil
+}
+
+// LeadTime returns the lead time report
+func (s *Service) LeadTime(organizationID int64, q Query) (*LeadTimeReport, error) {
+	f, meta, err := s.filter(organizationID, q)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.LeadTime(f)
+	if err != nil {
+		return nil, err
+	}
+	return &LeadTimeReport{Meta: meta, Rows: orEmpty(rows)}, nil
+}
+
+// StayLengths returns the length of stay distribution
+func (s *Service) StayLengths(organizationID int64, q Query) (*StayLengthReport, error) {
+	f, meta, err := s.filter(organizationID, q)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.StayLengths(f)
+	if err != nil {
+		return nil, err
+	}
+	return &StayLengthReport{Meta: meta, Rows: orEmpty(rows)}, nil
+}
+
+// Cancellations returns the cancellation rate report
+func (s *Service) Cancellations(organizationID int64, q Query) (*CancellationReport, error) {
+	f, meta, err := s.filter(organizationID, q)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.Cancellations(f)
+	if err != nil {
+		return nil, err
+	}
+	return &CancellationReport{Meta: meta, Rows: orEmpty(rows)}, nil
+}
+
+// ======== PRIVATE METHODS ========
+
+// filter validates a query. Without dates, a report covers the current
+// month; without a period length, rows are per month.
+func (s *Service) filter(organizationID int64, q Query) (filter, Meta, error) {
+	now := s.now().UTC()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, -1)
+
+	var err error
+	if q.From != "" {
+		if from, err = time.Parse(DateLayout, q.From); err != nil {
+			return filter{}, Meta{}, ErrInvalidRange
+		}
+	}
+	if q.To != "" {
+		if to, err = time.Parse(DateLayout, q.To); err != nil {
+			return filter{}, Meta{}, ErrInvalidRange
+		}
+	} else if q.From != "" {
+		to = from.AddDate(0, 1, -1)
+	}
+	if to.Before(from) || to.Sub(from) >= maxRange*24*time.Hour {
+		return filter{}, Meta{}, ErrInvalidRange
+	}
+
+	groupBy := q.GroupBy
+	if groupBy == "" {
+		groupBy = GroupMonth
+	}
+
+	f := filter{
+		OrganizationID: organizationID,
+		From:           from,
+		To:             to,
+		GroupBy:        groupBy,
+		ByProperty:     q.ByProperty,
+		PropertyID:     q.PropertyID,
+	}
+	meta := Meta{
+		From:       from.Format(DateLayout),
+		To:         to.Format(DateLayout),
+		GroupBy:    groupBy,
+		ByProperty: q.ByProperty,
+		PropertyID: q.PropertyID,
+	}
+	return f, meta, nil
+}
+
+// orEmpty returns an empty slice for nil, so that JSON has [] rather than
+// null
+func orEmpty[T any](rows []T) []T {
+	if rows == nil {
+		return []T{}
+	}
+	return rows
+}
@@ -0,0 +1,47 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testService() *Service {
+	return &Service{now: func() time.Time {
+		return time.Date(2026, time.February, 14, 9, 30, 0, 0, time.UTC)
+	}}
+}
+
+func TestFilterDefaultsToCurrentMonth(t *testing.T) {
+	f, meta, err := testService().filter(1, Query{})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(1), f.OrganizationID)
+	assert.Equal(t, "2026-02-01", meta.From)
+	assert.Equal(t, "2026-02-28", meta.To)
+	assert.Equal(t, GroupMonth, f.GroupBy)
+}
+
+func TestFilterFromCoversOneMonth(t *testing.T) {
+	_, meta, err := testService().filter(1, Query{From: "2026-07-15", GroupBy: GroupWeek})
+	require.NoError(t, err)
+
+	assert.Equal(t, "2026-07-15", meta.From)
+	assert.Equal(t, "2026-08-14", meta.To)
+	assert.Equal(t, GroupWeek, meta.GroupBy)
+}
+
+func TestFilterRejectsInvalidRanges(t *testing.T) {
+	for name, q := range map[string]Query{
+		"to before from": {From: "2026-07-15", To: "2026-07-14"},
+		"over two years": {From: "2026-01-01", To: "2028-01-02"},
+		"invalid date":   {From: "2026-13-01"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := testService().filter(1, q)
+			assert.ErrorIs(t, err, ErrInvalidRange)
+		})
+	}
+}
@@ -0,0 +1,325 @@
+package room
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Controller handles HTTP requests for room types and units
+type Controller struct {
+	service *Service
+}
+
+// NewController returns a Controller
+func NewController(service *Service) *Controller {
+	return &Controller{
+		service: service,
+	}
+}
+
+func (c *Controller) getOrgID(ctx *gin.Context) (int64, bool) {
+	val, exists := ctx.Get("organization_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return 0, false
+	}
+	return val.(int64), true
+}
+
+func (c *Controller) getID(ctx *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return 0, false
+	}
+	return id, true
+}
+
+// ======== ROOM TYPES ========
+
+// GetRoomTypesHandler godoc
+// @Summary Get the room types of a property
+// @Description Returns the room types of a property by name, with the number of active units of each
+// @Tags rooms
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Property ID"
+// @Success 200 {array} RoomType
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /properties/{id}/room-types [get]
+func (c *Controller) GetRoomTypesHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+	propertyID, ok := c.getID(ctx)
+	if !ok {
+		return
+	}
+
+	roomTypes, err := c.service.GetRoomTypes(orgID, propertyID)
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, roomTypes)
+}
+
+// CreateRoomTypeHandler godoc
+// @Summary Create a room type
+// @Description Creates a room type of a property. Once a property has room types, its reservations must reference a room type or a unit, and are available while the active units of the type are not all booked on any night. Properties without room types remain a single bookable unit.
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Property ID"
+// @Param request body RoomTypeRequest true "Room type"
+// @Success 201 {object} RoomType
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /properties/{id}/room-types [post]
+func (c *Controller) CreateRoomTypeHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+	propertyID, ok := c.getID(ctx)
+	if !ok {
+		return
+	}
+
+	var req RoomTypeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	roomType, err := c.service.CreateRoomType(orgID, propertyID, req)
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, roomType)
+}
+
+// UpdateRoomTypeHandler godoc
+// @Summary Update a room type
+// @Description Replaces the name, description and guest limit of a room type
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Room type ID"
+// @Param request body RoomTypeRequest true "Room type"
+// @Success 200 {object} RoomType
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /room-types/{id} [put]
+func (c *Controller) UpdateRoomTypeHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+	id, ok := c.getID(ctx)
+	if !ok {
+		return
+	}
+
+	var req RoomTypeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	roomType, err := c.service.UpdateRoomType(id, orgID, req)
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, roomType)
+}
+
+// DeleteRoomTypeHandler godoc
+// @Summary Delete a room type
+// @Description Deletes a room type that has no units or reservations
+// @Tags rooms
+// @Security ApiKeyAuth
+// @Param id path int true "Room type ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /room-types/{id} [delete]
+func (c *Controller) DeleteRoomTypeHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+	id, ok := c.getID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.service.DeleteRoomType(id, orgID); err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ======== UNITS ========
+
+// GetUnitsHandler godoc
+// @Summary Get the units of a room type
+// @Tags rooms
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Room type ID"
+// @Success 200 {array} Unit
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /room-types/{id}/units [get]
+func (c *Controller) GetUnitsHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+	id, ok := c.getID(ctx)
+	if !ok {
+		return
+	}
+
+	units, err := c.service.GetUnits(id, orgID)
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, units)
+}
+
+// CreateUnitHandler godoc
+// @Summary Add a unit to a room type
+// @Description Adds a unit to a room type. Active units count as inventory and are assigned to reservations of the type when they are confirmed.
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Room type ID"
+// @Param request body UnitRequest true "Unit"
+// @Success 201 {object} Unit
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /room-types/{id}/units [post]
+func (c *Controller) CreateUnitHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+	id, ok := c.getID(ctx)
+	if !ok {
+		return
+	}
+
+	var req UnitRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	unit, err := c.service.CreateUnit(id, orgID, req)
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, unit)
+}
+
+// UpdateUnitHandler godoc
+// @Summary Update a unit
+// @Description Renames, activates or deactivates a unit. Inactive units are not counted as inventory and are not assigned; reservations already assigned keep their unit.
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Unit ID"
+// @Param request body UnitRequest true "Unit"
+// @Success 200 {object} Unit
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /units/{id} [put]
+func (c *Controller) UpdateUnitHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+	id, ok := c.getID(ctx)
+	if !ok {
+		return
+	}
+
+	var req UnitRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	unit, err := c.service.UpdateUnit(id, orgID, req)
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, unit)
+}
+
+// DeleteUnitHandler godoc
+// @Summary Delete a unit
+// @Description Deletes a unit that was never assigned to a reservation; deactivate it otherwise
+// @Tags rooms
+// @Security ApiKeyAuth
+// @Param id path int true "Unit ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /units/{id} [delete]
+func (c *Controller) DeleteUnitHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+	id, ok := c.getID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.service.DeleteUnit(id, orgID); err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// fail responds with the status of a service error
+func (c *Controller) fail(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrRoomTypeNotFound), errors.Is(err, ErrUnitNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrDuplicateName), errors.Is(err, ErrInUse):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
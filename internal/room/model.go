@@ -0,0 +1,70 @@
+package room
+
+import (
+	"errors"
+	"time"
+)
+
+// roomTypeColumns are the columns scanned into RoomType. units counts the
+// active units of the type.
+const roomTypeColumns = `
+    t.id, t.organization_id, t.property_id, t.name, t.description, t.max_guests,
+    (SELECT COUNT(*) FROM room_unit u WHERE u.room_type_id = t.id AND u.active) AS units,
+    t.created_at, t.updated_at
+`
+
+// unitColumns are the columns scanned into Unit
+const unitColumns = `
+    id, organization_id, property_id, room_type_id, name, active, created_at, updated_at
+`
+
+// Errors returned by the room service
+var (
+	ErrRoomTypeNotFound = errors.New("room type not found")
+	ErrUnitNotFound     = errors.New("unit not found")
+	ErrDuplicateName    = errors.New("the name is already used")
+	ErrInUse            = errors.New("still referenced by units or reservations; deactivate units instead")
+)
+
+// RoomType is a kind of identical units of a property, such as the double
+// rooms of a guesthouse. Units is the number of active units, which is how
+// many reservations of the type a night can hold.
+type RoomType struct {
+	ID             int64     `json:"id" db:"id" example:"3"`
+	OrganizationID int64     `json:"organization_id" db:"organization_id" example:"1"`
+	PropertyID     int64     `json:"property_id" db:"property_id" example:"10"`
+	Name           string    `json:"name" db:"name" example:"Double room"`
+	Description    string    `json:"description" db:"description" example:"Queen bed, garden view"`
+	MaxGuests      int       `json:"max_guests" db:"max_guests" example:"2"`
+	Units          int       `json:"units" db:"units" example:"8"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// RoomTypeRequest is the body of a room type creation or update. A
+// MaxGuests of 0 doesn't limit the guests.
+type RoomTypeRequest struct {
+	Name        string `json:"name" binding:"required,max=200" example:"Double room"`
+	Description string `json:"description" binding:"max=2000" example:"Queen bed, garden view"`
+	MaxGuests   int    `json:"max_guests" binding:"min=0" example:"2"`
+}
+
+// Unit is a bookable unit of a room type. Inactive units are not counted as
+// inventory and are not assigned.
+type Unit struct {
+	ID             int64     `json:"id" db:"id" example:"21"`
+	OrganizationID int64     `json:"organization_id" db:"organization_id" example:"1"`
+	PropertyID     int64     `json:"property_id" db:"property_id" example:"10"`
+	RoomTypeID     int64     `json:"room_type_id" db:"room_type_id" example:"3"`
+	Name           string    `json:"name" db:"name" example:"Room 4"`
+	Active         bool      `json:"active" db:"active" example:"true"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// UnitRequest is the body of a unit creation or update. Units are active
+// unless Active is false.
+type UnitRequest struct {
+	Name   string `json:"name" binding:"required,max=200" example:"Room 4"`
+	Active *bool  `json:"active" example:"true"`
+}
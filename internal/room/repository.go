@@ -0,0 +1,243 @@
+package room
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Postgres error codes mapped to service errors
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+// Repository persists room types and units
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+// NewRepository returns a Repository
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+// ======== ROOM TYPES ========
+
+// GetRoomTypes returns the room types of a property, by name
+func (r *Repository) GetRoomTypes(organizationID, propertyID int64) ([]RoomType, error) {
+	query := `SELECT ` + roomTypeColumns + `
+        FROM room_type t
+        WHERE t.organization_id = $1
+          AND t.property_id = $2
+        ORDER BY t.name
+    `
+
+	rows, err := r.db.Query(context.Background(), query, organizationID, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[RoomType])
+}
+
+// GetRoomType returns a room type of an organization, or nil if it doesn't
+// exist
+func (r *Repository) GetRoomType(id, organizationID int64) (*RoomType, error) {
+	query := `SELECT ` + roomTypeColumns + `
+        FROM room_type t
+        WHERE t.id = $1
+          AND t.organization_id = $2
+    `
+
+	rows, err := r.db.Query(context.Background(), query, id, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectOne[RoomType](rows)
+}
+
+// CreateRoomType stores a new room type
+func (r *Repository) CreateRoomType(t *RoomType) (*RoomType, error) {
+	query := `
+        WITH t AS (
+            INSERT INTO room_type (organization_id, property_id, name, description, max_guests)
+            VALUES ($1, $2, $3, $4, $5)
+            RETURNING *
+        )
+        SELECT ` + roomTypeColumns + ` FROM t`
+
+	rows, err := r.db.Query(context.Background(), query,
+		t.OrganizationID,
+		t.PropertyID,
+		t.Name,
+		t.Description,
+		t.MaxGuests,
+	)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	created, err := collectOne[RoomType](rows)
+	return created, mapError(err)
+}
+
+// UpdateRoomType replaces the name, description and guest limit of a room
+// type. It returns nil if the room type doesn't exist.
+func (r *Repository) UpdateRoomType(t *RoomType) (*RoomType, error) {
+	query := `
+        WITH t AS (
+            UPDATE room_type
+            SET name = $3,
+                description = $4,
+                max_guests = $5,
+                updated_at = NOW()
+            WHERE id = $1
+              AND organization_id = $2
+            RETURNING *
+        )
+        SELECT ` + roomTypeColumns + ` FROM t`
+
+	rows, err := r.db.Query(context.Background(), query,
+		t.ID,
+		t.OrganizationID,
+		t.Name,
+		t.Description,
+		t.MaxGuests,
+	)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	updated, err := collectOne[RoomType](rows)
+	return updated, mapError(err)
+}
+
+// DeleteRoomType deletes a room type without units or reservations. It
+// returns false if the room type doesn't exist.
+func (r *Repository) DeleteRoomType(id, organizationID int64) (bool, error) {
+	tag, err := r.db.Exec(context.Background(), `
+        DELETE FROM room_type
+        WHERE id = $1
+          AND organization_id = $2
+    `, id, organizationID)
+	if err != nil {
+		return false, mapError(err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ======== UNITS ========
+
+// GetUnits returns the units of a room type, by name
+func (r *Repository) GetUnits(roomTypeID, organizationID int64) ([]Unit, error) {
+	query := `SELECT ` + unitColumns + `
+        FROM room_unit
+        WHERE room_type_id = $1
+          AND organization_id = $2
+        ORDER BY name
+    `
+
+	rows, err := r.db.Query(context.Background(), query, roomTypeID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[Unit])
+}
+
+// CreateUnit stores a new unit
+func (r *Repository) CreateUnit(u *Unit) (*Unit, error) {
+	query := `
+        INSERT INTO room_unit (organization_id, property_id, room_type_id, name, active)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING ` + unitColumns
+
+	rows, err := r.db.Query(context.Background(), query,
+		u.OrganizationID,
+		u.PropertyID,
+		u.RoomTypeID,
+		u.Name,
+		u.Active,
+	)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	created, err := collectOne[Unit](rows)
+	return created, mapError(err)
+}
+
+// UpdateUnit replaces the name and state of a unit. It returns nil if the
+// unit doesn't exist.
+func (r *Repository) UpdateUnit(u *Unit) (*Unit, error) {
+	query := `
+        UPDATE room_unit
+        SET name = $3,
+            active = $4,
+            updated_at = NOW()
+        WHERE id = $1
+          AND organization_id = $2
+        RETURNING ` + unitColumns
+
+	rows, err := r.db.Query(context.Background(), query,
+		u.ID,
+		u.OrganizationID,
+		u.Name,
+		u.Active,
+	)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	updated, err := collectOne[Unit](rows)
+	return updated, mapError(err)
+}
+
+// DeleteUnit deletes a unit that was never assigned. It returns false if
+// the unit doesn't exist.
+func (r *Repository) DeleteUnit(id, organizationID int64) (bool, error) {
+	tag, err := r.db.Exec(context.Background(), `
+        DELETE FROM room_unit
+        WHERE id = $1
+          AND organization_id = $2
+    `, id, organizationID)
+	if err != nil {
+		return false, mapError(err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// collectOne returns the only row, or nil if there is none
+func collectOne[T any](rows pgx.Rows) (*T, error) {
+	v, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[T])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &v, nil
+}
+
+// mapError maps constraint violations to service errors
+func mapError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case uniqueViolation:
+			return ErrDuplicateName
+		case foreignKeyViolation:
+			return ErrInUse
+		}
+	}
+	return err
+}
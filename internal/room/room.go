@@ -0,0 +1,12 @@
+package room
+
+import (
+	"go.uber.org/fx"
+)
+
+// ======== EXPORTS ========
+
+// Module exports the room types and units of properties
+var Module = fx.Options(
+	fx.Provide(NewRepository, NewService, NewController, SetRoutes),
+)
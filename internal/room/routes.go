@@ -0,0 +1,61 @@
+package room
+
+import (
+	"hostflow/booking-service/internal/middlewares"
+	"hostflow/booking-service/pkg/lib"
+)
+
+// Routes struct
+type Routes struct {
+	logger              lib.Logger
+	router              *lib.Router
+	controller          *Controller
+	authMiddleware      middlewares.AuthMiddleware
+	rateLimitMiddleware middlewares.RateLimitMiddleware
+}
+
+// SetRoutes returns a Routes struct
+func SetRoutes(
+	logger lib.Logger,
+	router *lib.Router,
+	controller *Controller,
+	authMiddleware middlewares.AuthMiddleware,
+	rateLimitMiddleware middlewares.RateLimitMiddleware,
+) Routes {
+	return Routes{
+		logger:              logger,
+		router:              router,
+		controller:          controller,
+		authMiddleware:      authMiddleware,
+		rateLimitMiddleware: rateLimitMiddleware,
+	}
+}
+
+// Setup registers the room type and unit routes. Reading requires
+// rooms:read and changes rooms:manage.
+func (route Routes) Setup() {
+	route.logger.Info("Setting up [ROOM] routes.")
+
+	properties := route.router.Group("/properties")
+	properties.Use(route.authMiddleware.Handler())
+	{
+		properties.GET("/:id/room-types", route.rateLimitMiddleware.Limit(middlewares.BudgetDefault), middlewares.RequirePermission(middlewares.RoomsRead), route.controller.GetRoomTypesHandler)
+		properties.POST("/:id/room-types", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), middlewares.RequirePermission(middlewares.RoomsManage), route.controller.CreateRoomTypeHandler)
+	}
+
+	roomTypes := route.router.Group("/room-types")
+	roomTypes.Use(route.authMiddleware.Handler())
+	{
+		roomTypes.PUT("/:id", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), middlewares.RequirePermission(middlewares.RoomsManage), route.controller.UpdateRoomTypeHandler)
+		roomTypes.DELETE("/:id", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), middlewares.RequirePermission(middlewares.RoomsManage), route.controller.DeleteRoomTypeHandler)
+		roomTypes.GET("/:id/units", route.rateLimitMiddleware.Limit(middlewares.BudgetDefault), middlewares.RequirePermission(middlewares.RoomsRead), route.controller.GetUnitsHandler)
+		roomTypes.POST("/:id/units", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), middlewares.RequirePermission(middlewares.RoomsManage), route.controller.CreateUnitHandler)
+	}
+
+	units := route.router.Group("/units")
+	units.Use(route.authMiddleware.Handler())
+	{
+		units.PUT("/:id", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), middlewares.RequirePermission(middlewares.RoomsManage), route.controller.UpdateUnitHandler)
+		units.DELETE("/:id", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), middlewares.RequirePermission(middlewares.RoomsManage), route.controller.DeleteUnitHandler)
+	}
+}
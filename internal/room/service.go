@@ -0,0 +1,140 @@
+package room
+
+import (
+	"hostflow/booking-service/pkg/lib"
+	"strings"
+)
+
+// Service manages the room types and units of properties. Availability and
+// unit assignment are part of the reservation service.
+type Service struct {
+	repo   *Repository
+	logger lib.Logger
+}
+
+// NewService returns a Service
+func NewService(repo *Repository, logger lib.Logger) *Service {
+	return &Service{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// ======== ROOM TYPES ========
+
+// GetRoomTypes returns the room types of a property
+func (s *Service) GetRoomTypes(organizationID, propertyID int64) ([]RoomType, error) {
+	return s.repo.GetRoomTypes(organizationID, propertyID)
+}
+
+// CreateRoomType creates a room type. Once a property has a room type, its
+// reservations must reference one.
+func (s *Service) CreateRoomType(organizationID, propertyID int64, req RoomTypeRequest) (*RoomType, error) {
+	return s.repo.CreateRoomType(&RoomType{
+		OrganizationID: organizationID,
+		PropertyID:     propertyID,
+		Name:           strings.TrimSpace(req.Name),
+		Description:    strings.TrimSpace(req.Description),
+		MaxGuests:      req.MaxGuests,
+	})
+}
+
+// UpdateRoomType replaces the details of a room type
+func (s *Service) UpdateRoomType(id, organizationID int64, req RoomTypeRequest) (*RoomType, error) {
+	updated, err := s.repo.UpdateRoomType(&RoomType{
+		ID:             id,
+		OrganizationID: organizationID,
+		Name:           strings.TrimSpace(req.Name),
+		Description:    strings.TrimSpace(req.Description),
+		MaxGuests:      req.MaxGuests,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		return nil, ErrRoomTypeNotFound
+	}
+	return updated, nil
+}
+
+// DeleteRoomType deletes a room type without units or reservations
+func (s *Service) DeleteRoomType(id, organizationID int64) error {
+	deleted, err := s.repo.DeleteRoomType(id, organizationID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrRoomTypeNotFound
+	}
+	return nil
+}
+
+// ======== UNITS ========
+
+// GetUnits returns the units of a room type
+func (s *Service) GetUnits(roomTypeID, organizationID int64) ([]Unit, error) {
+	if _, err := s.getRoomType(roomTypeID, organizationID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetUnits(roomTypeID, organizationID)
+}
+
+// CreateUnit adds a unit to a room type, which raises its inventory by one
+// if the unit is active
+func (s *Service) CreateUnit(roomTypeID, organizationID int64, req UnitRequest) (*Unit, error) {
+	roomType, err := s.getRoomType(roomTypeID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.CreateUnit(&Unit{
+		OrganizationID: organizationID,
+		PropertyID:     roomType.PropertyID,
+		RoomTypeID:     roomType.ID,
+		Name:           strings.TrimSpace(req.Name),
+		Active:         req.Active == nil || *req.Active,
+	})
+}
+
+// UpdateUnit renames, activates or deactivates a unit. Reservations already
+// assigned to a deactivated unit keep it.
+func (s *Service) UpdateUnit(id, organizationID int64, req UnitRequest) (*Unit, error) {
+	updated, err := s.repo.UpdateUnit(&Unit{
+		ID:             id,
+		OrganizationID: organizationID,
+		Name:           strings.TrimSpace(req.Name),
+		Active:         req.Active == nil || *req.Active,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		return nil, ErrUnitNotFound
+	}
+	return updated, nil
+}
+
+// DeleteUnit deletes a unit that was never assigned to a reservation
+func (s *Service) DeleteUnit(id, organizationID int64) error {
+	deleted, err := s.repo.DeleteUnit(id, organizationID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrUnitNotFound
+	}
+	return nil
+}
+
+// ======== PRIVATE METHODS ========
+
+func (s *Service) getRoomType(id, organizationID int64) (*RoomType, error) {
+	roomType, err := s.repo.GetRoomType(id, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if roomType == nil {
+		return nil, ErrRoomTypeNotFound
+	}
+	return roomType, nil
+}
@@ -0,0 +1,184 @@
+package scheduler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Controller handles HTTP requests for message schedules
+type Controller struct {
+	repo *Repository
+}
+
+// NewController returns a Controller
+func NewController(repo *Repository) *Controller {
+	return &Controller{
+		repo: repo,
+	}
+}
+
+func (c *Controller) getOrgID(ctx *gin.Context) (int64, bool) {
+	val, exists := ctx.Get("organization_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return 0, false
+	}
+	return val.(int64), true
+}
+
+// GetSchedulesHandler godoc
+// @Summary Get message schedules
+// @Description Returns the active guest communication schedules of the organization
+// @Tags communication
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} Schedule
+// @Failure 500 {object} map[string]string
+// @Router /communication/schedules [get]
+func (c *Controller) GetSchedulesHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	schedules, err := c.repo.GetSchedules(orgID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, schedules)
+}
+
+// CreateScheduleHandler godoc
+// @Summary Create a message schedule
+// @Description Sends the given email type offset_days from the check-in or check-out date of every confirmed reservation
+// @Tags communication
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param schedule body ScheduleRequest true "Schedule"
+// @Success 201 {object} Schedule
+// @Failure 400 {object} map[string]string
+// @Router /communication/schedules [post]
+func (c *Controller) CreateScheduleHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	var req ScheduleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule, err := c.repo.CreateSchedule(orgID, &req)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, schedule)
+}
+
+// UpdateScheduleHandler godoc
+// @Summary Update a message schedule
+// @Description Messages already planned with the old timing are re-planned
+// @Tags communication
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Schedule ID"
+// @Param schedule body ScheduleRequest true "Schedule"
+// @Success 200 {object} Schedule
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /communication/schedules/{id} [put]
+func (c *Controller) UpdateScheduleHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid schedule id"})
+		return
+	}
+
+	var req ScheduleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule, err := c.repo.UpdateSchedule(id, orgID, &req)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, schedule)
+}
+
+// DeleteScheduleHandler godoc
+// @Summary Delete a message schedule
+// @Description Stops the schedule; messages that have not been sent yet are dropped
+// @Tags communication
+// @Security ApiKeyAuth
+// @Param id path int true "Schedule ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} map[string]string
+// @Router /communication/schedules/{id} [delete]
+func (c *Controller) DeleteScheduleHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid schedule id"})
+		return
+	}
+
+	if err := c.repo.DeactivateSchedule(id, orgID); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetReservationMessagesHandler godoc
+// @Summary Get scheduled messages of a reservation
+// @Tags reservations
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Reservation ID"
+// @Success 200 {array} ScheduledMessage
+// @Failure 400 {object} map[string]string
+// @Router /reservations/{id}/scheduled-messages [get]
+func (c *Controller) GetReservationMessagesHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid reservation id"})
+		return
+	}
+
+	messages, err := c.repo.GetMessagesByReservation(id, orgID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, messages)
+}
@@ -0,0 +1,58 @@
+package scheduler
+
+import "time"
+
+// Schedule anchors
+const (
+	AnchorCheckIn  = "CHECK_IN"
+	AnchorCheckOut = "CHECK_OUT"
+)
+
+// Scheduled message statuses
+const (
+	MessagePending = "PENDING"
+	MessageQueued  = "QUEUED"
+	MessageSkipped = "SKIPPED"
+)
+
+// Schedule represents a guest communication sent at an offset from the stay dates
+type Schedule struct {
+	ID             int64     `json:"id" db:"id"`
+	OrganizationID int64     `json:"organization_id" db:"organization_id"`
+	PropertyID     *int64    `json:"property_id" db:"property_id"`
+	EmailType      string    `json:"email_type" db:"email_type"`
+	Anchor         string    `json:"anchor" db:"anchor"`
+	OffsetDays     int       `json:"offset_days" db:"offset_days"`
+	SendHour       *int      `json:"send_hour" db:"send_hour"`
+	Active         bool      `json:"active" db:"active"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ScheduleRequest represents the schedule creation/update request.
+//
+// The message is sent offset_days after the anchor date (negative values send
+// it before). When send_hour is set the message goes out at that hour (UTC)
+// of the resulting day, otherwise at the time of day of the anchor itself.
+// Leaving property_id empty applies the schedule to every property of the
+// organization that has no schedule of its own for the same email type.
+type ScheduleRequest struct {
+	PropertyID *int64 `json:"property_id" example:"10"`
+	EmailType  string `json:"email_type" binding:"required,oneof=PRE_ARRIVAL CHECK_IN_INSTRUCTIONS REVIEW_REQUEST" example:"PRE_ARRIVAL"`
+	Anchor     string `json:"anchor" binding:"required,oneof=CHECK_IN CHECK_OUT" example:"CHECK_IN"`
+	OffsetDays int    `json:"offset_days" binding:"min=-365,max=365" example:"-7"`
+	SendHour   *int   `json:"send_hour" binding:"omitempty,min=0,max=23" example:"9"`
+}
+
+// ScheduledMessage represents a planned message for a single reservation
+type ScheduledMessage struct {
+	ID             int64     `json:"id" db:"id"`
+	OrganizationID int64     `json:"organization_id" db:"organization_id"`
+	ScheduleID     int64     `json:"schedule_id" db:"schedule_id"`
+	ReservationID  int64     `json:"reservation_id" db:"reservation_id"`
+	EmailType      string    `json:"email_type" db:"email_type"`
+	SendAt         time.Time `json:"send_at" db:"send_at"`
+	Status         string    `json:"status" db:"status" enums:"PENDING QUEUED SKIPPED"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,297 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository persists schedules and planned messages
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+// NewRepository returns a Repository
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+// GetSchedules returns the active schedules of an organization
+func (r *Repository) GetSchedules(organizationID int64) ([]Schedule, error) {
+	query := `
+        SELECT id, organization_id, property_id, email_type, anchor, offset_days,
+               send_hour, active, created_at, updated_at
+        FROM message_schedule
+        WHERE organization_id = $1
+          AND active
+        ORDER BY property_id NULLS FIRST, email_type
+    `
+
+	rows, err := r.db.Query(context.Background(), query, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[Schedule])
+}
+
+// CreateSchedule stores a new schedule
+func (r *Repository) CreateSchedule(organizationID int64, req *ScheduleRequest) (*Schedule, error) {
+	query := `
+        INSERT INTO message_schedule (
+            organization_id, property_id, email_type, anchor, offset_days, send_hour
+        )
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id, organization_id, property_id, email_type, anchor, offset_days,
+                  send_hour, active, created_at, updated_at
+    `
+
+	rows, err := r.db.Query(context.Background(), query,
+		organizationID,
+		req.PropertyID,
+		req.EmailType,
+		req.Anchor,
+		req.OffsetDays,
+		req.SendHour,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedule, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[Schedule])
+	if err != nil {
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+// UpdateSchedule changes a schedule. Messages that were planned with the old
+// timing and have not been queued yet are dropped so they get planned again.
+func (r *Repository) UpdateSchedule(id, organizationID int64, req *ScheduleRequest) (*Schedule, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+        UPDATE message_schedule
+        SET property_id = $3,
+            email_type = $4,
+            anchor = $5,
+            offset_days = $6,
+            send_hour = $7,
+            updated_at = NOW()
+        WHERE id = $1
+          AND organization_id = $2
+          AND active
+        RETURNING id, organization_id, property_id, email_type, anchor, offset_days,
+                  send_hour, active, created_at, updated_at
+    `
+
+	rows, err := tx.Query(ctx, query,
+		id,
+		organizationID,
+		req.PropertyID,
+		req.EmailType,
+		req.Anchor,
+		req.OffsetDays,
+		req.SendHour,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[Schedule])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("schedule not found")
+		}
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM scheduled_message WHERE schedule_id = $1 AND status = $2`, id, MessagePending); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+// DeactivateSchedule stops a schedule and drops its pending messages. The
+// schedule row is kept so already queued messages still reference it.
+func (r *Repository) DeactivateSchedule(id, organizationID int64) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `
+        UPDATE message_schedule
+        SET active = FALSE,
+            updated_at = NOW()
+        WHERE id = $1
+          AND organization_id = $2
+          AND active
+    `, id, organizationID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return errors.New("schedule not found")
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM scheduled_message WHERE schedule_id = $1 AND status = $2`, id, MessagePending); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// PlanMessages creates the pending messages of every active schedule for
+// confirmed reservations. Messages whose send time has passed by more than
+// grace are only planned while the anchor date itself is still ahead, so a
+// late booking still gets its pre-arrival email but old stays don't get
+// review requests when a schedule is created. Offsets and send hours are
+// applied in UTC whatever the session time zone. Pending messages follow
+// their reservation: when its dates change, they are moved to the new send
+// time. Planning is idempotent and safe to run on several replicas at once.
+func (r *Repository) PlanMessages(grace time.Duration) (int64, error) {
+	query := `
+        INSERT INTO scheduled_message (organization_id, schedule_id, reservation_id, email_type, send_at)
+        SELECT organization_id, schedule_id, reservation_id, email_type, send_at
+        FROM (
+            SELECT s.organization_id,
+                   s.id AS schedule_id,
+                   r.id AS reservation_id,
+                   s.email_type,
+                   a.anchor_at,
+                   CASE
+                       WHEN s.send_hour IS NULL THEN (a.anchor_at AT TIME ZONE 'UTC' + make_interval(days => s.offset_days)) AT TIME ZONE 'UTC'
+                       ELSE (date_trunc('day', a.anchor_at AT TIME ZONE 'UTC') + make_interval(days => s.offset_days, hours => s.send_hour)) AT TIME ZONE 'UTC'
+                   END AS send_at
+            FROM message_schedule s
+            JOIN reservation r
+              ON r.organization_id = s.organization_id
+             AND (s.property_id IS NULL OR s.property_id = r.property_id)
+            CROSS JOIN LATERAL (
+                SELECT CASE WHEN s.anchor = 'CHECK_IN' THEN r.check_in_date ELSE r.check_out_date END AS anchor_at
+            ) a
+            WHERE s.active
+              AND r.status = 'CONFIRMED'
+              AND r.deleted_at IS NULL
+              AND (
+                  s.property_id IS NOT NULL
+                  OR NOT EXISTS (
+                      SELECT 1
+                      FROM message_schedule p
+                      WHERE p.organization_id = s.organization_id
+                        AND p.property_id = r.property_id
+                        AND p.email_type = s.email_type
+                        AND p.active
+                  )
+              )
+        ) planned
+        WHERE send_at >= NOW() - $1::interval
+           OR anchor_at > NOW()
+        ON CONFLICT (schedule_id, reservation_id) DO UPDATE
+        SET send_at = EXCLUDED.send_at,
+            updated_at = NOW()
+        WHERE scheduled_message.status = 'PENDING'
+          AND scheduled_message.send_at <> EXCLUDED.send_at
+    `
+
+	result, err := r.db.Exec(context.Background(), query, grace.String())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// QueueDueMessages moves up to limit due messages into the email delivery
+// queue in a single statement, so a message is queued exactly once even with
+// several replicas. Messages of reservations that are no longer confirmed, or
+// were deleted, are skipped instead.
+func (r *Repository) QueueDueMessages(limit int) (int64, error) {
+	ctx := context.Background()
+
+	skip := `
+        UPDATE scheduled_message m
+        SET status = $1,
+            updated_at = NOW()
+        FROM reservation r
+        WHERE r.id = m.reservation_id
+          AND m.status = $2
+          AND m.send_at <= NOW()
+          AND (r.status <> 'CONFIRMED' OR r.deleted_at IS NOT NULL)
+    `
+	if _, err := r.db.Exec(ctx, skip, MessageSkipped, MessagePending); err != nil {
+		return 0, err
+	}
+
+	queue := `
+        WITH due AS (
+            UPDATE scheduled_message m
+            SET status = $1,
+                updated_at = NOW()
+            WHERE m.id IN (
+                SELECT id
+                FROM scheduled_message
+                WHERE status = $2
+                  AND send_at <= NOW()
+                ORDER BY send_at
+                LIMIT $3
+                FOR UPDATE SKIP LOCKED
+            )
+            RETURNING m.organization_id, m.reservation_id, m.email_type
+        )
+        INSERT INTO email_delivery (organization_id, reservation_id, customer_id, property_id, email_type)
+        SELECT due.organization_id, due.reservation_id, r.customer_id, r.property_id, due.email_type
+        FROM due
+        JOIN reservation r ON r.id = due.reservation_id
+    `
+
+	result, err := r.db.Exec(ctx, queue, MessageQueued, MessagePending, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// GetMessagesByReservation returns the planned messages of a reservation
+func (r *Repository) GetMessagesByReservation(reservationID, organizationID int64) ([]ScheduledMessage, error) {
+	query := `
+        SELECT id, organization_id, schedule_id, reservation_id, email_type, send_at,
+               status, created_at, updated_at
+        FROM scheduled_message
+        WHERE reservation_id = $1
+          AND organization_id = $2
+        ORDER BY send_at
+    `
+
+	rows, err := r.db.Query(context.Background(), query, reservationID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[ScheduledMessage])
+}
@@ -0,0 +1,274 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hostflow/booking-service/internal/dbtest"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func utc(year int, month time.Month, day, hour, min int) time.Time {
+	return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+}
+
+func hour(h int) *int {
+	return &h
+}
+
+// seedReservation stores a reservation of organization 100 directly in the
+// test database
+func seedReservation(t *testing.T, db *pgxpool.Pool, id, propertyID int64, checkIn, checkOut time.Time, status string) {
+	t.Helper()
+
+	_, err := db.Exec(context.Background(), `
+        INSERT INTO reservation (id, organization_id, property_id, customer_id, check_in_date, check_out_date, status)
+        VALUES ($1, 100, $2, 7, $3, $4, $5)
+    `, id, propertyID, checkIn, checkOut, status)
+	require.NoError(t, err)
+}
+
+func TestPlanMessages_SendAt(t *testing.T) {
+	tests := []struct {
+		name     string
+		anchor   string
+		offset   int
+		sendHour *int
+		checkIn  time.Time
+		checkOut time.Time
+		want     time.Time
+	}{
+		{
+			name:     "days before check-in keep the time of day",
+			anchor:   AnchorCheckIn,
+			offset:   -7,
+			checkIn:  utc(2030, 4, 2, 14, 0),
+			checkOut: utc(2030, 4, 5, 10, 0),
+			// Crosses the end of March, when Europe moves its clocks
+			want: utc(2030, 3, 26, 14, 0),
+		},
+		{
+			name:     "day before check-in at a fixed hour",
+			anchor:   AnchorCheckIn,
+			offset:   -1,
+			sendHour: hour(9),
+			checkIn:  utc(2030, 4, 2, 14, 0),
+			checkOut: utc(2030, 4, 5, 10, 0),
+			want:     utc(2030, 4, 1, 9, 0),
+		},
+		{
+			name:     "check-in day is the UTC day of a late arrival",
+			anchor:   AnchorCheckIn,
+			sendHour: hour(9),
+			checkIn:  utc(2030, 4, 2, 23, 30),
+			checkOut: utc(2030, 4, 5, 10, 0),
+			want:     utc(2030, 4, 2, 9, 0),
+		},
+		{
+			name:     "check-out itself",
+			anchor:   AnchorCheckOut,
+			checkIn:  utc(2030, 4, 2, 14, 0),
+			checkOut: utc(2030, 4, 5, 10, 0),
+			want:     utc(2030, 4, 5, 10, 0),
+		},
+		{
+			name:     "day after check-out at a fixed hour",
+			anchor:   AnchorCheckOut,
+			offset:   1,
+			sendHour: hour(10),
+			checkIn:  utc(2030, 10, 20, 14, 0),
+			checkOut: utc(2030, 10, 26, 23, 0),
+			// The day after check-out is the day Europe moves its clocks back
+			want: utc(2030, 10, 27, 10, 0),
+		},
+	}
+
+	for _, timeZone := range []string{"UTC", "Europe/Ljubljana", "America/Los_Angeles"} {
+		t.Run(timeZone, func(t *testing.T) {
+			db := dbtest.OpenInTimeZone(t, timeZone)
+			repo := NewRepository(db)
+
+			for i, tt := range tests {
+				// Each case gets its own property so only its schedule applies
+				propertyID := int64(10 + i)
+				reservationID := int64(1 + i)
+				seedReservation(t, db, reservationID, propertyID, tt.checkIn, tt.checkOut, "CONFIRMED")
+				_, err := repo.CreateSchedule(100, &ScheduleRequest{
+					PropertyID: &propertyID,
+					EmailType:  "PRE_ARRIVAL",
+					Anchor:     tt.anchor,
+					OffsetDays: tt.offset,
+					SendHour:   tt.sendHour,
+				})
+				require.NoError(t, err)
+			}
+
+			planned, err := repo.PlanMessages(planGrace)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tests)), planned)
+
+			for i, tt := range tests {
+				messages, err := repo.GetMessagesByReservation(int64(1+i), 100)
+				require.NoError(t, err)
+				require.Len(t, messages, 1, tt.name)
+				assert.True(t, tt.want.Equal(messages[0].SendAt), "%s: got %s, want %s", tt.name, messages[0].SendAt.UTC(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanMessages_SkipsPastMessagesOfPastStays(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := NewRepository(db)
+	now := time.Now().UTC()
+
+	// Review request for a stay that ended a month ago
+	seedReservation(t, db, 1, 10, now.AddDate(0, -1, -3), now.AddDate(0, -1, 0), "CONFIRMED")
+	// Pre-arrival email of a late booking whose send time has passed
+	seedReservation(t, db, 2, 11, now.Add(24*time.Hour), now.Add(72*time.Hour), "CONFIRMED")
+
+	for _, req := range []ScheduleRequest{
+		{PropertyID: ptr(10), EmailType: "REVIEW_REQUEST", Anchor: AnchorCheckOut, OffsetDays: 1},
+		{PropertyID: ptr(11), EmailType: "PRE_ARRIVAL", Anchor: AnchorCheckIn, OffsetDays: -7},
+	} {
+		_, err := repo.CreateSchedule(100, &req)
+		require.NoError(t, err)
+	}
+
+	planned, err := repo.PlanMessages(planGrace)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), planned)
+
+	messages, err := repo.GetMessagesByReservation(2, 100)
+	require.NoError(t, err)
+	assert.Len(t, messages, 1)
+}
+
+func TestPlanAndQueueMessages_AreIdempotent(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := NewRepository(db)
+	now := time.Now().UTC()
+
+	// Check-in half an hour ago: the message is due but still within grace
+	seedReservation(t, db, 1, 10, now.Add(-30*time.Minute), now.Add(48*time.Hour), "CONFIRMED")
+	_, err := repo.CreateSchedule(100, &ScheduleRequest{EmailType: "CHECK_IN_INSTRUCTIONS", Anchor: AnchorCheckIn})
+	require.NoError(t, err)
+
+	for i, want := range []int64{1, 0} {
+		planned, err := repo.PlanMessages(planGrace)
+		require.NoError(t, err)
+		assert.Equal(t, want, planned, "plan pass %d", i+1)
+	}
+
+	for i, want := range []int64{1, 0} {
+		queued, err := repo.QueueDueMessages(queueBatchSize)
+		require.NoError(t, err)
+		assert.Equal(t, want, queued, "queue pass %d", i+1)
+	}
+
+	// Planning again after the message went out doesn't plan it anew
+	planned, err := repo.PlanMessages(planGrace)
+	require.NoError(t, err)
+	assert.Zero(t, planned)
+
+	var deliveries int
+	require.NoError(t, db.QueryRow(context.Background(), `
+        SELECT COUNT(*) FROM email_delivery WHERE reservation_id = 1 AND email_type = 'CHECK_IN_INSTRUCTIONS'
+    `).Scan(&deliveries))
+	assert.Equal(t, 1, deliveries)
+
+	messages, err := repo.GetMessagesByReservation(1, 100)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, MessageQueued, messages[0].Status)
+}
+
+func TestQueueDueMessages_SkipsReservationsNoLongerConfirmed(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := NewRepository(db)
+	now := time.Now().UTC()
+
+	seedReservation(t, db, 1, 10, now.Add(-30*time.Minute), now.Add(48*time.Hour), "CONFIRMED")
+	_, err := repo.CreateSchedule(100, &ScheduleRequest{EmailType: "CHECK_IN_INSTRUCTIONS", Anchor: AnchorCheckIn})
+	require.NoError(t, err)
+
+	_, err = repo.PlanMessages(planGrace)
+	require.NoError(t, err)
+
+	_, err = db.Exec(context.Background(), `UPDATE reservation SET status = 'CANCELLED' WHERE id = 1`)
+	require.NoError(t, err)
+
+	queued, err := repo.QueueDueMessages(queueBatchSize)
+	require.NoError(t, err)
+	assert.Zero(t, queued)
+
+	messages, err := repo.GetMessagesByReservation(1, 100)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, MessageSkipped, messages[0].Status)
+}
+
+func ptr(v int64) *int64 {
+	return &v
+}
+
+func TestPlanMessages_MovesPendingMessagesWithTheirStay(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+	checkIn, checkOut := utc(2030, 4, 2, 14, 0), utc(2030, 4, 5, 10, 0)
+
+	seedReservation(t, db, 1, 10, checkIn, checkOut, "CONFIRMED")
+	seedReservation(t, db, 2, 10, checkIn, checkOut, "CONFIRMED")
+	for _, req := range []ScheduleRequest{
+		{EmailType: "PRE_ARRIVAL", Anchor: AnchorCheckIn, OffsetDays: -7},
+		{EmailType: "REVIEW_REQUEST", Anchor: AnchorCheckOut, OffsetDays: 1, SendHour: hour(10)},
+	} {
+		_, err := repo.CreateSchedule(100, &req)
+		require.NoError(t, err)
+	}
+
+	planned, err := repo.PlanMessages(planGrace)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), planned)
+
+	// The review request of the second stay was already sent
+	_, err = db.Exec(ctx, `UPDATE scheduled_message SET status = $1 WHERE reservation_id = 2 AND email_type = 'REVIEW_REQUEST'`, MessageQueued)
+	require.NoError(t, err)
+
+	// Both stays move a week later
+	_, err = db.Exec(ctx, `
+        UPDATE reservation
+        SET check_in_date = check_in_date + INTERVAL '7 days',
+            check_out_date = check_out_date + INTERVAL '7 days'
+    `)
+	require.NoError(t, err)
+
+	planned, err = repo.PlanMessages(planGrace)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), planned)
+
+	want := map[int64]map[string]time.Time{
+		1: {"PRE_ARRIVAL": utc(2030, 4, 2, 14, 0), "REVIEW_REQUEST": utc(2030, 4, 13, 10, 0)},
+		// A message that went out is not planned again
+		2: {"PRE_ARRIVAL": utc(2030, 4, 2, 14, 0), "REVIEW_REQUEST": utc(2030, 4, 6, 10, 0)},
+	}
+	for reservationID, sendAt := range want {
+		messages, err := repo.GetMessagesByReservation(reservationID, 100)
+		require.NoError(t, err)
+		require.Len(t, messages, 2)
+		for _, m := range messages {
+			assert.True(t, sendAt[m.EmailType].Equal(m.SendAt), "reservation %d %s: got %s, want %s",
+				reservationID, m.EmailType, m.SendAt.UTC(), sendAt[m.EmailType])
+		}
+	}
+
+	// Planning again changes nothing
+	planned, err = repo.PlanMessages(planGrace)
+	require.NoError(t, err)
+	assert.Zero(t, planned)
+}
@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"hostflow/booking-service/internal/middlewares"
+	"hostflow/booking-service/pkg/lib"
+)
+
+// Routes struct
+type Routes struct {
+	logger              lib.Logger
+	router              *lib.Router
+	controller          *Controller
+	authMiddleware      middlewares.AuthMiddleware
+	rateLimitMiddleware middlewares.RateLimitMiddleware
+}
+
+// SetRoutes returns a Routes struct
+func SetRoutes(
+	logger lib.Logger,
+	router *lib.Router,
+	controller *Controller,
+	authMiddleware middlewares.AuthMiddleware,
+	rateLimitMiddleware middlewares.RateLimitMiddleware,
+) Routes {
+	return Routes{
+		logger:              logger,
+		router:              router,
+		controller:          controller,
+		authMiddleware:      authMiddleware,
+		rateLimitMiddleware: rateLimitMiddleware,
+	}
+}
+
+// Setup registers the scheduler routes
+func (route Routes) Setup() {
+	route.logger.Info("Setting up [SCHEDULER] routes.")
+
+	schedules := route.router.Group("/communication/schedules")
+	schedules.Use(route.authMiddleware.Handler())
+	{
+		schedules.GET("", route.rateLimitMiddleware.Limit(middlewares.BudgetDefault), middlewares.RequirePermission(middlewares.CommunicationRead), route.controller.GetSchedulesHandler)
+		schedules.POST("", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), middlewares.RequirePermission(middlewares.CommunicationManage), route.controller.CreateScheduleHandler)
+		schedules.PUT("/:id", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), middlewares.RequirePermission(middlewares.CommunicationManage), route.controller.UpdateScheduleHandler)
+		schedules.DELETE("/:id", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), middlewares.RequirePermission(middlewares.CommunicationManage), route.controller.DeleteScheduleHandler)
+	}
+
+	reservations := route.router.Group("/reservations")
+	reservations.Use(route.authMiddleware.Handler())
+	{
+		reservations.GET("/:id/scheduled-messages", route.rateLimitMiddleware.Limit(middlewares.BudgetDefault), middlewares.RequirePermission(middlewares.CommunicationRead), route.controller.GetReservationMessagesHandler)
+	}
+}
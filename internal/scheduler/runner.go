@@ -0,0 +1,69 @@
+package scheduler
+
+import (
+	"context"
+	"hostflow/booking-service/internal/communication"
+	"hostflow/booking-service/pkg/lib"
+	"time"
+
+	"go.uber.org/fx"
+)
+
+const (
+	// tickInterval is how often schedules are planned and due messages queued.
+	tickInterval = time.Minute
+	// planGrace is how late a message may be planned after its send time.
+	planGrace = time.Hour
+	// queueBatchSize limits how many messages are queued per statement.
+	queueBatchSize = 100
+)
+
+// Runner periodically plans scheduled messages and hands due ones to the
+// email dispatcher
+type Runner struct {
+	repo       *Repository
+	dispatcher *communication.EmailDispatcher
+	logger     lib.Logger
+	worker     *lib.Worker
+}
+
+// NewRunner returns a Runner
+func NewRunner(repo *Repository, dispatcher *communication.EmailDispatcher, logger lib.Logger) *Runner {
+	r := &Runner{
+		repo:       repo,
+		dispatcher: dispatcher,
+		logger:     logger,
+	}
+	r.worker = lib.NewWorker(tickInterval, r.tick)
+	return r
+}
+
+// tick runs a single planning and queueing pass.
+func (r *Runner) tick(ctx context.Context) {
+	if _, err := r.repo.PlanMessages(planGrace); err != nil {
+		r.logger.Error("Failed to plan scheduled messages:", err)
+		return
+	}
+
+	var queued int64
+	for ctx.Err() == nil {
+		n, err := r.repo.QueueDueMessages(queueBatchSize)
+		if err != nil {
+			r.logger.Error("Failed to queue scheduled messages:", err)
+			break
+		}
+		queued += n
+		if n < queueBatchSize {
+			break
+		}
+	}
+
+	if queued > 0 {
+		r.dispatcher.Wake()
+	}
+}
+
+// RegisterRunnerHooks starts the scheduler with the application
+func RegisterRunnerHooks(lifecycle fx.Lifecycle, runner *Runner) {
+	runner.worker.Start(lifecycle)
+}
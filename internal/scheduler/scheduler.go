@@ -0,0 +1,11 @@
+package scheduler
+
+import "go.uber.org/fx"
+
+// ======== EXPORTS ========
+
+// Module exports the scheduler subsystem
+var Module = fx.Options(
+	fx.Provide(NewRepository, NewRunner, NewController, SetRoutes),
+	fx.Invoke(RegisterRunnerHooks),
+)
@@ -0,0 +1,102 @@
+package touristtax
+
+import (
+	"encoding/json"
+	"hostflow/booking-service/internal/guest"
+	"math"
+	"time"
+)
+
+// Calculate returns the tourist tax of a stay under a rule. Guest data must
+// be decrypted. Guests are charged by their age on the check-in day; guests
+// without a known age, and guests not registered yet, pay the full rate.
+func Calculate(rule *Rule, stay Stay) (*Line, error) {
+	guests, err := guest.Guests(stay.GuestData)
+	if err != nil {
+		return nil, err
+	}
+
+	nights := Nights(stay.CheckIn, stay.CheckOut)
+	line := &Line{
+		Currency:         rule.Currency,
+		RuleID:           rule.ID,
+		MunicipalityCode: rule.MunicipalityCode,
+		MunicipalityName: rule.MunicipalityName,
+		Rate:             rule.Rate,
+		Nights:           nights,
+		Guests:           make([]GuestLine, 0, max(len(guests), stay.NoOfGuests)),
+	}
+
+	for i := 0; i < len(guests) || i < stay.NoOfGuests; i++ {
+		var g GuestLine
+		if i < len(guests) {
+			if age, ok := guests[i].Age(stay.CheckIn); ok {
+				g.Age = &age
+			}
+		}
+		g.Percent = rule.percent(g.Age)
+		g.Amount = round(rule.Rate * g.Percent / 100 * float64(nights))
+
+		line.Guests = append(line.Guests, g)
+		line.Amount += g.Amount
+	}
+	line.Amount = round(line.Amount)
+
+	return line, nil
+}
+
+// Nights returns the number of calendar nights between check-in and
+// check-out, regardless of the times of day
+func Nights(checkIn, checkOut time.Time) int {
+	nights := int(day(checkOut).Sub(day(checkIn)).Hours() / 24)
+	if nights < 0 {
+		return 0
+	}
+	return nights
+}
+
+// LineOf returns the tourist tax line of price elements, or nil if there is
+// none
+func LineOf(priceElements map[string]interface{}) (*Line, error) {
+	raw, ok := priceElements[PriceElementKey]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var line Line
+	if err := json.Unmarshal(encoded, &line); err != nil {
+		return nil, err
+	}
+	return &line, nil
+}
+
+// ======== PRIVATE METHODS ========
+
+// percent returns the share of the rate a guest of the given age pays: that
+// of the first band the age falls in, or all of it
+func (r *Rule) percent(age *int) float64 {
+	if age == nil {
+		return 100
+	}
+	for _, band := range r.AgeBands {
+		if *age <= band.MaxAge {
+			return band.Percent
+		}
+	}
+	return 100
+}
+
+// day truncates a time to the start of its day
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// round rounds an amount to cents
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
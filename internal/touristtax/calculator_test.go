@@ -0,0 +1,126 @@
+package touristtax
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRule() *Rule {
+	return &Rule{
+		ID:               3,
+		MunicipalityCode: "061",
+		MunicipalityName: "Ljubljana",
+		Rate:             3.13,
+		Currency:         "EUR",
+		AgeBands: []AgeBand{
+			{MaxAge: 6, Percent: 0},
+			{MaxAge: 17, Percent: 50},
+		},
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	stay := Stay{
+		CheckIn:    time.Date(2026, 7, 10, 15, 0, 0, 0, time.UTC),
+		CheckOut:   time.Date(2026, 7, 14, 10, 0, 0, 0, time.UTC),
+		NoOfGuests: 4,
+		GuestData: map[string]interface{}{
+			"guests": []interface{}{
+				map[string]interface{}{"first_name": "Ana", "birth_date": "1990-03-01"},
+				// Turns 7 the day after check-in: charged as 6
+				map[string]interface{}{"first_name": "Jan", "birth_date": "2019-07-11"},
+				map[string]interface{}{"first_name": "Eva", "birth_date": "2010-01-01"},
+			},
+		},
+	}
+
+	line, err := Calculate(testRule(), stay)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, line.Nights)
+	require.Len(t, line.Guests, 4)
+	assert.Equal(t, 36, *line.Guests[0].Age)
+	assert.Equal(t, 100.0, line.Guests[0].Percent)
+	assert.Equal(t, 12.52, line.Guests[0].Amount)
+	assert.Equal(t, 6, *line.Guests[1].Age)
+	assert.Equal(t, 0.0, line.Guests[1].Amount)
+	assert.Equal(t, 50.0, line.Guests[2].Percent)
+	assert.Equal(t, 6.26, line.Guests[2].Amount)
+	// Not registered yet: full rate
+	assert.Nil(t, line.Guests[3].Age)
+	assert.Equal(t, 12.52, line.Guests[3].Amount)
+
+	assert.Equal(t, 31.3, line.Amount)
+	assert.Equal(t, "061", line.MunicipalityCode)
+	assert.Equal(t, int64(3), line.RuleID)
+}
+
+func TestCalculate_UnknownBirthDate(t *testing.T) {
+	stay := Stay{
+		CheckIn:    time.Date(2026, 7, 10, 0, 0, 0, 0, time.UTC),
+		CheckOut:   time.Date(2026, 7, 11, 0, 0, 0, 0, time.UTC),
+		NoOfGuests: 1,
+		GuestData: map[string]interface{}{
+			"guests": []interface{}{map[string]interface{}{"first_name": "Ana"}},
+		},
+	}
+
+	line, err := Calculate(testRule(), stay)
+	require.NoError(t, err)
+	assert.Equal(t, 3.13, line.Amount)
+}
+
+func TestNights(t *testing.T) {
+	in := time.Date(2026, 7, 10, 22, 0, 0, 0, time.UTC)
+	assert.Equal(t, 1, Nights(in, time.Date(2026, 7, 11, 8, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 0, Nights(in, time.Date(2026, 7, 10, 23, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 0, Nights(in, time.Date(2026, 7, 9, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestBuildReport(t *testing.T) {
+	line, err := Calculate(testRule(), Stay{
+		CheckIn:    time.Date(2026, 7, 30, 15, 0, 0, 0, time.UTC),
+		CheckOut:   time.Date(2026, 8, 2, 10, 0, 0, 0, time.UTC),
+		NoOfGuests: 2,
+		GuestData: map[string]interface{}{
+			"guests": []interface{}{
+				map[string]interface{}{"birth_date": "1990-03-01"},
+				map[string]interface{}{"birth_date": "2022-01-01"},
+			},
+		},
+	})
+	require.NoError(t, err)
+	element, err := toMap(line)
+	require.NoError(t, err)
+
+	stays := []taxedStay{
+		{
+			ReservationID: 42,
+			PropertyID:    10,
+			CheckIn:       time.Date(2026, 7, 30, 15, 0, 0, 0, time.UTC),
+			CheckOut:      time.Date(2026, 8, 2, 10, 0, 0, 0, time.UTC),
+			PriceElements: map[string]interface{}{PriceElementKey: element},
+		},
+		{ReservationID: 43, PropertyID: 10, PriceElements: map[string]interface{}{}},
+	}
+
+	july, err := buildReport(1, time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), stays)
+	require.NoError(t, err)
+	require.Len(t, july.Municipalities, 1)
+	m := july.Municipalities[0]
+	assert.Equal(t, "2026-07", july.Month)
+	assert.Equal(t, 4, m.GuestNights)
+	assert.Equal(t, 2, m.ExemptGuestNights)
+	assert.Equal(t, 6.26, m.Amount)
+	require.Len(t, m.Stays, 1)
+	assert.Equal(t, 2, m.Stays[0].Nights)
+
+	august, err := buildReport(1, time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC), stays)
+	require.NoError(t, err)
+	require.Len(t, august.Municipalities, 1)
+	assert.Equal(t, 3.13, august.Municipalities[0].Amount)
+	assert.Equal(t, 1, august.Municipalities[0].Stays[0].Nights)
+}
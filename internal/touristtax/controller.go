@@ -0,0 +1,312 @@
+package touristtax
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Controller handles HTTP requests for tourist tax rules and reports
+type Controller struct {
+	service *Service
+}
+
+// NewController returns a Controller
+func NewController(service *Service) *Controller {
+	return &Controller{
+		service: service,
+	}
+}
+
+func (c *Controller) getOrgID(ctx *gin.Context) (int64, bool) {
+	val, exists := ctx.Get("organization_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return 0, false
+	}
+	return val.(int64), true
+}
+
+func (c *Controller) getID(ctx *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return 0, false
+	}
+	return id, true
+}
+
+// ======== RULES ========
+
+// GetRulesHandler godoc
+// @Summary Get the tourist tax rules
+// @Description Returns the tourist tax rules of the organization by municipality, newest first
+// @Tags tourist-tax
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} Rule
+// @Failure 500 {object} map[string]string
+// @Router /tourist-tax/rules [get]
+func (c *Controller) GetRulesHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	rules, err := c.service.GetRules(orgID)
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rules)
+}
+
+// CreateRuleHandler godoc
+// @Summary Create a tourist tax rule
+// @Description Creates the tourist tax of a municipality, or of a single property when property_id is set. The rate is charged per guest and night; age bands reduce it for guests up to max_age years old on check-in. A property rule takes precedence over the rule of its municipality.
+// @Tags tourist-tax
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body RuleRequest true "Rule"
+// @Success 201 {object} Rule
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tourist-tax/rules [post]
+func (c *Controller) CreateRuleHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	var req RuleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, err := c.service.CreateRule(orgID, req)
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, rule)
+}
+
+// UpdateRuleHandler godoc
+// @Summary Update a tourist tax rule
+// @Description Replaces a tourist tax rule. Reservations already charged keep their tourist tax until they are updated.
+// @Tags tourist-tax
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Rule ID"
+// @Param request body RuleRequest true "Rule"
+// @Success 200 {object} Rule
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /tourist-tax/rules/{id} [put]
+func (c *Controller) UpdateRuleHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+	id, ok := c.getID(ctx)
+	if !ok {
+		return
+	}
+
+	var req RuleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, err := c.service.UpdateRule(id, orgID, req)
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rule)
+}
+
+// DeleteRuleHandler godoc
+// @Summary Delete a tourist tax rule
+// @Tags tourist-tax
+// @Security ApiKeyAuth
+// @Param id path int true "Rule ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} map[string]string
+// @Router /tourist-tax/rules/{id} [delete]
+func (c *Controller) DeleteRuleHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+	id, ok := c.getID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.service.DeleteRule(id, orgID); err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ======== PROPERTIES ========
+
+// GetPropertiesHandler godoc
+// @Summary Get the municipalities of the properties
+// @Tags tourist-tax
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} PropertyMunicipality
+// @Failure 500 {object} map[string]string
+// @Router /tourist-tax/properties [get]
+func (c *Controller) GetPropertiesHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	properties, err := c.service.GetProperties(orgID)
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, properties)
+}
+
+// SetPropertyHandler godoc
+// @Summary Assign a property to a municipality
+// @Description Reservations of the property are charged the tourist tax of the municipality, unless the property has a rule of its own
+// @Tags tourist-tax
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Property ID"
+// @Param request body PropertyRequest true "Municipality"
+// @Success 200 {object} PropertyMunicipality
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tourist-tax/properties/{id} [put]
+func (c *Controller) SetPropertyHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+	id, ok := c.getID(ctx)
+	if !ok {
+		return
+	}
+
+	var req PropertyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	property, err := c.service.SetProperty(orgID, id, req)
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, property)
+}
+
+// DeletePropertyHandler godoc
+// @Summary Remove the municipality of a property
+// @Tags tourist-tax
+// @Security ApiKeyAuth
+// @Param id path int true "Property ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} map[string]string
+// @Router /tourist-tax/properties/{id} [delete]
+func (c *Controller) DeletePropertyHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+	id, ok := c.getID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.service.DeleteProperty(orgID, id); err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ======== REPORTS ========
+
+// GetReportHandler godoc
+// @Summary Get the monthly tourist tax report
+// @Description Returns the tourist tax owed for the guest-nights of a month by municipality, from the tourist tax charged on reservations that were not cancelled, rejected or no-shows. Stays spanning two months are split by night. CSV has one row per stay. The municipalities' XML format is not supported yet.
+// @Tags tourist-tax
+// @Produce json
+// @Produce text/csv
+// @Security ApiKeyAuth
+// @Param month path string true "Month (YYYY-MM)"
+// @Param format query string false "Report format" Enums(json, csv) default(json)
+// @Success 200 {object} Report
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tourist-tax/reports/{month} [get]
+func (c *Controller) GetReportHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	format := ctx.DefaultQuery("format", FormatJSON)
+	if format != FormatJSON && format != FormatCSV {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or csv"})
+		return
+	}
+
+	report, err := c.service.GetReport(orgID, ctx.Param("month"))
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	filename := "tourist-tax-" + report.Month + "." + format
+	switch format {
+	case FormatCSV:
+		ctx.Header("Content-Disposition", "attachment; filename="+filename)
+		ctx.Header("Content-Type", "text/csv; charset=utf-8")
+		ctx.Status(http.StatusOK)
+		if err := WriteCSV(ctx.Writer, report); err != nil {
+			ctx.Error(err)
+		}
+	default:
+		ctx.JSON(http.StatusOK, report)
+	}
+}
+
+// ======== PRIVATE METHODS ========
+
+// fail responds with the status of a service error
+func (c *Controller) fail(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrRuleNotFound), errors.Is(err, ErrPropertyNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrInvalidMonth), errors.Is(err, ErrInvalidValidity):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
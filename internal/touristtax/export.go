@@ -0,0 +1,57 @@
+package touristtax
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// csvHeader are the columns of a CSV report, one row per stay
+var csvHeader = []string{
+	"month", "municipality_code", "municipality_name", "property_id",
+	"reservation_id", "check_in_date", "check_out_date", "nights", "guests",
+	"guest_nights", "exempt_guest_nights", "reduced_guest_nights", "rate",
+	"amount", "currency",
+}
+
+// WriteCSV writes a report as CSV, one row per stay within each
+// municipality
+func WriteCSV(w io.Writer, report *Report) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, m := range report.Municipalities {
+		for _, stay := range m.Stays {
+			record := []string{
+				report.Month,
+				m.Code,
+				m.Name,
+				strconv.FormatInt(stay.PropertyID, 10),
+				strconv.FormatInt(stay.ReservationID, 10),
+				stay.CheckInDate,
+				stay.CheckOutDate,
+				strconv.Itoa(stay.Nights),
+				strconv.Itoa(stay.Guests),
+				strconv.Itoa(stay.GuestNights),
+				strconv.Itoa(stay.ExemptGuestNights),
+				strconv.Itoa(stay.ReducedNights),
+				formatAmount(stay.Rate),
+				formatAmount(stay.Amount),
+				m.Currency,
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatAmount formats an amount with two decimals
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
@@ -0,0 +1,45 @@
+package touristtax
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testReport() *Report {
+	return &Report{
+		OrganizationID: 1,
+		Month:          "2026-07",
+		Municipalities: []MunicipalityReport{{
+			Code:        "061",
+			Name:        "Ljubljana",
+			Currency:    "EUR",
+			GuestNights: 8,
+			Amount:      25.04,
+			Stays: []StayReport{{
+				ReservationID: 42,
+				PropertyID:    10,
+				CheckInDate:   "2026-07-10",
+				CheckOutDate:  "2026-07-14",
+				Nights:        4,
+				Guests:        2,
+				GuestNights:   8,
+				Rate:          3.13,
+				Amount:        25.04,
+			}},
+		}},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, testReport()))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, strings.Join(csvHeader, ","), lines[0])
+	assert.Equal(t, "2026-07,061,Ljubljana,10,42,2026-07-10,2026-07-14,4,2,8,0,0,3.13,25.04,EUR", lines[1])
+}
@@ -0,0 +1,167 @@
+package touristtax
+
+import (
+	"errors"
+	"time"
+)
+
+// PriceElementKey is the key of the tourist tax line in the price elements
+// of a reservation
+const PriceElementKey = "tourist_tax"
+
+// DateLayout is the format of rule validity dates
+const DateLayout = "2006-01-02"
+
+// MonthLayout is the format of report months
+const MonthLayout = "2006-01"
+
+// Report formats
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+// taxedStatuses are the statuses of stays that owe tourist tax; cancelled,
+// rejected and no-show reservations were never stayed
+var taxedStatuses = []string{"CREATED", "PAYMENT_REQUIRED", "CONFIRMED", "COMPLETED"}
+
+// ruleColumns are the columns scanned into Rule
+const ruleColumns = `
+    id, organization_id, municipality_code, municipality_name, property_id,
+    rate, currency, age_bands, valid_from, valid_to, created_at, updated_at
+`
+
+// Errors returned by the tourist tax service
+var (
+	ErrRuleNotFound     = errors.New("tourist tax rule not found")
+	ErrPropertyNotFound = errors.New("the property is not assigned to a municipality")
+	ErrInvalidValidity  = errors.New("valid_to must not be before valid_from")
+	ErrInvalidMonth     = errors.New("month must be in the format YYYY-MM")
+)
+
+// AgeBand charges guests up to MaxAge years old (inclusive) Percent of the
+// rate. Guests older than every band pay the full rate.
+type AgeBand struct {
+	MaxAge  int     `json:"max_age" binding:"min=0,max=120" example:"6"`
+	Percent float64 `json:"percent" binding:"min=0,max=100" example:"0"`
+}
+
+// Rule is the tourist tax of a municipality, or of a single property when
+// PropertyID is set, valid from ValidFrom to ValidTo (inclusive)
+type Rule struct {
+	ID               int64      `json:"id" db:"id"`
+	OrganizationID   int64      `json:"organization_id" db:"organization_id"`
+	MunicipalityCode string     `json:"municipality_code" db:"municipality_code" example:"061"`
+	MunicipalityName string     `json:"municipality_name" db:"municipality_name" example:"Ljubljana"`
+	PropertyID       *int64     `json:"property_id,omitempty" db:"property_id" example:"10"`
+	Rate             float64    `json:"rate" db:"rate" example:"3.13"`
+	Currency         string     `json:"currency" db:"currency" example:"EUR"`
+	AgeBands         []AgeBand  `json:"age_bands" db:"age_bands"`
+	ValidFrom        time.Time  `json:"valid_from" db:"valid_from" example:"2026-01-01T00:00:00Z"`
+	ValidTo          *time.Time `json:"valid_to,omitempty" db:"valid_to"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// RuleRequest is the body of a rule creation or update
+type RuleRequest struct {
+	MunicipalityCode string    `json:"municipality_code" binding:"required,max=20" example:"061"`
+	MunicipalityName string    `json:"municipality_name" binding:"required,max=200" example:"Ljubljana"`
+	PropertyID       *int64    `json:"property_id" binding:"omitempty,min=1" example:"10"`
+	Rate             float64   `json:"rate" binding:"min=0" example:"3.13"`
+	Currency         string    `json:"currency" binding:"omitempty,len=3" example:"EUR"`
+	AgeBands         []AgeBand `json:"age_bands" binding:"dive"`
+	ValidFrom        string    `json:"valid_from" binding:"required,datetime=2006-01-02" example:"2026-01-01"`
+	ValidTo          string    `json:"valid_to" binding:"omitempty,datetime=2006-01-02" example:"2026-12-31"`
+}
+
+// PropertyMunicipality assigns a property to a municipality
+type PropertyMunicipality struct {
+	OrganizationID   int64     `json:"organization_id" db:"organization_id"`
+	PropertyID       int64     `json:"property_id" db:"property_id" example:"10"`
+	MunicipalityCode string    `json:"municipality_code" db:"municipality_code" example:"061"`
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// PropertyRequest is the body of a property assignment
+type PropertyRequest struct {
+	MunicipalityCode string `json:"municipality_code" binding:"required,max=20" example:"061"`
+}
+
+// Stay is what the tourist tax of a reservation is calculated from.
+// GuestData may be encrypted.
+type Stay struct {
+	OrganizationID int64
+	PropertyID     int64
+	CheckIn        time.Time
+	CheckOut       time.Time
+	NoOfGuests     int
+	GuestData      map[string]interface{}
+}
+
+// Line is the tourist tax line stored in the price elements of a
+// reservation. It keeps the rate and the charged share of every guest, so
+// that reports match what was charged even if the rule changes later.
+type Line struct {
+	Amount           float64     `json:"amount" example:"25.04"`
+	Currency         string      `json:"currency" example:"EUR"`
+	RuleID           int64       `json:"rule_id" example:"3"`
+	MunicipalityCode string      `json:"municipality_code" example:"061"`
+	MunicipalityName string      `json:"municipality_name" example:"Ljubljana"`
+	Rate             float64     `json:"rate" example:"3.13"`
+	Nights           int         `json:"nights" example:"4"`
+	Guests           []GuestLine `json:"guests"`
+}
+
+// GuestLine is the tourist tax of a single guest. Age is unknown for
+// guests without a registered birth date, who pay the full rate.
+type GuestLine struct {
+	Age     *int    `json:"age,omitempty" example:"34"`
+	Percent float64 `json:"percent" example:"100"`
+	Amount  float64 `json:"amount" example:"12.52"`
+}
+
+// taxedStay is a reservation with a tourist tax line, as read for reports
+type taxedStay struct {
+	ReservationID int64                  `db:"id"`
+	PropertyID    int64                  `db:"property_id"`
+	CheckIn       time.Time              `db:"check_in_date"`
+	CheckOut      time.Time              `db:"check_out_date"`
+	PriceElements map[string]interface{} `db:"price_elements"`
+}
+
+// Report is the tourist tax owed by an organization for the guest-nights of
+// a month, by municipality
+type Report struct {
+	OrganizationID int64                `json:"organization_id"`
+	Month          string               `json:"month" example:"2026-07"`
+	Municipalities []MunicipalityReport `json:"municipalities"`
+}
+
+// MunicipalityReport is the part of a report owed to a single municipality
+type MunicipalityReport struct {
+	Code              string       `json:"code" example:"061"`
+	Name              string       `json:"name" example:"Ljubljana"`
+	Currency          string       `json:"currency" example:"EUR"`
+	GuestNights       int          `json:"guest_nights" example:"124"`
+	ExemptGuestNights int          `json:"exempt_guest_nights" example:"12"`
+	ReducedNights     int          `json:"reduced_guest_nights" example:"20"`
+	Amount            float64      `json:"amount" example:"356.82"`
+	Stays             []StayReport `json:"stays"`
+}
+
+// StayReport is a single reservation in a report, limited to the nights
+// within the month
+type StayReport struct {
+	ReservationID     int64   `json:"reservation_id" example:"42"`
+	PropertyID        int64   `json:"property_id" example:"10"`
+	CheckInDate       string  `json:"check_in_date" example:"2026-07-10"`
+	CheckOutDate      string  `json:"check_out_date" example:"2026-07-14"`
+	Nights            int     `json:"nights" example:"4"`
+	Guests            int     `json:"guests" example:"2"`
+	GuestNights       int     `json:"guest_nights" example:"8"`
+	ExemptGuestNights int     `json:"exempt_guest_nights" example:"0"`
+	ReducedNights     int     `json:"reduced_guest_nights" example:"0"`
+	Rate              float64 `json:"rate" example:"3.13"`
+	Amount            float64 `json:"amount" example:"25.04"`
+}
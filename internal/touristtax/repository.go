@@ -0,0 +1,258 @@
+package touristtax
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository persists tourist tax rules and property municipalities, and
+// reads the taxed reservations for reports
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+// NewRepository returns a Repository
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+// ======== RULES ========
+
+// GetRules returns the rules of an organization by municipality, newest
+// first
+func (r *Repository) GetRules(organizationID int64) ([]Rule, error) {
+	query := `SELECT ` + ruleColumns + `
+        FROM tourist_tax_rule
+        WHERE organization_id = $1
+        ORDER BY municipality_code, property_id NULLS FIRST, valid_from DESC
+    `
+
+	rows, err := r.db.Query(context.Background(), query, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[Rule])
+}
+
+// GetRule returns a rule of an organization, or nil if it doesn't exist
+func (r *Repository) GetRule(id, organizationID int64) (*Rule, error) {
+	query := `SELECT ` + ruleColumns + `
+        FROM tourist_tax_rule
+        WHERE id = $1
+          AND organization_id = $2
+    `
+
+	rows, err := r.db.Query(context.Background(), query, id, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectRule(rows)
+}
+
+// CreateRule stores a new rule
+func (r *Repository) CreateRule(rule *Rule) (*Rule, error) {
+	query := `
+        INSERT INTO tourist_tax_rule (
+            organization_id, municipality_code, municipality_name, property_id,
+            rate, currency, age_bands, valid_from, valid_to
+        )
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+        RETURNING ` + ruleColumns
+
+	rows, err := r.db.Query(context.Background(), query,
+		rule.OrganizationID,
+		rule.MunicipalityCode,
+		rule.MunicipalityName,
+		rule.PropertyID,
+		rule.Rate,
+		rule.Currency,
+		rule.AgeBands,
+		rule.ValidFrom,
+		rule.ValidTo,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectRule(rows)
+}
+
+// UpdateRule replaces a rule. It returns nil if the rule doesn't exist.
+func (r *Repository) UpdateRule(rule *Rule) (*Rule, error) {
+	query := `
+        UPDATE tourist_tax_rule
+        SET municipality_code = $3,
+            municipality_name = $4,
+            property_id = $5,
+            rate = $6,
+            currency = $7,
+            age_bands = $8,
+            valid_from = $9,
+            valid_to = $10,
+            updated_at = NOW()
+        WHERE id = $1
+          AND organization_id = $2
+        RETURNING ` + ruleColumns
+
+	rows, err := r.db.Query(context.Background(), query,
+		rule.ID,
+		rule.OrganizationID,
+		rule.MunicipalityCode,
+		rule.MunicipalityName,
+		rule.PropertyID,
+		rule.Rate,
+		rule.Currency,
+		rule.AgeBands,
+		rule.ValidFrom,
+		rule.ValidTo,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectRule(rows)
+}
+
+// DeleteRule deletes a rule. It returns false if the rule doesn't exist.
+func (r *Repository) DeleteRule(id, organizationID int64) (bool, error) {
+	tag, err := r.db.Exec(context.Background(), `
+        DELETE FROM tourist_tax_rule
+        WHERE id = $1
+          AND organization_id = $2
+    `, id, organizationID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// FindRule returns the rule applying to a property on a day: a rule for the
+// property itself, or else the rule of the municipality the property is
+// assigned to. It returns nil if neither exists.
+func (r *Repository) FindRule(organizationID, propertyID int64, on time.Time) (*Rule, error) {
+	query := `SELECT ` + ruleColumns + `
+        FROM tourist_tax_rule
+        WHERE organization_id = $1
+          AND valid_from <= $3
+          AND (valid_to IS NULL OR valid_to >= $3)
+          AND (
+              property_id = $2
+              OR (property_id IS NULL AND municipality_code = (
+                  SELECT municipality_code
+                  FROM tourist_tax_property
+                  WHERE organization_id = $1
+                    AND property_id = $2
+              ))
+          )
+        ORDER BY property_id NULLS LAST, valid_from DESC
+        LIMIT 1
+    `
+
+	rows, err := r.db.Query(context.Background(), query, organizationID, propertyID, day(on))
+	if err != nil {
+		return nil, err
+	}
+
+	return collectRule(rows)
+}
+
+// ======== PROPERTIES ========
+
+// GetProperties returns the municipalities the properties of an
+// organization are assigned to
+func (r *Repository) GetProperties(organizationID int64) ([]PropertyMunicipality, error) {
+	rows, err := r.db.Query(context.Background(), `
+        SELECT organization_id, property_id, municipality_code, updated_at
+        FROM tourist_tax_property
+        WHERE organization_id = $1
+        ORDER BY property_id
+    `, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[PropertyMunicipality])
+}
+
+// SetProperty assigns a property to a municipality
+func (r *Repository) SetProperty(organizationID, propertyID int64, municipalityCode string) (*PropertyMunicipality, error) {
+	rows, err := r.db.Query(context.Background(), `
+        INSERT INTO tourist_tax_property (organization_id, property_id, municipality_code)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (organization_id, property_id)
+        DO UPDATE SET municipality_code = EXCLUDED.municipality_code,
+                      updated_at = NOW()
+        RETURNING organization_id, property_id, municipality_code, updated_at
+    `, organizationID, propertyID, municipalityCode)
+	if err != nil {
+		return nil, err
+	}
+
+	property, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[PropertyMunicipality])
+	if err != nil {
+		return nil, err
+	}
+	return &property, nil
+}
+
+// DeleteProperty removes the municipality of a property. It returns false
+// if the property wasn't assigned.
+func (r *Repository) DeleteProperty(organizationID, propertyID int64) (bool, error) {
+	tag, err := r.db.Exec(context.Background(), `
+        DELETE FROM tourist_tax_property
+        WHERE organization_id = $1
+          AND property_id = $2
+    `, organizationID, propertyID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ======== REPORTS ========
+
+// GetTaxedStays returns the bookings of an organization with a tourist tax
+// line and one of the given statuses that have nights between from and to.
+// Holds are left out: their prospective guests haven't booked the stay.
+func (r *Repository) GetTaxedStays(organizationID int64, from, to time.Time, statuses []string) ([]taxedStay, error) {
+	rows, err := r.db.Query(context.Background(), `
+        SELECT id, property_id, check_in_date, check_out_date, price_elements
+        FROM reservation
+        WHERE organization_id = $1
+          AND deleted_at IS NULL
+          AND kind = 'BOOKING'
+          AND price_elements ? 'tourist_tax'
+          AND check_in_date < $3
+          AND check_out_date > $2
+          AND status = ANY($4)
+        ORDER BY property_id, check_in_date, id
+    `, organizationID, from, to, statuses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[taxedStay])
+}
+
+// collectRule scans a single rule, or returns nil if there is none
+func collectRule(rows pgx.Rows) (*Rule, error) {
+	rule, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[Rule])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
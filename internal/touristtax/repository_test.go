@@ -0,0 +1,31 @@
+package touristtax
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hostflow/booking-service/internal/dbtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTaxedStays_LeavesHoldsOut(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := NewRepository(db)
+
+	// A booking and an active hold, both charged the tourist tax
+	_, err := db.Exec(context.Background(), `
+        INSERT INTO reservation (id, organization_id, property_id, customer_id, check_in_date, check_out_date, status, kind, price_elements)
+        VALUES (1, 100, 10, 7, '2030-06-01T14:00:00Z', '2030-06-04T10:00:00Z', 'CONFIRMED', 'BOOKING', '{"tourist_tax": {"amount": 15}}'),
+               (2, 100, 11, 8, '2030-06-01T14:00:00Z', '2030-06-04T10:00:00Z', 'CREATED', 'HOLD', '{"tourist_tax": {"amount": 15}}')
+    `)
+	require.NoError(t, err)
+
+	from := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+	stays, err := repo.GetTaxedStays(100, from, from.AddDate(0, 1, 0), taxedStatuses)
+	require.NoError(t, err)
+	require.Len(t, stays, 1)
+	assert.Equal(t, int64(1), stays[0].ReservationID)
+}
@@ -0,0 +1,53 @@
+package touristtax
+
+import (
+	"hostflow/booking-service/internal/middlewares"
+	"hostflow/booking-service/pkg/lib"
+)
+
+// Routes struct
+type Routes struct {
+	logger              lib.Logger
+	router              *lib.Router
+	controller          *Controller
+	authMiddleware      middlewares.AuthMiddleware
+	rateLimitMiddleware middlewares.RateLimitMiddleware
+}
+
+// SetRoutes returns a Routes struct
+func SetRoutes(
+	logger lib.Logger,
+	router *lib.Router,
+	controller *Controller,
+	authMiddleware middlewares.AuthMiddleware,
+	rateLimitMiddleware middlewares.RateLimitMiddleware,
+) Routes {
+	return Routes{
+		logger:              logger,
+		router:              router,
+		controller:          controller,
+		authMiddleware:      authMiddleware,
+		rateLimitMiddleware: rateLimitMiddleware,
+	}
+}
+
+// Setup registers the tourist tax routes, which all require
+// touristtax:manage
+func (route Routes) Setup() {
+	route.logger.Info("Setting up [TOURIST TAX] routes.")
+
+	touristTax := route.router.Group("/tourist-tax")
+	touristTax.Use(route.authMiddleware.Handler(), middlewares.RequirePermission(middlewares.TouristTaxManage))
+	{
+		touristTax.GET("/rules", route.rateLimitMiddleware.Limit(middlewares.BudgetDefault), route.controller.GetRulesHandler)
+		touristTax.POST("/rules", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), route.controller.CreateRuleHandler)
+		touristTax.PUT("/rules/:id", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), route.controller.UpdateRuleHandler)
+		touristTax.DELETE("/rules/:id", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), route.controller.DeleteRuleHandler)
+
+		touristTax.GET("/properties", route.rateLimitMiddleware.Limit(middlewares.BudgetDefault), route.controller.GetPropertiesHandler)
+		touristTax.PUT("/properties/:id", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), route.controller.SetPropertyHandler)
+		touristTax.DELETE("/properties/:id", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), route.controller.DeletePropertyHandler)
+
+		touristTax.GET("/reports/:month", route.rateLimitMiddleware.Limit(middlewares.BudgetSearch), route.controller.GetReportHandler)
+	}
+}
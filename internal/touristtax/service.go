@@ -0,0 +1,322 @@
+package touristtax
+
+import (
+	"context"
+	"encoding/json"
+	"hostflow/booking-service/internal/encryption"
+	"hostflow/booking-service/pkg/lib"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultCurrency is the currency of rules created without one
+const defaultCurrency = "EUR"
+
+// Service manages tourist tax rules, charges the tax on reservations and
+// builds the monthly reports for municipalities
+type Service struct {
+	repo      *Repository
+	guestData *encryption.FieldEncryptor
+	logger    lib.Logger
+}
+
+// NewService returns a Service
+func NewService(repo *Repository, guestData *encryption.FieldEncryptor, logger lib.Logger) *Service {
+	return &Service{
+		repo:      repo,
+		guestData: guestData,
+		logger:    logger,
+	}
+}
+
+// ======== RULES ========
+
+// GetRules returns the rules of an organization
+func (s *Service) GetRules(organizationID int64) ([]Rule, error) {
+	return s.repo.GetRules(organizationID)
+}
+
+// CreateRule creates a rule
+func (s *Service) CreateRule(organizationID int64, req RuleRequest) (*Rule, error) {
+	rule, err := newRule(organizationID, req)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.CreateRule(rule)
+}
+
+// UpdateRule replaces a rule. Reservations already charged keep their
+// tourist tax until they are updated.
+func (s *Service) UpdateRule(id, organizationID int64, req RuleRequest) (*Rule, error) {
+	rule, err := newRule(organizationID, req)
+	if err != nil {
+		return nil, err
+	}
+	rule.ID = id
+
+	updated, err := s.repo.UpdateRule(rule)
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		return nil, ErrRuleNotFound
+	}
+	return updated, nil
+}
+
+// DeleteRule deletes a rule
+func (s *Service) DeleteRule(id, organizationID int64) error {
+	deleted, err := s.repo.DeleteRule(id, organizationID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrRuleNotFound
+	}
+	return nil
+}
+
+// ======== PROPERTIES ========
+
+// GetProperties returns the municipalities of the properties of an
+// organization
+func (s *Service) GetProperties(organizationID int64) ([]PropertyMunicipality, error) {
+	return s.repo.GetProperties(organizationID)
+}
+
+// SetProperty assigns a property to a municipality
+func (s *Service) SetProperty(organizationID, propertyID int64, req PropertyRequest) (*PropertyMunicipality, error) {
+	return s.repo.SetProperty(organizationID, propertyID, strings.TrimSpace(req.MunicipalityCode))
+}
+
+// DeleteProperty removes the municipality of a property, which is no longer
+// charged tourist tax unless it has a rule of its own
+func (s *Service) DeleteProperty(organizationID, propertyID int64) error {
+	deleted, err := s.repo.DeleteProperty(organizationID, propertyID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrPropertyNotFound
+	}
+	return nil
+}
+
+// ======== RESERVATIONS ========
+
+// Apply sets the tourist tax line in the price elements of a stay and
+// returns the total price with the tax. Any previous line is replaced, so
+// the total only changes by the difference; without a rule for the stay,
+// the line is removed.
+func (s *Service) Apply(stay Stay, priceElements map[string]interface{}, totalPrice float64) (float64, error) {
+	previous, err := LineOf(priceElements)
+	if err != nil {
+		return 0, err
+	}
+	if previous != nil {
+		totalPrice -= previous.Amount
+	}
+	delete(priceElements, PriceElementKey)
+
+	rule, err := s.repo.FindRule(stay.OrganizationID, stay.PropertyID, stay.CheckIn)
+	if err != nil {
+		return 0, err
+	}
+	if rule == nil {
+		return round(totalPrice), nil
+	}
+
+	// The calculation needs the birth dates, which may be encrypted
+	guestData, err := clone(stay.GuestData)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.guestData.Decrypt(context.Background(), guestData); err != nil {
+		return 0, err
+	}
+	stay.GuestData = guestData
+
+	line, err := Calculate(rule, stay)
+	if err != nil {
+		return 0, err
+	}
+
+	element, err := toMap(line)
+	if err != nil {
+		return 0, err
+	}
+	priceElements[PriceElementKey] = element
+
+	return round(totalPrice + line.Amount), nil
+}
+
+// ======== REPORTS ========
+
+// GetReport returns the tourist tax of the guest-nights of a month (YYYY-MM)
+func (s *Service) GetReport(organizationID int64, month string) (*Report, error) {
+	from, err := time.Parse(MonthLayout, month)
+	if err != nil {
+		return nil, ErrInvalidMonth
+	}
+	to := from.AddDate(0, 1, 0)
+
+	stays, err := s.repo.GetTaxedStays(organizationID, from, to, taxedStatuses)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildReport(organizationID, from, stays)
+}
+
+// ======== PRIVATE METHODS ========
+
+// newRule validates a rule request
+func newRule(organizationID int64, req RuleRequest) (*Rule, error) {
+	rule := &Rule{
+		OrganizationID:   organizationID,
+		MunicipalityCode: strings.TrimSpace(req.MunicipalityCode),
+		MunicipalityName: strings.TrimSpace(req.MunicipalityName),
+		PropertyID:       req.PropertyID,
+		Rate:             round(req.Rate),
+		Currency:         strings.ToUpper(req.Currency),
+		AgeBands:         req.AgeBands,
+	}
+	if rule.Currency == "" {
+		rule.Currency = defaultCurrency
+	}
+	if rule.AgeBands == nil {
+		rule.AgeBands = []AgeBand{}
+	}
+	// The first band an age falls in applies
+	sort.SliceStable(rule.AgeBands, func(i, j int) bool {
+		return rule.AgeBands[i].MaxAge < rule.AgeBands[j].MaxAge
+	})
+
+	var err error
+	if rule.ValidFrom, err = time.Parse(DateLayout, req.ValidFrom); err != nil {
+		return nil, ErrInvalidValidity
+	}
+	if req.ValidTo != "" {
+		validTo, err := time.Parse(DateLayout, req.ValidTo)
+		if err != nil || validTo.Before(rule.ValidFrom) {
+			return nil, ErrInvalidValidity
+		}
+		rule.ValidTo = &validTo
+	}
+
+	return rule, nil
+}
+
+// buildReport splits the taxed stays into the nights within the month
+// starting at from, by municipality. Amounts are recalculated from the rate
+// and the share of every guest stored on the reservation, so stays spanning
+// two months are split between their reports.
+func buildReport(organizationID int64, from time.Time, stays []taxedStay) (*Report, error) {
+	to := from.AddDate(0, 1, 0)
+	report := &Report{
+		OrganizationID: organizationID,
+		Month:          from.Format(MonthLayout),
+		Municipalities: []MunicipalityReport{},
+	}
+	municipalities := make(map[string]*MunicipalityReport)
+	var order []string
+
+	for _, stay := range stays {
+		line, err := LineOf(stay.PriceElements)
+		if err != nil {
+			return nil, err
+		}
+		if line == nil {
+			continue
+		}
+
+		checkIn, checkOut := day(stay.CheckIn), day(stay.CheckOut)
+		nights := Nights(later(checkIn, from), earlier(checkOut, to))
+		if nights <= 0 {
+			continue
+		}
+
+		entry := StayReport{
+			ReservationID: stay.ReservationID,
+			PropertyID:    stay.PropertyID,
+			CheckInDate:   checkIn.Format(DateLayout),
+			CheckOutDate:  checkOut.Format(DateLayout),
+			Nights:        nights,
+			Guests:        len(line.Guests),
+			Rate:          line.Rate,
+		}
+		for _, g := range line.Guests {
+			entry.GuestNights += nights
+			switch {
+			case g.Percent <= 0:
+				entry.ExemptGuestNights += nights
+			case g.Percent < 100:
+				entry.ReducedNights += nights
+			}
+			entry.Amount += line.Rate * g.Percent / 100 * float64(nights)
+		}
+		entry.Amount = round(entry.Amount)
+
+		municipality, ok := municipalities[line.MunicipalityCode]
+		if !ok {
+			municipality = &MunicipalityReport{
+				Code:     line.MunicipalityCode,
+				Name:     line.MunicipalityName,
+				Currency: line.Currency,
+				Stays:    []StayReport{},
+			}
+			municipalities[line.MunicipalityCode] = municipality
+			order = append(order, line.MunicipalityCode)
+		}
+		municipality.GuestNights += entry.GuestNights
+		municipality.ExemptGuestNights += entry.ExemptGuestNights
+		municipality.ReducedNights += entry.ReducedNights
+		municipality.Amount = round(municipality.Amount + entry.Amount)
+		municipality.Stays = append(municipality.Stays, entry)
+	}
+
+	sort.Strings(order)
+	for _, code := range order {
+		report.Municipalities = append(report.Municipalities, *municipalities[code])
+	}
+	return report, nil
+}
+
+// clone returns a deep copy of a JSON value
+func clone[T any](value T) (T, error) {
+	var copied T
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return copied, err
+	}
+	err = json.Unmarshal(raw, &copied)
+	return copied, err
+}
+
+// toMap converts a value to a JSON object
+func toMap(value interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]interface{}
+	err = json.Unmarshal(raw, &object)
+	return object, err
+}
+
+// later returns the later of two times
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// earlier returns the earlier of two times
+func earlier(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,185 @@
+package waitlist
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Controller handles HTTP requests for the waitlist
+type Controller struct {
+	service *Service
+}
+
+// NewController returns a Controller
+func NewController(service *Service) *Controller {
+	return &Controller{
+		service: service,
+	}
+}
+
+func (c *Controller) getOrgID(ctx *gin.Context) (int64, bool) {
+	val, exists := ctx.Get("organization_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return 0, false
+	}
+	return val.(int64), true
+}
+
+func (c *Controller) getID(ctx *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return 0, false
+	}
+	return id, true
+}
+
+// GetEntriesHandler godoc
+// @Summary Get the waitlist
+// @Description Returns the waitlist entries of the organization in the order they are offered freed dates: by descending priority, then in the order they were added. Filtering by property includes the entries for any property.
+// @Tags waitlist
+// @Produce json
+// @Security ApiKeyAuth
+// @Param status query string false "Status" Enums(WAITING, OFFERED, BOOKED, EXPIRED)
+// @Param property_id query int false "Property ID"
+// @Param customer_id query int false "Customer ID"
+// @Success 200 {array} Entry
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /waitlist [get]
+func (c *Controller) GetEntriesHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	var filter EntryFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entries, err := c.service.GetEntries(orgID, filter)
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entries)
+}
+
+// CreateEntryHandler godoc
+// @Summary Add a customer to the waitlist
+// @Description Puts a customer on the waitlist for a stay that is fully booked, at a property or at any property of the organization when property_id is omitted. When a cancellation, deletion or expired offer frees dates the stay fits in, the customer is emailed an offer that expires after WAITLIST_OFFER_HOURS; booking the stay fulfils the entry.
+// @Tags waitlist
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body EntryRequest true "Waitlist entry"
+// @Success 201 {object} Entry
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /waitlist [post]
+func (c *Controller) CreateEntryHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+
+	var req EntryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry, err := c.service.CreateEntry(orgID, req)
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, entry)
+}
+
+// UpdateEntryHandler godoc
+// @Summary Update a waitlist entry
+// @Description Replaces the details of an entry that wasn't booked and puts it back on the waitlist, withdrawing any offer it had. Expired entries are put back in line this way.
+// @Tags waitlist
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Waitlist entry ID"
+// @Param request body EntryRequest true "Waitlist entry"
+// @Success 200 {object} Entry
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /waitlist/{id} [put]
+func (c *Controller) UpdateEntryHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+	id, ok := c.getID(ctx)
+	if !ok {
+		return
+	}
+
+	var req EntryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry, err := c.service.UpdateEntry(id, orgID, req)
+	if err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entry)
+}
+
+// DeleteEntryHandler godoc
+// @Summary Remove a waitlist entry
+// @Description Takes a customer off the waitlist
+// @Tags waitlist
+// @Security ApiKeyAuth
+// @Param id path int true "Waitlist entry ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /waitlist/{id} [delete]
+func (c *Controller) DeleteEntryHandler(ctx *gin.Context) {
+	orgID, ok := c.getOrgID(ctx)
+	if !ok {
+		return
+	}
+	id, ok := c.getID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.service.DeleteEntry(id, orgID); err != nil {
+		c.fail(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// fail responds with the status of a service error
+func (c *Controller) fail(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrEntryNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrInvalidStay):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
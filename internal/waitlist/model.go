@@ -0,0 +1,87 @@
+package waitlist
+
+import (
+	"errors"
+	"time"
+)
+
+// Entry statuses
+const (
+	// StatusWaiting entries are offered dates once they free up
+	StatusWaiting = "WAITING"
+	// StatusOffered entries were offered a stay until OfferExpiresAt
+	StatusOffered = "OFFERED"
+	// StatusBooked entries were fulfilled by a reservation of the customer
+	StatusBooked = "BOOKED"
+	// StatusExpired entries let their offer expire; they can be put back on
+	// the waitlist by updating them
+	StatusExpired = "EXPIRED"
+)
+
+// entryColumns are the columns scanned into Entry
+const entryColumns = `
+    id, organization_id, customer_id, property_id, check_in_date, check_out_date,
+    no_of_guests, priority, notes, status, offered_property_id, offered_at,
+    offer_expires_at, created_at, updated_at
+`
+
+// Errors returned by the waitlist service
+var (
+	ErrEntryNotFound = errors.New("waitlist entry not found")
+	ErrInvalidStay   = errors.New("check-out must be after check-in")
+)
+
+// Entry is a customer waiting for a stay that was fully booked, at a
+// property or at any property of the organization when PropertyID is nil.
+// Entries are offered freed dates by descending priority, then in the order
+// they were added.
+type Entry struct {
+	ID                int64      `json:"id" db:"id" example:"12"`
+	OrganizationID    int64      `json:"organization_id" db:"organization_id" example:"1"`
+	CustomerID        int64      `json:"customer_id" db:"customer_id" example:"100"`
+	PropertyID        *int64     `json:"property_id" db:"property_id" example:"10"`
+	CheckInDate       time.Time  `json:"check_in_date" db:"check_in_date" example:"2024-12-20T15:00:00Z"`
+	CheckOutDate      time.Time  `json:"check_out_date" db:"check_out_date" example:"2024-12-25T11:00:00Z"`
+	NoOfGuests        int        `json:"no_of_guests" db:"no_of_guests" example:"2"`
+	Priority          int        `json:"priority" db:"priority" example:"0"`
+	Notes             string     `json:"notes" db:"notes" example:"Prefers a sea view"`
+	Status            string     `json:"status" db:"status" example:"WAITING" enums:"WAITING,OFFERED,BOOKED,EXPIRED"`
+	OfferedPropertyID *int64     `json:"offered_property_id,omitempty" db:"offered_property_id" example:"10"`
+	OfferedAt         *time.Time `json:"offered_at,omitempty" db:"offered_at"`
+	OfferExpiresAt    *time.Time `json:"offer_expires_at,omitempty" db:"offer_expires_at"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// EntryRequest is the body of a waitlist entry creation or update. Without a
+// property, any property of the organization is offered.
+type EntryRequest struct {
+	CustomerID   int64     `json:"customer_id" binding:"required,min=1" example:"100"`
+	PropertyID   *int64    `json:"property_id" binding:"omitempty,min=1" example:"10"`
+	CheckInDate  time.Time `json:"check_in_date" binding:"required" example:"2024-12-20T15:00:00Z"`
+	CheckOutDate time.Time `json:"check_out_date" binding:"required" example:"2024-12-25T11:00:00Z"`
+	NoOfGuests   int       `json:"no_of_guests" binding:"required,min=1" example:"2"`
+	Priority     int       `json:"priority" example:"0"`
+	Notes        string    `json:"notes" binding:"max=2000" example:"Prefers a sea view"`
+}
+
+// EntryFilter narrows the entries listed
+type EntryFilter struct {
+	Status     string `form:"status" binding:"omitempty,oneof=WAITING OFFERED BOOKED EXPIRED"`
+	PropertyID int64  `form:"property_id" binding:"omitempty,min=1"`
+	CustomerID int64  `form:"customer_id" binding:"omitempty,min=1"`
+}
+
+// Release is a stay freed at a property, to be offered to the waitlist
+type Release struct {
+	ID             int64     `db:"id"`
+	OrganizationID int64     `db:"organization_id"`
+	PropertyID     int64     `db:"property_id"`
+	CheckInDate    time.Time `db:"check_in_date"`
+	CheckOutDate   time.Time `db:"check_out_date"`
+}
+
+// overlaps reports whether the stay of an entry overlaps the given one
+func (e *Entry) overlaps(checkIn, checkOut time.Time) bool {
+	return e.CheckInDate.Before(checkOut) && checkIn.Before(e.CheckOutDate)
+}
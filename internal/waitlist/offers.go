@@ -0,0 +1,152 @@
+package waitlist
+
+import (
+	"context"
+	"fmt"
+	"hostflow/booking-service/internal/communication"
+	"time"
+
+	pb "hostflow/booking-service/internal/communication/proto"
+
+	"go.uber.org/fx"
+)
+
+// offerInterval is how often expired offers are swept and queued releases
+// offered when nobody wakes the worker.
+const offerInterval = time.Minute
+
+// tick expires the offers that weren't booked in time, which releases their
+// stays, then offers every queued release.
+func (s *Service) tick(ctx context.Context) {
+	if _, err := s.repo.ExpireOffers(); err != nil {
+		s.logger.Error("Failed to expire waitlist offers:", err)
+	}
+
+	for ctx.Err() == nil {
+		var release *Release
+		var offered []*Entry
+		err := s.repo.InTx(func(tx *Repository) error {
+			var err error
+			release, err = tx.ClaimRelease()
+			if err != nil || release == nil {
+				return err
+			}
+
+			offered, err = s.offerRelease(tx, release)
+			if err != nil {
+				return err
+			}
+			return tx.DeleteRelease(release.ID)
+		})
+		if err != nil {
+			s.logger.Error("Failed to offer released dates to the waitlist:", err)
+			return
+		}
+		if release == nil {
+			return
+		}
+
+		for _, entry := range offered {
+			s.sendOffer(entry)
+		}
+	}
+}
+
+// offerRelease offers a stay freed at a property to the waiting entries
+// chosen by nextOffers and returns the entries that were offered.
+func (s *Service) offerRelease(tx *Repository, release *Release) ([]*Entry, error) {
+	waiting, err := tx.GetWaitingEntries(release.OrganizationID, release.PropertyID, release.CheckInDate, release.CheckOutDate)
+	if err != nil || len(waiting) == 0 {
+		return nil, err
+	}
+	pending, err := tx.GetPendingOffers(release.PropertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	chosen, err := nextOffers(waiting, pending, func(e *Entry) (bool, error) {
+		return s.availability.IsAvailable(release.OrganizationID, release.PropertyID, e.CheckInDate, e.CheckOutDate, e.NoOfGuests)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(s.offerFor)
+	offered := make([]*Entry, 0, len(chosen))
+	for _, entry := range chosen {
+		updated, err := tx.OfferEntry(entry.ID, release.PropertyID, expiresAt)
+		if err != nil {
+			return nil, err
+		}
+		if updated != nil {
+			offered = append(offered, updated)
+		}
+	}
+	return offered, nil
+}
+
+// nextOffers returns the waiting entries to offer a freed stay to, in the
+// order they are waiting: each one whose stay is available, unless it
+// overlaps a pending offer or a stay chosen before it. The first entries in
+// line thus get the dates, and later entries only get the dates they leave.
+func nextOffers(waiting, pending []Entry, available func(*Entry) (bool, error)) ([]*Entry, error) {
+	taken := make([]*Entry, 0, len(pending))
+	for i := range pending {
+		taken = append(taken, &pending[i])
+	}
+
+	var chosen []*Entry
+	for i := range waiting {
+		entry := &waiting[i]
+		if overlapsAny(entry, taken) {
+			continue
+		}
+
+		ok, err := available(entry)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		taken = append(taken, entry)
+		chosen = append(chosen, entry)
+	}
+	return chosen, nil
+}
+
+// overlapsAny reports whether the stay of an entry overlaps any of the others
+func overlapsAny(entry *Entry, others []*Entry) bool {
+	for _, other := range others {
+		if entry.overlaps(other.CheckInDate, other.CheckOutDate) {
+			return true
+		}
+	}
+	return false
+}
+
+// sendOffer queues the offer email of an entry. Delivery happens in the
+// background; a failure to queue it leaves the offer in place.
+func (s *Service) sendOffer(entry *Entry) {
+	_, err := s.dispatcher.Enqueue(communication.EmailJob{
+		OrganizationID: entry.OrganizationID,
+		CustomerID:     entry.CustomerID,
+		PropertyID:     *entry.OfferedPropertyID,
+		Type:           pb.EmailType_WAITLIST_OFFER,
+		Offer: &communication.Offer{
+			WaitlistEntryID: entry.ID,
+			CheckInDate:     entry.CheckInDate,
+			CheckOutDate:    entry.CheckOutDate,
+			ExpiresAt:       *entry.OfferExpiresAt,
+		},
+	})
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to queue the offer email of waitlist entry %d:", entry.ID), err)
+	}
+}
+
+// RegisterOfferHooks starts the offer worker with the application
+func RegisterOfferHooks(lifecycle fx.Lifecycle, service *Service) {
+	service.worker.Start(lifecycle)
+}
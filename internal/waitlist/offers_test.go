@@ -0,0 +1,151 @@
+package waitlist
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hostflow/booking-service/internal/booking"
+	"hostflow/booking-service/internal/communication"
+	"hostflow/booking-service/internal/dbtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func entry(id int64, checkIn, checkOut string) Entry {
+	in, _ := time.Parse(time.DateOnly, checkIn)
+	out, _ := time.Parse(time.DateOnly, checkOut)
+	return Entry{ID: id, CheckInDate: in, CheckOutDate: out, NoOfGuests: 2}
+}
+
+func ids(entries []*Entry) []int64 {
+	var out []int64
+	for _, e := range entries {
+		out = append(out, e.ID)
+	}
+	return out
+}
+
+func TestNextOffers(t *testing.T) {
+	always := func(*Entry) (bool, error) { return true, nil }
+
+	t.Run("first in line gets overlapping dates", func(t *testing.T) {
+		waiting := []Entry{
+			entry(1, "2026-07-01", "2026-07-04"),
+			entry(2, "2026-07-02", "2026-07-05"),
+			entry(3, "2026-07-04", "2026-07-06"),
+		}
+		chosen, err := nextOffers(waiting, nil, always)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1, 3}, ids(chosen))
+	})
+
+	t.Run("unavailable entries are skipped", func(t *testing.T) {
+		waiting := []Entry{
+			entry(1, "2026-07-01", "2026-07-10"),
+			entry(2, "2026-07-02", "2026-07-05"),
+		}
+		chosen, err := nextOffers(waiting, nil, func(e *Entry) (bool, error) { return e.ID != 1, nil })
+		require.NoError(t, err)
+		assert.Equal(t, []int64{2}, ids(chosen))
+	})
+
+	t.Run("pending offers keep their dates", func(t *testing.T) {
+		waiting := []Entry{
+			entry(2, "2026-07-03", "2026-07-05"),
+			entry(3, "2026-07-05", "2026-07-07"),
+		}
+		pending := []Entry{entry(1, "2026-07-01", "2026-07-04")}
+		chosen, err := nextOffers(waiting, pending, always)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{3}, ids(chosen))
+	})
+
+	t.Run("check failure", func(t *testing.T) {
+		waiting := []Entry{entry(1, "2026-07-01", "2026-07-04")}
+		_, err := nextOffers(waiting, nil, func(*Entry) (bool, error) { return false, errors.New("db down") })
+		assert.Error(t, err)
+	})
+}
+
+func TestNewEntry(t *testing.T) {
+	in := time.Date(2026, 7, 1, 15, 0, 0, 0, time.UTC)
+	req := EntryRequest{CustomerID: 100, CheckInDate: in, CheckOutDate: in.AddDate(0, 0, 3), NoOfGuests: 2, Notes: "  sea view "}
+
+	e, err := newEntry(1, req)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), e.OrganizationID)
+	assert.Nil(t, e.PropertyID)
+	assert.Equal(t, "sea view", e.Notes)
+
+	req.CheckOutDate = in
+	_, err = newEntry(1, req)
+	assert.ErrorIs(t, err, ErrInvalidStay)
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Info(args ...interface{})  {}
+func (nopLogger) Fatal(args ...interface{}) {}
+func (nopLogger) Error(args ...interface{}) {}
+
+func TestTick_OffersDatesOfCancelledReservation(t *testing.T) {
+	db := dbtest.Open(t)
+	ctx := context.Background()
+	repo := NewRepository(db)
+	service := NewService(
+		repo,
+		booking.GetReservationRepository(db),
+		communication.NewEmailDispatcher(nil, communication.NewDeliveryRepository(db), nopLogger{}),
+		nopLogger{},
+	)
+
+	checkIn := time.Date(2030, 6, 1, 14, 0, 0, 0, time.UTC)
+	checkOut := time.Date(2030, 6, 4, 10, 0, 0, 0, time.UTC)
+	_, err := db.Exec(ctx, `
+        INSERT INTO reservation (id, organization_id, property_id, customer_id, check_in_date, check_out_date, status)
+        VALUES (1, 100, 10, 7, $1, $2, 'CONFIRMED')
+    `, checkIn, checkOut)
+	require.NoError(t, err)
+
+	propertyID := int64(10)
+	waiting, err := service.CreateEntry(100, EntryRequest{
+		CustomerID:   8,
+		PropertyID:   &propertyID,
+		CheckInDate:  checkIn,
+		CheckOutDate: checkOut,
+		NoOfGuests:   2,
+	})
+	require.NoError(t, err)
+
+	// While the reservation holds the dates, a release isn't offered
+	require.NoError(t, service.DatesReleased(100, 10, checkIn, checkOut))
+	service.tick(ctx)
+
+	entry, err := repo.GetEntry(waiting.ID, 100)
+	require.NoError(t, err)
+	assert.Equal(t, StatusWaiting, entry.Status)
+
+	_, err = db.Exec(ctx, `UPDATE reservation SET status = 'CANCELLED' WHERE id = 1`)
+	require.NoError(t, err)
+	require.NoError(t, service.DatesReleased(100, 10, checkIn, checkOut))
+	service.tick(ctx)
+
+	entry, err = repo.GetEntry(waiting.ID, 100)
+	require.NoError(t, err)
+	assert.Equal(t, StatusOffered, entry.Status)
+	require.NotNil(t, entry.OfferedPropertyID)
+	assert.Equal(t, propertyID, *entry.OfferedPropertyID)
+
+	var offers int
+	require.NoError(t, db.QueryRow(ctx, `
+        SELECT COUNT(*) FROM email_delivery WHERE customer_id = 8 AND email_type = 'WAITLIST_OFFER'
+    `).Scan(&offers))
+	assert.Equal(t, 1, offers)
+
+	var queued int
+	require.NoError(t, db.QueryRow(ctx, `SELECT COUNT(*) FROM waitlist_release`).Scan(&queued))
+	assert.Zero(t, queued)
+}
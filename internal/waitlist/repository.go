@@ -0,0 +1,356 @@
+package waitlist
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// dbtx is satisfied by both the pool and a transaction, so the repository
+// can run its queries inside a transaction opened with InTx
+type dbtx interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// Repository persists waitlist entries and freed stays
+type Repository struct {
+	db dbtx
+}
+
+// NewRepository returns a Repository
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+// InTx runs fn with a repository bound to a new transaction, which is
+// committed if fn returns nil and rolled back otherwise
+func (r *Repository) InTx(fn func(tx *Repository) error) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(&Repository{db: tx}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ======== ENTRIES ========
+
+// GetEntries returns the entries of an organization matching the filter, in
+// the order they are offered dates
+func (r *Repository) GetEntries(organizationID int64, f EntryFilter) ([]Entry, error) {
+	conditions := []string{"organization_id = $1"}
+	args := []any{organizationID}
+
+	if f.Status != "" {
+		args = append(args, f.Status)
+		conditions = append(conditions, "status = $"+strconv.Itoa(len(args)))
+	}
+	if f.PropertyID != 0 {
+		args = append(args, f.PropertyID)
+		conditions = append(conditions, "(property_id = $"+strconv.Itoa(len(args))+" OR property_id IS NULL)")
+	}
+	if f.CustomerID != 0 {
+		args = append(args, f.CustomerID)
+		conditions = append(conditions, "customer_id = $"+strconv.Itoa(len(args)))
+	}
+
+	query := `SELECT ` + entryColumns + `
+        FROM waitlist_entry
+        WHERE ` + strings.Join(conditions, " AND ") + `
+        ORDER BY priority DESC, created_at, id
+    `
+
+	rows, err := r.db.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[Entry])
+}
+
+// GetEntry returns an entry of an organization, or nil if it doesn't exist
+func (r *Repository) GetEntry(id, organizationID int64) (*Entry, error) {
+	query := `SELECT ` + entryColumns + `
+        FROM waitlist_entry
+        WHERE id = $1
+          AND organization_id = $2
+    `
+
+	rows, err := r.db.Query(context.Background(), query, id, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectOne[Entry](rows)
+}
+
+// CreateEntry stores a new waiting entry
+func (r *Repository) CreateEntry(e *Entry) (*Entry, error) {
+	query := `
+        INSERT INTO waitlist_entry (
+            organization_id, customer_id, property_id, check_in_date, check_out_date,
+            no_of_guests, priority, notes
+        )
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING ` + entryColumns
+
+	rows, err := r.db.Query(context.Background(), query,
+		e.OrganizationID,
+		e.CustomerID,
+		e.PropertyID,
+		e.CheckInDate,
+		e.CheckOutDate,
+		e.NoOfGuests,
+		e.Priority,
+		e.Notes,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectOne[Entry](rows)
+}
+
+// UpdateEntry replaces the details of an entry and puts it back on the
+// waitlist, withdrawing any offer it had. It returns nil if the entry
+// doesn't exist or was already booked.
+func (r *Repository) UpdateEntry(e *Entry) (*Entry, error) {
+	query := `
+        UPDATE waitlist_entry
+        SET customer_id = $3,
+            property_id = $4,
+            check_in_date = $5,
+            check_out_date = $6,
+            no_of_guests = $7,
+            priority = $8,
+            notes = $9,
+            status = 'WAITING',
+            offered_property_id = NULL,
+            offered_at = NULL,
+            offer_expires_at = NULL,
+            updated_at = NOW()
+        WHERE id = $1
+          AND organization_id = $2
+          AND status <> 'BOOKED'
+        RETURNING ` + entryColumns
+
+	rows, err := r.db.Query(context.Background(), query,
+		e.ID,
+		e.OrganizationID,
+		e.CustomerID,
+		e.PropertyID,
+		e.CheckInDate,
+		e.CheckOutDate,
+		e.NoOfGuests,
+		e.Priority,
+		e.Notes,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectOne[Entry](rows)
+}
+
+// DeleteEntry removes an entry and reports whether it existed
+func (r *Repository) DeleteEntry(id, organizationID int64) (bool, error) {
+	tag, err := r.db.Exec(context.Background(),
+		`DELETE FROM waitlist_entry WHERE id = $1 AND organization_id = $2`, id, organizationID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetWaitingEntries returns the waiting entries of an organization for the
+// property, or any property, whose stay overlaps the given one, in the order
+// they are offered dates
+func (r *Repository) GetWaitingEntries(organizationID, propertyID int64, checkIn, checkOut time.Time) ([]Entry, error) {
+	query := `SELECT ` + entryColumns + `
+        FROM waitlist_entry
+        WHERE organization_id = $1
+          AND status = 'WAITING'
+          AND (property_id = $2 OR property_id IS NULL)
+          AND check_in_date < $4
+          AND check_out_date > $3
+        ORDER BY priority DESC, created_at, id
+    `
+
+	rows, err := r.db.Query(context.Background(), query, organizationID, propertyID, checkIn, checkOut)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[Entry])
+}
+
+// GetPendingOffers returns the entries with an unexpired offer of a stay at
+// the property
+func (r *Repository) GetPendingOffers(propertyID int64) ([]Entry, error) {
+	query := `SELECT ` + entryColumns + `
+        FROM waitlist_entry
+        WHERE offered_property_id = $1
+          AND status = 'OFFERED'
+          AND offer_expires_at > NOW()
+    `
+
+	rows, err := r.db.Query(context.Background(), query, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[Entry])
+}
+
+// OfferEntry marks a waiting entry as offered its stay at the property until
+// expiresAt. It returns nil if the entry is no longer waiting.
+func (r *Repository) OfferEntry(id, propertyID int64, expiresAt time.Time) (*Entry, error) {
+	query := `
+        UPDATE waitlist_entry
+        SET status = 'OFFERED',
+            offered_property_id = $2,
+            offered_at = NOW(),
+            offer_expires_at = $3,
+            updated_at = NOW()
+        WHERE id = $1
+          AND status = 'WAITING'
+        RETURNING ` + entryColumns
+
+	rows, err := r.db.Query(context.Background(), query, id, propertyID, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectOne[Entry](rows)
+}
+
+// MarkBooked marks the waiting or offered entries of a customer that a stay
+// at the property fulfils as booked: those for the property or any property
+// whose stay overlaps it. It returns how many entries were fulfilled.
+func (r *Repository) MarkBooked(organizationID, customerID, propertyID int64, checkIn, checkOut time.Time) (int64, error) {
+	query := `
+        UPDATE waitlist_entry
+        SET status = 'BOOKED',
+            updated_at = NOW()
+        WHERE organization_id = $1
+          AND customer_id = $2
+          AND status IN ('WAITING', 'OFFERED')
+          AND (property_id = $3 OR property_id IS NULL)
+          AND check_in_date < $5
+          AND check_out_date > $4
+    `
+
+	tag, err := r.db.Exec(context.Background(), query, organizationID, customerID, propertyID, checkIn, checkOut)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// ExpireOffers expires the offers that weren't booked in time and queues
+// their stays as released, so that they are offered to the next entries. It
+// returns how many offers expired.
+func (r *Repository) ExpireOffers() (int64, error) {
+	query := `
+        WITH expired AS (
+            UPDATE waitlist_entry
+            SET status = 'EXPIRED',
+                updated_at = NOW()
+            WHERE status = 'OFFERED'
+              AND offer_expires_at <= NOW()
+            RETURNING organization_id, offered_property_id, check_in_date, check_out_date
+        )
+        INSERT INTO waitlist_release (organization_id, property_id, check_in_date, check_out_date)
+        SELECT organization_id, offered_property_id, check_in_date, check_out_date FROM expired
+    `
+
+	tag, err := r.db.Exec(context.Background(), query)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// ======== RELEASES ========
+
+// CreateRelease queues a stay freed at a property
+func (r *Repository) CreateRelease(organizationID, propertyID int64, checkIn, checkOut time.Time) error {
+	_, err := r.db.Exec(context.Background(), `
+        INSERT INTO waitlist_release (organization_id, property_id, check_in_date, check_out_date)
+        VALUES ($1, $2, $3, $4)
+    `, organizationID, propertyID, checkIn, checkOut)
+	return err
+}
+
+// ClaimRelease locks the oldest queued release that no other replica is
+// processing, and its property, and returns it, or nil if there is none. It
+// must run inside InTx; the release stays locked until the transaction ends.
+func (r *Repository) ClaimRelease() (*Release, error) {
+	rows, err := r.db.Query(context.Background(), `
+        SELECT id, organization_id, property_id, check_in_date, check_out_date
+        FROM waitlist_release
+        ORDER BY id
+        LIMIT 1
+        FOR UPDATE SKIP LOCKED
+    `)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := collectOne[Release](rows)
+	if err != nil || release == nil {
+		return nil, err
+	}
+
+	// Releases of a property are offered one at a time, so that overlapping
+	// stays are never offered to two entries
+	if _, err := r.db.Exec(context.Background(), `SELECT pg_advisory_xact_lock(hashtext($1))`, propertyLockKey(release.PropertyID)); err != nil {
+		return nil, err
+	}
+
+	return release, nil
+}
+
+// propertyLockKey names the lock ClaimRelease holds so that the releases of a
+// property are offered one at a time
+func propertyLockKey(propertyID int64) string {
+	return "waitlist_property:" + strconv.FormatInt(propertyID, 10)
+}
+
+// DeleteRelease removes a processed release
+func (r *Repository) DeleteRelease(id int64) error {
+	_, err := r.db.Exec(context.Background(), `DELETE FROM waitlist_release WHERE id = $1`, id)
+	return err
+}
+
+// collectOne returns the only row of rows, or nil if there is none
+func collectOne[T any](rows pgx.Rows) (*T, error) {
+	row, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[T])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
@@ -0,0 +1,59 @@
+package waitlist
+
+import (
+	"testing"
+	"time"
+
+	"hostflow/booking-service/internal/dbtest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaimRelease_LocksReleaseAndProperty(t *testing.T) {
+	repo := NewRepository(dbtest.Open(t))
+	checkIn := time.Date(2030, 6, 1, 14, 0, 0, 0, time.UTC)
+	checkOut := time.Date(2030, 6, 4, 10, 0, 0, 0, time.UTC)
+
+	require.NoError(t, repo.CreateRelease(100, 10, checkIn, checkOut))
+	require.NoError(t, repo.CreateRelease(100, 10, checkIn, checkOut))
+
+	claimed := make(chan *Release, 1)
+	release := make(chan struct{})
+	first := make(chan error, 1)
+	go func() {
+		first <- repo.InTx(func(tx *Repository) error {
+			r, err := tx.ClaimRelease()
+			if err != nil {
+				return err
+			}
+			claimed <- r
+			<-release
+			return nil
+		})
+	}()
+	r := <-claimed
+	require.NotNil(t, r)
+
+	// The second release of the property is claimable but waits for the
+	// property lock
+	second := make(chan error, 1)
+	go func() {
+		second <- repo.InTx(func(tx *Repository) error {
+			other, err := tx.ClaimRelease()
+			if err == nil && (other == nil || other.ID == r.ID) {
+				t.Errorf("claimed %+v while release %d was locked", other, r.ID)
+			}
+			return err
+		})
+	}()
+
+	select {
+	case err := <-second:
+		t.Fatalf("property lock acquired while held by another transaction: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	close(release)
+	require.NoError(t, <-first)
+	require.NoError(t, <-second)
+}
@@ -0,0 +1,47 @@
+package waitlist
+
+import (
+	"hostflow/booking-service/internal/middlewares"
+	"hostflow/booking-service/pkg/lib"
+)
+
+// Routes struct
+type Routes struct {
+	logger              lib.Logger
+	router              *lib.Router
+	controller          *Controller
+	authMiddleware      middlewares.AuthMiddleware
+	rateLimitMiddleware middlewares.RateLimitMiddleware
+}
+
+// SetRoutes returns a Routes struct
+func SetRoutes(
+	logger lib.Logger,
+	router *lib.Router,
+	controller *Controller,
+	authMiddleware middlewares.AuthMiddleware,
+	rateLimitMiddleware middlewares.RateLimitMiddleware,
+) Routes {
+	return Routes{
+		logger:              logger,
+		router:              router,
+		controller:          controller,
+		authMiddleware:      authMiddleware,
+		rateLimitMiddleware: rateLimitMiddleware,
+	}
+}
+
+// Setup registers the waitlist routes. Reading requires waitlist:read and
+// changes waitlist:manage.
+func (route Routes) Setup() {
+	route.logger.Info("Setting up [WAITLIST] routes.")
+
+	waitlist := route.router.Group("/waitlist")
+	waitlist.Use(route.authMiddleware.Handler())
+	{
+		waitlist.GET("", route.rateLimitMiddleware.Limit(middlewares.BudgetDefault), middlewares.RequirePermission(middlewares.WaitlistRead), route.controller.GetEntriesHandler)
+		waitlist.POST("", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), middlewares.RequirePermission(middlewares.WaitlistManage), route.controller.CreateEntryHandler)
+		waitlist.PUT("/:id", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), middlewares.RequirePermission(middlewares.WaitlistManage), route.controller.UpdateEntryHandler)
+		waitlist.DELETE("/:id", route.rateLimitMiddleware.Limit(middlewares.BudgetWrite), middlewares.RequirePermission(middlewares.WaitlistManage), route.controller.DeleteEntryHandler)
+	}
+}
@@ -0,0 +1,140 @@
+package waitlist
+
+import (
+	"hostflow/booking-service/internal/communication"
+	"hostflow/booking-service/pkg/interfaces"
+	"hostflow/booking-service/pkg/lib"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultOfferHours is used when WAITLIST_OFFER_HOURS is not set.
+const defaultOfferHours = 24
+
+// Service manages the waitlist of fully booked stays. It implements
+// interfaces.WaitlistNotifier: freed stays are queued and offered to the
+// waitlist in the background by the offer worker.
+type Service struct {
+	repo         *Repository
+	availability interfaces.AvailabilityChecker
+	dispatcher   *communication.EmailDispatcher
+	logger       lib.Logger
+	offerFor     time.Duration
+	worker       *lib.Worker
+}
+
+// NewService returns a Service. Offers expire after WAITLIST_OFFER_HOURS.
+func NewService(
+	repo *Repository,
+	availability interfaces.AvailabilityChecker,
+	dispatcher *communication.EmailDispatcher,
+	logger lib.Logger,
+) *Service {
+	offerHours := defaultOfferHours
+	if v, err := strconv.Atoi(os.Getenv("WAITLIST_OFFER_HOURS")); err == nil && v > 0 {
+		offerHours = v
+	}
+
+	s := &Service{
+		repo:         repo,
+		availability: availability,
+		dispatcher:   dispatcher,
+		logger:       logger,
+		offerFor:     time.Duration(offerHours) * time.Hour,
+	}
+	s.worker = lib.NewWorker(offerInterval, s.tick)
+	return s
+}
+
+// ======== ENTRIES ========
+
+// GetEntries returns the entries of an organization in the order they are
+// offered dates
+func (s *Service) GetEntries(organizationID int64, f EntryFilter) ([]Entry, error) {
+	return s.repo.GetEntries(organizationID, f)
+}
+
+// CreateEntry puts a customer on the waitlist
+func (s *Service) CreateEntry(organizationID int64, req EntryRequest) (*Entry, error) {
+	entry, err := newEntry(organizationID, req)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.CreateEntry(entry)
+}
+
+// UpdateEntry replaces the details of an entry that wasn't booked and puts it
+// back on the waitlist, withdrawing any offer it had
+func (s *Service) UpdateEntry(id, organizationID int64, req EntryRequest) (*Entry, error) {
+	entry, err := newEntry(organizationID, req)
+	if err != nil {
+		return nil, err
+	}
+	entry.ID = id
+
+	updated, err := s.repo.UpdateEntry(entry)
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		return nil, ErrEntryNotFound
+	}
+	return updated, nil
+}
+
+// DeleteEntry takes an entry off the waitlist
+func (s *Service) DeleteEntry(id, organizationID int64) error {
+	deleted, err := s.repo.DeleteEntry(id, organizationID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrEntryNotFound
+	}
+	return nil
+}
+
+// newEntry validates a request and returns the entry it describes
+func newEntry(organizationID int64, req EntryRequest) (*Entry, error) {
+	if !req.CheckOutDate.After(req.CheckInDate) {
+		return nil, ErrInvalidStay
+	}
+
+	return &Entry{
+		OrganizationID: organizationID,
+		CustomerID:     req.CustomerID,
+		PropertyID:     req.PropertyID,
+		CheckInDate:    req.CheckInDate,
+		CheckOutDate:   req.CheckOutDate,
+		NoOfGuests:     req.NoOfGuests,
+		Priority:       req.Priority,
+		Notes:          strings.TrimSpace(req.Notes),
+	}, nil
+}
+
+// ======== NOTIFICATIONS ========
+
+// DatesReleased queues a stay freed at a property and wakes the offer worker
+func (s *Service) DatesReleased(organizationID, propertyID int64, checkIn, checkOut time.Time) error {
+	if err := s.repo.CreateRelease(organizationID, propertyID, checkIn, checkOut); err != nil {
+		return err
+	}
+
+	s.Wake()
+
+	return nil
+}
+
+// StayBooked marks the entries of the customer that the stay fulfils as
+// booked, whether or not it was offered to them
+func (s *Service) StayBooked(organizationID, customerID, propertyID int64, checkIn, checkOut time.Time) error {
+	_, err := s.repo.MarkBooked(organizationID, customerID, propertyID, checkIn, checkOut)
+	return err
+}
+
+// Wake asks the offer worker to offer queued releases right away
+func (s *Service) Wake() {
+	s.worker.Wake()
+}
@@ -0,0 +1,21 @@
+package waitlist
+
+import (
+	"hostflow/booking-service/pkg/interfaces"
+
+	"go.uber.org/fx"
+)
+
+// ======== EXPORTS ========
+
+// Module exports the waitlist and its offer worker
+var Module = fx.Options(
+	fx.Provide(NewRepository, NewService, NewController, SetRoutes),
+	fx.Provide(
+		fx.Annotate(
+			func(service *Service) *Service { return service },
+			fx.As(new(interfaces.WaitlistNotifier)),
+		),
+	),
+	fx.Invoke(RegisterOfferHooks),
+)
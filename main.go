@@ -2,10 +2,22 @@ package main
 
 import (
 	_ "hostflow/booking-service/docs" // Import generated swagger docs
+	"hostflow/booking-service/internal/apikey"
+	"hostflow/booking-service/internal/audit"
 	"hostflow/booking-service/internal/bootstrap"
 	"hostflow/booking-service/internal/communication"
 	"hostflow/booking-service/internal/customer"
+	"hostflow/booking-service/internal/encryption"
+	"hostflow/booking-service/internal/guest"
+	"hostflow/booking-service/internal/invoice"
 	"hostflow/booking-service/internal/kafka"
+	"hostflow/booking-service/internal/portal"
+	"hostflow/booking-service/internal/privacy"
+	"hostflow/booking-service/internal/report"
+	"hostflow/booking-service/internal/room"
+	"hostflow/booking-service/internal/scheduler"
+	"hostflow/booking-service/internal/touristtax"
+	"hostflow/booking-service/internal/waitlist"
 
 	"github.com/joho/godotenv"
 	"go.uber.org/fx"
@@ -27,6 +39,11 @@ import (
 // @in header
 // @name Authorization
 
+// @securityDefinitions.apikey GuestPortalToken
+// @in header
+// @name Authorization
+// @description Guest portal link token, as "Bearer <token>"
+
 // @host hostflow.software/booking
 // @BasePath /
 // @schemes https
@@ -37,6 +54,36 @@ import (
 // @tag.name customers
 // @tag.description Customer-specific reservation operations
 
+// @tag.name communication
+// @tag.description Guest emails and scheduled guest communications
+
+// @tag.name api-keys
+// @tag.description Organization API keys for integrations
+
+// @tag.name audit
+// @tag.description Append-only log of changes to reservations
+
+// @tag.name privacy
+// @tag.description GDPR export and erasure of guest data
+
+// @tag.name guest-portal
+// @tag.description Magic links and the self-service portal for guests
+
+// @tag.name tourist-tax
+// @tag.description Tourist tax rules and monthly municipal reports
+
+// @tag.name invoices
+// @tag.description Invoices and credit notes of reservations
+
+// @tag.name reports
+// @tag.description Occupancy, revenue and booking reports
+
+// @tag.name rooms
+// @tag.description Room types and units of properties with several bookable units
+
+// @tag.name waitlist
+// @tag.description Customers waiting for fully booked dates, offered them when they free up
+
 func main() {
 	_ = godotenv.Load()
 
@@ -45,5 +92,17 @@ func main() {
 		kafka.Module,
 		customer.Module,
 		communication.Module,
+		scheduler.Module,
+		apikey.Module,
+		audit.Module,
+		privacy.Module,
+		encryption.Module,
+		guest.Module,
+		portal.Module,
+		touristtax.Module,
+		invoice.Module,
+		report.Module,
+		room.Module,
+		waitlist.Module,
 	).Run()
 }
@@ -0,0 +1,26 @@
+-- Delivery status of guest emails sent automatically on reservation
+-- lifecycle transitions. Rows double as the work queue of the email
+-- dispatcher, so pending sends survive restarts and are shared between
+-- replicas.
+CREATE TABLE IF NOT EXISTS email_delivery (
+    id              BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT      NOT NULL,
+    reservation_id  BIGINT      NOT NULL,
+    customer_id     BIGINT      NOT NULL,
+    property_id     BIGINT      NOT NULL,
+    email_type      TEXT        NOT NULL,
+    payment_url     TEXT        NOT NULL DEFAULT '',
+    status          TEXT        NOT NULL DEFAULT 'PENDING',
+    attempts        INT         NOT NULL DEFAULT 0,
+    last_error      TEXT        NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at         TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS email_delivery_queue_idx
+    ON email_delivery (status, next_attempt_at);
+
+CREATE INDEX IF NOT EXISTS email_delivery_reservation_idx
+    ON email_delivery (organization_id, reservation_id);
@@ -0,0 +1,36 @@
+-- Guest communications sent at an offset from the check-in or check-out date.
+-- A schedule with a NULL property_id applies to every property of the
+-- organization that has no schedule of its own for the same email type.
+CREATE TABLE IF NOT EXISTS message_schedule (
+    id              BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT      NOT NULL,
+    property_id     BIGINT,
+    email_type      TEXT        NOT NULL,
+    anchor          TEXT        NOT NULL CHECK (anchor IN ('CHECK_IN', 'CHECK_OUT')),
+    offset_days     INT         NOT NULL DEFAULT 0,
+    send_hour       INT CHECK (send_hour BETWEEN 0 AND 23),
+    active          BOOLEAN     NOT NULL DEFAULT TRUE,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS message_schedule_org_idx
+    ON message_schedule (organization_id, active);
+
+-- One row per (schedule, reservation). The unique constraint is what keeps
+-- several replicas from planning the same message twice.
+CREATE TABLE IF NOT EXISTS scheduled_message (
+    id              BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT      NOT NULL,
+    schedule_id     BIGINT      NOT NULL REFERENCES message_schedule (id) ON DELETE CASCADE,
+    reservation_id  BIGINT      NOT NULL,
+    email_type      TEXT        NOT NULL,
+    send_at         TIMESTAMPTZ NOT NULL,
+    status          TEXT        NOT NULL DEFAULT 'PENDING',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (schedule_id, reservation_id)
+);
+
+CREATE INDEX IF NOT EXISTS scheduled_message_due_idx
+    ON scheduled_message (status, send_at);
@@ -0,0 +1,20 @@
+-- Organization-scoped API keys for integrations and jobs that cannot obtain
+-- a user JWT. Only an argon2id hash of the secret is stored; the prefix is
+-- public and is used to find the row to verify against.
+CREATE TABLE IF NOT EXISTS api_key (
+    id              BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT      NOT NULL,
+    name            TEXT        NOT NULL,
+    prefix          TEXT        NOT NULL UNIQUE,
+    key_hash        TEXT        NOT NULL,
+    scopes          TEXT[]      NOT NULL DEFAULT '{}',
+    created_by      TEXT,
+    expires_at      TIMESTAMPTZ,
+    last_used_at    TIMESTAMPTZ,
+    last_used_ip    TEXT,
+    revoked_at      TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS api_key_org_idx
+    ON api_key (organization_id, created_at DESC);
@@ -0,0 +1,11 @@
+-- Emails requested through POST /communication/email are recorded with who
+-- requested them. The same rows are counted for the per-organization limit.
+ALTER TABLE email_delivery
+    ADD COLUMN IF NOT EXISTS source       TEXT NOT NULL DEFAULT 'AUTOMATIC',
+    ADD COLUMN IF NOT EXISTS requested_by TEXT,
+    ADD COLUMN IF NOT EXISTS api_key_id   BIGINT,
+    ADD COLUMN IF NOT EXISTS client_ip    TEXT;
+
+CREATE INDEX IF NOT EXISTS email_delivery_manual_idx
+    ON email_delivery (organization_id, created_at)
+    WHERE source = 'MANUAL';
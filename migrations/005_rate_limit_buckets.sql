@@ -0,0 +1,8 @@
+-- Token buckets of the shared rate limit store (RATE_LIMIT_STORE=postgres).
+-- Rows are tiny and overwritten in place; idle ones can be removed at any
+-- time, since a missing bucket starts full.
+CREATE UNLOGGED TABLE IF NOT EXISTS rate_limit_bucket (
+    key        TEXT PRIMARY KEY,
+    tokens     DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ      NOT NULL
+);
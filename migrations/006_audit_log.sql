@@ -0,0 +1,30 @@
+-- Append-only log of every mutating operation. Entries are written in the
+-- same transaction as the change they describe.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id              BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT      NOT NULL,
+    actor_type      TEXT        NOT NULL CHECK (actor_type IN ('user', 'api_key', 'system')),
+    actor_id        TEXT        NOT NULL,
+    actor_role      TEXT,
+    action          TEXT        NOT NULL,
+    entity_type     TEXT        NOT NULL,
+    entity_id       TEXT        NOT NULL,
+    changes         JSONB       NOT NULL DEFAULT '{}',
+    request_id      TEXT,
+    client_ip       TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_entity_idx
+    ON audit_log (organization_id, entity_type, entity_id, created_at DESC);
+
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
@@ -0,0 +1,10 @@
+-- Reservations are soft-deleted so that their financial history is kept.
+-- Deleted rows are excluded from reads and availability checks, and are
+-- permanently removed by the purge job after RESERVATION_PURGE_AFTER_DAYS.
+ALTER TABLE reservation
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS deleted_by TEXT;
+
+CREATE INDEX IF NOT EXISTS reservation_deleted_at_idx
+    ON reservation (deleted_at)
+    WHERE deleted_at IS NOT NULL;
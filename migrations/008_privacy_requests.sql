@@ -0,0 +1,41 @@
+-- GDPR export and erasure requests. A request is created as PENDING and
+-- ends as COMPLETED or FAILED; it never holds the exported personal data.
+CREATE TABLE IF NOT EXISTS privacy_request (
+    id              BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT      NOT NULL,
+    customer_id     BIGINT      NOT NULL,
+    type            TEXT        NOT NULL CHECK (type IN ('EXPORT', 'ERASURE')),
+    status          TEXT        NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'COMPLETED', 'FAILED')),
+    reason          TEXT,
+    requested_by    TEXT        NOT NULL,
+    result          JSONB       NOT NULL DEFAULT '{}',
+    failure_reason  TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS privacy_request_organization_idx
+    ON privacy_request (organization_id, created_at DESC);
+
+-- Set when the personal data of a reservation was erased, on request or by
+-- the retention job. Financial fields are kept.
+ALTER TABLE reservation
+    ADD COLUMN IF NOT EXISTS anonymized_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS reservation_retention_idx
+    ON reservation (check_out_date)
+    WHERE anonymized_at IS NULL;
+
+-- Erasure must also remove personal data from earlier audit entries. The
+-- audit log stays append-only, except that the changes of an entry may be
+-- rewritten by a transaction that enabled hostflow.audit_redaction.
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'UPDATE'
+       AND current_setting('hostflow.audit_redaction', true) = 'on'
+       AND (to_jsonb(NEW) - 'changes') = (to_jsonb(OLD) - 'changes') THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,23 @@
+-- Magic links giving guests access to the guest portal of a reservation.
+-- The token itself is never stored: it is signed with GUEST_PORTAL_SECRET
+-- and carries the link id, which is checked against this table on every use.
+CREATE TABLE IF NOT EXISTS guest_portal_link (
+    id                  BIGSERIAL PRIMARY KEY,
+    organization_id     BIGINT      NOT NULL,
+    reservation_id      BIGINT      NOT NULL,
+    pin_hash            TEXT,
+    failed_pin_attempts INT         NOT NULL DEFAULT 0,
+    expires_at          TIMESTAMPTZ NOT NULL,
+    revoked_at          TIMESTAMPTZ,
+    last_used_at        TIMESTAMPTZ,
+    created_by          TEXT        NOT NULL,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS guest_portal_link_reservation_idx
+    ON guest_portal_link (organization_id, reservation_id, created_at DESC);
+
+-- Guests act through the portal and are recorded in the audit log as such
+ALTER TABLE audit_log DROP CONSTRAINT IF EXISTS audit_log_actor_type_check;
+ALTER TABLE audit_log ADD CONSTRAINT audit_log_actor_type_check
+    CHECK (actor_type IN ('user', 'api_key', 'system', 'guest'));
@@ -0,0 +1,37 @@
+-- Tourist tax rules. A rule applies to every property assigned to its
+-- municipality, unless a rule for the specific property exists. Age bands
+-- reduce the rate for younger guests, e.g.
+-- [{"max_age": 6, "percent": 0}, {"max_age": 17, "percent": 50}].
+CREATE TABLE IF NOT EXISTS tourist_tax_rule (
+    id                BIGSERIAL PRIMARY KEY,
+    organization_id   BIGINT        NOT NULL,
+    municipality_code TEXT          NOT NULL,
+    municipality_name TEXT          NOT NULL,
+    property_id       BIGINT,
+    rate              NUMERIC(10,2) NOT NULL CHECK (rate >= 0),
+    currency          TEXT          NOT NULL DEFAULT 'EUR',
+    age_bands         JSONB         NOT NULL DEFAULT '[]',
+    valid_from        DATE          NOT NULL,
+    valid_to          DATE,
+    created_at        TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    CHECK (valid_to IS NULL OR valid_to >= valid_from)
+);
+
+CREATE INDEX IF NOT EXISTS tourist_tax_rule_lookup_idx
+    ON tourist_tax_rule (organization_id, municipality_code, property_id, valid_from DESC);
+
+-- The municipality each property is in
+CREATE TABLE IF NOT EXISTS tourist_tax_property (
+    organization_id   BIGINT      NOT NULL,
+    property_id       BIGINT      NOT NULL,
+    municipality_code TEXT        NOT NULL,
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (organization_id, property_id)
+);
+
+-- Monthly reports read the reservations with a tourist tax line that
+-- overlap the month
+CREATE INDEX IF NOT EXISTS reservation_tourist_tax_idx
+    ON reservation (organization_id, check_in_date, check_out_date)
+    WHERE deleted_at IS NULL AND price_elements ? 'tourist_tax';
@@ -0,0 +1,66 @@
+-- Issuer details of an organization, printed on every invoice. The VAT rate
+-- applies to price elements that don't set their own.
+CREATE TABLE IF NOT EXISTS invoice_settings (
+    organization_id BIGINT PRIMARY KEY,
+    issuer_name     TEXT          NOT NULL,
+    issuer_address  TEXT          NOT NULL,
+    issuer_tax_id   TEXT          NOT NULL,
+    issuer_email    TEXT,
+    vat_rate        NUMERIC(5,2)  NOT NULL DEFAULT 0 CHECK (vat_rate >= 0 AND vat_rate < 100),
+    currency        TEXT          NOT NULL DEFAULT 'EUR',
+    footer          TEXT,
+    updated_at      TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+-- Last number issued per organization, fiscal year and series. The row is
+-- incremented in the transaction that stores the invoice, so a failed
+-- issue rolls the number back and numbers have no gaps.
+CREATE TABLE IF NOT EXISTS invoice_sequence (
+    organization_id BIGINT NOT NULL,
+    fiscal_year     INT    NOT NULL,
+    series          TEXT   NOT NULL,
+    last_number     BIGINT NOT NULL,
+    PRIMARY KEY (organization_id, fiscal_year, series)
+);
+
+-- Issued invoices and credit notes. The document is the invoice as issued
+-- and the PDF its rendering; neither ever changes.
+CREATE TABLE IF NOT EXISTS invoice (
+    id                  BIGSERIAL PRIMARY KEY,
+    organization_id     BIGINT        NOT NULL,
+    reservation_id      BIGINT        NOT NULL,
+    kind                TEXT          NOT NULL CHECK (kind IN ('INVOICE', 'CREDIT_NOTE')),
+    series              TEXT          NOT NULL,
+    fiscal_year         INT           NOT NULL,
+    sequence_number     BIGINT        NOT NULL,
+    number              TEXT          NOT NULL,
+    credited_invoice_id BIGINT REFERENCES invoice (id),
+    currency            TEXT          NOT NULL,
+    net_total           NUMERIC(12,2) NOT NULL,
+    vat_total           NUMERIC(12,2) NOT NULL,
+    total               NUMERIC(12,2) NOT NULL,
+    document            JSONB         NOT NULL,
+    pdf                 BYTEA         NOT NULL,
+    issued_by           TEXT          NOT NULL,
+    issued_at           TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    UNIQUE (organization_id, fiscal_year, series, sequence_number),
+    CHECK ((kind = 'CREDIT_NOTE') = (credited_invoice_id IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS invoice_reservation_idx
+    ON invoice (organization_id, reservation_id, issued_at);
+
+CREATE INDEX IF NOT EXISTS invoice_credited_idx
+    ON invoice (credited_invoice_id)
+    WHERE credited_invoice_id IS NOT NULL;
+
+CREATE OR REPLACE FUNCTION invoice_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'issued invoices are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS invoice_immutable ON invoice;
+CREATE TRIGGER invoice_immutable
+    BEFORE UPDATE OR DELETE ON invoice
+    FOR EACH ROW EXECUTE FUNCTION invoice_immutable();
@@ -0,0 +1,43 @@
+-- Room types and units of properties with several bookable units, such as
+-- guesthouses with identical rooms. Reservations of such a property
+-- reference a room type, and a unit once one is assigned; availability is
+-- the number of active units of the type per night. Properties without room
+-- types remain a single bookable unit.
+CREATE TABLE IF NOT EXISTS room_type (
+    id              BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT      NOT NULL,
+    property_id     BIGINT      NOT NULL,
+    name            TEXT        NOT NULL,
+    description     TEXT        NOT NULL DEFAULT '',
+    max_guests      INT         NOT NULL DEFAULT 0 CHECK (max_guests >= 0),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (organization_id, property_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS room_unit (
+    id              BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT      NOT NULL,
+    property_id     BIGINT      NOT NULL,
+    room_type_id    BIGINT      NOT NULL REFERENCES room_type (id),
+    name            TEXT        NOT NULL,
+    active          BOOLEAN     NOT NULL DEFAULT TRUE,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (room_type_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS room_unit_property_idx
+    ON room_unit (organization_id, property_id);
+
+ALTER TABLE reservation
+    ADD COLUMN IF NOT EXISTS room_type_id BIGINT REFERENCES room_type (id),
+    ADD COLUMN IF NOT EXISTS unit_id      BIGINT REFERENCES room_unit (id);
+
+CREATE INDEX IF NOT EXISTS reservation_room_type_idx
+    ON reservation (room_type_id, check_in_date, check_out_date)
+    WHERE room_type_id IS NOT NULL AND deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS reservation_unit_idx
+    ON reservation (unit_id, check_in_date, check_out_date)
+    WHERE unit_id IS NOT NULL AND deleted_at IS NULL;
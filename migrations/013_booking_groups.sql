@@ -0,0 +1,21 @@
+-- Booking groups: several stays, possibly at different properties and on
+-- different dates, that are booked, priced and paid together with a single
+-- payment link. The stays are ordinary reservations referencing the group.
+CREATE TABLE IF NOT EXISTS booking_group (
+    id              BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT      NOT NULL,
+    customer_id     BIGINT      NOT NULL,
+    payment_url     TEXT        NOT NULL DEFAULT '',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS booking_group_customer_idx
+    ON booking_group (organization_id, customer_id);
+
+ALTER TABLE reservation
+    ADD COLUMN IF NOT EXISTS group_id BIGINT REFERENCES booking_group (id);
+
+CREATE INDEX IF NOT EXISTS reservation_group_idx
+    ON reservation (group_id)
+    WHERE group_id IS NOT NULL;
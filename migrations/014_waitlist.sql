@@ -0,0 +1,47 @@
+-- Customers waiting for dates that were fully booked, at a property or at
+-- any property of the organization when property_id is NULL. When a
+-- reservation frees dates, the waiting entries that overlap them and now fit
+-- are offered the stay in order of priority, with an offer that expires
+-- after WAITLIST_OFFER_HOURS.
+CREATE TABLE IF NOT EXISTS waitlist_entry (
+    id                  BIGSERIAL PRIMARY KEY,
+    organization_id     BIGINT      NOT NULL,
+    customer_id         BIGINT      NOT NULL,
+    property_id         BIGINT,
+    check_in_date       TIMESTAMPTZ NOT NULL,
+    check_out_date      TIMESTAMPTZ NOT NULL CHECK (check_out_date > check_in_date),
+    no_of_guests        INT         NOT NULL CHECK (no_of_guests > 0),
+    priority            INT         NOT NULL DEFAULT 0,
+    notes               TEXT        NOT NULL DEFAULT '',
+    status              TEXT        NOT NULL DEFAULT 'WAITING',
+    offered_property_id BIGINT,
+    offered_at          TIMESTAMPTZ,
+    offer_expires_at    TIMESTAMPTZ,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS waitlist_entry_waiting_idx
+    ON waitlist_entry (organization_id, check_in_date)
+    WHERE status = 'WAITING';
+
+CREATE INDEX IF NOT EXISTS waitlist_entry_offer_idx
+    ON waitlist_entry (offer_expires_at)
+    WHERE status = 'OFFERED';
+
+-- Stays freed by cancelled, rejected, deleted or moved reservations and by
+-- expired offers. Rows are the work queue of the waitlist runner, so freed
+-- dates are offered even if the replica that freed them stops.
+CREATE TABLE IF NOT EXISTS waitlist_release (
+    id              BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT      NOT NULL,
+    property_id     BIGINT      NOT NULL,
+    check_in_date   TIMESTAMPTZ NOT NULL,
+    check_out_date  TIMESTAMPTZ NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Waitlist offers are not about a reservation: their deliveries have
+-- reservation_id 0 and carry the offered stay instead.
+ALTER TABLE email_delivery
+    ADD COLUMN IF NOT EXISTS offer JSONB;
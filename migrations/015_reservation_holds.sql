@@ -0,0 +1,11 @@
+-- Holds are reservations that block their stay for a prospective guest
+-- without a payment until hold_expires_at. A hold is converted into a
+-- booking, which starts the payment, or released; the hold sweeper cancels
+-- holds that expire. Reports leave holds out.
+ALTER TABLE reservation
+    ADD COLUMN IF NOT EXISTS kind            TEXT NOT NULL DEFAULT 'BOOKING',
+    ADD COLUMN IF NOT EXISTS hold_expires_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS reservation_hold_expiry_idx
+    ON reservation (hold_expires_at)
+    WHERE kind = 'HOLD' AND status = 'CREATED' AND deleted_at IS NULL;
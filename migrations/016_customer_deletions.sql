@@ -0,0 +1,20 @@
+-- Duplicate customers removed by a merge. The row is written in the same
+-- transaction that moves the duplicate's reservations, and the customer
+-- record is deleted from the customer service after the commit; failed
+-- deletes are retried from this table, so a merge never leaves
+-- reservations pointing to a deleted customer or a duplicate behind.
+CREATE TABLE IF NOT EXISTS customer_deletion (
+    id              BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT      NOT NULL,
+    customer_id     BIGINT      NOT NULL,
+    status          TEXT        NOT NULL DEFAULT 'PENDING',
+    attempts        INT         NOT NULL DEFAULT 0,
+    last_error      TEXT        NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at      TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS customer_deletion_queue_idx
+    ON customer_deletion (status, next_attempt_at);
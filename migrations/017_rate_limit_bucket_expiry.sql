@@ -0,0 +1,9 @@
+-- When a bucket of the shared rate limit store has been full for a whole
+-- refill window. Such buckets are removed periodically, as a missing bucket
+-- starts full anyway. Buckets that exist already are kept for an hour, in
+-- which the default budgets refill.
+ALTER TABLE rate_limit_bucket
+    ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ NOT NULL DEFAULT NOW() + INTERVAL '1 hour';
+
+CREATE INDEX IF NOT EXISTS rate_limit_bucket_expires_at_idx
+    ON rate_limit_bucket (expires_at);
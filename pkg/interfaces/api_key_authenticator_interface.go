@@ -0,0 +1,55 @@
+/*
+Package Name: interfaces
+File Name: api_key_authenticator_interface.go
+Abstract: Interface for authenticating API keys, used by the auth middleware
+without depending on the apikey package.
+
+# MIT License
+
+# Copyright 2023 Alejandro Modroño Vara
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+package interfaces
+
+import "errors"
+
+// ======== TYPES ========
+
+// APIKeyIdentity is the organization and scopes an API key was issued with.
+type APIKeyIdentity struct {
+	ID             int64
+	OrganizationID int64
+	Name           string
+	Scopes         []string
+}
+
+// ======== ERRORS ========
+
+// InvalidAPIKeyException is returned for unknown, revoked or expired keys.
+var InvalidAPIKeyException = errors.New("The API key is invalid.")
+
+// ======== INTERFACES ========
+
+// The interface for authenticating API keys.
+type APIKeyAuthenticator interface {
+	// AuthenticateAPIKey verifies a key presented by a client and records
+	// its use. It returns InvalidAPIKeyException if the key is not valid.
+	AuthenticateAPIKey(key, clientIP string) (*APIKeyIdentity, error)
+}
@@ -0,0 +1,41 @@
+/*
+Package Name: interfaces
+File Name: availability_checker_interface.go
+Abstract: Interface used by the waitlist module to check whether a stay could be
+booked without importing the booking module.
+
+# MIT License
+
+# Copyright 2023 Alejandro Modroño Vara
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+package interfaces
+
+import "time"
+
+// ======== INTERFACES ========
+
+// The interface for checking the availability of properties from other modules.
+type AvailabilityChecker interface {
+	// IsAvailable reports whether a stay of guests from checkIn to checkOut
+	// could be booked at the property, in any of its room types if it has
+	// some.
+	IsAvailable(organizationID, propertyID int64, checkIn, checkOut time.Time, guests int) (bool, error)
+}
@@ -0,0 +1,51 @@
+/*
+Package Name: interfaces
+File Name: reservation_lookup_interface.go
+Abstract: Interface used by the communication module to look up the stored
+details of a reservation without importing the booking module.
+
+# MIT License
+
+# Copyright 2023 Alejandro Modroño Vara
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+package interfaces
+
+// ======== TYPES ========
+
+// ReservationContact holds the details of a reservation needed to message
+// its guest.
+type ReservationContact struct {
+	ID             int64
+	OrganizationID int64
+	CustomerID     int64
+	PropertyID     int64
+	Status         string
+	PaymentURL     string
+}
+
+// ======== INTERFACES ========
+
+// The interface for looking up reservations from other modules.
+type ReservationLookup interface {
+	// GetReservationContact returns the reservation if it belongs to the
+	// organization, or nil if it does not exist there.
+	GetReservationContact(reservationID, organizationID int64) (*ReservationContact, error)
+}
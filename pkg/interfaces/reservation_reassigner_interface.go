@@ -0,0 +1,42 @@
+/*
+Package Name: interfaces
+File Name: reservation_reassigner_interface.go
+Abstract: Interface used by the customer module to move reservations between
+customers without importing the booking module (which already imports it).
+
+# MIT License
+
+# Copyright 2023 Alejandro Modroño Vara
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+package interfaces
+
+// ======== INTERFACES ========
+
+// The interface for moving reservations from one customer to another.
+type ReservationReassigner interface {
+	// ReassignCustomer re-points every reservation of fromCustomerID in the
+	// organization to toCustomerID and returns how many rows were moved.
+	//
+	// The deletion of fromCustomerID is queued in the customer_deletion
+	// table in the same database transaction, so once the reservations are
+	// moved the duplicate is guaranteed to be deleted eventually.
+	ReassignCustomer(organizationID, fromCustomerID, toCustomerID int64) (int64, error)
+}
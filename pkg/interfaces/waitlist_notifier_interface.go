@@ -0,0 +1,44 @@
+/*
+Package Name: interfaces
+File Name: waitlist_notifier_interface.go
+Abstract: Interface used by the booking module to tell the waitlist module about
+reservation changes without importing it.
+
+# MIT License
+
+# Copyright 2023 Alejandro Modroño Vara
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+package interfaces
+
+import "time"
+
+// ======== INTERFACES ========
+
+// The interface for telling the waitlist about reservation changes.
+type WaitlistNotifier interface {
+	// DatesReleased is called once a reservation no longer takes its stay at
+	// the property, because it was cancelled, rejected, deleted or moved.
+	DatesReleased(organizationID, propertyID int64, checkIn, checkOut time.Time) error
+
+	// StayBooked is called once a customer booked a stay, which fulfils
+	// their waitlist entries for it.
+	StayBooked(organizationID, customerID, propertyID int64, checkIn, checkOut time.Time) error
+}
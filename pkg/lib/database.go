@@ -23,18 +23,13 @@ func GetDatabase(logger Logger) *pgxpool.Pool { // Assuming your *Database is a
 	}
 
 	// 1. Parse the connection string into a config object
-	config, err := pgxpool.ParseConfig(url)
+	config, err := NewPoolConfig(url)
 	if err != nil {
 		logger.Fatal("Unable to parse DATABASE_URL: ", err)
 		os.Exit(1)
 	}
 
-	// 2. Fix for Supabase/PgBouncer:
-	// This mode tells pgx to describe the statement but not give it a name,
-	// avoiding collisions in transaction-mode poolers.
-	config.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeDescribeExec
-
-	// 3. Create the pool using the modified config
+	// 2. Create the pool using the modified config
 	dbPool, err := pgxpool.NewWithConfig(context.Background(), config)
 	if err != nil {
 		logger.Fatal("Unable to connect to database: ", err)
@@ -50,3 +45,20 @@ func GetDatabase(logger Logger) *pgxpool.Pool { // Assuming your *Database is a
 	logger.Info("Connected to the database successfully using DescribeExec mode.")
 	return dbPool
 }
+
+// NewPoolConfig parses a connection string into the pool configuration used
+// by the service. Tests that talk to a real database use it too, so queries
+// are encoded the same way as in production.
+func NewPoolConfig(url string) (*pgxpool.Config, error) {
+	config, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fix for Supabase/PgBouncer:
+	// This mode tells pgx to describe the statement but not give it a name,
+	// avoiding collisions in transaction-mode poolers.
+	config.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeDescribeExec
+
+	return config, nil
+}
@@ -0,0 +1,109 @@
+/*
+Package Name: lib
+File Name: worker.go
+Abstract: A background worker that runs a task periodically for the
+lifetime of the application.
+
+# MIT License
+
+# Copyright 2023 Alejandro Modroño Vara
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+package lib
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/fx"
+)
+
+// ======== TYPES ========
+
+// Worker runs a task in the background. The task runs once when the worker
+// starts and then every interval, or earlier when the worker is woken.
+type Worker struct {
+	interval time.Duration
+	task     func(context.Context)
+	wake     chan struct{}
+}
+
+// ======== METHODS ========
+
+// NewWorker returns a Worker that runs task every interval
+func NewWorker(interval time.Duration, task func(context.Context)) *Worker {
+	return &Worker{
+		interval: interval,
+		task:     task,
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Wake makes the worker run its task without waiting for the next tick. It
+// never blocks; wakes that arrive while the task is running are coalesced.
+func (w *Worker) Wake() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run runs the task until ctx is cancelled.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.task(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-w.wake:
+		}
+	}
+}
+
+// Start runs the worker with the application: it starts when the
+// application starts and is stopped, waiting for the running task, when it
+// stops.
+func (w *Worker) Start(lifecycle fx.Lifecycle) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lifecycle.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				w.Run(ctx)
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,82 @@
+package lib
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorker_RunsImmediatelyAndWhenWoken(t *testing.T) {
+	var runs atomic.Int32
+	ran := make(chan struct{}, 10)
+	worker := NewWorker(time.Hour, func(context.Context) {
+		runs.Add(1)
+		ran <- struct{}{}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		worker.Run(ctx)
+	}()
+
+	waitRun := func() {
+		select {
+		case <-ran:
+		case <-time.After(time.Second):
+			t.Fatal("task did not run")
+		}
+	}
+
+	waitRun()
+	worker.Wake()
+	waitRun()
+
+	cancel()
+	worker.Wake()
+	<-done
+	assert.LessOrEqual(t, runs.Load(), int32(3))
+	assert.GreaterOrEqual(t, runs.Load(), int32(2))
+}
+
+func TestWorker_RunsEveryInterval(t *testing.T) {
+	ran := make(chan struct{}, 10)
+	worker := NewWorker(10*time.Millisecond, func(context.Context) {
+		ran <- struct{}{}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go worker.Run(ctx)
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-ran:
+		case <-time.After(time.Second):
+			t.Fatalf("task ran %d times, want 3", i)
+		}
+	}
+}
+
+func TestWorker_WakeDoesNotBlock(t *testing.T) {
+	worker := NewWorker(time.Hour, func(context.Context) {})
+
+	finished := make(chan struct{})
+	go func() {
+		worker.Wake()
+		worker.Wake()
+		worker.Wake()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		require.Fail(t, "Wake blocked without a running worker")
+	}
+}